@@ -0,0 +1,149 @@
+package vcard
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Property lists the value types returned by Get
+type Property interface {
+	string | Kind | Name | Email | Phone | Address | Organization | URL | Geo | time.Time
+}
+
+// propertyValues maps property names to the values they hold on a card.
+// Adding a property here makes it available to Get without new getters.
+var propertyValues = map[string]func(v *VCard) []any{
+	"FN": func(v *VCard) []any { return textValues(v.formattedName()) },
+	"N": func(v *VCard) []any {
+		if v.name == (Name{}) {
+			return nil
+		}
+		return []any{v.name}
+	},
+	"KIND": func(v *VCard) []any {
+		if v.kind == "" {
+			return nil
+		}
+		return []any{v.kind}
+	},
+	"EMAIL": func(v *VCard) []any { return anyValues(v.emails) },
+	"TEL":   func(v *VCard) []any { return anyValues(v.phones) },
+	"ADR":   func(v *VCard) []any { return anyValues(v.addresses) },
+	"ORG": func(v *VCard) []any {
+		if v.organization.Name == "" && len(v.organization.OrgUnits()) == 0 {
+			return nil
+		}
+		return []any{v.GetOrganization()}
+	},
+	"TITLE": func(v *VCard) []any { return textValues(v.organization.Title) },
+	"ROLE":  func(v *VCard) []any { return textValues(v.organization.Role) },
+	"URL":   func(v *VCard) []any { return anyValues(v.urls) },
+	"GEO": func(v *VCard) []any {
+		if v.geo == nil {
+			return nil
+		}
+		return []any{*v.geo}
+	},
+	"PHOTO":       func(v *VCard) []any { return textValues(v.photo) },
+	"LOGO":        func(v *VCard) []any { return textValues(v.logo) },
+	"NOTE":        func(v *VCard) []any { return textValues(v.note) },
+	"BDAY":        func(v *VCard) []any { return dateValues(v.birthday, v.bdayText) },
+	"ANNIVERSARY": func(v *VCard) []any { return dateValues(v.anniversary, v.annivText) },
+	"UID":         func(v *VCard) []any { return textValues(v.uid) },
+	"MEMBER":      func(v *VCard) []any { return anyValues(v.members) },
+}
+
+// Get returns the values of the named property (case-insensitive) as T.
+// Properties holding T return their values directly, e.g. Get[Email](card,
+// "EMAIL"); with T string every property returns its text form, e.g.
+// Get[string](card, "EMAIL") returns the addresses. Custom properties are
+// read as text. Values of other types are skipped.
+func Get[T Property](card *VCard, name string) []T {
+	name = strings.ToUpper(name)
+
+	var values []any
+	if accessor, ok := propertyValues[name]; ok {
+		values = accessor(card)
+	} else {
+		for property, value := range card.customProps {
+			if strings.EqualFold(property, name) && value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	var result []T
+	for _, value := range values {
+		if typed, ok := value.(T); ok {
+			result = append(result, typed)
+		} else if text, ok := any(propertyText(value, card.version)).(T); ok {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
+// GetFirst returns the first value of the named property as T and whether
+// there was one
+func GetFirst[T Property](card *VCard, name string) (T, bool) {
+	values := Get[T](card, name)
+	if len(values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return values[0], true
+}
+
+// textValues returns the value as a single-element list, or nil when empty
+func textValues(value string) []any {
+	if value == "" {
+		return nil
+	}
+	return []any{value}
+}
+
+// anyValues converts a typed slice for propertyValues
+func anyValues[T any](values []T) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// dateValues returns a date property as a time or free text
+func dateValues(date *time.Time, text string) []any {
+	if date != nil {
+		return []any{*date}
+	}
+	return textValues(text)
+}
+
+// propertyText returns the text form of a property value
+func propertyText(value any, version Version) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case Kind:
+		return string(value)
+	case Name:
+		return value.FormattedName()
+	case Email:
+		return value.Address
+	case Phone:
+		return value.Number
+	case Address:
+		return value.FormattedAddress()
+	case Organization:
+		return value.Name
+	case URL:
+		return value.Address
+	case Geo:
+		return strconv.FormatFloat(value.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(value.Longitude, 'f', -1, 64)
+	case time.Time:
+		return formatDate(value, version)
+	default:
+		return ""
+	}
+}
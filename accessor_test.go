@@ -0,0 +1,67 @@
+package vcard
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	card := NewWithVersion(Version40).
+		SetKind(KindIndividual).
+		AddName("Jane", "Doe").
+		AddEmail("jane@example.com", EmailWork).
+		AddEmail("jane@home.example", EmailHome).
+		AddPhone("+15551234567").
+		AddOrganization("Acme").
+		AddNote("Met at GopherCon").
+		AddBirthday(time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC)).
+		SetGeo(42.5, -71.25).
+		AddCustomProperty("X-Skype", "jane.doe")
+
+	if emails := Get[Email](card, "email"); len(emails) != 2 || emails[1].Type != EmailHome {
+		t.Errorf("Unexpected emails: %v", emails)
+	}
+	if addresses := Get[string](card, "EMAIL"); !slices.Equal(addresses, []string{"jane@example.com", "jane@home.example"}) {
+		t.Errorf("Unexpected email text: %v", addresses)
+	}
+	if kind, ok := GetFirst[Kind](card, "KIND"); !ok || kind != KindIndividual {
+		t.Errorf("Unexpected kind: %v", kind)
+	}
+	if birthday, ok := GetFirst[time.Time](card, "BDAY"); !ok || birthday.Year() != 1990 {
+		t.Errorf("Unexpected birthday: %v", birthday)
+	}
+
+	tests := map[string]string{
+		"FN":      "Jane Doe",
+		"KIND":    "individual",
+		"TEL":     "+15551234567",
+		"ORG":     "Acme",
+		"NOTE":    "Met at GopherCon",
+		"BDAY":    "19900515",
+		"GEO":     "42.5,-71.25",
+		"X-SKYPE": "jane.doe",
+	}
+	for name, expected := range tests {
+		if value, ok := GetFirst[string](card, name); !ok || value != expected {
+			t.Errorf("GetFirst[string](%s) = %q, %v; want %q", name, value, ok, expected)
+		}
+	}
+
+	if values := Get[Phone](card, "EMAIL"); values != nil {
+		t.Errorf("Expected no values of another type, got %v", values)
+	}
+	if _, ok := GetFirst[string](card, "ROLE"); ok {
+		t.Error("Expected no value for an unset property")
+	}
+}
+
+func TestGetReturnsCopies(t *testing.T) {
+	card := New().AddName("Jane", "Doe").AddOrganization("Acme").AddOrgUnit("Platform")
+
+	org, _ := GetFirst[Organization](card, "ORG")
+	org.Units[0] = "Changed"
+	if card.GetOrganization().Units[0] != "Platform" {
+		t.Error("Expected Get to return copies")
+	}
+}
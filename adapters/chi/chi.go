@@ -3,10 +3,20 @@ package chi
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/jws"
+	"go.rumenx.com/vcard/photoproxy"
+	"go.rumenx.com/vcard/server"
+	"go.rumenx.com/vcard/sharelink"
+	"go.rumenx.com/vcard/webhook"
 )
 
 // VCardHandler is a function that returns a VCard
@@ -19,31 +29,143 @@ type Options struct {
 
 	// ContentDisposition sets how the file should be handled (attachment/inline)
 	ContentDisposition string
+
+	// FilenameTemplate builds the filename from card fields (e.g. "{first}-{last}")
+	// when Filename is not set. See vcard.VCard.Filename for supported tokens.
+	FilenameTemplate string
+
+	// ExtraHeaders are added to every successful vCard response
+	ExtraHeaders map[string]string
+
+	// StatusOnInvalid is the HTTP status returned when the card fails validation
+	StatusOnInvalid int
+
+	// HeaderProperties maps request header names to custom card properties
+	// (e.g. "X-Employee-Id" to "X-EMPLOYEE-ID") added to every served card
+	HeaderProperties map[string]string
+
+	// Properties returns additional custom properties for the request, such
+	// as values taken from authentication claims
+	Properties func(w http.ResponseWriter, r *http.Request) map[string]string
+
+	// Visibility selects the fields served for the request, e.g. returning
+	// vcard.PublicFields for unauthenticated requests. Nil serves all fields.
+	Visibility func(w http.ResponseWriter, r *http.Request) vcard.FieldMask
+
+	// Redaction, when set, limits every served card to the fields of a named
+	// visibility profile such as vcard.RedactionPublicWeb, applied after
+	// Visibility. Visibility and Redaction apply to every handler: VCard,
+	// VCardJSON, Bulk, ShareLink and Photo.
+	Redaction *vcard.Redaction
+
+	// Signer, when set, signs the served .vcf bytes and sends the detached
+	// JWS in the jws.Header response header, so clients can verify that
+	// cards came from this service. Bulk responses are signed too.
+	Signer jws.Signer
+
+	// Tracer, when set, traces generating the served cards, e.g. with
+	// vcardotel.New(provider). Spans are children of the request context.
+	Tracer vcard.Tracer
+
+	// Logger receives a generation event per served card (client, size,
+	// duration). Names are omitted and emails and phone numbers are only
+	// counted unless LogKey is set; nil disables logging.
+	Logger *slog.Logger
+
+	// LogKey, when set, logs the emails and phone numbers of cards as
+	// vcard.HashPII digests under this secret key, so requests for the same
+	// person can be correlated
+	LogKey []byte
+
+	// Webhook is notified asynchronously of every generated or imported card.
+	// Events of both VCard and Bulk carry the full card, before Visibility
+	// and Redaction are applied to the response.
+	Webhook *webhook.Notifier
+
+	// Enricher fills in missing data on every card of a Bulk import, e.g.
+	// vcard.DomainEnricher{}. Enrichment is best effort: failures are logged
+	// to Logger and the card is served as is.
+	Enricher vcard.Enricher
+
+	// MaxBulkSize caps the request body of Bulk in bytes. Zero uses
+	// server.DefaultUploadLimits.MaxBodySize and a negative value disables
+	// the limit. Larger requests are rejected with 413.
+	MaxBulkSize int64
+
+	// MaxBulkContacts caps the number of contacts in a Bulk request. Zero
+	// uses server.DefaultUploadLimits.MaxCards and a negative value disables
+	// the limit. Longer arrays are rejected with 413.
+	MaxBulkContacts int
+
+	// Version selects the vCard version to serve for the request (e.g. "4.0").
+	// An empty result keeps the version the handler built the card with.
+	Version func(w http.ResponseWriter, r *http.Request) string
+
+	// QR renders the card as a QR code PNG, added to VCardJSON responses as a
+	// base64 data URI under "qrCode"; nil omits the QR code
+	QR vcard.QREncoder
+
+	// DataURI adds the card to VCardJSON responses as a data:text/vcard URI
+	// under "dataUri", so that pages can offer the download without another
+	// request
+	DataURI bool
 }
 
 // DefaultOptions provides sensible defaults
 var DefaultOptions = Options{
 	Filename: func(w http.ResponseWriter, r *http.Request) string {
-		return "contact.vcf"
+		return vcard.DefaultFilename
 	},
 	ContentDisposition: "attachment",
+	StatusOnInvalid:    http.StatusBadRequest,
+	MaxBulkSize:        server.DefaultUploadLimits.MaxBodySize,
+	MaxBulkContacts:    server.DefaultUploadLimits.MaxCards,
+	Version: func(w http.ResponseWriter, r *http.Request) string {
+		return r.URL.Query().Get("version")
+	},
 }
 
-// VCard middleware for Chi that generates vCard responses
-func VCard(handler VCardHandler, opts ...Options) http.HandlerFunc {
+// resolveOptions applies the defaults to missing option fields
+func resolveOptions(opts []Options) Options {
 	options := DefaultOptions
 	if len(opts) > 0 {
 		options = opts[0]
 		// Apply defaults for missing fields
-		if options.Filename == nil {
+		if options.Filename == nil && options.FilenameTemplate == "" {
 			options.Filename = DefaultOptions.Filename
 		}
 		if options.ContentDisposition == "" {
 			options.ContentDisposition = DefaultOptions.ContentDisposition
 		}
+		if options.StatusOnInvalid == 0 {
+			options.StatusOnInvalid = DefaultOptions.StatusOnInvalid
+		}
+		if options.MaxBulkSize == 0 {
+			options.MaxBulkSize = DefaultOptions.MaxBulkSize
+		}
+		if options.MaxBulkContacts == 0 {
+			options.MaxBulkContacts = DefaultOptions.MaxBulkContacts
+		}
+		if options.Version == nil {
+			options.Version = DefaultOptions.Version
+		}
 	}
 
+	return options
+}
+
+// bulkLimits returns the upload limits of the Bulk options
+func bulkLimits(options Options) server.UploadLimits {
+	return server.UploadLimits{MaxBodySize: options.MaxBulkSize, MaxCards: options.MaxBulkContacts}
+}
+
+// VCard middleware for Chi that generates vCard responses
+func VCard(handler VCardHandler, opts ...Options) http.HandlerFunc {
+	options := resolveOptions(opts)
+
 	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
 		// Generate vCard
 		card := handler(w, r)
 		if card == nil {
@@ -51,37 +173,272 @@ func VCard(handler VCardHandler, opts ...Options) http.HandlerFunc {
 			return
 		}
 
+		// Serve the requested version without mutating the handler's card
+		if requested := options.Version(w, r); requested != "" {
+			version, err := vcard.ParseVersion(requested)
+			if err != nil {
+				http.Error(w, "Unsupported vCard version", http.StatusBadRequest)
+				return
+			}
+			card = card.Clone().SetVersion(version)
+		}
+
+		// Enrich the card with request metadata
+		if props := requestProperties(options, w, r); len(props) > 0 {
+			card = card.Clone().AddCustomProperties(props)
+		}
+
+		// Strip fields the requester may not see, keeping the full card for
+		// webhooks
+		full := card
+		card = restrict(options, w, r, card)
+
+		// Validate vCard
+		if err := card.Validate(); err != nil {
+			http.Error(w, "Invalid vCard: "+err.Error(), options.StatusOnInvalid)
+			return
+		}
+
 		// Generate vCard content
-		content, err := card.String()
+		content, err := card.StringContext(vcard.ContextWithTracer(r.Context(), options.Tracer))
 		if err != nil {
 			http.Error(w, "Failed to generate vCard content", http.StatusInternalServerError)
 			return
 		}
+		if options.Signer != nil {
+			signature, err := jws.Detached(options.Signer, []byte(content))
+			if err != nil {
+				http.Error(w, "Failed to sign vCard content", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(jws.Header, signature)
+		}
 
 		// Set headers
-		filename := options.Filename(w, r)
-		w.Header().Set("Content-Type", "text/vcard")
-		w.Header().Set("Content-Disposition", options.ContentDisposition+"; filename="+filename)
+		var filename string
+		if options.Filename != nil {
+			filename = options.Filename(w, r)
+		} else {
+			filename = card.Filename(options.FilenameTemplate)
+		}
+		w.Header().Set("Content-Type", vcard.MIMEType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(options.ContentDisposition, map[string]string{"filename": filename}))
+		for key, value := range options.ExtraHeaders {
+			w.Header().Set(key, value)
+		}
+
+		if options.Webhook != nil {
+			notify(options, webhook.EventGenerated, full)
+		}
+
+		if options.Logger != nil {
+			options.Logger.Info("vcard generated",
+				slog.String("client", r.RemoteAddr),
+				slog.String("path", r.URL.Path),
+				slog.Int("bytes", len(content)),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("card", card.LogWithKey(options.LogKey)),
+			)
+		}
 
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(content))
 	}
 }
 
-// VCardJSON middleware for Chi that returns vCard data as JSON
-func VCardJSON(handler VCardHandler) http.HandlerFunc {
+// restrict applies the Visibility and Redaction options to a served card
+func restrict(options Options, w http.ResponseWriter, r *http.Request, card *vcard.VCard) *vcard.VCard {
+	if options.Visibility != nil {
+		card = card.Masked(options.Visibility(w, r))
+	}
+	if options.Redaction != nil {
+		card = card.Redacted(*options.Redaction)
+	}
+	return card
+}
+
+// requestProperties collects custom properties from the mapped request headers
+// and the Properties hook
+func requestProperties(options Options, w http.ResponseWriter, r *http.Request) map[string]string {
+	props := make(map[string]string)
+	for header, property := range options.HeaderProperties {
+		if value := r.Header.Get(header); value != "" {
+			props[property] = value
+		}
+	}
+
+	if options.Properties != nil {
+		for name, value := range options.Properties(w, r) {
+			props[name] = value
+		}
+	}
+
+	return props
+}
+
+// notify sends a webhook event for the card, logging cards that can't be
+// generated instead
+func notify(options Options, eventType string, card *vcard.VCard) {
+	event, err := webhook.NewEvent(eventType, card)
+	if err != nil {
+		if options.Logger != nil {
+			options.Logger.Warn("vcard webhook event failed", "type", eventType, "error", err)
+		}
+		return
+	}
+	options.Webhook.Notify(event)
+}
+
+// ShareLink serves the card addressed by a signed share-link token passed in
+// the "token" query parameter. Invalid tokens are rejected with 403, expired
+// tokens with 410 and unknown UIDs with 404.
+func ShareLink(signer *sharelink.Signer, lookup func(uid string) *vcard.VCard, opts ...Options) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Generate vCard
-		card := handler(w, r)
+		uid, err := signer.Verify(r.URL.Query().Get(sharelink.QueryParam))
+		if errors.Is(err, sharelink.ErrExpired) {
+			http.Error(w, "Share link expired", http.StatusGone)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Invalid share link", http.StatusForbidden)
+			return
+		}
+
+		card := lookup(uid)
 		if card == nil {
+			http.Error(w, "Contact not found", http.StatusNotFound)
+			return
+		}
+
+		VCard(func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+			return card
+		}, opts...)(w, r)
+	}
+}
+
+// Bulk handles requests carrying a JSON array of contacts and responds with a
+// single .vcf file containing all cards. Contacts that fail validation are
+// reported by index with the StatusOnInvalid status, and requests over
+// MaxBulkSize or MaxBulkContacts with 413. Valid cards are passed through the
+// Enricher, when set, and limited by Visibility and Redaction before they are
+// written. The file is named by the
+// Filename option when set, else by FilenameTemplate when every card gives
+// the same name (see vcard.AddressBook.Filename), else DefaultBulkFilename.
+func Bulk(opts ...Options) http.HandlerFunc {
+	options := resolveOptions(opts)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if options.MaxBulkSize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, options.MaxBulkSize)
+		}
+		contacts, err := server.DecodeContacts(r.Body, bulkLimits(options))
+		if err != nil {
+			if status := server.UploadStatus(err); status == http.StatusRequestEntityTooLarge {
+				http.Error(w, "Request too large", status)
+				return
+			}
+			http.Error(w, "Invalid JSON: expected an array of contacts", http.StatusBadRequest)
+			return
+		}
+
+		var version vcard.Version
+		if requested := options.Version(w, r); requested != "" {
+			parsed, err := vcard.ParseVersion(requested)
+			if err != nil {
+				http.Error(w, "Unsupported vCard version", http.StatusBadRequest)
+				return
+			}
+			version = parsed
+		}
+
+		book, errs := vcard.NewAddressBookFromContacts(contacts, version)
+		if len(errs) > 0 {
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": "Failed to generate vCard",
+			w.WriteHeader(options.StatusOnInvalid)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": errs,
 			})
 			return
 		}
 
+		if options.Enricher != nil {
+			for _, failure := range book.Enrich(r.Context(), options.Enricher) {
+				if options.Logger != nil {
+					options.Logger.Warn("vcard enrichment failed", "index", failure.Index, "error", failure.Message)
+				}
+			}
+		}
+
+		served := book
+		if options.Visibility != nil {
+			served = served.Masked(options.Visibility(w, r))
+		}
+		if options.Redaction != nil {
+			served = served.Redacted(*options.Redaction)
+		}
+		content, err := served.StringContext(vcard.ContextWithTracer(r.Context(), options.Tracer))
+		if err != nil {
+			http.Error(w, "Failed to generate vCard content", http.StatusInternalServerError)
+			return
+		}
+		if options.Signer != nil {
+			signature, err := jws.Detached(options.Signer, []byte(content))
+			if err != nil {
+				http.Error(w, "Failed to sign vCard content", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(jws.Header, signature)
+		}
+
+		if options.Webhook != nil {
+			for _, card := range book.Cards() {
+				notify(options, webhook.EventImported, card)
+			}
+		}
+
+		filename := served.Filename(options.FilenameTemplate)
+		if len(opts) > 0 && opts[0].Filename != nil {
+			filename = options.Filename(w, r)
+		}
+		w.Header().Set("Content-Type", vcard.MIMEType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(options.ContentDisposition, map[string]string{"filename": filename}))
+		for key, value := range options.ExtraHeaders {
+			w.Header().Set(key, value)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(content))
+	}
+}
+
+// VCardJSON middleware for Chi that returns vCard data as JSON, optionally
+// with the QR code and data URI selected by the QR and DataURI options. The
+// requested version, request properties, Visibility and Redaction are applied
+// as by VCard.
+func VCardJSON(handler VCardHandler, opts ...Options) http.HandlerFunc {
+	options := resolveOptions(opts)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Generate vCard
+		card := handler(w, r)
+		if card == nil {
+			writeJSONError(w, http.StatusInternalServerError, "Failed to generate vCard")
+			return
+		}
+
+		if requested := options.Version(w, r); requested != "" {
+			version, err := vcard.ParseVersion(requested)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "Unsupported vCard version")
+				return
+			}
+			card = card.Clone().SetVersion(version)
+		}
+		if props := requestProperties(options, w, r); len(props) > 0 {
+			card = card.Clone().AddCustomProperties(props)
+		}
+		card = restrict(options, w, r, card)
+
 		// Convert to JSON-friendly structure
 		response := map[string]interface{}{
 			"name":         card.GetName(),
@@ -94,78 +451,88 @@ func VCardJSON(handler VCardHandler) http.HandlerFunc {
 			"note":         card.GetNote(),
 		}
 
+		if err := addShareData(response, card, options); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Failed to generate vCard: "+err.Error())
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(response)
 	}
 }
 
-// CreateFromParams creates a vCard from Chi context parameters and query values
-func CreateFromParams(w http.ResponseWriter, r *http.Request) *vcard.VCard {
-	card := vcard.New()
-
-	// Name from path parameters or query parameters
-	if firstName := r.URL.Query().Get("firstName"); firstName == "" {
-		firstName = chi.URLParam(r, "firstName")
-		if firstName != "" {
-			lastName := r.URL.Query().Get("lastName")
-			if lastName == "" {
-				lastName = chi.URLParam(r, "lastName")
-			}
-			card.AddName(firstName, lastName)
-		}
-	} else {
-		lastName := r.URL.Query().Get("lastName")
-		card.AddName(firstName, lastName)
-	}
+// writeJSONError writes a VCardJSON error response
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+	})
+}
 
-	// Email
-	if email := r.URL.Query().Get("email"); email != "" {
-		emailType := r.URL.Query().Get("emailType")
-		switch emailType {
-		case "home":
-			card.AddEmail(email, vcard.EmailHome)
-		case "mobile":
-			card.AddEmail(email, vcard.EmailMobile)
-		default:
-			card.AddEmail(email, vcard.EmailWork)
+// addShareData adds the QR code and data URI selected by the options to a
+// VCardJSON response
+func addShareData(response map[string]interface{}, card *vcard.VCard, options Options) error {
+	if options.QR != nil {
+		qr, err := card.QRCode(options.QR)
+		if err != nil {
+			return err
 		}
+		response["qrCode"] = qr
 	}
 
-	// Phone
-	if phone := r.URL.Query().Get("phone"); phone != "" {
-		phoneType := r.URL.Query().Get("phoneType")
-		switch phoneType {
-		case "home":
-			card.AddPhone(phone, vcard.PhoneHome)
-		case "mobile", "cell":
-			card.AddPhone(phone, vcard.PhoneMobile)
-		case "fax":
-			card.AddPhone(phone, vcard.PhoneFax)
-		default:
-			card.AddPhone(phone, vcard.PhoneWork)
+	if options.DataURI {
+		uri, err := card.DataURI()
+		if err != nil {
+			return err
 		}
+		response["dataUri"] = uri
 	}
 
-	// Organization
-	if org := r.URL.Query().Get("organization"); org != "" {
-		card.AddOrganization(org)
+	return nil
+}
+
+// Info returns a handler that serves the library metadata as JSON
+func Info() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(vcard.GetInfo())
 	}
+}
+
+// Photo serves the photo of the card returned by handler through the proxy,
+// scaled to the "size" query parameter when given. Cards without a photo,
+// including photos hidden by Visibility or Redaction, are answered with 404,
+// failed downloads with 502 or 504.
+func Photo(proxy *photoproxy.Proxy, handler VCardHandler, opts ...Options) http.HandlerFunc {
+	options := resolveOptions(opts)
 
-	// Title
-	if title := r.URL.Query().Get("title"); title != "" {
-		card.AddTitle(title)
+	return func(w http.ResponseWriter, r *http.Request) {
+		card := handler(w, r)
+		if card != nil {
+			card = restrict(options, w, r, card)
+		}
+		proxy.Serve(w, r, card)
 	}
+}
 
-	// URL
-	if url := r.URL.Query().Get("url"); url != "" {
-		card.AddURL(url, vcard.URLWork)
+// CreateFromParams creates a vCard from the request parameters using the
+// schema documented at vcard.FromParams. Query and form values take
+// precedence over Chi URL parameters.
+func CreateFromParams(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+	path := make(map[string]string)
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		for i, key := range rctx.URLParams.Keys {
+			path[key] = rctx.URLParams.Values[i]
+		}
 	}
 
-	// Note
-	if note := r.URL.Query().Get("note"); note != "" {
-		card.AddNote(note)
+	var form url.Values
+	if r.ParseForm() == nil {
+		form = r.PostForm
 	}
 
-	return card
+	return vcard.FromParams(vcard.ParamValues(path, r.URL.Query(), form))
 }
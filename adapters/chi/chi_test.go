@@ -1,15 +1,24 @@
 package chi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	vcard "go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/jws"
+	"go.rumenx.com/vcard/photoproxy"
+	"go.rumenx.com/vcard/sharelink"
+	"go.rumenx.com/vcard/webhook"
 )
 
 func TestVCardMiddleware(t *testing.T) {
@@ -85,6 +94,27 @@ func TestVCardWithCustomOptions(t *testing.T) {
 	}
 }
 
+func TestVCardQuotedFilename(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+	options := Options{
+		Filename: func(w http.ResponseWriter, r *http.Request) string {
+			return `Jane "JS" Smith.vcf`
+		},
+	}
+
+	rr := httptest.NewRecorder()
+	VCard(handler, options)(rr, httptest.NewRequest("GET", "/", nil))
+
+	disposition, params, err := mime.ParseMediaType(rr.Header().Get("Content-Disposition"))
+	if err != nil || disposition != "attachment" || params["filename"] != `Jane "JS" Smith.vcf` {
+		t.Errorf("Expected a quoted filename, got %q", rr.Header().Get("Content-Disposition"))
+	}
+}
+
 func TestVCardJSONMiddleware(t *testing.T) {
 	r := chi.NewRouter()
 
@@ -181,6 +211,25 @@ func TestCreateFromParamsWithURLParams(t *testing.T) {
 	}
 }
 
+func TestCreateFromParamsForm(t *testing.T) {
+	var card *vcard.VCard
+	r := chi.NewRouter()
+	r.Post("/user/{firstName}", func(w http.ResponseWriter, r *http.Request) {
+		card = CreateFromParams(w, r)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/user/John?lastName=Doe", strings.NewReader("firstName=Johnny&department=R%26D"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if name := card.GetName(); name.First != "Johnny" || name.Last != "Doe" {
+		t.Errorf("Expected form and query values to be merged over URL params, got %+v", name)
+	}
+	if card.GetOrganization().Department != "R&D" {
+		t.Errorf("Expected department from form, got %+v", card.GetOrganization())
+	}
+}
+
 func TestCreateFromParamsEmailTypes(t *testing.T) {
 	r := chi.NewRouter()
 
@@ -249,3 +298,601 @@ func TestVCardJSONErrorHandling(t *testing.T) {
 		t.Errorf("Expected JSON response for error, got Content-Type: %s", contentType)
 	}
 }
+
+func TestVCardVersionQueryParam(t *testing.T) {
+	r := chi.NewRouter()
+
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	r.Get("/test", VCard(handler))
+
+	// Requested version is applied
+	req := httptest.NewRequest("GET", "/test?version=4.0", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "VERSION:4.0") {
+		t.Errorf("Expected VERSION:4.0 in body, got %s", rr.Body.String())
+	}
+
+	// Unsupported version is rejected
+	req = httptest.NewRequest("GET", "/test?version=9.9", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestVCardTemplateOptions(t *testing.T) {
+	r := chi.NewRouter()
+
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+
+	options := Options{
+		FilenameTemplate: "{first}-{last}",
+		ExtraHeaders:     map[string]string{"Cache-Control": "no-store"},
+	}
+	r.Get("/test", VCard(handler, options))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "Jane-Smith.vcf") {
+		t.Errorf("Expected templated filename, got %s", rr.Header().Get("Content-Disposition"))
+	}
+	if rr.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected extra header, got %s", rr.Header().Get("Cache-Control"))
+	}
+}
+
+func TestVCardStatusOnInvalid(t *testing.T) {
+	r := chi.NewRouter()
+
+	// Handler that returns a card without a name
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New()
+	}
+
+	r.Get("/default", VCard(handler))
+	r.Get("/custom", VCard(handler, Options{StatusOnInvalid: http.StatusUnprocessableEntity}))
+
+	req := httptest.NewRequest("GET", "/default", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/custom", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", rr.Code)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/info", Info())
+
+	req := httptest.NewRequest("GET", "/info", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+
+	var info vcard.Info
+	if err := json.NewDecoder(rr.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if info.Version != vcard.LibraryVersion {
+		t.Errorf("Expected version %s, got %s", vcard.LibraryVersion, info.Version)
+	}
+}
+
+func TestPhoto(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/contacts/{id}/photo", Photo(photoproxy.New(), func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		if chi.URLParam(r, "id") != "jane" {
+			return nil
+		}
+		return vcard.New().AddName("Jane", "Doe").AddPhoto("data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+	}))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/contacts/jane/photo?size=32", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	if rr.Header().Get("Content-Type") != "image/png" || rr.Header().Get("Cache-Control") == "" {
+		t.Errorf("Unexpected headers: %v", rr.Header())
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/contacts/john/photo", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+}
+
+func TestVCardLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddEmail("john@example.com")
+		return card
+	}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, Options{Logger: logger, LogKey: []byte("log-key")}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	output := buf.String()
+	if !strings.Contains(output, "vcard generated") {
+		t.Errorf("Expected generation event to be logged, got %s", output)
+	}
+	if strings.Contains(output, "john@example.com") {
+		t.Errorf("Expected email to be scrubbed from log, got %s", output)
+	}
+	if !strings.Contains(output, vcard.HashPII([]byte("log-key"), "john@example.com")) {
+		t.Errorf("Expected the keyed email digest in log, got %s", output)
+	}
+}
+
+func TestShareLink(t *testing.T) {
+	signer, err := sharelink.New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(uid string) *vcard.VCard {
+		if uid != "john" {
+			return nil
+		}
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	r := chi.NewRouter()
+	r.Get("/share", ShareLink(signer, lookup))
+
+	tests := []struct {
+		token    string
+		expected int
+	}{
+		{signer.Token("john", time.Hour), http.StatusOK},
+		{signer.Token("john", -time.Hour), http.StatusGone},
+		{signer.Token("jane", time.Hour), http.StatusNotFound},
+		{"forged", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/share?token="+tt.token, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("Expected status %d, got %d", tt.expected, rr.Code)
+		}
+	}
+}
+
+func TestVCardRequestProperties(t *testing.T) {
+	original := vcard.New()
+	original.AddName("John", "Doe")
+
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return original
+	}
+
+	options := Options{
+		HeaderProperties: map[string]string{"X-Employee-Id": "X-EMPLOYEE-ID"},
+		Properties: func(w http.ResponseWriter, r *http.Request) map[string]string {
+			return map[string]string{"X-COST-CENTER": "CC-7"}
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, options))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Employee-Id", "E42")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	body := rr.Body.String()
+
+	if !strings.Contains(body, "X-EMPLOYEE-ID:E42") || !strings.Contains(body, "X-COST-CENTER:CC-7") {
+		t.Errorf("Expected request properties in body, got %s", body)
+	}
+	if len(original.GetCustomProperties()) != 0 {
+		t.Error("Handler card should not be modified")
+	}
+}
+
+func TestVCardVisibility(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddPhone("+1111", vcard.PhoneWork)
+		card.AddPhone("+2222", vcard.PhoneHome)
+		return card
+	}
+
+	options := Options{
+		Visibility: func(w http.ResponseWriter, r *http.Request) vcard.FieldMask {
+			if r.Header.Get("Authorization") == "" {
+				return vcard.PublicFields
+			}
+			return vcard.FieldAll
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, options))
+
+	serve := func(auth string) string {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Body.String()
+	}
+
+	if body := serve(""); strings.Contains(body, "+2222") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected home phone to be hidden from anonymous requests, got %s", body)
+	}
+	if body := serve("Bearer token"); !strings.Contains(body, "+2222") {
+		t.Errorf("Expected full card for authenticated requests, got %s", body)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).AddNote("Internal note")
+	}
+	options := Options{Redaction: &vcard.RedactionPublicWeb}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, options))
+	r.Post("/bulk", Bulk(options))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/test", nil))
+	if body := rr.Body.String(); strings.Contains(body, "Internal note") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected the note to be redacted, got %s", body)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`)))
+	if body := rr.Body.String(); rr.Code != http.StatusOK || strings.Contains(body, "Internal note") {
+		t.Errorf("Expected the bulk note to be redacted, got %d %s", rr.Code, body)
+	}
+}
+
+func TestVisibilityAllHandlers(t *testing.T) {
+	photo := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).
+			AddPhone("+2222", vcard.PhoneHome).AddNote("Internal note").AddPhoto(photo)
+	}
+	options := Options{
+		Visibility: func(w http.ResponseWriter, r *http.Request) vcard.FieldMask {
+			return vcard.PublicFields &^ vcard.FieldPhoto
+		},
+		Redaction: &vcard.RedactionPublicWeb,
+		DataURI:   true,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/json", VCardJSON(handler, options))
+	r.Post("/bulk", Bulk(options))
+	r.Get("/photo", Photo(photoproxy.New(), handler, options))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/json", nil))
+	if body := rr.Body.String(); rr.Code != http.StatusOK || strings.Contains(body, "+2222") ||
+		strings.Contains(body, "Internal note") || strings.Contains(body, "iVBOR") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected VCardJSON to apply Visibility and Redaction, got %d %s", rr.Code, body)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("POST", "/bulk", strings.NewReader(
+		`[{"Name": {"First": "Jane", "Last": "Doe"}, "Phones": [{"Number": "+2222", "Type": "HOME"}]}]`)))
+	if body := rr.Body.String(); rr.Code != http.StatusOK || strings.Contains(body, "+2222") {
+		t.Errorf("Expected Bulk to apply Visibility, got %d %s", rr.Code, body)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/photo", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected a hidden photo to be answered with 404, got %d", rr.Code)
+	}
+}
+
+func TestRedactionWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 2)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer receiver.Close()
+
+	notifier := webhook.New(receiver.URL)
+	options := Options{Redaction: &vcard.RedactionPublicWeb, Webhook: notifier}
+	bulk := `[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddNote("Internal note")
+	}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, options))
+	r.Post("/bulk", Bulk(options))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/bulk", strings.NewReader(bulk)))
+	notifier.Close()
+
+	for _, eventType := range []string{webhook.EventGenerated, webhook.EventImported} {
+		select {
+		case event := <-events:
+			if event.Type != eventType || !strings.Contains(event.Card, "Internal note") {
+				t.Errorf("Expected a full %s card, got %+v", eventType, event)
+			}
+		default:
+			t.Errorf("Expected a %s event", eventType)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	secret := []byte("directory-secret-0123456789abcdef")
+	signer, err := jws.HMAC(secret, "directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := Options{Signer: signer}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, options))
+	r.Post("/bulk", Bulk(options))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/test", nil),
+		httptest.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`)),
+	} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if err := jws.Verify(rr.Header().Get(jws.Header), rr.Body.Bytes(), secret); err != nil {
+			t.Errorf("%s %s: expected a valid signature, got %v", req.Method, req.URL.Path, err)
+		}
+	}
+}
+
+func TestTracer(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	var spans spanNames
+	options := Options{Tracer: &spans}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, options))
+	r.Post("/bulk", Bulk(options))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`)))
+	if len(spans) != 2 || spans[0] != vcard.SpanGenerate || spans[1] != vcard.SpanBulk {
+		t.Errorf("Expected generate and bulk spans, got %v", spans)
+	}
+}
+
+// spanNames is a vcard.Tracer recording the names of the spans it starts
+type spanNames []string
+
+func (s *spanNames) Start(ctx context.Context, name string) (context.Context, vcard.Span) {
+	*s = append(*s, name)
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+func TestBulk(t *testing.T) {
+	r := chi.NewRouter()
+	r.Post("/bulk", Bulk())
+
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest("POST", "/bulk", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code, rr.Body.String()
+	}
+
+	code, body := post(`[{"Name": {"First": "John", "Last": "Doe"}}, {"Name": {"First": "Jane"}}]`)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if strings.Count(body, "BEGIN:VCARD") != 2 {
+		t.Errorf("Expected 2 cards, got %s", body)
+	}
+
+	code, body = post(`[{"Name": {"First": "John"}}, {"Note": "no name"}]`)
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid item, got %d", code)
+	}
+	if !strings.Contains(body, `"index":1`) {
+		t.Errorf("Expected error for index 1, got %s", body)
+	}
+
+	if code, _ = post(`{"not": "an array"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for malformed JSON, got %d", code)
+	}
+}
+
+func TestBulkLimits(t *testing.T) {
+	r := chi.NewRouter()
+	r.Post("/bulk", Bulk(Options{MaxBulkSize: 300, MaxBulkContacts: 2, FilenameTemplate: "{org}"}))
+
+	post := func(body string) (int, string) {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("POST", "/bulk", strings.NewReader(body)))
+		return rr.Code, rr.Header().Get("Content-Disposition")
+	}
+
+	same := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Acme"}}]`
+	if code, disposition := post(same); code != http.StatusOK || !strings.Contains(disposition, "Acme.vcf") {
+		t.Errorf("Expected the template filename, got %d %q", code, disposition)
+	}
+	mixed := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Globex"}}]`
+	if _, disposition := post(mixed); !strings.Contains(disposition, vcard.DefaultBulkFilename) {
+		t.Errorf("Expected the default bulk filename, got %q", disposition)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}}, {"Name": {"First": "B"}}, {"Name": {"First": "C"}}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for too many contacts, got %d", code)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}, "Note": "` + strings.Repeat("x", 512) + `"}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for a large body, got %d", code)
+	}
+}
+
+func TestBulkEnricher(t *testing.T) {
+	var logs bytes.Buffer
+	options := Options{
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		Enricher: vcard.Enrichers(vcard.DomainEnricher{}, vcard.EnricherFunc(func(ctx context.Context, card *vcard.VCard) error {
+			if card.GetName().First == "Fail" {
+				return errors.New("lookup failed")
+			}
+			return nil
+		})),
+	}
+	payload := `[{"Name": {"First": "Jane"}, "Emails": [{"Address": "jane@acme.com"}]}, {"Name": {"First": "Fail"}}]`
+
+	r := chi.NewRouter()
+	r.Post("/bulk", Bulk(options))
+	req := httptest.NewRequest("POST", "/bulk", strings.NewReader(payload))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	code, body := rr.Code, rr.Body.String()
+
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if !strings.Contains(body, "ORG:Acme") || !strings.Contains(body, "URL;TYPE=WORK:https://acme.com") {
+		t.Errorf("Expected enriched card, got %s", body)
+	}
+	if !strings.Contains(logs.String(), "vcard enrichment failed") {
+		t.Errorf("Expected enrichment failure to be logged, got %q", logs.String())
+	}
+}
+
+func TestVCardWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer server.Close()
+
+	notifier := webhook.New(server.URL)
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+
+	r := chi.NewRouter()
+	r.Get("/test", VCard(handler, Options{Webhook: notifier}))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	notifier.Close()
+
+	select {
+	case event := <-events:
+		if event.Type != webhook.EventGenerated || event.Name != "John Doe" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Error("Expected a webhook event")
+	}
+}
+
+func TestCreateFromParamsLink(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/qr", VCard(CreateFromParams))
+
+	req := httptest.NewRequest(http.MethodGet, "/qr?firstName=Jane&lastName=Doe&birthday=1990-05-15&photoUrl=https://example.com/jane.jpg&prop.X-SKYPE=jane.doe", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	body := rr.Body.String()
+	for _, expected := range []string{"BDAY:1990-05-15", "PHOTO;VALUE=uri:https://example.com/jane.jpg", "X-SKYPE:jane.doe"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %q in response:\n%s", expected, body)
+		}
+	}
+}
+
+func TestVCardJSONShareData(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+		return vcard.New().AddName("Jane", "Smith")
+	}
+	qr := func(content string) ([]byte, error) {
+		return []byte("png"), nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/vcard", VCardJSON(handler, Options{QR: qr, DataURI: true}))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/vcard", nil))
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response["qrCode"] != "data:image/png;base64,cG5n" {
+		t.Errorf("Unexpected qrCode: %v", response["qrCode"])
+	}
+	if uri, _ := response["dataUri"].(string); !strings.HasPrefix(uri, "data:text/vcard;charset=utf-8;base64,") {
+		t.Errorf("Unexpected dataUri: %v", response["dataUri"])
+	}
+
+	failing := VCardJSON(handler, Options{QR: func(string) ([]byte, error) {
+		return nil, errors.New("content too large")
+	}})
+	rr = httptest.NewRecorder()
+	failing(rr, httptest.NewRequest("GET", "/vcard", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 when the QR encoder fails, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,32 @@
+package chi
+
+import (
+	"net/http"
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/vcardtest/adaptertest"
+)
+
+func TestConformance(t *testing.T) {
+	adaptertest.Run(t, adaptertest.Adapter{
+		VCard: func(build adaptertest.CardFunc, options adaptertest.Options) http.Handler {
+			return VCard(func(w http.ResponseWriter, r *http.Request) *vcard.VCard {
+				return build(r)
+			}, conformanceOptions(options))
+		},
+		Bulk: func(options adaptertest.Options) http.Handler {
+			return Bulk(conformanceOptions(options))
+		},
+	})
+}
+
+// conformanceOptions maps the suite options onto the adapter options
+func conformanceOptions(options adaptertest.Options) Options {
+	return Options{
+		FilenameTemplate:   options.FilenameTemplate,
+		ContentDisposition: options.ContentDisposition,
+		ExtraHeaders:       options.ExtraHeaders,
+		StatusOnInvalid:    options.StatusOnInvalid,
+	}
+}
@@ -7,4 +7,6 @@ require (
 	go.rumenx.com/vcard v0.0.0
 )
 
+require golang.org/x/text v0.28.0 // indirect
+
 replace go.rumenx.com/vcard => ../../
@@ -0,0 +1,37 @@
+package echo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/vcardtest/adaptertest"
+)
+
+func TestConformance(t *testing.T) {
+	adaptertest.Run(t, adaptertest.Adapter{
+		VCard: func(build adaptertest.CardFunc, options adaptertest.Options) http.Handler {
+			e := echo.New()
+			e.GET("/", VCard(func(c echo.Context) *vcard.VCard {
+				return build(c.Request())
+			}, conformanceOptions(options)))
+			return e
+		},
+		Bulk: func(options adaptertest.Options) http.Handler {
+			e := echo.New()
+			e.POST("/", Bulk(conformanceOptions(options)))
+			return e
+		},
+	})
+}
+
+// conformanceOptions maps the suite options onto the adapter options
+func conformanceOptions(options adaptertest.Options) Options {
+	return Options{
+		FilenameTemplate:   options.FilenameTemplate,
+		ContentDisposition: options.ContentDisposition,
+		ExtraHeaders:       options.ExtraHeaders,
+		StatusOnInvalid:    options.StatusOnInvalid,
+	}
+}
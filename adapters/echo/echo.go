@@ -2,10 +2,20 @@
 package echo
 
 import (
+	"errors"
+	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/jws"
+	"go.rumenx.com/vcard/photoproxy"
+	"go.rumenx.com/vcard/server"
+	"go.rumenx.com/vcard/sharelink"
+	"go.rumenx.com/vcard/webhook"
 )
 
 // VCardHandler is a function that returns a VCard
@@ -18,54 +28,378 @@ type Options struct {
 
 	// ContentDisposition sets how the file should be handled (attachment/inline)
 	ContentDisposition string
+
+	// FilenameTemplate builds the filename from card fields (e.g. "{first}-{last}")
+	// when Filename is not set. See vcard.VCard.Filename for supported tokens.
+	FilenameTemplate string
+
+	// ExtraHeaders are added to every successful vCard response
+	ExtraHeaders map[string]string
+
+	// StatusOnInvalid is the HTTP status returned when the card fails validation
+	StatusOnInvalid int
+
+	// HeaderProperties maps request header names to custom card properties
+	// (e.g. "X-Employee-Id" to "X-EMPLOYEE-ID") added to every served card
+	HeaderProperties map[string]string
+
+	// Properties returns additional custom properties for the request, such
+	// as values taken from authentication claims
+	Properties func(c echo.Context) map[string]string
+
+	// Visibility selects the fields served for the request, e.g. returning
+	// vcard.PublicFields for unauthenticated requests. Nil serves all fields.
+	Visibility func(c echo.Context) vcard.FieldMask
+
+	// Redaction, when set, limits every served card to the fields of a named
+	// visibility profile such as vcard.RedactionPublicWeb, applied after
+	// Visibility. Visibility and Redaction apply to every handler: VCard,
+	// VCardJSON, Bulk, ShareLink and Photo.
+	Redaction *vcard.Redaction
+
+	// Signer, when set, signs the served .vcf bytes and sends the detached
+	// JWS in the jws.Header response header, so clients can verify that
+	// cards came from this service. Bulk responses are signed too.
+	Signer jws.Signer
+
+	// Tracer, when set, traces generating the served cards, e.g. with
+	// vcardotel.New(provider). Spans are children of the request context.
+	Tracer vcard.Tracer
+
+	// Logger receives a generation event per served card (client, size,
+	// duration). Names are omitted and emails and phone numbers are only
+	// counted unless LogKey is set; nil disables logging.
+	Logger *slog.Logger
+
+	// LogKey, when set, logs the emails and phone numbers of cards as
+	// vcard.HashPII digests under this secret key, so requests for the same
+	// person can be correlated
+	LogKey []byte
+
+	// Webhook is notified asynchronously of every generated or imported card.
+	// Events of both VCard and Bulk carry the full card, before Visibility
+	// and Redaction are applied to the response.
+	Webhook *webhook.Notifier
+
+	// Enricher fills in missing data on every card of a Bulk import, e.g.
+	// vcard.DomainEnricher{}. Enrichment is best effort: failures are logged
+	// to Logger and the card is served as is.
+	Enricher vcard.Enricher
+
+	// MaxBulkSize caps the request body of Bulk in bytes. Zero uses
+	// server.DefaultUploadLimits.MaxBodySize and a negative value disables
+	// the limit. Larger requests are rejected with 413.
+	MaxBulkSize int64
+
+	// MaxBulkContacts caps the number of contacts in a Bulk request. Zero
+	// uses server.DefaultUploadLimits.MaxCards and a negative value disables
+	// the limit. Longer arrays are rejected with 413.
+	MaxBulkContacts int
+
+	// Version selects the vCard version to serve for the request (e.g. "4.0").
+	// An empty result keeps the version the handler built the card with.
+	Version func(c echo.Context) string
+
+	// QR renders the card as a QR code PNG, added to VCardJSON responses as a
+	// base64 data URI under "qrCode"; nil omits the QR code
+	QR vcard.QREncoder
+
+	// DataURI adds the card to VCardJSON responses as a data:text/vcard URI
+	// under "dataUri", so that pages can offer the download without another
+	// request
+	DataURI bool
 }
 
 // DefaultOptions provides sensible defaults
 var DefaultOptions = Options{
 	Filename: func(c echo.Context) string {
-		return "contact.vcf"
+		return vcard.DefaultFilename
 	},
 	ContentDisposition: "attachment",
+	StatusOnInvalid:    http.StatusBadRequest,
+	MaxBulkSize:        server.DefaultUploadLimits.MaxBodySize,
+	MaxBulkContacts:    server.DefaultUploadLimits.MaxCards,
+	Version: func(c echo.Context) string {
+		return c.QueryParam("version")
+	},
 }
 
-// VCard middleware for Echo that generates vCard responses
-func VCard(handler VCardHandler, opts ...Options) echo.HandlerFunc {
+// resolveOptions applies the defaults to missing option fields
+func resolveOptions(opts []Options) Options {
 	options := DefaultOptions
 	if len(opts) > 0 {
 		options = opts[0]
 		// Apply defaults for missing fields
-		if options.Filename == nil {
+		if options.Filename == nil && options.FilenameTemplate == "" {
 			options.Filename = DefaultOptions.Filename
 		}
 		if options.ContentDisposition == "" {
 			options.ContentDisposition = DefaultOptions.ContentDisposition
 		}
+		if options.StatusOnInvalid == 0 {
+			options.StatusOnInvalid = DefaultOptions.StatusOnInvalid
+		}
+		if options.MaxBulkSize == 0 {
+			options.MaxBulkSize = DefaultOptions.MaxBulkSize
+		}
+		if options.MaxBulkContacts == 0 {
+			options.MaxBulkContacts = DefaultOptions.MaxBulkContacts
+		}
+		if options.Version == nil {
+			options.Version = DefaultOptions.Version
+		}
 	}
 
+	return options
+}
+
+// bulkLimits returns the upload limits of the Bulk options
+func bulkLimits(options Options) server.UploadLimits {
+	return server.UploadLimits{MaxBodySize: options.MaxBulkSize, MaxCards: options.MaxBulkContacts}
+}
+
+// VCard middleware for Echo that generates vCard responses
+func VCard(handler VCardHandler, opts ...Options) echo.HandlerFunc {
+	options := resolveOptions(opts)
+
 	return func(c echo.Context) error {
+		start := time.Now()
+
 		// Generate vCard
 		card := handler(c)
 		if card == nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate vCard")
 		}
 
+		// Serve the requested version without mutating the handler's card
+		if requested := options.Version(c); requested != "" {
+			version, err := vcard.ParseVersion(requested)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Unsupported vCard version")
+			}
+			card = card.Clone().SetVersion(version)
+		}
+
+		// Enrich the card with request metadata
+		if props := requestProperties(options, c); len(props) > 0 {
+			card = card.Clone().AddCustomProperties(props)
+		}
+
+		// Strip fields the requester may not see, keeping the full card for
+		// webhooks
+		full := card
+		card = restrict(options, c, card)
+
+		// Validate vCard
+		if err := card.Validate(); err != nil {
+			return echo.NewHTTPError(options.StatusOnInvalid, "Invalid vCard: "+err.Error())
+		}
+
 		// Generate vCard content
-		content, err := card.String()
+		content, err := card.StringContext(vcard.ContextWithTracer(c.Request().Context(), options.Tracer))
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate vCard content")
 		}
+		if options.Signer != nil {
+			signature, err := jws.Detached(options.Signer, []byte(content))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to sign vCard content")
+			}
+			c.Response().Header().Set(jws.Header, signature)
+		}
 
 		// Set headers
-		filename := options.Filename(c)
-		c.Response().Header().Set("Content-Type", "text/vcard")
-		c.Response().Header().Set("Content-Disposition", options.ContentDisposition+"; filename="+filename)
+		var filename string
+		if options.Filename != nil {
+			filename = options.Filename(c)
+		} else {
+			filename = card.Filename(options.FilenameTemplate)
+		}
+		c.Response().Header().Set("Content-Type", vcard.MIMEType)
+		c.Response().Header().Set("Content-Disposition", mime.FormatMediaType(options.ContentDisposition, map[string]string{"filename": filename}))
+		for key, value := range options.ExtraHeaders {
+			c.Response().Header().Set(key, value)
+		}
+
+		if options.Webhook != nil {
+			notify(options, webhook.EventGenerated, full)
+		}
+
+		if options.Logger != nil {
+			options.Logger.Info("vcard generated",
+				slog.String("client", c.RealIP()),
+				slog.String("path", c.Request().URL.Path),
+				slog.Int("bytes", len(content)),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("card", card.LogWithKey(options.LogKey)),
+			)
+		}
 
 		return c.String(http.StatusOK, content)
 	}
 }
 
-// VCardJSON middleware for Echo that returns vCard data as JSON
-func VCardJSON(handler VCardHandler) echo.HandlerFunc {
+// restrict applies the Visibility and Redaction options to a served card
+func restrict(options Options, c echo.Context, card *vcard.VCard) *vcard.VCard {
+	if options.Visibility != nil {
+		card = card.Masked(options.Visibility(c))
+	}
+	if options.Redaction != nil {
+		card = card.Redacted(*options.Redaction)
+	}
+	return card
+}
+
+// requestProperties collects custom properties from the mapped request headers
+// and the Properties hook
+func requestProperties(options Options, c echo.Context) map[string]string {
+	props := make(map[string]string)
+	for header, property := range options.HeaderProperties {
+		if value := c.Request().Header.Get(header); value != "" {
+			props[property] = value
+		}
+	}
+
+	if options.Properties != nil {
+		for name, value := range options.Properties(c) {
+			props[name] = value
+		}
+	}
+
+	return props
+}
+
+// notify sends a webhook event for the card, logging cards that can't be
+// generated instead
+func notify(options Options, eventType string, card *vcard.VCard) {
+	event, err := webhook.NewEvent(eventType, card)
+	if err != nil {
+		if options.Logger != nil {
+			options.Logger.Warn("vcard webhook event failed", "type", eventType, "error", err)
+		}
+		return
+	}
+	options.Webhook.Notify(event)
+}
+
+// ShareLink serves the card addressed by a signed share-link token passed in
+// the "token" query parameter. Invalid tokens are rejected with 403, expired
+// tokens with 410 and unknown UIDs with 404.
+func ShareLink(signer *sharelink.Signer, lookup func(uid string) *vcard.VCard, opts ...Options) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		uid, err := signer.Verify(c.QueryParam(sharelink.QueryParam))
+		if errors.Is(err, sharelink.ErrExpired) {
+			return echo.NewHTTPError(http.StatusGone, "Share link expired")
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusForbidden, "Invalid share link")
+		}
+
+		card := lookup(uid)
+		if card == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Contact not found")
+		}
+
+		return VCard(func(c echo.Context) *vcard.VCard {
+			return card
+		}, opts...)(c)
+	}
+}
+
+// Bulk handles requests carrying a JSON array of contacts and responds with a
+// single .vcf file containing all cards. Contacts that fail validation are
+// reported by index with the StatusOnInvalid status, and requests over
+// MaxBulkSize or MaxBulkContacts with 413. Valid cards are passed through the
+// Enricher, when set, and limited by Visibility and Redaction before they are
+// written. The file is named by the
+// Filename option when set, else by FilenameTemplate when every card gives
+// the same name (see vcard.AddressBook.Filename), else DefaultBulkFilename.
+func Bulk(opts ...Options) echo.HandlerFunc {
+	options := resolveOptions(opts)
+
+	return func(c echo.Context) error {
+		req := c.Request()
+		if options.MaxBulkSize > 0 {
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, options.MaxBulkSize)
+		}
+		contacts, err := server.DecodeContacts(req.Body, bulkLimits(options))
+		if err != nil {
+			if status := server.UploadStatus(err); status == http.StatusRequestEntityTooLarge {
+				return echo.NewHTTPError(status, "Request too large")
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON: expected an array of contacts")
+		}
+
+		var version vcard.Version
+		if requested := options.Version(c); requested != "" {
+			parsed, err := vcard.ParseVersion(requested)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Unsupported vCard version")
+			}
+			version = parsed
+		}
+
+		book, errs := vcard.NewAddressBookFromContacts(contacts, version)
+		if len(errs) > 0 {
+			return c.JSON(options.StatusOnInvalid, map[string]interface{}{
+				"errors": errs,
+			})
+		}
+
+		if options.Enricher != nil {
+			for _, failure := range book.Enrich(c.Request().Context(), options.Enricher) {
+				if options.Logger != nil {
+					options.Logger.Warn("vcard enrichment failed", "index", failure.Index, "error", failure.Message)
+				}
+			}
+		}
+
+		served := book
+		if options.Visibility != nil {
+			served = served.Masked(options.Visibility(c))
+		}
+		if options.Redaction != nil {
+			served = served.Redacted(*options.Redaction)
+		}
+		content, err := served.StringContext(vcard.ContextWithTracer(c.Request().Context(), options.Tracer))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate vCard content")
+		}
+		if options.Signer != nil {
+			signature, err := jws.Detached(options.Signer, []byte(content))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to sign vCard content")
+			}
+			c.Response().Header().Set(jws.Header, signature)
+		}
+
+		if options.Webhook != nil {
+			for _, card := range book.Cards() {
+				notify(options, webhook.EventImported, card)
+			}
+		}
+
+		filename := served.Filename(options.FilenameTemplate)
+		if len(opts) > 0 && opts[0].Filename != nil {
+			filename = options.Filename(c)
+		}
+		c.Response().Header().Set("Content-Type", vcard.MIMEType)
+		c.Response().Header().Set("Content-Disposition", mime.FormatMediaType(options.ContentDisposition, map[string]string{"filename": filename}))
+		for key, value := range options.ExtraHeaders {
+			c.Response().Header().Set(key, value)
+		}
+
+		return c.String(http.StatusOK, content)
+	}
+}
+
+// VCardJSON middleware for Echo that returns vCard data as JSON, optionally
+// with the QR code and data URI selected by the QR and DataURI options. The
+// requested version, request properties, Visibility and Redaction are applied
+// as by VCard.
+func VCardJSON(handler VCardHandler, opts ...Options) echo.HandlerFunc {
+	options := resolveOptions(opts)
+
 	return func(c echo.Context) error {
 		// Generate vCard
 		card := handler(c)
@@ -73,6 +407,18 @@ func VCardJSON(handler VCardHandler) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate vCard")
 		}
 
+		if requested := options.Version(c); requested != "" {
+			version, err := vcard.ParseVersion(requested)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Unsupported vCard version")
+			}
+			card = card.Clone().SetVersion(version)
+		}
+		if props := requestProperties(options, c); len(props) > 0 {
+			card = card.Clone().AddCustomProperties(props)
+		}
+		card = restrict(options, c, card)
+
 		// Convert to JSON-friendly structure
 		response := map[string]interface{}{
 			"name":         card.GetName(),
@@ -85,76 +431,81 @@ func VCardJSON(handler VCardHandler) echo.HandlerFunc {
 			"note":         card.GetNote(),
 		}
 
+		if err := addShareData(response, card, options); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate vCard: "+err.Error())
+		}
+
 		return c.JSON(http.StatusOK, response)
 	}
 }
 
-// CreateFromParams creates a vCard from Echo context parameters and query values
-func CreateFromParams(c echo.Context) *vcard.VCard {
-	card := vcard.New()
-
-	// Name from path parameters or query parameters
-	if firstName := c.QueryParam("firstName"); firstName == "" {
-		firstName = c.Param("firstName")
-		if firstName != "" {
-			lastName := c.QueryParam("lastName")
-			if lastName == "" {
-				lastName = c.Param("lastName")
-			}
-			card.AddName(firstName, lastName)
+// addShareData adds the QR code and data URI selected by the options to a
+// VCardJSON response
+func addShareData(response map[string]interface{}, card *vcard.VCard, options Options) error {
+	if options.QR != nil {
+		qr, err := card.QRCode(options.QR)
+		if err != nil {
+			return err
 		}
-	} else {
-		lastName := c.QueryParam("lastName")
-		card.AddName(firstName, lastName)
+		response["qrCode"] = qr
 	}
 
-	// Email
-	if email := c.QueryParam("email"); email != "" {
-		emailType := c.QueryParam("emailType")
-		switch emailType {
-		case "home":
-			card.AddEmail(email, vcard.EmailHome)
-		case "mobile":
-			card.AddEmail(email, vcard.EmailMobile)
-		default:
-			card.AddEmail(email, vcard.EmailWork)
+	if options.DataURI {
+		uri, err := card.DataURI()
+		if err != nil {
+			return err
 		}
+		response["dataUri"] = uri
 	}
 
-	// Phone
-	if phone := c.QueryParam("phone"); phone != "" {
-		phoneType := c.QueryParam("phoneType")
-		switch phoneType {
-		case "home":
-			card.AddPhone(phone, vcard.PhoneHome)
-		case "mobile", "cell":
-			card.AddPhone(phone, vcard.PhoneMobile)
-		case "fax":
-			card.AddPhone(phone, vcard.PhoneFax)
-		default:
-			card.AddPhone(phone, vcard.PhoneWork)
-		}
-	}
+	return nil
+}
 
-	// Organization
-	if org := c.QueryParam("organization"); org != "" {
-		card.AddOrganization(org)
+// Info returns a handler that serves the library metadata as JSON
+func Info() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, vcard.GetInfo())
 	}
+}
+
+// Photo serves the photo of the card returned by handler through the proxy,
+// scaled to the "size" query parameter when given. Cards without a photo,
+// including photos hidden by Visibility or Redaction, are answered with 404,
+// failed downloads with 502 or 504.
+func Photo(proxy *photoproxy.Proxy, handler VCardHandler, opts ...Options) echo.HandlerFunc {
+	options := resolveOptions(opts)
 
-	// Title
-	if title := c.QueryParam("title"); title != "" {
-		card.AddTitle(title)
+	return func(c echo.Context) error {
+		card := handler(c)
+		if card != nil {
+			card = restrict(options, c, card)
+		}
+		photo, err := proxy.Photo(c.Request().Context(), card, proxy.RequestedSize(c.QueryParam(photoproxy.SizeParam)))
+		if err != nil {
+			status := photoproxy.StatusCode(err)
+			return echo.NewHTTPError(status, http.StatusText(status))
+		}
+
+		for name, value := range proxy.Headers(photo) {
+			c.Response().Header().Set(name, value)
+		}
+		return c.Blob(http.StatusOK, photo.MediaType, photo.Data)
 	}
+}
 
-	// URL
-	if url := c.QueryParam("url"); url != "" {
-		card.AddURL(url, vcard.URLWork)
+// CreateFromParams creates a vCard from the request parameters using the
+// schema documented at vcard.FromParams. Query and form values take
+// precedence over Echo path parameters.
+func CreateFromParams(c echo.Context) *vcard.VCard {
+	path := make(map[string]string)
+	for i, name := range c.ParamNames() {
+		path[name] = c.ParamValues()[i]
 	}
 
-	// Note
-	if note := c.QueryParam("note"); note != "" {
-		card.AddNote(note)
+	var form url.Values
+	if c.Request().ParseForm() == nil {
+		form = c.Request().PostForm
 	}
 
-	return card
+	return vcard.FromParams(vcard.ParamValues(path, c.QueryParams(), form))
 }
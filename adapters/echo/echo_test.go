@@ -1,13 +1,24 @@
 package echo
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/jws"
+	"go.rumenx.com/vcard/photoproxy"
+	"go.rumenx.com/vcard/sharelink"
+	"go.rumenx.com/vcard/webhook"
 )
 
 func TestVCard(t *testing.T) {
@@ -216,6 +227,30 @@ func TestVCardWithCustomOptions(t *testing.T) {
 	}
 }
 
+func TestVCardQuotedFilename(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+	options := Options{
+		Filename: func(c echo.Context) string {
+			return `Jane "JS" Smith.vcf`
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if err := VCard(handler, options)(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	disposition, params, err := mime.ParseMediaType(rec.Header().Get("Content-Disposition"))
+	if err != nil || disposition != "attachment" || params["filename"] != `Jane "JS" Smith.vcf` {
+		t.Errorf("Expected a quoted filename, got %q", rec.Header().Get("Content-Disposition"))
+	}
+}
+
 func TestVCardNilHandler(t *testing.T) {
 	// Create a test handler that returns nil
 	handler := func(c echo.Context) *vcard.VCard {
@@ -246,3 +281,602 @@ func TestVCardNilHandler(t *testing.T) {
 		t.Errorf("Expected status 500, got %d", echoErr.Code)
 	}
 }
+
+func TestVCardVersionQueryParam(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?version=4.0", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := VCard(handler)(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), "VERSION:4.0") {
+		t.Errorf("Expected VERSION:4.0 in body, got %s", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?version=9.9", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+
+	err := VCard(handler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok || httpErr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 HTTPError, got %v", err)
+	}
+}
+
+func TestVCardTemplateOptions(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := VCard(handler, Options{
+		FilenameTemplate: "{first}-{last}",
+		ExtraHeaders:     map[string]string{"Cache-Control": "no-store"},
+	})(c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), "Jane-Smith.vcf") {
+		t.Errorf("Expected templated filename, got %s", rec.Header().Get("Content-Disposition"))
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected extra header, got %s", rec.Header().Get("Cache-Control"))
+	}
+}
+
+func TestVCardStatusOnInvalid(t *testing.T) {
+	// Handler that returns a card without a name
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New()
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := VCard(handler, Options{StatusOnInvalid: http.StatusUnprocessableEntity})(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok || httpErr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 HTTPError, got %v", err)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/info", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := Info()(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), vcard.LibraryVersion) {
+		t.Errorf("Expected library version in body, got %s", rec.Body.String())
+	}
+}
+
+func TestPhoto(t *testing.T) {
+	handler := Photo(photoproxy.New(), func(c echo.Context) *vcard.VCard {
+		if c.QueryParam("id") != "jane" {
+			return nil
+		}
+		return vcard.New().AddName("Jane", "Doe").AddPhoto("data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+	})
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	if err := handler(e.NewContext(httptest.NewRequest(http.MethodGet, "/photo?id=jane&size=32", nil), rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rec.Header().Get("Content-Type") != "image/png" || rec.Header().Get("Cache-Control") == "" {
+		t.Errorf("Unexpected headers: %v", rec.Header())
+	}
+
+	err := handler(e.NewContext(httptest.NewRequest(http.MethodGet, "/photo?id=john", nil), httptest.NewRecorder()))
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 error, got %v", err)
+	}
+}
+
+func TestVCardLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := func(c echo.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddEmail("john@example.com")
+		return card
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := VCard(handler, Options{Logger: logger, LogKey: []byte("log-key")})(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "vcard generated") {
+		t.Errorf("Expected generation event to be logged, got %s", output)
+	}
+	if strings.Contains(output, "john@example.com") {
+		t.Errorf("Expected email to be scrubbed from log, got %s", output)
+	}
+	if !strings.Contains(output, vcard.HashPII([]byte("log-key"), "john@example.com")) {
+		t.Errorf("Expected the keyed email digest in log, got %s", output)
+	}
+}
+
+func TestShareLink(t *testing.T) {
+	signer, err := sharelink.New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(uid string) *vcard.VCard {
+		if uid != "john" {
+			return nil
+		}
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	e := echo.New()
+
+	tests := []struct {
+		token    string
+		expected int
+	}{
+		{signer.Token("john", time.Hour), http.StatusOK},
+		{signer.Token("john", -time.Hour), http.StatusGone},
+		{signer.Token("jane", time.Hour), http.StatusNotFound},
+		{"forged", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/share?token="+tt.token, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		code := http.StatusOK
+		if err := ShareLink(signer, lookup)(c); err != nil {
+			code = err.(*echo.HTTPError).Code
+		}
+
+		if code != tt.expected {
+			t.Errorf("Expected status %d, got %d", tt.expected, code)
+		}
+	}
+}
+
+func TestVCardRequestProperties(t *testing.T) {
+	original := vcard.New()
+	original.AddName("John", "Doe")
+
+	handler := func(c echo.Context) *vcard.VCard {
+		return original
+	}
+
+	options := Options{
+		HeaderProperties: map[string]string{"X-Employee-Id": "X-EMPLOYEE-ID"},
+		Properties: func(c echo.Context) map[string]string {
+			return map[string]string{"X-COST-CENTER": "CC-7"}
+		},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Employee-Id", "E42")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := VCard(handler, options)(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body := rec.Body.String()
+
+	if !strings.Contains(body, "X-EMPLOYEE-ID:E42") || !strings.Contains(body, "X-COST-CENTER:CC-7") {
+		t.Errorf("Expected request properties in body, got %s", body)
+	}
+	if len(original.GetCustomProperties()) != 0 {
+		t.Error("Handler card should not be modified")
+	}
+}
+
+func TestVCardVisibility(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddPhone("+1111", vcard.PhoneWork)
+		card.AddPhone("+2222", vcard.PhoneHome)
+		return card
+	}
+
+	options := Options{
+		Visibility: func(c echo.Context) vcard.FieldMask {
+			if c.Request().Header.Get("Authorization") == "" {
+				return vcard.PublicFields
+			}
+			return vcard.FieldAll
+		},
+	}
+
+	e := echo.New()
+	serve := func(auth string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := VCard(handler, options)(c); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return rec.Body.String()
+	}
+
+	if body := serve(""); strings.Contains(body, "+2222") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected home phone to be hidden from anonymous requests, got %s", body)
+	}
+	if body := serve("Bearer token"); !strings.Contains(body, "+2222") {
+		t.Errorf("Expected full card for authenticated requests, got %s", body)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).AddNote("Internal note")
+	}
+	options := Options{Redaction: &vcard.RedactionPublicWeb}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	if err := VCard(handler, options)(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "Internal note") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected the note to be redacted, got %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`))
+	if err := Bulk(options)(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body := rec.Body.String(); rec.Code != http.StatusOK || strings.Contains(body, "Internal note") {
+		t.Errorf("Expected the bulk note to be redacted, got %d %s", rec.Code, body)
+	}
+}
+
+func TestVisibilityAllHandlers(t *testing.T) {
+	photo := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).
+			AddPhone("+2222", vcard.PhoneHome).AddNote("Internal note").AddPhoto(photo)
+	}
+	options := Options{
+		Visibility: func(c echo.Context) vcard.FieldMask {
+			return vcard.PublicFields &^ vcard.FieldPhoto
+		},
+		Redaction: &vcard.RedactionPublicWeb,
+		DataURI:   true,
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	if err := VCardJSON(handler, options)(e.NewContext(httptest.NewRequest(http.MethodGet, "/json", nil), rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body := rec.Body.String(); rec.Code != http.StatusOK || strings.Contains(body, "+2222") ||
+		strings.Contains(body, "Internal note") || strings.Contains(body, "iVBOR") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected VCardJSON to apply Visibility and Redaction, got %d %s", rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(
+		`[{"Name": {"First": "Jane", "Last": "Doe"}, "Phones": [{"Number": "+2222", "Type": "HOME"}]}]`))
+	if err := Bulk(options)(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body := rec.Body.String(); rec.Code != http.StatusOK || strings.Contains(body, "+2222") {
+		t.Errorf("Expected Bulk to apply Visibility, got %d %s", rec.Code, body)
+	}
+
+	err := Photo(photoproxy.New(), handler, options)(e.NewContext(httptest.NewRequest(http.MethodGet, "/photo", nil), httptest.NewRecorder()))
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusNotFound {
+		t.Errorf("Expected a hidden photo to be answered with 404, got %v", err)
+	}
+}
+
+func TestRedactionWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 2)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer receiver.Close()
+
+	notifier := webhook.New(receiver.URL)
+	options := Options{Redaction: &vcard.RedactionPublicWeb, Webhook: notifier}
+	bulk := `[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddNote("Internal note")
+	}
+
+	e := echo.New()
+	if err := VCard(handler, options)(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := Bulk(options)(e.NewContext(httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(bulk)), httptest.NewRecorder())); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	notifier.Close()
+
+	for _, eventType := range []string{webhook.EventGenerated, webhook.EventImported} {
+		select {
+		case event := <-events:
+			if event.Type != eventType || !strings.Contains(event.Card, "Internal note") {
+				t.Errorf("Expected a full %s card, got %+v", eventType, event)
+			}
+		default:
+			t.Errorf("Expected a %s event", eventType)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	secret := []byte("directory-secret-0123456789abcdef")
+	signer, err := jws.HMAC(secret, "directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := Options{Signer: signer}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	if err := VCard(handler, options)(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := jws.Verify(rec.Header().Get(jws.Header), rec.Body.Bytes(), secret); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`))
+	if err := Bulk(options)(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := jws.Verify(rec.Header().Get(jws.Header), rec.Body.Bytes(), secret); err != nil {
+		t.Errorf("Expected a valid bulk signature, got %v", err)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	var spans spanNames
+	options := Options{Tracer: &spans}
+
+	e := echo.New()
+	if err := VCard(handler, options)(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`))
+	if err := Bulk(options)(e.NewContext(req, httptest.NewRecorder())); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(spans) != 2 || spans[0] != vcard.SpanGenerate || spans[1] != vcard.SpanBulk {
+		t.Errorf("Expected generate and bulk spans, got %v", spans)
+	}
+}
+
+// spanNames is a vcard.Tracer recording the names of the spans it starts
+type spanNames []string
+
+func (s *spanNames) Start(ctx context.Context, name string) (context.Context, vcard.Span) {
+	*s = append(*s, name)
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+func TestBulk(t *testing.T) {
+	e := echo.New()
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := Bulk()(c); err != nil {
+			return err.(*echo.HTTPError).Code, ""
+		}
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := post(`[{"Name": {"First": "John", "Last": "Doe"}}, {"Name": {"First": "Jane"}}]`)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if strings.Count(body, "BEGIN:VCARD") != 2 {
+		t.Errorf("Expected 2 cards, got %s", body)
+	}
+
+	code, body = post(`[{"Name": {"First": "John"}}, {"Note": "no name"}]`)
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid item, got %d", code)
+	}
+	if !strings.Contains(body, `"index":1`) {
+		t.Errorf("Expected error for index 1, got %s", body)
+	}
+
+	if code, _ = post(`{"not": "an array"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for malformed JSON, got %d", code)
+	}
+}
+
+func TestBulkLimits(t *testing.T) {
+	e := echo.New()
+	post := func(body string) (int, string) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(body)), rec)
+
+		if err := Bulk(Options{MaxBulkSize: 300, MaxBulkContacts: 2, FilenameTemplate: "{org}"})(c); err != nil {
+			return err.(*echo.HTTPError).Code, ""
+		}
+		return rec.Code, rec.Header().Get("Content-Disposition")
+	}
+
+	same := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Acme"}}]`
+	if code, disposition := post(same); code != http.StatusOK || !strings.Contains(disposition, "Acme.vcf") {
+		t.Errorf("Expected the template filename, got %d %q", code, disposition)
+	}
+	mixed := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Globex"}}]`
+	if _, disposition := post(mixed); !strings.Contains(disposition, vcard.DefaultBulkFilename) {
+		t.Errorf("Expected the default bulk filename, got %q", disposition)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}}, {"Name": {"First": "B"}}, {"Name": {"First": "C"}}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for too many contacts, got %d", code)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}, "Note": "` + strings.Repeat("x", 512) + `"}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for a large body, got %d", code)
+	}
+}
+
+func TestBulkEnricher(t *testing.T) {
+	var logs bytes.Buffer
+	options := Options{
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		Enricher: vcard.Enrichers(vcard.DomainEnricher{}, vcard.EnricherFunc(func(ctx context.Context, card *vcard.VCard) error {
+			if card.GetName().First == "Fail" {
+				return errors.New("lookup failed")
+			}
+			return nil
+		})),
+	}
+	payload := `[{"Name": {"First": "Jane"}, "Emails": [{"Address": "jane@acme.com"}]}, {"Name": {"First": "Fail"}}]`
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(payload))
+	rec := httptest.NewRecorder()
+	if err := Bulk(options)(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("Bulk returned error: %v", err)
+	}
+	code, body := rec.Code, rec.Body.String()
+
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if !strings.Contains(body, "ORG:Acme") || !strings.Contains(body, "URL;TYPE=WORK:https://acme.com") {
+		t.Errorf("Expected enriched card, got %s", body)
+	}
+	if !strings.Contains(logs.String(), "vcard enrichment failed") {
+		t.Errorf("Expected enrichment failure to be logged, got %q", logs.String())
+	}
+}
+
+func TestVCardWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer server.Close()
+
+	notifier := webhook.New(server.URL)
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	if err := VCard(handler, Options{Webhook: notifier})(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	notifier.Close()
+
+	select {
+	case event := <-events:
+		if event.Type != webhook.EventGenerated || event.Name != "John Doe" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Error("Expected a webhook event")
+	}
+}
+
+func TestCreateFromParamsAddress(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?firstName=Jane&lastName=Doe&street=1+Main+St&city=Sofia&postalCode=1000&country=Bulgaria&addressType=home", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	content, err := CreateFromParams(c).String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard content: %v", err)
+	}
+	if !strings.Contains(content, "ADR;TYPE=HOME:;;1 Main St;Sofia;;1000;Bulgaria") {
+		t.Errorf("Expected address in vCard:\n%s", content)
+	}
+}
+
+func TestVCardJSONShareData(t *testing.T) {
+	handler := func(c echo.Context) *vcard.VCard {
+		return vcard.New().AddName("Jane", "Smith")
+	}
+	qr := func(content string) ([]byte, error) {
+		return []byte("png"), nil
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	if err := VCardJSON(handler, Options{QR: qr, DataURI: true})(c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response["qrCode"] != "data:image/png;base64,cG5n" {
+		t.Errorf("Unexpected qrCode: %v", response["qrCode"])
+	}
+	if uri, _ := response["dataUri"].(string); !strings.HasPrefix(uri, "data:text/vcard;charset=utf-8;base64,") {
+		t.Errorf("Unexpected dataUri: %v", response["dataUri"])
+	}
+	if _, ok := response["name"]; !ok {
+		t.Error("Expected the structured data alongside the share data")
+	}
+}
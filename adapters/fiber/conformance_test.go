@@ -0,0 +1,42 @@
+package fiber
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/vcardtest/adaptertest"
+)
+
+func TestConformance(t *testing.T) {
+	adaptertest.Run(t, adaptertest.Adapter{
+		VCard: func(build adaptertest.CardFunc, options adaptertest.Options) http.Handler {
+			app := fiber.New()
+			app.Get("/", VCard(func(c *fiber.Ctx) *vcard.VCard {
+				r, err := adaptor.ConvertRequest(c, false)
+				if err != nil {
+					return nil
+				}
+				return build(r)
+			}, conformanceOptions(options)))
+			return adaptor.FiberApp(app)
+		},
+		Bulk: func(options adaptertest.Options) http.Handler {
+			app := fiber.New()
+			app.Post("/", Bulk(conformanceOptions(options)))
+			return adaptor.FiberApp(app)
+		},
+	})
+}
+
+// conformanceOptions maps the suite options onto the adapter options
+func conformanceOptions(options adaptertest.Options) Options {
+	return Options{
+		FilenameTemplate:   options.FilenameTemplate,
+		ContentDisposition: options.ContentDisposition,
+		ExtraHeaders:       options.ExtraHeaders,
+		StatusOnInvalid:    options.StatusOnInvalid,
+	}
+}
@@ -1,15 +1,25 @@
 package fiber
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	vcard "go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/jws"
+	"go.rumenx.com/vcard/photoproxy"
+	"go.rumenx.com/vcard/sharelink"
+	"go.rumenx.com/vcard/webhook"
 )
 
 func TestVCardMiddleware(t *testing.T) {
@@ -89,6 +99,31 @@ func TestVCardWithCustomOptions(t *testing.T) {
 	}
 }
 
+func TestVCardQuotedFilename(t *testing.T) {
+	app := fiber.New()
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+	options := Options{
+		Filename: func(c *fiber.Ctx) string {
+			return `Jane "JS" Smith.vcf`
+		},
+	}
+	app.Get("/test", VCard(handler, options))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	disposition, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition"))
+	if err != nil || disposition != "attachment" || params["filename"] != `Jane "JS" Smith.vcf` {
+		t.Errorf("Expected a quoted filename, got %q", resp.Header.Get("Content-Disposition"))
+	}
+}
+
 func TestVCardJSONMiddleware(t *testing.T) {
 	app := fiber.New()
 
@@ -220,3 +255,623 @@ func TestVCardErrorHandling(t *testing.T) {
 		t.Errorf("Expected status 500, got %d", resp.StatusCode)
 	}
 }
+
+func TestVCardVersionQueryParam(t *testing.T) {
+	app := fiber.New()
+
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	app.Get("/test", VCard(handler))
+
+	req := httptest.NewRequest("GET", "/test?version=4.0", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "VERSION:4.0") {
+		t.Errorf("Expected VERSION:4.0 in body, got %s", string(body))
+	}
+
+	req = httptest.NewRequest("GET", "/test?version=9.9", nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestVCardTemplateOptions(t *testing.T) {
+	app := fiber.New()
+
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+
+	app.Get("/test", VCard(handler, Options{
+		FilenameTemplate: "{first}-{last}",
+		ExtraHeaders:     map[string]string{"Cache-Control": "no-store"},
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if !strings.Contains(resp.Header.Get("Content-Disposition"), "Jane-Smith.vcf") {
+		t.Errorf("Expected templated filename, got %s", resp.Header.Get("Content-Disposition"))
+	}
+	if resp.Header.Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected extra header, got %s", resp.Header.Get("Cache-Control"))
+	}
+}
+
+func TestVCardStatusOnInvalid(t *testing.T) {
+	app := fiber.New()
+
+	// Handler that returns a card without a name
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New()
+	}
+
+	app.Get("/test", VCard(handler, Options{StatusOnInvalid: http.StatusUnprocessableEntity}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", resp.StatusCode)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	app := fiber.New()
+	app.Get("/info", Info())
+
+	req := httptest.NewRequest("GET", "/info", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	var info vcard.Info
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if info.Version != vcard.LibraryVersion {
+		t.Errorf("Expected version %s, got %s", vcard.LibraryVersion, info.Version)
+	}
+}
+
+func TestPhoto(t *testing.T) {
+	app := fiber.New()
+	app.Get("/contacts/:id/photo", Photo(photoproxy.New(), func(c *fiber.Ctx) *vcard.VCard {
+		if c.Params("id") != "jane" {
+			return nil
+		}
+		return vcard.New().AddName("Jane", "Doe").AddPhoto("data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+	}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/contacts/jane/photo?size=32", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "image/png" || resp.Header.Get("Cache-Control") == "" {
+		t.Errorf("Unexpected headers: %v", resp.Header)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/contacts/john/photo", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestVCardLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddEmail("john@example.com")
+		return card
+	}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, Options{Logger: logger, LogKey: []byte("log-key")}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "vcard generated") {
+		t.Errorf("Expected generation event to be logged, got %s", output)
+	}
+	if strings.Contains(output, "john@example.com") {
+		t.Errorf("Expected email to be scrubbed from log, got %s", output)
+	}
+	if !strings.Contains(output, vcard.HashPII([]byte("log-key"), "john@example.com")) {
+		t.Errorf("Expected the keyed email digest in log, got %s", output)
+	}
+}
+
+func TestShareLink(t *testing.T) {
+	signer, err := sharelink.New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(uid string) *vcard.VCard {
+		if uid != "john" {
+			return nil
+		}
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	app := fiber.New()
+	app.Get("/share", ShareLink(signer, lookup))
+
+	tests := []struct {
+		token    string
+		expected int
+	}{
+		{signer.Token("john", time.Hour), http.StatusOK},
+		{signer.Token("john", -time.Hour), http.StatusGone},
+		{signer.Token("jane", time.Hour), http.StatusNotFound},
+		{"forged", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/share?token="+tt.token, nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+
+		if resp.StatusCode != tt.expected {
+			t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+		}
+	}
+}
+
+func TestVCardRequestProperties(t *testing.T) {
+	original := vcard.New()
+	original.AddName("John", "Doe")
+
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return original
+	}
+
+	options := Options{
+		HeaderProperties: map[string]string{"X-Employee-Id": "X-EMPLOYEE-ID"},
+		Properties: func(c *fiber.Ctx) map[string]string {
+			return map[string]string{"X-COST-CENTER": "CC-7"}
+		},
+	}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, options))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Employee-Id", "E42")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	body := string(data)
+
+	if !strings.Contains(body, "X-EMPLOYEE-ID:E42") || !strings.Contains(body, "X-COST-CENTER:CC-7") {
+		t.Errorf("Expected request properties in body, got %s", body)
+	}
+	if len(original.GetCustomProperties()) != 0 {
+		t.Error("Handler card should not be modified")
+	}
+}
+
+func TestVCardVisibility(t *testing.T) {
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddPhone("+1111", vcard.PhoneWork)
+		card.AddPhone("+2222", vcard.PhoneHome)
+		return card
+	}
+
+	options := Options{
+		Visibility: func(c *fiber.Ctx) vcard.FieldMask {
+			if c.Get("Authorization") == "" {
+				return vcard.PublicFields
+			}
+			return vcard.FieldAll
+		},
+	}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, options))
+
+	serve := func(auth string) string {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	if body := serve(""); strings.Contains(body, "+2222") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected home phone to be hidden from anonymous requests, got %s", body)
+	}
+	if body := serve("Bearer token"); !strings.Contains(body, "+2222") {
+		t.Errorf("Expected full card for authenticated requests, got %s", body)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).AddNote("Internal note")
+	}
+	options := Options{Redaction: &vcard.RedactionPublicWeb}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, options))
+	app.Post("/bulk", Bulk(options))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/test", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if body := string(data); strings.Contains(body, "Internal note") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected the note to be redacted, got %s", body)
+	}
+
+	req := httptest.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ = io.ReadAll(resp.Body)
+	if body := string(data); resp.StatusCode != http.StatusOK || strings.Contains(body, "Internal note") {
+		t.Errorf("Expected the bulk note to be redacted, got %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestVisibilityAllHandlers(t *testing.T) {
+	photo := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).
+			AddPhone("+2222", vcard.PhoneHome).AddNote("Internal note").AddPhoto(photo)
+	}
+	options := Options{
+		Visibility: func(c *fiber.Ctx) vcard.FieldMask {
+			return vcard.PublicFields &^ vcard.FieldPhoto
+		},
+		Redaction: &vcard.RedactionPublicWeb,
+		DataURI:   true,
+	}
+
+	app := fiber.New()
+	app.Get("/json", VCardJSON(handler, options))
+	app.Post("/bulk", Bulk(options))
+	app.Get("/photo", Photo(photoproxy.New(), handler, options))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/json", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if body := string(data); resp.StatusCode != http.StatusOK || strings.Contains(body, "+2222") ||
+		strings.Contains(body, "Internal note") || strings.Contains(body, "iVBOR") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected VCardJSON to apply Visibility and Redaction, got %d %s", resp.StatusCode, body)
+	}
+
+	req := httptest.NewRequest("POST", "/bulk", strings.NewReader(
+		`[{"Name": {"First": "Jane", "Last": "Doe"}, "Phones": [{"Number": "+2222", "Type": "HOME"}]}]`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ = io.ReadAll(resp.Body)
+	if body := string(data); resp.StatusCode != http.StatusOK || strings.Contains(body, "+2222") {
+		t.Errorf("Expected Bulk to apply Visibility, got %d %s", resp.StatusCode, body)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/photo", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Expected a hidden photo to be answered with 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestRedactionWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 2)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer receiver.Close()
+
+	notifier := webhook.New(receiver.URL)
+	options := Options{Redaction: &vcard.RedactionPublicWeb, Webhook: notifier}
+	bulk := `[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddNote("Internal note")
+	}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, options))
+	app.Post("/bulk", Bulk(options))
+	if _, err := app.Test(httptest.NewRequest("GET", "/test", nil)); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if _, err := app.Test(httptest.NewRequest("POST", "/bulk", strings.NewReader(bulk))); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	notifier.Close()
+
+	for _, eventType := range []string{webhook.EventGenerated, webhook.EventImported} {
+		select {
+		case event := <-events:
+			if event.Type != eventType || !strings.Contains(event.Card, "Internal note") {
+				t.Errorf("Expected a full %s card, got %+v", eventType, event)
+			}
+		default:
+			t.Errorf("Expected a %s event", eventType)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	secret := []byte("directory-secret-0123456789abcdef")
+	signer, err := jws.HMAC(secret, "directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := Options{Signer: signer}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, options))
+	app.Post("/bulk", Bulk(options))
+
+	bulk := httptest.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`))
+	bulk.Header.Set("Content-Type", "application/json")
+	for _, req := range []*http.Request{httptest.NewRequest("GET", "/test", nil), bulk} {
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if err := jws.Verify(resp.Header.Get(jws.Header), data, secret); err != nil {
+			t.Errorf("%s %s: expected a valid signature, got %v", req.Method, req.URL.Path, err)
+		}
+	}
+}
+
+func TestTracer(t *testing.T) {
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	var spans spanNames
+	options := Options{Tracer: &spans}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, options))
+	app.Post("/bulk", Bulk(options))
+
+	bulk := httptest.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`))
+	bulk.Header.Set("Content-Type", "application/json")
+	for _, req := range []*http.Request{httptest.NewRequest("GET", "/test", nil), bulk} {
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+	if len(spans) != 2 || spans[0] != vcard.SpanGenerate || spans[1] != vcard.SpanBulk {
+		t.Errorf("Expected generate and bulk spans, got %v", spans)
+	}
+}
+
+// spanNames is a vcard.Tracer recording the names of the spans it starts
+type spanNames []string
+
+func (s *spanNames) Start(ctx context.Context, name string) (context.Context, vcard.Span) {
+	*s = append(*s, name)
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+func TestBulk(t *testing.T) {
+	app := fiber.New()
+	app.Post("/bulk", Bulk())
+
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest("POST", "/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	code, body := post(`[{"Name": {"First": "John", "Last": "Doe"}}, {"Name": {"First": "Jane"}}]`)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if strings.Count(body, "BEGIN:VCARD") != 2 {
+		t.Errorf("Expected 2 cards, got %s", body)
+	}
+
+	code, body = post(`[{"Name": {"First": "John"}}, {"Note": "no name"}]`)
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid item, got %d", code)
+	}
+	if !strings.Contains(body, `"index":1`) {
+		t.Errorf("Expected error for index 1, got %s", body)
+	}
+
+	if code, _ = post(`{"not": "an array"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for malformed JSON, got %d", code)
+	}
+}
+
+func TestBulkLimits(t *testing.T) {
+	app := fiber.New()
+	app.Post("/bulk", Bulk(Options{MaxBulkSize: 300, MaxBulkContacts: 2, FilenameTemplate: "{org}"}))
+
+	post := func(body string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest("POST", "/bulk", strings.NewReader(body)))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Disposition")
+	}
+
+	same := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Acme"}}]`
+	if code, disposition := post(same); code != http.StatusOK || !strings.Contains(disposition, "Acme.vcf") {
+		t.Errorf("Expected the template filename, got %d %q", code, disposition)
+	}
+	mixed := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Globex"}}]`
+	if _, disposition := post(mixed); !strings.Contains(disposition, vcard.DefaultBulkFilename) {
+		t.Errorf("Expected the default bulk filename, got %q", disposition)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}}, {"Name": {"First": "B"}}, {"Name": {"First": "C"}}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for too many contacts, got %d", code)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}, "Note": "` + strings.Repeat("x", 512) + `"}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for a large body, got %d", code)
+	}
+}
+
+func TestBulkEnricher(t *testing.T) {
+	var logs bytes.Buffer
+	options := Options{
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		Enricher: vcard.Enrichers(vcard.DomainEnricher{}, vcard.EnricherFunc(func(ctx context.Context, card *vcard.VCard) error {
+			if card.GetName().First == "Fail" {
+				return errors.New("lookup failed")
+			}
+			return nil
+		})),
+	}
+	payload := `[{"Name": {"First": "Jane"}, "Emails": [{"Address": "jane@acme.com"}]}, {"Name": {"First": "Fail"}}]`
+
+	app := fiber.New()
+	app.Post("/bulk", Bulk(options))
+	req := httptest.NewRequest("POST", "/bulk", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	code, body := resp.StatusCode, string(data)
+
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if !strings.Contains(body, "ORG:Acme") || !strings.Contains(body, "URL;TYPE=WORK:https://acme.com") {
+		t.Errorf("Expected enriched card, got %s", body)
+	}
+	if !strings.Contains(logs.String(), "vcard enrichment failed") {
+		t.Errorf("Expected enrichment failure to be logged, got %q", logs.String())
+	}
+}
+
+func TestVCardWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer server.Close()
+
+	notifier := webhook.New(server.URL)
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+
+	app := fiber.New()
+	app.Get("/test", VCard(handler, Options{Webhook: notifier}))
+	if _, err := app.Test(httptest.NewRequest("GET", "/test", nil)); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	notifier.Close()
+
+	select {
+	case event := <-events:
+		if event.Type != webhook.EventGenerated || event.Name != "John Doe" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Error("Expected a webhook event")
+	}
+}
+
+func TestVCardJSONShareData(t *testing.T) {
+	app := fiber.New()
+	handler := func(c *fiber.Ctx) *vcard.VCard {
+		return vcard.New().AddName("Jane", "Smith")
+	}
+	qr := func(content string) ([]byte, error) {
+		return []byte("png"), nil
+	}
+	app.Get("/vcard", VCardJSON(handler, Options{QR: qr, DataURI: true}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/vcard", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	var response map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if response["qrCode"] != "data:image/png;base64,cG5n" {
+		t.Errorf("Unexpected qrCode: %v", response["qrCode"])
+	}
+	if uri, _ := response["dataUri"].(string); !strings.HasPrefix(uri, "data:text/vcard;charset=utf-8;base64,") {
+		t.Errorf("Unexpected dataUri: %v", response["dataUri"])
+	}
+}
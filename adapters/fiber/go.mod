@@ -19,6 +19,7 @@ require (
 	github.com/valyala/fasthttp v1.51.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
 )
 
 replace go.rumenx.com/vcard => ../../
@@ -0,0 +1,37 @@
+package gin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/vcardtest/adaptertest"
+)
+
+func TestConformance(t *testing.T) {
+	adaptertest.Run(t, adaptertest.Adapter{
+		VCard: func(build adaptertest.CardFunc, options adaptertest.Options) http.Handler {
+			router := gin.New()
+			router.GET("/", VCard(func(c *gin.Context) *vcard.VCard {
+				return build(c.Request)
+			}, conformanceOptions(options)))
+			return router
+		},
+		Bulk: func(options adaptertest.Options) http.Handler {
+			router := gin.New()
+			router.POST("/", Bulk(conformanceOptions(options)))
+			return router
+		},
+	})
+}
+
+// conformanceOptions maps the suite options onto the adapter options
+func conformanceOptions(options adaptertest.Options) Options {
+	return Options{
+		FilenameTemplate:   options.FilenameTemplate,
+		ContentDisposition: options.ContentDisposition,
+		ExtraHeaders:       options.ExtraHeaders,
+		StatusOnInvalid:    options.StatusOnInvalid,
+	}
+}
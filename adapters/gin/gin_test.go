@@ -1,13 +1,24 @@
 package gin
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/jws"
+	"go.rumenx.com/vcard/photoproxy"
+	"go.rumenx.com/vcard/sharelink"
+	"go.rumenx.com/vcard/webhook"
 )
 
 func TestMain(m *testing.M) {
@@ -78,3 +89,641 @@ func TestVCardNilHandler(t *testing.T) {
 		t.Errorf("Expected status 500, got %d", w.Code)
 	}
 }
+
+func TestVCardQuotedFilename(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+	options := Options{
+		Filename: func(c *gin.Context) string {
+			return `Jane "JS" Smith.vcf`
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	VCard(handler, options)(c)
+
+	disposition, params, err := mime.ParseMediaType(w.Header().Get("Content-Disposition"))
+	if err != nil || disposition != "attachment" || params["filename"] != `Jane "JS" Smith.vcf` {
+		t.Errorf("Expected a quoted filename, got %q", w.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestVCardVersionQueryParam(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/?version=4.0", nil)
+	c.Request = req
+
+	VCard(handler)(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "VERSION:4.0") {
+		t.Errorf("Expected VERSION:4.0 in body, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	req, _ = http.NewRequest("GET", "/?version=9.9", nil)
+	c.Request = req
+
+	VCard(handler)(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestVCardTemplateOptions(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("Jane", "Smith")
+		return card
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/", nil)
+	c.Request = req
+
+	VCard(handler, Options{
+		FilenameTemplate: "{first}-{last}",
+		ExtraHeaders:     map[string]string{"Cache-Control": "no-store"},
+	})(c)
+
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "Jane-Smith.vcf") {
+		t.Errorf("Expected templated filename, got %s", w.Header().Get("Content-Disposition"))
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected extra header, got %s", w.Header().Get("Cache-Control"))
+	}
+}
+
+func TestVCardStatusOnInvalid(t *testing.T) {
+	// Handler that returns a card without a name
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New()
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/", nil)
+	c.Request = req
+
+	VCard(handler, Options{StatusOnInvalid: http.StatusUnprocessableEntity})(c)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/info", nil)
+	c.Request = req
+
+	Info()(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), vcard.LibraryVersion) {
+		t.Errorf("Expected library version in body, got %s", w.Body.String())
+	}
+}
+
+func TestPhoto(t *testing.T) {
+	handler := Photo(photoproxy.New(), func(c *gin.Context) *vcard.VCard {
+		if c.Query("id") != "jane" {
+			return nil
+		}
+		return vcard.New().AddName("Jane", "Doe").AddPhoto("data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/photo?id=jane&size=32", nil)
+	handler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "image/png" || w.Header().Get("Cache-Control") == "" {
+		t.Errorf("Unexpected headers: %v", w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/photo?id=john", nil)
+	handler(c)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
+
+func TestVCardLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := func(c *gin.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddEmail("john@example.com")
+		return card
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/", nil)
+	c.Request = req
+
+	VCard(handler, Options{Logger: logger, LogKey: []byte("log-key")})(c)
+
+	output := buf.String()
+	if !strings.Contains(output, "vcard generated") {
+		t.Errorf("Expected generation event to be logged, got %s", output)
+	}
+	if strings.Contains(output, "john@example.com") {
+		t.Errorf("Expected email to be scrubbed from log, got %s", output)
+	}
+	if !strings.Contains(output, vcard.HashPII([]byte("log-key"), "john@example.com")) {
+		t.Errorf("Expected the keyed email digest in log, got %s", output)
+	}
+}
+
+func TestShareLink(t *testing.T) {
+	signer, err := sharelink.New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(uid string) *vcard.VCard {
+		if uid != "john" {
+			return nil
+		}
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		return card
+	}
+
+	tests := []struct {
+		token    string
+		expected int
+	}{
+		{signer.Token("john", time.Hour), http.StatusOK},
+		{signer.Token("john", -time.Hour), http.StatusGone},
+		{signer.Token("jane", time.Hour), http.StatusNotFound},
+		{"forged", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest("GET", "/share?token="+tt.token, nil)
+		c.Request = req
+
+		ShareLink(signer, lookup)(c)
+
+		if w.Code != tt.expected {
+			t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+		}
+	}
+}
+
+func TestVCardRequestProperties(t *testing.T) {
+	original := vcard.New()
+	original.AddName("John", "Doe")
+
+	handler := func(c *gin.Context) *vcard.VCard {
+		return original
+	}
+
+	options := Options{
+		HeaderProperties: map[string]string{"X-Employee-Id": "X-EMPLOYEE-ID"},
+		Properties: func(c *gin.Context) map[string]string {
+			return map[string]string{"X-COST-CENTER": "CC-7"}
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Employee-Id", "E42")
+	c.Request = req
+
+	VCard(handler, options)(c)
+	body := w.Body.String()
+
+	if !strings.Contains(body, "X-EMPLOYEE-ID:E42") || !strings.Contains(body, "X-COST-CENTER:CC-7") {
+		t.Errorf("Expected request properties in body, got %s", body)
+	}
+	if len(original.GetCustomProperties()) != 0 {
+		t.Error("Handler card should not be modified")
+	}
+}
+
+func TestVCardVisibility(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		card := vcard.New()
+		card.AddName("John", "Doe")
+		card.AddPhone("+1111", vcard.PhoneWork)
+		card.AddPhone("+2222", vcard.PhoneHome)
+		return card
+	}
+
+	options := Options{
+		Visibility: func(c *gin.Context) vcard.FieldMask {
+			if c.GetHeader("Authorization") == "" {
+				return vcard.PublicFields
+			}
+			return vcard.FieldAll
+		},
+	}
+
+	serve := func(auth string) string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest("GET", "/", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		c.Request = req
+
+		VCard(handler, options)(c)
+		return w.Body.String()
+	}
+
+	if body := serve(""); strings.Contains(body, "+2222") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected home phone to be hidden from anonymous requests, got %s", body)
+	}
+	if body := serve("Bearer token"); !strings.Contains(body, "+2222") {
+		t.Errorf("Expected full card for authenticated requests, got %s", body)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).AddNote("Internal note")
+	}
+	options := Options{Redaction: &vcard.RedactionPublicWeb}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	VCard(handler, options)(c)
+	if body := w.Body.String(); strings.Contains(body, "Internal note") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected the note to be redacted, got %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	Bulk(options)(c)
+	if body := w.Body.String(); w.Code != http.StatusOK || strings.Contains(body, "Internal note") {
+		t.Errorf("Expected the bulk note to be redacted, got %d %s", w.Code, body)
+	}
+}
+
+func TestVisibilityAllHandlers(t *testing.T) {
+	photo := "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddPhone("+1111", vcard.PhoneWork).
+			AddPhone("+2222", vcard.PhoneHome).AddNote("Internal note").AddPhoto(photo)
+	}
+	options := Options{
+		Visibility: func(c *gin.Context) vcard.FieldMask {
+			return vcard.PublicFields &^ vcard.FieldPhoto
+		},
+		Redaction: &vcard.RedactionPublicWeb,
+		DataURI:   true,
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/json", nil)
+	VCardJSON(handler, options)(c)
+	if body := w.Body.String(); w.Code != http.StatusOK || strings.Contains(body, "+2222") ||
+		strings.Contains(body, "Internal note") || strings.Contains(body, "iVBOR") || !strings.Contains(body, "+1111") {
+		t.Errorf("Expected VCardJSON to apply Visibility and Redaction, got %d %s", w.Code, body)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/bulk", strings.NewReader(
+		`[{"Name": {"First": "Jane", "Last": "Doe"}, "Phones": [{"Number": "+2222", "Type": "HOME"}]}]`))
+	Bulk(options)(c)
+	if body := w.Body.String(); w.Code != http.StatusOK || strings.Contains(body, "+2222") {
+		t.Errorf("Expected Bulk to apply Visibility, got %d %s", w.Code, body)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/photo", nil)
+	Photo(photoproxy.New(), handler, options)(c)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected a hidden photo to be answered with 404, got %d", w.Code)
+	}
+}
+
+func TestRedactionWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 2)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer receiver.Close()
+
+	notifier := webhook.New(receiver.URL)
+	options := Options{Redaction: &vcard.RedactionPublicWeb, Webhook: notifier}
+	bulk := `[{"Name": {"First": "Jane", "Last": "Doe"}, "Note": "Internal note"}]`
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe").AddNote("Internal note")
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	VCard(handler, options)(c)
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/bulk", strings.NewReader(bulk))
+	Bulk(options)(c)
+	notifier.Close()
+
+	for _, eventType := range []string{webhook.EventGenerated, webhook.EventImported} {
+		select {
+		case event := <-events:
+			if event.Type != eventType || !strings.Contains(event.Card, "Internal note") {
+				t.Errorf("Expected a full %s card, got %+v", eventType, event)
+			}
+		default:
+			t.Errorf("Expected a %s event", eventType)
+		}
+	}
+}
+
+func TestSigner(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	secret := []byte("directory-secret-0123456789abcdef")
+	signer, err := jws.HMAC(secret, "directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := Options{Signer: signer}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	VCard(handler, options)(c)
+	if err := jws.Verify(w.Header().Get(jws.Header), w.Body.Bytes(), secret); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	Bulk(options)(c)
+	if err := jws.Verify(w.Header().Get(jws.Header), w.Body.Bytes(), secret); err != nil {
+		t.Errorf("Expected a valid bulk signature, got %v", err)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+	var spans spanNames
+	options := Options{Tracer: &spans}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	VCard(handler, options)(c)
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/bulk", strings.NewReader(`[{"Name": {"First": "Jane", "Last": "Doe"}}]`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	Bulk(options)(c)
+	if len(spans) != 2 || spans[0] != vcard.SpanGenerate || spans[1] != vcard.SpanBulk {
+		t.Errorf("Expected generate and bulk spans, got %v", spans)
+	}
+}
+
+// spanNames is a vcard.Tracer recording the names of the spans it starts
+type spanNames []string
+
+func (s *spanNames) Start(ctx context.Context, name string) (context.Context, vcard.Span) {
+	*s = append(*s, name)
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+func TestBulk(t *testing.T) {
+	post := func(body string) (int, string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest("POST", "/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		Bulk()(c)
+		return w.Code, w.Body.String()
+	}
+
+	code, body := post(`[{"Name": {"First": "John", "Last": "Doe"}}, {"Name": {"First": "Jane"}}]`)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if strings.Count(body, "BEGIN:VCARD") != 2 {
+		t.Errorf("Expected 2 cards, got %s", body)
+	}
+
+	code, body = post(`[{"Name": {"First": "John"}}, {"Note": "no name"}]`)
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid item, got %d", code)
+	}
+	if !strings.Contains(body, `"index":1`) {
+		t.Errorf("Expected error for index 1, got %s", body)
+	}
+
+	if code, _ = post(`{"not": "an array"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for malformed JSON, got %d", code)
+	}
+}
+
+func TestBulkLimits(t *testing.T) {
+	post := func(body string) (int, string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/bulk", strings.NewReader(body))
+
+		Bulk(Options{MaxBulkSize: 300, MaxBulkContacts: 2, FilenameTemplate: "{org}"})(c)
+		return w.Code, w.Header().Get("Content-Disposition")
+	}
+
+	same := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Acme"}}]`
+	if code, disposition := post(same); code != http.StatusOK || !strings.Contains(disposition, "Acme.vcf") {
+		t.Errorf("Expected the template filename, got %d %q", code, disposition)
+	}
+	mixed := `[{"Name": {"First": "John"}, "Organization": {"Name": "Acme"}}, {"Name": {"First": "Jane"}, "Organization": {"Name": "Globex"}}]`
+	if _, disposition := post(mixed); !strings.Contains(disposition, vcard.DefaultBulkFilename) {
+		t.Errorf("Expected the default bulk filename, got %q", disposition)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}}, {"Name": {"First": "B"}}, {"Name": {"First": "C"}}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for too many contacts, got %d", code)
+	}
+	if code, _ := post(`[{"Name": {"First": "A"}, "Note": "` + strings.Repeat("x", 512) + `"}]`); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413 for a large body, got %d", code)
+	}
+}
+
+func TestBulkEnricher(t *testing.T) {
+	var logs bytes.Buffer
+	options := Options{
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		Enricher: vcard.Enrichers(vcard.DomainEnricher{}, vcard.EnricherFunc(func(ctx context.Context, card *vcard.VCard) error {
+			if card.GetName().First == "Fail" {
+				return errors.New("lookup failed")
+			}
+			return nil
+		})),
+	}
+	payload := `[{"Name": {"First": "Jane"}, "Emails": [{"Address": "jane@acme.com"}]}, {"Name": {"First": "Fail"}}]`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest("POST", "/bulk", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	Bulk(options)(c)
+	code, body := w.Code, w.Body.String()
+
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", code, body)
+	}
+	if !strings.Contains(body, "ORG:Acme") || !strings.Contains(body, "URL;TYPE=WORK:https://acme.com") {
+		t.Errorf("Expected enriched card, got %s", body)
+	}
+	if !strings.Contains(logs.String(), "vcard enrichment failed") {
+		t.Errorf("Expected enrichment failure to be logged, got %q", logs.String())
+	}
+}
+
+func TestVCardWebhook(t *testing.T) {
+	events := make(chan webhook.Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer server.Close()
+
+	notifier := webhook.New(server.URL)
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("John", "Doe")
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	VCard(handler, Options{Webhook: notifier})(c)
+	notifier.Close()
+
+	select {
+	case event := <-events:
+		if event.Type != webhook.EventGenerated || event.Name != "John Doe" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Error("Expected a webhook event")
+	}
+}
+
+func TestFromParams(t *testing.T) {
+	var card *vcard.VCard
+	r := gin.New()
+	r.POST("/contact/:firstName/:lastName", func(c *gin.Context) {
+		card = FromParams(c)
+	})
+
+	form := strings.NewReader("email=jane@example.com&emailType=home&title=CTO&firstName=Janet")
+	req := httptest.NewRequest(http.MethodPost, "/contact/Jane/Smith?phone=555-0100&phoneType=mobile", form)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if card == nil {
+		t.Fatal("Expected card to be created")
+	}
+	if name := card.GetName(); name.First != "Janet" || name.Last != "Smith" {
+		t.Errorf("Expected form value to override path parameter, got %+v", name)
+	}
+	if emails := card.GetEmails(); len(emails) != 1 || emails[0].Type != vcard.EmailHome {
+		t.Errorf("Unexpected emails: %+v", emails)
+	}
+	if phones := card.GetPhones(); len(phones) != 1 || phones[0].Type != vcard.PhoneMobile {
+		t.Errorf("Expected query parameters to be read, got %+v", phones)
+	}
+	if card.GetOrganization().Title != "CTO" {
+		t.Errorf("Expected title without organization, got %+v", card.GetOrganization())
+	}
+}
+
+func TestFromParamsRepeated(t *testing.T) {
+	var card *vcard.VCard
+	r := gin.New()
+	r.GET("/contact", func(c *gin.Context) {
+		card = FromParams(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/contact?firstName=Jane&email=a@x.com&email=b@y.com&emailType=work&emailType=home", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if card == nil {
+		t.Fatal("Expected card to be created")
+	}
+	emails := card.GetEmails()
+	if len(emails) != 2 || emails[0].Type != vcard.EmailWork || emails[1].Type != vcard.EmailHome {
+		t.Errorf("Expected repeated emails mapped to their types, got %+v", emails)
+	}
+}
+
+func TestVCardJSONShareData(t *testing.T) {
+	handler := func(c *gin.Context) *vcard.VCard {
+		return vcard.New().AddName("Jane", "Smith")
+	}
+
+	r := gin.New()
+	r.GET("/vcard", VCardJSON(handler, Options{DataURI: true}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/vcard", nil))
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode JSON response: %v", err)
+	}
+	if uri, _ := response["dataUri"].(string); !strings.HasPrefix(uri, "data:text/vcard;charset=utf-8;base64,") {
+		t.Errorf("Unexpected dataUri: %v", response["dataUri"])
+	}
+	if _, ok := response["qrCode"]; ok {
+		t.Error("Expected no qrCode without a QR encoder")
+	}
+}
@@ -0,0 +1,376 @@
+package vcard
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AddressBook is an ordered collection of vCards
+type AddressBook struct {
+	cards []*VCard
+}
+
+// NewAddressBook creates an address book containing the given cards
+func NewAddressBook(cards ...*VCard) *AddressBook {
+	book := &AddressBook{
+		cards: make([]*VCard, 0, len(cards)),
+	}
+	return book.Add(cards...)
+}
+
+// Add appends cards to the address book, skipping nil entries
+func (b *AddressBook) Add(cards ...*VCard) *AddressBook {
+	for _, card := range cards {
+		if card != nil {
+			b.cards = append(b.cards, card)
+		}
+	}
+	return b
+}
+
+// Cards returns a copy of the list of cards in the address book. The cards
+// themselves are shared with the address book, not cloned.
+func (b *AddressBook) Cards() []*VCard {
+	return append([]*VCard(nil), b.cards...)
+}
+
+// Len returns the number of cards in the address book
+func (b *AddressBook) Len() int {
+	return len(b.cards)
+}
+
+// String generates the content of all cards as a single .vcf document
+func (b *AddressBook) String() (string, error) {
+	var builder strings.Builder
+	for _, card := range b.cards {
+		content, err := card.String()
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(content)
+	}
+	return builder.String(), nil
+}
+
+// shardSize is the number of cards serialized per EncodeParallel task
+const shardSize = 256
+
+// shardResult is the serialized content of one shard
+type shardResult struct {
+	content string
+	err     error
+}
+
+// EncodeParallel serializes the address book to w using the given number of
+// workers (GOMAXPROCS when less than 1). Cards are encoded in shards
+// concurrently and written in their original order; at most two shards per
+// worker are buffered at any time.
+func (b *AddressBook) EncodeParallel(w io.Writer, workers int) error {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	shards := (len(b.cards) + shardSize - 1) / shardSize
+	results := make([]chan shardResult, shards)
+	for i := range results {
+		results[i] = make(chan shardResult, 1)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// Limit how far workers may run ahead of the writer
+	window := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < shards; i++ {
+			select {
+			case window <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for shard := range jobs {
+				results[shard] <- b.encodeShard(shard)
+			}
+		}()
+	}
+
+	for _, result := range results {
+		res := <-result
+		<-window
+		if res.err != nil {
+			return res.err
+		}
+		if _, err := io.WriteString(w, res.content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeShard serializes the cards of the given shard
+func (b *AddressBook) encodeShard(shard int) shardResult {
+	end := min((shard+1)*shardSize, len(b.cards))
+
+	var builder strings.Builder
+	for _, card := range b.cards[shard*shardSize : end] {
+		content, err := card.String()
+		if err != nil {
+			return shardResult{err: err}
+		}
+		builder.WriteString(content)
+	}
+	return shardResult{content: builder.String()}
+}
+
+// ItemError reports a bulk input item that could not be converted to a card
+type ItemError struct {
+	// Index of the item in the input
+	Index int `json:"index"`
+
+	// Message describes the problem
+	Message string `json:"error"`
+}
+
+// Error implements the error interface
+func (e ItemError) Error() string {
+	return fmt.Sprintf("item %d: %s", e.Index, e.Message)
+}
+
+// NewAddressBookFromContacts builds and validates a card for every contact
+// using the given version (empty for the default). Contacts that fail are
+// reported by index and left out of the address book.
+func NewAddressBookFromContacts(contacts []Contact, version Version) (*AddressBook, []ItemError) {
+	book := NewAddressBook()
+	var errs []ItemError
+
+	for i, contact := range contacts {
+		card := New()
+		if version != "" {
+			card.SetVersion(version)
+		}
+
+		card.AddContact(contact)
+		if err := card.Validate(); err != nil {
+			errs = append(errs, ItemError{Index: i, Message: err.Error()})
+			continue
+		}
+
+		book.Add(card)
+	}
+
+	return book, errs
+}
+
+// DateKind identifies which date property an UpcomingDate refers to
+type DateKind string
+
+const (
+	// DateBirthday refers to the BDAY property
+	DateBirthday DateKind = "birthday"
+
+	// DateAnniversary refers to the ANNIVERSARY property
+	DateAnniversary DateKind = "anniversary"
+)
+
+// UpcomingDate is a birthday or anniversary occurring within a window
+type UpcomingDate struct {
+	// Card the date belongs to
+	Card *VCard
+
+	// Kind of date (birthday or anniversary)
+	Kind DateKind
+
+	// Date is the next occurrence
+	Date time.Time
+
+	// Years is the age or number of years being celebrated (0 if the year is unknown)
+	Years int
+
+	// Days until the occurrence (0 means today)
+	Days int
+}
+
+// UpcomingDates returns birthdays and anniversaries occurring between today
+// and the end of the window, ordered by date. Dates without a year and
+// February 29 in non-leap years are handled.
+func (b *AddressBook) UpcomingDates(window time.Duration) []UpcomingDate {
+	return b.upcomingDates(time.Now(), window)
+}
+
+// upcomingDates computes UpcomingDates relative to now
+func (b *AddressBook) upcomingDates(now time.Time, window time.Duration) []UpcomingDate {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	end := today.Add(window)
+
+	var upcoming []UpcomingDate
+	for _, card := range b.cards {
+		for kind, date := range map[DateKind]*time.Time{
+			DateBirthday:    card.birthday,
+			DateAnniversary: card.anniversary,
+		} {
+			if date == nil {
+				continue
+			}
+
+			next := nextOccurrence(*date, today)
+			if next.After(end) {
+				continue
+			}
+
+			entry := UpcomingDate{
+				Card: card,
+				Kind: kind,
+				Date: next,
+				Days: int(next.Sub(today).Hours() / 24),
+			}
+			if date.Year() > 0 {
+				entry.Years = next.Year() - date.Year()
+			}
+			upcoming = append(upcoming, entry)
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		if !upcoming[i].Date.Equal(upcoming[j].Date) {
+			return upcoming[i].Date.Before(upcoming[j].Date)
+		}
+		return upcoming[i].Kind < upcoming[j].Kind
+	})
+
+	return upcoming
+}
+
+// nextOccurrence returns the first anniversary of date on or after today
+func nextOccurrence(date, today time.Time) time.Time {
+	for year := today.Year(); ; year++ {
+		day := date.Day()
+		if date.Month() == time.February && day == 29 && !isLeapYear(year) {
+			day = 28
+		}
+
+		next := time.Date(year, date.Month(), day, 0, 0, 0, 0, time.UTC)
+		if !next.Before(today) {
+			return next
+		}
+	}
+}
+
+// isLeapYear reports whether year is a leap year
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// GroupByDomain splits the address book by the domain of each card's
+// preferred (or first) email address. Domains are lower-cased; cards without
+// an email address are grouped under the empty string.
+func (b *AddressBook) GroupByDomain() map[string]*AddressBook {
+	groups := make(map[string]*AddressBook)
+	for _, card := range b.cards {
+		domain := emailDomain(card)
+		if groups[domain] == nil {
+			groups[domain] = NewAddressBook()
+		}
+		groups[domain].Add(card)
+	}
+	return groups
+}
+
+// OrganizationRollup is a company directory entry built by
+// RollupOrganizations
+type OrganizationRollup struct {
+	// Name is the organization name as first spelled in the address book
+	Name string
+
+	// Card is a vCard 4.0 group card for the organization listing every
+	// member by UID
+	Card *VCard
+
+	// Members holds the organization's contacts in address book order.
+	// Contacts that had no UID are copies carrying the UID listed in Card.
+	Members *AddressBook
+}
+
+// RollupOrganizations groups the cards by organization name, compared
+// case-insensitively, and synthesizes a group card per organization whose
+// MEMBER properties reference the contacts' UIDs. Contacts without a UID are
+// referenced by one derived from their identity fields (FN, N, EMAIL and
+// TEL), set on copies in Members; the address book's cards are not modified.
+// Cards without an organization are left out. Organizations are ordered by
+// name.
+func (b *AddressBook) RollupOrganizations() []OrganizationRollup {
+	index := make(map[string]int)
+	var rollups []OrganizationRollup
+
+	for _, card := range b.cards {
+		name := strings.TrimSpace(card.organization.Name)
+		if name == "" || card.kind == KindGroup {
+			continue
+		}
+
+		member := card
+		if member.uid == "" {
+			member = card.Clone().SetUID(identityUID(card))
+		}
+
+		key := strings.ToLower(name)
+		i, ok := index[key]
+		if !ok {
+			i = len(rollups)
+			index[key] = i
+			rollups = append(rollups, OrganizationRollup{
+				Name: name,
+				Card: NewWithVersion(Version40).
+					SetKind(KindGroup).
+					SetFormattedName(name).
+					AddOrganization(name).
+					SetUID(derivedUID("org:" + key)),
+				Members: NewAddressBook(),
+			})
+		}
+
+		rollups[i].Card.AddMember(member.uid)
+		rollups[i].Members.Add(member)
+	}
+
+	sort.SliceStable(rollups, func(i, j int) bool {
+		return strings.ToLower(rollups[i].Name) < strings.ToLower(rollups[j].Name)
+	})
+	return rollups
+}
+
+// emailDomain returns the lower-cased domain of the preferred (or first)
+// email address of the card
+func emailDomain(card *VCard) string {
+	address := ""
+	for i, email := range card.emails {
+		if i == 0 || email.Preferred {
+			address = email.Address
+		}
+		if email.Preferred {
+			break
+		}
+	}
+
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(address[at+1:]))
+}
@@ -0,0 +1,229 @@
+package vcard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddressBook(t *testing.T) {
+	john := New().AddName("John", "Doe")
+	jane := New().AddName("Jane", "Smith")
+
+	book := NewAddressBook(john, nil)
+	book.Add(jane)
+
+	if book.Len() != 2 {
+		t.Fatalf("Expected 2 cards, got %d", book.Len())
+	}
+
+	content, err := book.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	if strings.Count(content, "BEGIN:VCARD") != 2 {
+		t.Errorf("Expected 2 cards in output, got %s", content)
+	}
+
+	book.Add(New())
+	if _, err := book.String(); err == nil {
+		t.Error("Expected error for invalid card")
+	}
+}
+
+func TestUpcomingDates(t *testing.T) {
+	now := time.Date(2023, time.December, 28, 15, 0, 0, 0, time.UTC)
+
+	john := New().AddName("John", "Doe")
+	john.AddBirthdayFromString("1990-01-02")
+
+	jane := New().AddName("Jane", "Smith")
+	jane.AddBirthdayFromString("--12-28")
+	jane.AddAnniversaryFromString("2015-06-01")
+
+	leap := New().AddName("Leap", "Day")
+	leap.AddBirthdayFromString("2000-02-29")
+
+	book := NewAddressBook(john, jane, leap)
+
+	upcoming := book.upcomingDates(now, 7*24*time.Hour)
+	if len(upcoming) != 2 {
+		t.Fatalf("Expected 2 upcoming dates, got %d", len(upcoming))
+	}
+
+	// Year-less birthday today comes first
+	if upcoming[0].Card != jane || upcoming[0].Days != 0 || upcoming[0].Years != 0 {
+		t.Errorf("Unexpected first entry: %+v", upcoming[0])
+	}
+
+	// Crosses the year boundary
+	if upcoming[1].Card != john || upcoming[1].Days != 5 || upcoming[1].Years != 34 {
+		t.Errorf("Unexpected second entry: %+v", upcoming[1])
+	}
+
+	// February 29 is celebrated on February 28 in non-leap years
+	feb := time.Date(2023, time.February, 20, 0, 0, 0, 0, time.UTC)
+	upcoming = book.upcomingDates(feb, 10*24*time.Hour)
+	if len(upcoming) != 1 || upcoming[0].Date.Day() != 28 || upcoming[0].Years != 23 {
+		t.Errorf("Unexpected leap day handling: %+v", upcoming)
+	}
+}
+
+func TestYearlessDateOutput(t *testing.T) {
+	card := New().AddName("John", "Doe")
+	if err := card.AddBirthdayFromString("--05-15"); err != nil {
+		t.Fatalf("Failed to parse year-less date: %v", err)
+	}
+
+	content, _ := card.String()
+	if !strings.Contains(content, "BDAY:--05-15") {
+		t.Errorf("Expected year-less BDAY, got %s", content)
+	}
+}
+
+func TestNewAddressBookFromContacts(t *testing.T) {
+	approximate := "circa 1990"
+	contacts := []Contact{
+		{Name: Name{First: "John", Last: "Doe"}},
+		{Emails: []Email{{Address: "nobody@example.com"}}},
+		{Name: Name{First: "Jane"}, Birthday: &approximate},
+		{Name: Name{Last: "Smith"}, Organization: Organization{Name: "Acme"}},
+		{Organization: Organization{Name: "Acme"}},
+	}
+
+	book, errs := NewAddressBookFromContacts(contacts, Version40)
+	if book.Len() != 3 {
+		t.Errorf("Expected 3 valid cards, got %d", book.Len())
+	}
+
+	if len(errs) != 2 || errs[0].Index != 1 || errs[1].Index != 4 {
+		t.Fatalf("Expected errors for items 1 and 4, got %v", errs)
+	}
+
+	if !strings.HasPrefix(errs[1].Error(), "item 4: ") {
+		t.Errorf("Unexpected error message: %s", errs[1].Error())
+	}
+
+	if got := book.Cards()[1].GetBirthdayText(); got != approximate {
+		t.Errorf("Expected free-text birthday to be preserved, got %q", got)
+	}
+
+	if book.Cards()[0].GetVersion() != Version40 {
+		t.Error("Expected cards to use requested version")
+	}
+}
+
+func TestEncodeParallel(t *testing.T) {
+	book := NewAddressBook()
+	for i := 0; i < 1000; i++ {
+		book.Add(New().AddName(fmt.Sprintf("User%d", i), "Doe").AddEmail(fmt.Sprintf("user%d@example.com", i)))
+	}
+
+	expected, err := book.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	for _, workers := range []int{0, 1, 4, 16} {
+		var buf bytes.Buffer
+		if err := book.EncodeParallel(&buf, workers); err != nil {
+			t.Fatalf("EncodeParallel(%d) returned error: %v", workers, err)
+		}
+		if buf.String() != expected {
+			t.Errorf("EncodeParallel(%d) output differs from String()", workers)
+		}
+	}
+}
+
+func TestEncodeParallelInvalidCard(t *testing.T) {
+	book := NewAddressBook()
+	for i := 0; i < 600; i++ {
+		book.Add(New().AddName("John", "Doe"))
+	}
+	book.Add(New())
+
+	var buf bytes.Buffer
+	if err := book.EncodeParallel(&buf, 4); err == nil {
+		t.Error("Expected error for invalid card")
+	}
+}
+
+func BenchmarkEncodeParallel(b *testing.B) {
+	book := NewAddressBook()
+	for i := 0; i < 10000; i++ {
+		book.Add(New().AddName("John", "Doe").AddEmail("john@example.com").AddPhone("+1234567890"))
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := book.EncodeParallel(io.Discard, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupByDomain(t *testing.T) {
+	john := New().AddName("John", "Doe").AddEmail("john@Acme.com")
+	jane := New().AddName("Jane", "Doe").AddEmail("jane@gmail.com").AddEmailWithPreference("jane@acme.com", EmailWork, true)
+	bob := New().AddName("Bob", "Smith").AddEmail("bob@globex.com")
+	anon := New().AddName("No", "Email")
+
+	groups := NewAddressBook(john, jane, bob, anon).GroupByDomain()
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+	if groups["acme.com"].Len() != 2 || groups["acme.com"].Cards()[1] != jane {
+		t.Errorf("Expected John and Jane under acme.com, got %v", groups["acme.com"].Cards())
+	}
+	if groups["globex.com"].Len() != 1 || groups[""].Len() != 1 {
+		t.Error("Expected Bob under globex.com and the card without email under empty domain")
+	}
+}
+
+func TestRollupOrganizations(t *testing.T) {
+	john := New().AddName("John", "Doe").AddOrganization("Globex").SetUID("urn:uuid:john")
+	jane := New().AddName("Jane", "Doe").AddOrganization("globex ")
+	bob := New().AddName("Bob", "Smith").AddOrganization("Acme")
+	anon := New().AddName("No", "Org")
+
+	rollups := NewAddressBook(john, jane, bob, anon).RollupOrganizations()
+	if len(rollups) != 2 {
+		t.Fatalf("Expected 2 organizations, got %d", len(rollups))
+	}
+	if rollups[0].Name != "Acme" || rollups[1].Name != "Globex" {
+		t.Errorf("Expected organizations ordered by name, got %s, %s", rollups[0].Name, rollups[1].Name)
+	}
+
+	globex := rollups[1]
+	if globex.Members.Len() != 2 {
+		t.Errorf("Expected 2 Globex members, got %d", globex.Members.Len())
+	}
+	if jane.GetUID() != "" {
+		t.Errorf("Expected the caller's card to be left unchanged, got UID %q", jane.GetUID())
+	}
+	janeUID := globex.Members.Cards()[1].GetUID()
+	if janeUID == "" {
+		t.Error("Expected member without UID to be given one")
+	}
+	again := NewAddressBook(john, jane.Clone().AddNote("Met at the conference")).RollupOrganizations()
+	if uid := again[0].Members.Cards()[1].GetUID(); uid != janeUID {
+		t.Errorf("Expected the derived UID to depend on identity fields only, got %q and %q", janeUID, uid)
+	}
+
+	content, err := globex.Card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate organization card: %v", err)
+	}
+	for _, expected := range []string{"KIND:group", "FN:Globex", "ORG:Globex", "MEMBER:urn:uuid:john", "MEMBER:" + janeUID} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in organization card:\n%s", expected, content)
+		}
+	}
+}
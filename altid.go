@@ -0,0 +1,135 @@
+package vcard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Alternate is an alternative representation of a text property, such as
+// the formatted name in another language or script. vCard 4.0 links it to
+// the primary value with the ALTID parameter (RFC 6350 section 5.4), so
+// clients show the variants as one property instead of duplicates.
+type Alternate struct {
+	// Language is the LANGUAGE parameter (e.g. "ja" or "zh-Hant")
+	Language string
+
+	// Value is the property value in that language or script
+	Value string
+}
+
+// alternateProperties lists the properties that accept alternates
+var alternateProperties = map[string]bool{
+	"FN":    true,
+	"ORG":   true,
+	"TITLE": true,
+	"ROLE":  true,
+	"NOTE":  true,
+}
+
+// AddAlternate adds an alternative representation of FN, ORG (the
+// organization name), TITLE, ROLE or NOTE, e.g.
+//
+//	card.SetFormattedName("Yamada Taro").AddAlternate("FN", "ja", "山田太郎")
+//
+// Alternates are written for vCard 4.0 only; Lint reports them for 3.0 and
+// for other properties.
+func (v *VCard) AddAlternate(property, language, value string) *VCard {
+	if value == "" {
+		return v
+	}
+
+	if v.alternates == nil {
+		v.alternates = make(map[string][]Alternate)
+	}
+	property = strings.ToUpper(property)
+	v.alternates[property] = append(v.alternates[property], Alternate{Language: language, Value: v.options.text(value)})
+	return v
+}
+
+// GetAlternates returns a copy of the alternates of the property
+func (v *VCard) GetAlternates(property string) []Alternate {
+	return append([]Alternate(nil), v.alternates[strings.ToUpper(property)]...)
+}
+
+// validateAlternates rejects alternate languages that are not language tags,
+// which would otherwise inject parameters into the output
+func (v *VCard) validateAlternates() error {
+	for property, alternates := range v.alternates {
+		for _, alternate := range alternates {
+			if !isLanguageTag(alternate.Language) {
+				return fmt.Errorf("invalid %s alternate language %q", property, alternate.Language)
+			}
+		}
+	}
+	return nil
+}
+
+// isLanguageTag reports whether s is empty or looks like a BCP 47 language
+// tag: letters and digits separated by dashes
+func isLanguageTag(s string) bool {
+	for _, part := range strings.Split(s, "-") {
+		if s != "" && part == "" {
+			return false
+		}
+		for _, r := range part {
+			if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// altIDParameter returns the ALTID parameter linking the primary value of
+// the property to its alternates, or an empty string when none are written
+func (v *VCard) altIDParameter(property string) string {
+	if v.version != Version40 || len(v.alternates[property]) == 0 {
+		return ""
+	}
+	params := Params{{Name: "ALTID", Values: []string{"1"}}}
+	return params.String()
+}
+
+// writeAlternates writes the alternates of the property for vCard 4.0
+func (v *VCard) writeAlternates(builder *strings.Builder, property string) {
+	if v.altIDParameter(property) == "" {
+		return
+	}
+
+	for _, alternate := range v.alternates[property] {
+		params := Params{{Name: "ALTID", Values: []string{"1"}}}
+		params.Add("LANGUAGE", alternate.Language)
+		builder.WriteString(foldLine(property+params.String()+":"+escapeValue(alternate.Value)) + "\n")
+	}
+}
+
+// alternateWarnings reports alternates that are not written, either because
+// the property does not accept them or, with versionOnly, because the card's
+// version has no ALTID parameter
+func (v *VCard) alternateWarnings(versionOnly bool) []Warning {
+	properties := make([]string, 0, len(v.alternates))
+	for property := range v.alternates {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	var warnings []Warning
+	for _, property := range properties {
+		switch {
+		case !alternateProperties[property]:
+			if !versionOnly {
+				warnings = append(warnings, Warning{
+					Property: property,
+					Message:  "alternates are only written for FN, ORG, TITLE, ROLE and NOTE",
+				})
+			}
+		case v.version != Version40 && versionOnly:
+			warnings = append(warnings, Warning{
+				Property: property,
+				Message:  "alternates (ALTID) require vCard 4.0 and are not written",
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,110 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlternates(t *testing.T) {
+	card := NewWithVersion(Version40).
+		SetFormattedName("Yamada Taro").
+		AddAlternate("FN", "ja", "山田太郎").
+		AddAlternate("fn", "ja-Latn", "Yamada, Taro").
+		AddOrganization("Example Corp").
+		AddAlternate("ORG", "ja", "株式会社例").
+		AddTitle("Engineer").
+		AddNote("Hello").
+		AddAlternate("NOTE", "", "Hi; there")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+
+	for _, expected := range []string{
+		"FN;ALTID=1:Yamada Taro\n",
+		"FN;ALTID=1;LANGUAGE=ja:山田太郎\n",
+		"FN;ALTID=1;LANGUAGE=ja-Latn:Yamada\\, Taro\n",
+		"ORG;ALTID=1:Example Corp\n",
+		"ORG;ALTID=1;LANGUAGE=ja:株式会社例\n",
+		"TITLE:Engineer\n",
+		"NOTE;ALTID=1:Hello\n",
+		"NOTE;ALTID=1:Hi\\; there\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, content)
+		}
+	}
+
+	if alternates := card.GetAlternates("Fn"); len(alternates) != 2 || alternates[0].Language != "ja" {
+		t.Errorf("Unexpected alternates: %+v", alternates)
+	}
+	if warnings := card.Lint(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
+
+func TestAlternatesVersion30(t *testing.T) {
+	card := New().AddName("Taro", "Yamada").AddAlternate("FN", "ja", "山田太郎").AddAlternate("EMAIL", "ja", "x")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if strings.Contains(content, "ALTID") || strings.Contains(content, "山田太郎") {
+		t.Errorf("Expected alternates to be dropped from 3.0 output:\n%s", content)
+	}
+
+	warnings := card.Lint()
+	if len(warnings) != 2 || warnings[0].Property != "EMAIL" || warnings[1].Property != "FN" {
+		t.Errorf("Expected EMAIL and FN warnings, got %v", warnings)
+	}
+	if err := card.ValidateStrict(); err == nil {
+		t.Error("Expected strict validation to reject alternates on 3.0")
+	}
+}
+
+func TestAlternateLanguageInjection(t *testing.T) {
+	card := NewWithVersion(Version40).SetFormattedName("Jane").AddAlternate("FN", "en:x\nEMAIL", "evil")
+	if err := card.Validate(); err == nil {
+		t.Error("Expected invalid alternate language to be rejected")
+	}
+}
+
+func TestAlternatesCloneAndMask(t *testing.T) {
+	card := NewWithVersion(Version40).
+		SetFormattedName("Jane").
+		AddAlternate("FN", "ja", "ジェーン").
+		AddNote("Private").
+		AddAlternate("NOTE", "ja", "非公開")
+
+	clone := card.Clone()
+	clone.AddAlternate("FN", "ko", "제인")
+	if len(card.GetAlternates("FN")) != 1 {
+		t.Error("Expected clone alternates to be independent")
+	}
+
+	masked := card.Masked(FieldAll &^ FieldNote)
+	if len(masked.GetAlternates("NOTE")) != 0 || len(masked.GetAlternates("FN")) != 1 {
+		t.Errorf("Expected masking the note to drop its alternates only")
+	}
+
+	if len(card.Reset().GetAlternates("FN")) != 0 {
+		t.Error("Expected Reset to clear alternates")
+	}
+}
+
+func TestAlternatesWithoutPrimary(t *testing.T) {
+	card := NewWithVersion(Version40).
+		SetFormattedName("Jane").
+		AddAlternate("TITLE", "ja", "エンジニア").
+		AddAlternate("ROLE", "ja", "開発")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if strings.Contains(content, "TITLE") || strings.Contains(content, "ROLE") {
+		t.Errorf("Expected alternates without a primary value to be dropped:\n%s", content)
+	}
+}
@@ -0,0 +1,359 @@
+package vcard
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// DefaultArchiveTemplate names the card files written to archives
+const DefaultArchiveTemplate = "{name}"
+
+// ArchiveLimits bound what LoadFromZip and LoadFromTar read, so a hostile
+// archive such as a zip bomb can't exhaust memory. Zero values mean no
+// limit.
+type ArchiveLimits struct {
+	// MaxFileSize is the maximum decompressed size in bytes of one .vcf file
+	MaxFileSize int64
+
+	// MaxTotalSize is the maximum decompressed size in bytes of all .vcf
+	// files together
+	MaxTotalSize int64
+
+	// Decoder limits the cards read from the files; MaxDecodedCards counts
+	// the cards of all files together
+	Decoder DecoderLimits
+}
+
+// DefaultArchiveLimits are the limits of LoadFromZip and LoadFromTar. They
+// suit phone backups and desktop exports with inline photos.
+var DefaultArchiveLimits = ArchiveLimits{
+	MaxFileSize:  64 << 20,
+	MaxTotalSize: 256 << 20,
+	Decoder:      DefaultDecoderLimits,
+}
+
+// LoadFromZip reads the cards of every .vcf file in a .zip archive, such as
+// a phone backup, in path order. Files in nested folders are read too;
+// hidden files and macOS resource forks (__MACOSX) are skipped. Files are
+// decoded from UTF-8, UTF-16 with a byte order mark or, when not valid
+// UTF-8, Windows-1252 as written by older desktop exporters. The archive is
+// read with the DefaultArchiveLimits.
+func LoadFromZip(name string) (*AddressBook, error) {
+	return LoadFromZipWithLimits(name, DefaultArchiveLimits)
+}
+
+// LoadFromZipWithLimits reads a .zip archive like LoadFromZip with the given
+// limits, e.g. tighter ones for user uploads. Archives exceeding them fail
+// with a *LimitError.
+func LoadFromZipWithLimits(name string, limits ArchiveLimits) (*AddressBook, error) {
+	archive, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer archive.Close()
+
+	book := NewAddressBook()
+	err = WalkArchive(archive, limits, func(name, text string) error {
+		return decodeArchived(book, name, text, limits)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// LoadFromTar reads the cards of every .vcf file in a .tar archive,
+// compressed with gzip or not, as LoadFromZip does for .zip archives
+func LoadFromTar(r io.Reader) (*AddressBook, error) {
+	return LoadFromTarWithLimits(r, DefaultArchiveLimits)
+}
+
+// LoadFromTarWithLimits reads a .tar archive like LoadFromTar with the
+// given limits. Archives exceeding them fail with a *LimitError.
+func LoadFromTarWithLimits(r io.Reader, limits ArchiveLimits) (*AddressBook, error) {
+	buffered := bufio.NewReader(r)
+	if magic, _ := buffered.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = buffered
+	}
+
+	var total int64
+	files := make(map[string][]byte)
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !isArchivedCard(header.Name) {
+			continue
+		}
+		if files[header.Name], err = readArchived(reader, limits, &total); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	book := NewAddressBook()
+	for _, name := range names {
+		text, err := decodeText(files[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := decodeArchived(book, name, text, limits); err != nil {
+			return nil, err
+		}
+	}
+	return book, nil
+}
+
+// readArchived reads an archived file within the size limits, adding its
+// size to total
+func readArchived(r io.Reader, limits ArchiveLimits, total *int64) ([]byte, error) {
+	max := limits.MaxFileSize
+	if remaining := limits.MaxTotalSize - *total; limits.MaxTotalSize > 0 && (max <= 0 || remaining < max) {
+		max = remaining
+	}
+	if max > 0 {
+		r = io.LimitReader(r, max+1)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	*total += int64(len(data))
+	switch {
+	case limits.MaxFileSize > 0 && int64(len(data)) > limits.MaxFileSize:
+		return nil, &LimitError{Limit: "MaxFileSize", Max: int(limits.MaxFileSize)}
+	case limits.MaxTotalSize > 0 && *total > limits.MaxTotalSize:
+		return nil, &LimitError{Limit: "MaxTotalSize", Max: int(limits.MaxTotalSize)}
+	}
+	return data, nil
+}
+
+// ExportToZip writes the address book to w as a .zip archive with one .vcf
+// file per card in the folder dir (the root when empty), named with the
+// filename template (DefaultArchiveTemplate when empty). Names used twice
+// get a counter, e.g. "Jane-Doe-2.vcf".
+func (b *AddressBook) ExportToZip(w io.Writer, dir, template string) error {
+	archive := zip.NewWriter(w)
+	err := b.exportArchived(dir, template, func(name string, content []byte, modified time.Time) error {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// ExportToTarGz writes the address book to w as a gzip-compressed .tar
+// archive, with the files ExportToZip would write
+func (b *AddressBook) ExportToTarGz(w io.Writer, dir, template string) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	err := b.exportArchived(dir, template, func(name string, content []byte, modified time.Time) error {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: modified,
+			Format:  tar.FormatPAX,
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := archive.Write(content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ArchiveFiles returns the paths ExportToZip and ExportToTarGz would write
+// for the address book, without writing anything: a dry run of an export
+// that also reports the first card failing to generate
+func (b *AddressBook) ArchiveFiles(dir, template string) ([]string, error) {
+	files := make([]string, 0, len(b.cards))
+	err := b.exportArchived(dir, template, func(name string, _ []byte, _ time.Time) error {
+		files = append(files, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// exportArchived generates every card and passes it to write with its path
+// in the archive and the export time
+func (b *AddressBook) exportArchived(dir, template string, write func(name string, content []byte, modified time.Time) error) error {
+	if template == "" {
+		template = DefaultArchiveTemplate
+	}
+
+	used := make(map[string]bool)
+	modified := time.Now()
+	for i, card := range b.cards {
+		content, err := card.String()
+		if err != nil {
+			return fmt.Errorf("card %d: %w", i+1, err)
+		}
+
+		name := UniqueFilename(used, card.Filename(template))
+
+		if err := write(path.Join(dir, name), []byte(content), modified); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// WalkArchive calls fn with the path and text of every .vcf file in the
+// file system, such as an unpacked export or a *zip.Reader, in path order.
+// Hidden files and macOS resource forks are skipped and text is decoded as
+// LoadFromZip does. Files exceeding the size limits fail with a *LimitError;
+// the decoder limits are left to fn.
+func WalkArchive(fsys fs.FS, limits ArchiveLimits, fn func(name, text string) error) error {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case name == ".":
+		case entry.IsDir() && isSkippedArchivePart(entry.Name()):
+			return fs.SkipDir
+		case !entry.IsDir() && isArchivedCard(name):
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	sort.Strings(names)
+
+	var total int64
+	for _, name := range names {
+		data, err := readArchivedFile(fsys, name, limits, &total)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		text, err := decodeText(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := fn(name, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readArchivedFile reads a file of the file system within the size limits
+func readArchivedFile(fsys fs.FS, name string, limits ArchiveLimits, total *int64) ([]byte, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readArchived(file, limits, total)
+}
+
+// isArchivedCard reports whether the archive path names a .vcf file outside
+// hidden folders and macOS resource forks
+func isArchivedCard(name string) bool {
+	for _, part := range strings.Split(strings.Trim(name, "/"), "/") {
+		if isSkippedArchivePart(part) {
+			return false
+		}
+	}
+	return strings.EqualFold(path.Ext(name), FileExtension)
+}
+
+// isSkippedArchivePart reports whether a path element is hidden or a macOS
+// resource fork folder
+func isSkippedArchivePart(part string) bool {
+	return strings.HasPrefix(part, ".") || part == "__MACOSX"
+}
+
+// decodeArchived adds the cards of an archived file to the book within the
+// decoder limits
+func decodeArchived(book *AddressBook, name, text string, limits ArchiveLimits) error {
+	// The card limit applies to the archive, not to each file; once it is
+	// used up the file's size limit bounds the decoding below
+	decoderLimits, max := limits.Decoder, limits.Decoder.MaxDecodedCards
+	if max > 0 {
+		decoderLimits.MaxDecodedCards = max - book.Len()
+	}
+	found, err := NewDecoder(strings.NewReader(text)).Limits(decoderLimits).DecodeAddressBook()
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		limitErr.Card += book.Len()
+		if limitErr.Limit == "MaxDecodedCards" {
+			limitErr.Max = max
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if max > 0 && book.Len()+found.Len() > max {
+		return fmt.Errorf("%s: %w", name, &LimitError{Limit: "MaxDecodedCards", Max: max, Card: max + 1})
+	}
+	book.Add(found.cards...)
+	return nil
+}
+
+// decodeText converts the content of a .vcf file to UTF-8: UTF-16 is
+// recognized by its byte order mark, and text that is not valid UTF-8 is
+// read as Windows-1252
+func decodeText(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}), bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		decoded, err := unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Bytes(data)
+		return string(decoded), err
+	case utf8.Valid(data):
+		return string(data), nil
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	return string(decoded), err
+}
@@ -0,0 +1,223 @@
+package vcard
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// writeZip writes the files to a .zip archive in a temporary directory
+func writeZip(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "backup.zip")
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for path, content := range files {
+		w, err := archive.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(content)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestLoadFromZip(t *testing.T) {
+	utf16, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Zoë Ortiz\r\nN:Ortiz;Zoë;;;\r\nEND:VCARD\r\n")
+	latin1, _ := charmap.Windows1252.NewEncoder().String(
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:René Müller\r\nN:Müller;René;;;\r\nEND:VCARD\r\n")
+
+	name := writeZip(t, map[string][]byte{
+		"Contacts/b/jane.vcf":       []byte("BEGIN:VCARD\nVERSION:4.0\nFN:Jane Doe\nEND:VCARD\nBEGIN:VCARD\nVERSION:4.0\nFN:John Doe\nEND:VCARD\n"),
+		"Contacts/a/zoe.VCF":        []byte(utf16),
+		"Contacts/c/rene.vcf":       []byte(latin1),
+		"__MACOSX/Contacts/._x.vcf": []byte("resource fork"),
+		"Contacts/.hidden.vcf":      []byte("not a card"),
+		"Contacts/readme.txt":       []byte("not a card"),
+	})
+
+	book, err := LoadFromZip(name)
+	if err != nil {
+		t.Fatalf("LoadFromZip() error = %v", err)
+	}
+
+	var names []string
+	for _, card := range book.Cards() {
+		names = append(names, card.GetFormattedName())
+	}
+	if got := strings.Join(names, ", "); got != "Zoë Ortiz, Jane Doe, John Doe, René Müller" {
+		t.Errorf("cards = %s", got)
+	}
+}
+
+func TestLoadFromZipErrors(t *testing.T) {
+	if _, err := LoadFromZip(filepath.Join(t.TempDir(), "missing.zip")); err == nil {
+		t.Error("Expected an error for a missing archive")
+	}
+
+	name := writeZip(t, map[string][]byte{"broken.vcf": []byte("BEGIN:VCARD\nFN:No version\nEND:VCARD\n")})
+	if _, err := LoadFromZip(name); err == nil || !strings.Contains(err.Error(), "broken.vcf") {
+		t.Errorf("Expected an error naming the file, got %v", err)
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	book := NewAddressBook(
+		New().AddName("Jane", "Doe").AddEmail("jane@example.com"),
+		New().AddName("Jane", "Doe").AddEmail("jane.doe@example.org"),
+		New().AddName("Émile", "Zola"),
+	)
+
+	var gz bytes.Buffer
+	if err := book.ExportToTarGz(&gz, "Contacts", ""); err != nil {
+		t.Fatalf("ExportToTarGz() error = %v", err)
+	}
+	fromTar, err := LoadFromTar(&gz)
+	if err != nil {
+		t.Fatalf("LoadFromTar() error = %v", err)
+	}
+
+	var zipped bytes.Buffer
+	if err := book.ExportToZip(&zipped, "", "{last}-{first}"); err != nil {
+		t.Fatalf("ExportToZip() error = %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(zipped.Bytes()), int64(zipped.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, file := range reader.File {
+		files = append(files, file.Name)
+	}
+	if got := strings.Join(files, " "); got != "Doe-Jane.vcf Doe-Jane-2.vcf Zola-Émile.vcf" {
+		t.Errorf("zip files = %s", got)
+	}
+
+	planned, err := book.ArchiveFiles("", "{last}-{first}")
+	if err != nil || strings.Join(planned, " ") != strings.Join(files, " ") {
+		t.Errorf("ArchiveFiles() = %v, %v, want the zip files %v", planned, err, files)
+	}
+
+	// A name given a counter must not collide with a card named like it
+	var collided bytes.Buffer
+	namesakes := NewAddressBook(New().AddName("Jane", "Doe"), New().AddName("Jane", "Doe"), New().AddName("Jane", "Doe 2"))
+	if err := namesakes.ExportToZip(&collided, "", "{name}"); err != nil {
+		t.Fatal(err)
+	}
+	reader, _ = zip.NewReader(bytes.NewReader(collided.Bytes()), int64(collided.Len()))
+	files = files[:0]
+	for _, file := range reader.File {
+		files = append(files, file.Name)
+	}
+	if got := strings.Join(files, " "); got != "Jane-Doe.vcf Jane-Doe-2.vcf Jane-Doe-2-2.vcf" {
+		t.Errorf("zip files = %s", got)
+	}
+
+	name := filepath.Join(t.TempDir(), "export.zip")
+	if err := os.WriteFile(name, zipped.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fromZip, err := LoadFromZip(name)
+	if err != nil {
+		t.Fatalf("LoadFromZip() error = %v", err)
+	}
+
+	for _, loaded := range []*AddressBook{fromTar, fromZip} {
+		if loaded.Len() != book.Len() {
+			t.Fatalf("Expected %d cards, got %d", book.Len(), loaded.Len())
+		}
+		emails := map[string]bool{}
+		for _, card := range loaded.Cards() {
+			emails[card.GetEmail()] = true
+		}
+		if !emails["jane@example.com"] || !emails["jane.doe@example.org"] {
+			t.Errorf("Expected both Janes, got %v", emails)
+		}
+	}
+}
+
+func TestLoadFromTarUncompressed(t *testing.T) {
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	content := []byte("BEGIN:VCARD\nVERSION:4.0\nFN:Jane Doe\nEND:VCARD\n")
+	archive.WriteHeader(&tar.Header{Name: "backup/contacts/", Typeflag: tar.TypeDir, Mode: 0755})
+	archive.WriteHeader(&tar.Header{Name: "backup/contacts/jane.vcf", Mode: 0644, Size: int64(len(content))})
+	archive.Write(content)
+	archive.Close()
+
+	book, err := LoadFromTar(&buf)
+	if err != nil {
+		t.Fatalf("LoadFromTar() error = %v", err)
+	}
+	if book.Len() != 1 || book.Cards()[0].GetFormattedName() != "Jane Doe" {
+		t.Errorf("Expected Jane Doe, got %d cards", book.Len())
+	}
+}
+
+func TestLoadArchiveLimits(t *testing.T) {
+	card := "BEGIN:VCARD\nVERSION:4.0\nFN:Jane Doe\nEND:VCARD\n"
+	limitOf := func(err error) string {
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("Expected a *LimitError, got %v", err)
+		}
+		return limitErr.Limit
+	}
+
+	// A small zip that inflates to 8 MiB
+	bomb := writeZip(t, map[string][]byte{"bomb.vcf": bytes.Repeat([]byte("X"), 8<<20)})
+	_, err := LoadFromZipWithLimits(bomb, ArchiveLimits{MaxFileSize: 1 << 20})
+	if limit := limitOf(err); limit != "MaxFileSize" {
+		t.Errorf("Expected MaxFileSize, got %s", limit)
+	}
+
+	files := map[string][]byte{"a.vcf": []byte(card + card), "b.vcf": []byte(card + card)}
+	_, err = LoadFromZipWithLimits(writeZip(t, files), ArchiveLimits{MaxTotalSize: int64(3 * len(card))})
+	if limit := limitOf(err); limit != "MaxTotalSize" {
+		t.Errorf("Expected MaxTotalSize, got %s", limit)
+	}
+
+	// The card limit counts the cards of all files
+	for _, max := range []int{2, 3} {
+		_, err = LoadFromZipWithLimits(writeZip(t, files), ArchiveLimits{Decoder: DecoderLimits{MaxDecodedCards: max}})
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != "MaxDecodedCards" || limitErr.Max != max || limitErr.Card != max+1 {
+			t.Errorf("Expected card %d to exceed MaxDecodedCards of %d, got %v", max+1, max, err)
+		}
+	}
+	if book, err := LoadFromZipWithLimits(writeZip(t, files), ArchiveLimits{Decoder: DecoderLimits{MaxDecodedCards: 4}}); err != nil || book.Len() != 4 {
+		t.Errorf("Expected 4 cards within the limit, got %v", err)
+	}
+
+	long := "BEGIN:VCARD\nVERSION:4.0\nFN:Jane Doe\nNOTE:" + strings.Repeat("x", 2000) + "\nEND:VCARD\n"
+	_, err = LoadFromZipWithLimits(writeZip(t, map[string][]byte{"long.vcf": []byte(long)}), ArchiveLimits{Decoder: DecoderLimits{MaxLineLength: 1000}})
+	if limit := limitOf(err); limit != "MaxLineLength" {
+		t.Errorf("Expected MaxLineLength, got %s", limit)
+	}
+
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	large := bytes.Repeat([]byte("X"), 2<<20)
+	archive.WriteHeader(&tar.Header{Name: "large.vcf", Mode: 0644, Size: int64(len(large))})
+	archive.Write(large)
+	archive.Close()
+	_, err = LoadFromTarWithLimits(&buf, ArchiveLimits{MaxFileSize: 1 << 20})
+	if limit := limitOf(err); limit != "MaxFileSize" {
+		t.Errorf("Expected MaxFileSize, got %s", limit)
+	}
+}
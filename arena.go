@@ -0,0 +1,63 @@
+package vcard
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// DefaultArenaSize is the size of the blocks of Decoder.Arena
+const DefaultArenaSize = 1 << 20
+
+// arena hands out strings carved from shared blocks. Bytes are only ever
+// appended to a block within its capacity and never changed afterwards, so
+// the strings stay immutable; a full block is left to the garbage collector
+// and freed once no string refers to it.
+type arena struct {
+	size  int
+	block []byte
+}
+
+// unfold appends the logical lines of the card text to lines, as
+// UnfoldLines would, with the lines allocated in the arena
+func (a *arena) unfold(text []byte, lines []string) []string {
+	// The unfolded lines are never longer than the text
+	if cap(a.block)-len(a.block) < len(text) {
+		a.block = make([]byte, 0, max(a.size, len(text)))
+	}
+
+	start := -1
+	finish := func() {
+		if start >= 0 {
+			lines = append(lines, a.string(start))
+		}
+	}
+	for len(text) > 0 {
+		var line []byte
+		line, text, _ = bytes.Cut(text, []byte("\n"))
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		for more := true; more; {
+			// Bare CR line breaks split the line further
+			var part []byte
+			part, line, more = bytes.Cut(line, []byte("\r"))
+			switch {
+			case len(part) == 0:
+			case (part[0] == ' ' || part[0] == '\t') && start >= 0:
+				a.block = append(a.block, part[1:]...)
+			default:
+				finish()
+				start = len(a.block)
+				a.block = append(a.block, part...)
+			}
+		}
+	}
+	finish()
+	return lines
+}
+
+// string returns the bytes of the block from start as a string
+func (a *arena) string(start int) string {
+	if start == len(a.block) {
+		return ""
+	}
+	return unsafe.String(&a.block[start], len(a.block)-start)
+}
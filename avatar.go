@@ -0,0 +1,40 @@
+package vcard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"html"
+)
+
+// DefaultMonogramSize is the monogram width and height used by Preview
+const DefaultMonogramSize = 128
+
+// Initials returns up to two upper-cased initials derived from the display name
+func (v *VCard) Initials() string {
+	return initials(v.displayName())
+}
+
+// MonogramSVG returns a square SVG avatar showing the card's initials on a
+// background color derived from a hash of the display name, so the same
+// contact always gets the same color
+func (v *VCard) MonogramSVG(size int) string {
+	if size <= 0 {
+		size = DefaultMonogramSize
+	}
+
+	name := v.displayName()
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	hue := hash.Sum32() % 360
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 100 100">`+
+		`<rect width="100" height="100" fill="hsl(%d,55%%,45%%)"/>`+
+		`<text x="50" y="50" dy=".35em" text-anchor="middle" font-family="sans-serif" font-size="40" fill="#fff">%s</text>`+
+		`</svg>`, size, size, hue, html.EscapeString(initials(name)))
+}
+
+// MonogramDataURI returns the monogram avatar as a base64 SVG data URI
+func (v *VCard) MonogramDataURI(size int) string {
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(v.MonogramSVG(size)))
+}
@@ -0,0 +1,78 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInitials(t *testing.T) {
+	tests := []struct {
+		first, last string
+		expected    string
+	}{
+		{"John", "Doe", "JD"},
+		{"jane", "", "J"},
+		{"Émile", "Zola", "ÉZ"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		card := New().AddName(tt.first, tt.last)
+		if got := card.Initials(); got != tt.expected {
+			t.Errorf("Initials() for %q %q = %q, want %q", tt.first, tt.last, got, tt.expected)
+		}
+	}
+
+	card := New().AddName("John", "Doe").AddPrefix("Dr.")
+	if got := card.Initials(); got != "DD" {
+		t.Errorf("Expected initials from first and last words, got %q", got)
+	}
+}
+
+func TestMonogramSVG(t *testing.T) {
+	card := New().AddName("John", "Doe")
+
+	svg := card.MonogramSVG(64)
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, ">JD</text>") {
+		t.Errorf("Unexpected monogram SVG: %s", svg)
+	}
+	if !strings.Contains(svg, `width="64"`) {
+		t.Error("Monogram should use requested size")
+	}
+
+	if card.MonogramSVG(0) != card.MonogramSVG(DefaultMonogramSize) {
+		t.Error("Non-positive size should fall back to default")
+	}
+
+	// Color is stable per name and varies between names
+	other := New().AddName("Jane", "Smith")
+	if card.MonogramSVG(64) != New().AddName("John", "Doe").MonogramSVG(64) {
+		t.Error("Monogram should be deterministic")
+	}
+	if monogramFill(other.MonogramSVG(64)) == monogramFill(card.MonogramSVG(64)) {
+		t.Error("Different names should produce different colors")
+	}
+
+	if !strings.HasPrefix(card.MonogramDataURI(64), "data:image/svg+xml;base64,") {
+		t.Error("MonogramDataURI should return an SVG data URI")
+	}
+}
+
+func TestPreviewMonogramFallback(t *testing.T) {
+	card := New().AddName("John", "Doe")
+
+	if card.Preview().Avatar != card.MonogramDataURI(DefaultMonogramSize) {
+		t.Error("Preview should fall back to monogram avatar without a photo")
+	}
+
+	if New().Preview().Avatar != "" {
+		t.Error("Empty card should have no avatar")
+	}
+}
+
+// monogramFill extracts the background color from a monogram SVG
+func monogramFill(svg string) string {
+	start := strings.Index(svg, "hsl(")
+	end := strings.Index(svg[start:], ")")
+	return svg[start : start+end]
+}
@@ -0,0 +1,265 @@
+// Package badge turns an attendee list into conference badge material.
+//
+// Generate reads a CSV file whose header row names the template parameters,
+// builds a card per attendee with a registered vcard template and produces
+// the .vcf file, an optional QR code PNG and a printable HTML sheet:
+//
+//	result, err := badge.Generate(file, badge.Options{
+//		Template: "employee",
+//		QR:       myQREncoder, // e.g. wrapping github.com/skip2/go-qrcode
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = result.WriteDir("badges")
+package badge
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"go.rumenx.com/vcard"
+)
+
+// DefaultTemplate is the card template used when Options.Template is empty
+const DefaultTemplate = "employee"
+
+// DefaultFilenameTemplate names the files of each badge
+const DefaultFilenameTemplate = "{first}-{last}"
+
+// SheetFilename is the name of the HTML sheet written by WriteDir
+const SheetFilename = "badges.html"
+
+// QREncoder renders content as a QR code PNG
+type QREncoder = vcard.QREncoder
+
+// Options configures Generate
+type Options struct {
+	// Template is the registered vcard template building each card
+	Template string
+
+	// FilenameTemplate names the per-attendee files (see vcard.VCard.Filename)
+	FilenameTemplate string
+
+	// QR renders the QR code of each card; nil skips QR codes
+	QR QREncoder
+
+	// Title is shown at the top of the printable sheet
+	Title string
+}
+
+// Badge is the material generated for one attendee
+type Badge struct {
+	// Params are the CSV values keyed by template parameter
+	Params map[string]string
+
+	// Card is the attendee's card
+	Card *vcard.VCard
+
+	// Name is the file name without extension, unique within the result
+	Name string
+
+	// VCF is the serialized card
+	VCF []byte
+
+	// QR is the QR code PNG, if a QR encoder was configured
+	QR []byte
+}
+
+// Result is the output of Generate
+type Result struct {
+	// Badges holds one entry per valid attendee, in CSV order
+	Badges []Badge
+
+	// Errors reports attendee rows that could not be converted, indexed
+	// from 0 for the first row after the header
+	Errors []vcard.ItemError
+
+	// Sheet is the printable HTML sheet with all badges
+	Sheet []byte
+}
+
+// Generate builds the badges for the attendees in the CSV data
+func Generate(r io.Reader, opts Options) (*Result, error) {
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+	if opts.FilenameTemplate == "" {
+		opts.FilenameTemplate = DefaultFilenameTemplate
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("badge: failed to read CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("badge: CSV has no header row")
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, column := range rows[0] {
+		header[i] = paramName(column)
+	}
+
+	result := &Result{}
+	names := make(map[string]bool)
+	for i, row := range rows[1:] {
+		params := make(map[string]string, len(header))
+		for j, value := range row {
+			if j < len(header) {
+				params[header[j]] = strings.TrimSpace(value)
+			}
+		}
+
+		card, err := vcard.NewFromTemplate(opts.Template, params)
+		if err != nil {
+			result.Errors = append(result.Errors, vcard.ItemError{Index: i, Message: err.Error()})
+			continue
+		}
+
+		content, err := card.String()
+		if err != nil {
+			result.Errors = append(result.Errors, vcard.ItemError{Index: i, Message: err.Error()})
+			continue
+		}
+
+		b := Badge{
+			Params: params,
+			Card:   card,
+			Name:   strings.TrimSuffix(vcard.UniqueFilename(names, card.Filename(opts.FilenameTemplate)), vcard.FileExtension),
+			VCF:    []byte(content),
+		}
+
+		if opts.QR != nil {
+			if b.QR, err = opts.QR(content); err != nil {
+				result.Errors = append(result.Errors, vcard.ItemError{Index: i, Message: "qr code: " + err.Error()})
+				continue
+			}
+		}
+
+		result.Badges = append(result.Badges, b)
+	}
+
+	if result.Sheet, err = renderSheet(opts.Title, result.Badges); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// WriteDir writes every badge's .vcf and .png file and the HTML sheet to dir,
+// creating it if needed
+func (r *Result) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("badge: %w", err)
+	}
+
+	for _, b := range r.Badges {
+		if err := os.WriteFile(filepath.Join(dir, b.Name+".vcf"), b.VCF, 0644); err != nil {
+			return fmt.Errorf("badge: %w", err)
+		}
+		if b.QR != nil {
+			if err := os.WriteFile(filepath.Join(dir, b.Name+".png"), b.QR, 0644); err != nil {
+				return fmt.Errorf("badge: %w", err)
+			}
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, SheetFilename), r.Sheet, 0644); err != nil {
+		return fmt.Errorf("badge: %w", err)
+	}
+	return nil
+}
+
+// paramName converts a CSV column title to a lowerCamelCase template
+// parameter, e.g. "Employee ID" to "employeeId"
+func paramName(column string) string {
+	words := strings.FieldsFunc(column, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var builder strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		builder.WriteString(word)
+	}
+	return builder.String()
+}
+
+// sheetTemplate lays out the badges on A4 pages
+var sheetTemplate = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { size: A4; margin: 10mm; }
+body { font-family: sans-serif; margin: 0; }
+h1 { font-size: 14pt; }
+.sheet { display: grid; grid-template-columns: repeat(2, 1fr); gap: 5mm; }
+.badge { border: 1px dashed #999; height: 60mm; padding: 5mm; box-sizing: border-box; break-inside: avoid; text-align: center; }
+.name { font-size: 20pt; font-weight: bold; }
+.title, .org { font-size: 11pt; }
+.badge img { height: 28mm; margin-top: 3mm; }
+</style>
+</head>
+<body>
+{{if .Title}}<h1>{{.Title}}</h1>{{end}}
+<div class="sheet">
+{{range .Badges}}<div class="badge">
+<div class="name">{{.Name}}</div>
+{{with .JobTitle}}<div class="title">{{.}}</div>{{end}}
+{{with .Organization}}<div class="org">{{.}}</div>{{end}}
+{{with .QR}}<img src="{{.}}" alt="QR code">{{end}}
+</div>
+{{end}}</div>
+</body>
+</html>
+`))
+
+// sheetBadge holds the values shown on a printed badge
+type sheetBadge struct {
+	Name         string
+	JobTitle     string
+	Organization string
+	QR           template.URL
+}
+
+// renderSheet renders the printable HTML sheet
+func renderSheet(title string, badges []Badge) ([]byte, error) {
+	data := struct {
+		Title  string
+		Badges []sheetBadge
+	}{Title: title}
+
+	for _, b := range badges {
+		org := b.Card.GetOrganization()
+		entry := sheetBadge{
+			Name:         b.Card.GetFormattedName(),
+			JobTitle:     org.Title,
+			Organization: org.Name,
+		}
+		if b.QR != nil {
+			entry.QR = template.URL(vcard.EncodeDataURI("image/png", b.QR))
+		}
+		data.Badges = append(data.Badges, entry)
+	}
+
+	var buf bytes.Buffer
+	if err := sheetTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("badge: failed to render sheet: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+package badge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const attendees = `First,Last,Title,Organization,Email,Employee ID
+John,Doe,Engineer,Acme,john@example.com,E1
+Jane,Smith,<CTO>,Globex,jane@example.com,
+,,,,nobody@example.com,
+John,Doe,Designer,Initech,john.doe@example.com,
+`
+
+func TestGenerate(t *testing.T) {
+	var encoded []string
+	qr := func(content string) ([]byte, error) {
+		encoded = append(encoded, content)
+		return []byte("png"), nil
+	}
+
+	result, err := Generate(strings.NewReader(attendees), Options{QR: qr, Title: "DevConf"})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	if len(result.Badges) != 3 {
+		t.Fatalf("Expected 3 badges, got %d", len(result.Badges))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 2 {
+		t.Errorf("Expected error for row 2, got %v", result.Errors)
+	}
+
+	first := result.Badges[0]
+	if first.Name != "John-Doe" || !strings.Contains(string(first.VCF), "X-EMPLOYEE-ID:E1") {
+		t.Errorf("Unexpected first badge: %s %s", first.Name, first.VCF)
+	}
+	if result.Badges[2].Name != "John-Doe-2" {
+		t.Errorf("Expected duplicate name to be made unique, got %s", result.Badges[2].Name)
+	}
+	if len(encoded) != 3 || !strings.HasPrefix(encoded[0], "BEGIN:VCARD") {
+		t.Errorf("Expected QR codes to encode the cards, got %v", encoded)
+	}
+
+	sheet := string(result.Sheet)
+	for _, expected := range []string{"<h1>DevConf</h1>", "John Doe", "&lt;CTO&gt;", `src="data:image/png;base64,cG5n"`} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("Expected %q in sheet", expected)
+		}
+	}
+}
+
+func TestGenerateNameCollisions(t *testing.T) {
+	csv := "First,Last\nJohn,Doe\nJohn,Doe\nJohn,Doe-2\n"
+	result, err := Generate(strings.NewReader(csv), Options{})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	var names []string
+	for _, b := range result.Badges {
+		names = append(names, b.Name)
+	}
+	if got := strings.Join(names, " "); got != "John-Doe John-Doe-2 John-Doe-2-2" {
+		t.Errorf("Expected unique badge names, got %s", got)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := Generate(strings.NewReader(""), Options{}); err == nil {
+		t.Error("Expected error for empty CSV")
+	}
+
+	result, err := Generate(strings.NewReader("first,last\nJohn,Doe\n"), Options{Template: "missing"})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "unknown vcard template") {
+		t.Errorf("Expected unknown template error, got %v", result.Errors)
+	}
+
+	failing := func(string) ([]byte, error) { return nil, errors.New("too long") }
+	result, _ = Generate(strings.NewReader("first,last\nJohn,Doe\n"), Options{QR: failing})
+	if len(result.Badges) != 0 || len(result.Errors) != 1 {
+		t.Errorf("Expected QR failure to be reported, got %v", result.Errors)
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	result, err := Generate(strings.NewReader("first,last\nJohn,Doe\n"), Options{
+		QR: func(string) ([]byte, error) { return []byte("png"), nil },
+	})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	if err := result.WriteDir(dir); err != nil {
+		t.Fatalf("WriteDir() returned error: %v", err)
+	}
+
+	for _, name := range []string{"John-Doe.vcf", "John-Doe.png", SheetFilename} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+}
+
+func TestParamName(t *testing.T) {
+	tests := map[string]string{
+		"First":         "first",
+		"Employee ID":   "employeeId",
+		"postal_code":   "postalCode",
+		" Organization": "organization",
+	}
+	for column, expected := range tests {
+		if got := paramName(column); got != expected {
+			t.Errorf("paramName(%q) = %q, want %q", column, got, expected)
+		}
+	}
+}
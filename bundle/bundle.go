@@ -0,0 +1,193 @@
+// Package bundle extracts vCards from exported address book bundles.
+//
+// Read accepts a single .vcf file, a directory (such as a macOS Contacts
+// .abbu archive or an unpacked export) or a .zip archive and returns every
+// card found in the .vcf files it contains, in path order:
+//
+//	cards, err := bundle.Read("Contacts.abbu")
+//	for _, card := range cards {
+//		fmt.Println(card.Source, len(card.Content))
+//	}
+//
+// macOS stores the live address book of an .abbu archive in a Core Data
+// database; export the contacts as vCards (File > Export > Export vCard) to
+// include them in the archive or the zip file read here.
+//
+// Directories and archives are read with vcard.WalkArchive, so they follow
+// the skip rules, text decoding and vcard.DefaultArchiveLimits of
+// vcard.LoadFromZip.
+package bundle
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+var (
+	// ErrTooManyCards is returned when a stream holds more cards than allowed
+	ErrTooManyCards = errors.New("bundle: too many cards")
+
+	// ErrCardTooLarge is returned when a card exceeds the allowed size
+	ErrCardTooLarge = errors.New("bundle: card too large")
+
+	// ErrNoCards is returned by Read and ReadFS when no vCard is found
+	ErrNoCards = errors.New("bundle: no vCards found")
+)
+
+// Limits bounds the cards read from an untrusted stream. Zero values mean no
+// limit.
+type Limits struct {
+	// MaxCards is the maximum number of cards
+	MaxCards int
+
+	// MaxCardSize is the maximum size of a card in bytes
+	MaxCardSize int
+}
+
+// Card is a vCard found in a bundle
+type Card struct {
+	// Source is the path of the .vcf file within the bundle
+	Source string
+
+	// Content is the vCard text from BEGIN:VCARD to END:VCARD
+	Content string
+}
+
+// Read extracts the vCards from a .vcf file, a directory or a .zip archive.
+// It fails with ErrNoCards when none holds a vCard.
+func Read(name string) ([]Card, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %w", err)
+	}
+
+	switch {
+	case info.IsDir():
+		return ReadFS(os.DirFS(name))
+	case strings.EqualFold(path.Ext(name), ".zip"):
+		archive, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: failed to open archive: %w", err)
+		}
+		defer archive.Close()
+		return ReadFS(archive)
+	default:
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %w", err)
+		}
+		defer file.Close()
+		cards, err := split(info.Name(), file, Limits{})
+		if err == nil && len(cards) == 0 {
+			return nil, fmt.Errorf("%w in %s", ErrNoCards, name)
+		}
+		return cards, err
+	}
+}
+
+// ReadStream extracts the vCards from a .vcf stream, such as an uploaded file,
+// enforcing the limits while reading. Source names the stream in the
+// returned cards.
+func ReadStream(source string, r io.Reader, limits Limits) ([]Card, error) {
+	return split(source, r, limits)
+}
+
+// ReadFS extracts the vCards from every .vcf file in the file system,
+// skipping hidden files and macOS resource forks (__MACOSX). Files are read
+// within the vcard.DefaultArchiveLimits, and ReadFS fails with ErrNoCards
+// when none holds a vCard.
+func ReadFS(fsys fs.FS) ([]Card, error) {
+	limits := vcard.DefaultArchiveLimits
+	var cards []Card
+	var splitErr error
+	err := vcard.WalkArchive(fsys, limits, func(name, text string) error {
+		found, err := split(name, strings.NewReader(text), Limits{MaxCards: limits.Decoder.MaxDecodedCards})
+		if err == nil && limits.Decoder.MaxDecodedCards > 0 && len(cards)+len(found) > limits.Decoder.MaxDecodedCards {
+			err = fmt.Errorf("%w: the bundle holds more than %d cards", ErrTooManyCards, limits.Decoder.MaxDecodedCards)
+		}
+		if err != nil {
+			splitErr = err
+			return err
+		}
+		cards = append(cards, found...)
+		return nil
+	})
+	switch {
+	case splitErr != nil:
+		return nil, splitErr
+	case err != nil:
+		return nil, fmt.Errorf("bundle: %w", err)
+	case len(cards) == 0:
+		return nil, ErrNoCards
+	}
+	return cards, nil
+}
+
+// split returns the cards contained in a .vcf stream. Line endings are
+// normalized to "\n"; folded lines are kept as they are. A card left open at
+// the end of the stream fails with io.ErrUnexpectedEOF.
+func split(source string, r io.Reader, limits Limits) ([]Card, error) {
+	var cards []Card
+	var current strings.Builder
+	depth := 0
+
+	maxLine := 16 * 1024 * 1024
+	if limits.MaxCardSize > 0 && limits.MaxCardSize < maxLine {
+		// a longer line cannot fit in a card; +2 leaves room for CRLF
+		maxLine = limits.MaxCardSize + 2
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, min(64*1024, maxLine)), maxLine)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(cards) == 0 && depth == 0 {
+			// Drop a UTF-8 byte order mark at the start of the file
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		switch {
+		case strings.EqualFold(line, "BEGIN:VCARD"):
+			depth++
+		case depth == 0:
+			// Text outside a card
+			continue
+		}
+
+		if limits.MaxCardSize > 0 && current.Len()+len(line)+1 > limits.MaxCardSize {
+			return nil, fmt.Errorf("%w: card %d in %s exceeds %d bytes", ErrCardTooLarge, len(cards)+1, source, limits.MaxCardSize)
+		}
+		current.WriteString(line + "\n")
+
+		if strings.EqualFold(line, "END:VCARD") {
+			depth--
+			if depth == 0 {
+				if limits.MaxCards > 0 && len(cards) == limits.MaxCards {
+					return nil, fmt.Errorf("%w: %s holds more than %d cards", ErrTooManyCards, source, limits.MaxCards)
+				}
+				cards = append(cards, Card{Source: source, Content: current.String()})
+				current.Reset()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) && limits.MaxCardSize > 0 {
+			return nil, fmt.Errorf("%w: line in %s exceeds %d bytes", ErrCardTooLarge, source, limits.MaxCardSize)
+		}
+		return nil, fmt.Errorf("bundle: failed to read %s: %w", source, err)
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("bundle: card %d in %s is not terminated: %w", len(cards)+1, source, io.ErrUnexpectedEOF)
+	}
+
+	return cards, nil
+}
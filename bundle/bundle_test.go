@@ -0,0 +1,130 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"go.rumenx.com/vcard"
+)
+
+const twoCards = "\ufeffBEGIN:VCARD\r\nVERSION:3.0\r\nFN:John Doe\r\nNOTE:long\r\n  folded\r\nEND:VCARD\r\n" +
+	"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Jane Doe\r\nEND:VCARD\r\n"
+
+func TestReadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Contacts.abbu/Exports/all.vcf":          {Data: []byte(twoCards)},
+		"Contacts.abbu/Exports/single.VCF":       {Data: []byte("begin:vcard\nFN:Jim\nend:vcard\n")},
+		"Contacts.abbu/AddressBook-v22.abcddb":   {Data: []byte("sqlite")},
+		"Contacts.abbu/.hidden.vcf":              {Data: []byte(twoCards)},
+		"__MACOSX/Contacts.abbu/Exports/all.vcf": {Data: []byte(twoCards)},
+	}
+
+	cards, err := ReadFS(fsys)
+	if err != nil {
+		t.Fatalf("ReadFS() returned error: %v", err)
+	}
+
+	if len(cards) != 3 {
+		t.Fatalf("Expected 3 cards, got %d: %v", len(cards), cards)
+	}
+	if cards[0].Source != "Contacts.abbu/Exports/all.vcf" {
+		t.Errorf("Unexpected source: %s", cards[0].Source)
+	}
+	if !strings.HasPrefix(cards[0].Content, "BEGIN:VCARD\nVERSION:3.0\nFN:John Doe\nNOTE:long\n  folded\n") {
+		t.Errorf("Unexpected content: %q", cards[0].Content)
+	}
+	if !strings.Contains(cards[2].Content, "FN:Jim") {
+		t.Errorf("Expected lower-case card to be found, got %q", cards[2].Content)
+	}
+}
+
+func TestReadZipAndFile(t *testing.T) {
+	dir := t.TempDir()
+
+	vcf := filepath.Join(dir, "contacts.vcf")
+	if err := os.WriteFile(vcf, []byte(twoCards), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	archive := filepath.Join(dir, "export.zip")
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	writer := zip.NewWriter(file)
+	entry, _ := writer.Create("export/contacts.vcf")
+	entry.Write([]byte(twoCards))
+	writer.Close()
+	file.Close()
+
+	for _, name := range []string{vcf, archive, dir} {
+		cards, err := Read(name)
+		if err != nil {
+			t.Fatalf("Read(%s) returned error: %v", name, err)
+		}
+		if len(cards) != 2 {
+			t.Errorf("Read(%s): expected 2 cards, got %d", name, len(cards))
+		}
+	}
+
+	if _, err := Read(filepath.Join(dir, "missing.vcf")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestReadStreamLimits(t *testing.T) {
+	cards, err := ReadStream("upload", strings.NewReader(twoCards), Limits{MaxCards: 2, MaxCardSize: 100})
+	if err != nil || len(cards) != 2 || cards[0].Source != "upload" {
+		t.Fatalf("Expected 2 cards within limits, got %v, %v", cards, err)
+	}
+
+	if _, err := ReadStream("upload", strings.NewReader(twoCards), Limits{MaxCards: 1}); !errors.Is(err, ErrTooManyCards) {
+		t.Errorf("Expected ErrTooManyCards, got %v", err)
+	}
+	if _, err := ReadStream("upload", strings.NewReader(twoCards), Limits{MaxCardSize: 40}); !errors.Is(err, ErrCardTooLarge) {
+		t.Errorf("Expected ErrCardTooLarge, got %v", err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.vcf")
+	if err := os.WriteFile(empty, []byte("no cards here\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(empty); !errors.Is(err, ErrNoCards) {
+		t.Errorf("Expected ErrNoCards for a file without cards, got %v", err)
+	}
+	if _, err := ReadFS(fstest.MapFS{"notes.txt": {Data: []byte(twoCards)}}); !errors.Is(err, ErrNoCards) {
+		t.Errorf("Expected ErrNoCards for a bundle without .vcf files, got %v", err)
+	}
+
+	truncated := twoCards[:strings.LastIndex(twoCards, "END:VCARD")]
+	if _, err := ReadStream("upload", strings.NewReader(truncated), Limits{}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF for an unterminated card, got %v", err)
+	}
+}
+
+func TestReadFSLimits(t *testing.T) {
+	// A zip bomb inflating past the archive's file size limit
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	entry, _ := writer.Create("bomb.vcf")
+	entry.Write(bytes.Repeat([]byte("X"), int(vcard.DefaultArchiveLimits.MaxFileSize)+1))
+	writer.Close()
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFS(archive); !errors.Is(err, vcard.ErrLimitExceeded) {
+		t.Errorf("Expected the archive limits to apply, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package vcard
+
+import (
+	"sort"
+	"strings"
+)
+
+// canonicalize rewrites encoded cards in the canonical text format: one
+// unfolded line per property, LF line endings, and the properties between
+// VERSION and END sorted by name (then group), keeping the order of the
+// values of each property
+func canonicalize(content string) string {
+	var builder strings.Builder
+	var properties []ContentLine
+	var lines []string
+
+	for _, line := range unfoldLines(content) {
+		property, err := ParseProperty(line)
+		if err != nil {
+			// Encoded cards always parse; keep anything else in place
+			property = ContentLine{Name: strings.ToUpper(line)}
+		}
+
+		switch property.Name {
+		case "BEGIN", "VERSION":
+			builder.WriteString(line + "\n")
+		case "END":
+			order := make([]int, len(lines))
+			for i := range order {
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool {
+				a, b := properties[order[i]], properties[order[j]]
+				if a.Name != b.Name {
+					return a.Name < b.Name
+				}
+				return a.Group < b.Group
+			})
+			for _, i := range order {
+				builder.WriteString(lines[i] + "\n")
+			}
+			builder.WriteString(line + "\n")
+			properties, lines = properties[:0], lines[:0]
+		default:
+			properties = append(properties, property)
+			lines = append(lines, line)
+		}
+	}
+	return builder.String()
+}
+
+// FromCanonical converts cards in the canonical text format written by
+// Encoder.Canonical back to interchange form by folding every line at 75
+// octets. The content is otherwise kept as is, so unknown properties
+// survive the conversion.
+func FromCanonical(text string) string {
+	var builder strings.Builder
+	for _, line := range unfoldLines(text) {
+		builder.WriteString(foldLine(line) + "\n")
+	}
+	return builder.String()
+}
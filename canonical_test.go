@@ -0,0 +1,63 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	card := New().AddName("Jane", "Doe").SetUID("1").
+		AddEmail("jane@example.com").AddEmail("doe@example.com").
+		AddNote(strings.Repeat("A long note, ", 20)).
+		AddCustomProperties(map[string]string{"X-ZETA": "z", "X-ALPHA": "a", "X-MIDDLE": "m"})
+
+	encode := func() string {
+		var builder strings.Builder
+		if err := NewEncoder(&builder).Canonical(true).Encode(card); err != nil {
+			t.Fatalf("Encode() returned error: %v", err)
+		}
+		return builder.String()
+	}
+
+	content := encode()
+	for i := 0; i < 10; i++ {
+		if again := encode(); again != content {
+			t.Fatalf("Canonical output is not stable:\n%s\n%s", content, again)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if lines[0] != "BEGIN:VCARD" || lines[1] != "VERSION:3.0" || lines[len(lines)-1] != "END:VCARD" {
+		t.Errorf("Expected BEGIN, VERSION first and END last:\n%s", content)
+	}
+	names := make([]string, 0, len(lines))
+	for _, line := range lines[2 : len(lines)-1] {
+		names = append(names, strings.SplitN(strings.SplitN(line, ":", 2)[0], ";", 2)[0])
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("Properties not sorted: %v", names)
+			break
+		}
+	}
+	if !strings.Contains(content, "EMAIL;TYPE=INTERNET:jane@example.com\nEMAIL;TYPE=INTERNET:doe@example.com\n") {
+		t.Errorf("Expected the email order to be kept:\n%s", content)
+	}
+	if strings.Contains(content, "\r") || strings.Contains(content, "\n ") {
+		t.Errorf("Expected unfolded LF lines:\n%q", content)
+	}
+
+	interchange := FromCanonical(content)
+	for _, line := range strings.Split(interchange, "\n") {
+		if len(strings.TrimSuffix(line, "\r")) > 75 {
+			t.Errorf("Line longer than 75 octets after FromCanonical: %q", line)
+		}
+	}
+	parsed, err := Parse(interchange)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if changes := Diff(card, parsed); len(changes) != 0 {
+		t.Errorf("Conversion lost data: %+v", changes)
+	}
+}
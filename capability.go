@@ -0,0 +1,128 @@
+package vcard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// propertyVersions is the capability matrix of the properties that are not
+// defined by every vCard version, listing the versions defining each one.
+// Properties missing from the matrix are defined by all versions. Writers
+// consult the matrix through Supports, and Lint reports data that the
+// card's version cannot carry.
+var propertyVersions = map[string][]Version{
+	// Introduced by RFC 6350 and its extensions (RFC 6474, RFC 6715,
+	// RFC 8605, RFC 9554)
+	"ANNIVERSARY":   {Version40},
+	"BIRTHPLACE":    {Version40},
+	"CLIENTPIDMAP":  {Version40},
+	"CONTACT-URI":   {Version40},
+	"CREATED":       {Version40},
+	"DEATHDATE":     {Version40},
+	"DEATHPLACE":    {Version40},
+	"EXPERTISE":     {Version40},
+	"GENDER":        {Version40},
+	"GRAMGENDER":    {Version40},
+	"HOBBY":         {Version40},
+	"INTEREST":      {Version40},
+	"JSPROP":        {Version40},
+	"KIND":          {Version40},
+	"LANG":          {Version40},
+	"LANGUAGE":      {Version40},
+	"MEMBER":        {Version40},
+	"ORG-DIRECTORY": {Version40},
+	"PRONOUNS":      {Version40},
+	"RELATED":       {Version40},
+	"SOCIALPROFILE": {Version40},
+	"XML":           {Version40},
+
+	// Removed by RFC 6350
+	"AGENT":       {Version30},
+	"CLASS":       {Version30},
+	"MAILER":      {Version30},
+	"NAME":        {Version30},
+	"PROFILE":     {Version30},
+	"SORT-STRING": {Version30},
+}
+
+// Supports reports whether the vCard version defines the property. Extension
+// (X-) properties and properties missing from the capability matrix are
+// supported by every version.
+func Supports(version Version, property string) bool {
+	versions, ok := propertyVersions[strings.ToUpper(property)]
+	if !ok {
+		return true
+	}
+	for _, supported := range versions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredVersions describes the versions defining a property, e.g. "vCard 4.0"
+func requiredVersions(property string) string {
+	versions := propertyVersions[strings.ToUpper(property)]
+	names := make([]string, len(versions))
+	for i, version := range versions {
+		names[i] = "vCard " + version.String()
+	}
+	return strings.Join(names, " or ")
+}
+
+// versionWarnings reports the data held by the card that its version does
+// not define. Dedicated fields are dropped by the writers; custom properties
+// are written as supplied but may be ignored by clients.
+func (v *VCard) versionWarnings() []Warning {
+	var warnings []Warning
+
+	if v.kind != "" && !Supports(v.version, "KIND") {
+		warnings = append(warnings, Warning{
+			Property: "KIND",
+			Message:  fmt.Sprintf("requires %s and is not written", requiredVersions("KIND")),
+		})
+	}
+
+	if v.version != Version40 && v.bdayText != "" {
+		warnings = append(warnings, Warning{
+			Property: "BDAY",
+			Message:  "free-text birthday requires vCard 4.0 and is not written",
+		})
+	}
+
+	if !Supports(v.version, "ANNIVERSARY") {
+		switch {
+		case v.annivText != "":
+			warnings = append(warnings, Warning{
+				Property: "ANNIVERSARY",
+				Message:  "free-text anniversary requires vCard 4.0 and is not written",
+			})
+		case v.anniversary != nil:
+			warnings = append(warnings, Warning{
+				Property: "ANNIVERSARY",
+				Message:  fmt.Sprintf("requires %s and is not written", requiredVersions("ANNIVERSARY")),
+			})
+		}
+	}
+
+	warnings = append(warnings, v.alternateWarnings(true)...)
+
+	names := make([]string, 0, len(v.customProps))
+	for name := range v.customProps {
+		if isCustomPropertyAllowed(name) && !Supports(v.version, name) {
+			names = append(names, strings.ToUpper(name))
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		warnings = append(warnings, Warning{
+			Property: name,
+			Message:  fmt.Sprintf("defined by %s only; clients may ignore it", requiredVersions(name)),
+		})
+	}
+
+	return warnings
+}
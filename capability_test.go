@@ -0,0 +1,76 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		version  Version
+		property string
+		expected bool
+	}{
+		{Version30, "KIND", false},
+		{Version40, "kind", true},
+		{Version30, "ANNIVERSARY", false},
+		{Version40, "ANNIVERSARY", true},
+		{Version30, "SORT-STRING", true},
+		{Version40, "SORT-STRING", false},
+		{Version30, "EMAIL", true},
+		{Version40, "X-CUSTOM", true},
+	}
+
+	for _, test := range tests {
+		if got := Supports(test.version, test.property); got != test.expected {
+			t.Errorf("Supports(%s, %s) = %v, want %v", test.version, test.property, got, test.expected)
+		}
+	}
+}
+
+func TestLintVersionCapabilities(t *testing.T) {
+	card := New().
+		AddName("Jane", "Doe").
+		SetKind(KindIndividual).
+		AddAnniversary(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)).
+		AddCustomProperty("GENDER", "F").
+		AddCustomProperty("NICKNAME", "JD")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if strings.Contains(content, "KIND:") || strings.Contains(content, "ANNIVERSARY:") {
+		t.Errorf("Expected 4.0 properties to be dropped from 3.0 output:\n%s", content)
+	}
+
+	var properties []string
+	for _, warning := range card.Lint() {
+		properties = append(properties, warning.Property)
+	}
+	if strings.Join(properties, ",") != "KIND,ANNIVERSARY,GENDER" {
+		t.Errorf("Expected warnings for KIND, ANNIVERSARY and GENDER, got %v", card.Lint())
+	}
+
+	if err := card.ValidateStrict(); err == nil || !strings.Contains(err.Error(), "KIND") {
+		t.Errorf("Expected strict validation to reject KIND on 3.0, got %v", err)
+	}
+
+	card.SetVersion(Version40)
+	if warnings := card.Lint(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings for 4.0, got %v", warnings)
+	}
+	if err := card.ValidateStrict(); err != nil {
+		t.Errorf("Expected 4.0 card to pass strict validation, got %v", err)
+	}
+}
+
+func TestLintRemovedProperties(t *testing.T) {
+	card := NewWithVersion(Version40).AddName("Jane", "Doe").AddCustomProperty("SORT-STRING", "Doe")
+
+	warnings := card.Lint()
+	if len(warnings) != 1 || warnings[0].String() != "SORT-STRING: defined by vCard 3.0 only; clients may ignore it" {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/server"
+	"go.rumenx.com/vcard/vcardtest"
+)
+
+func TestSelfcheck(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"selfcheck", "-v"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d:\n%s%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "ok   rfc6350.vcf") || !strings.Contains(stdout.String(), ", 0 failed") {
+		t.Errorf("Unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"frobnicate"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `unknown command "frobnicate"`) {
+		t.Errorf("Unexpected error output: %q", stderr.String())
+	}
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 without a command, got %d", code)
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	dir := t.TempDir()
+	for _, spec := range []string{"dir:" + filepath.Join(dir, "cards"), "file:" + filepath.Join(dir, "cards.vcf")} {
+		contacts, err := openStore(spec)
+		if err != nil {
+			t.Errorf("openStore(%q) returned error: %v", spec, err)
+			continue
+		}
+		contacts.Close()
+	}
+
+	for _, spec := range []string{"dir", "dir:", "ldap:cards"} {
+		if _, err := openStore(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestOpenStoreSQLite(t *testing.T) {
+	spec := "sqlite:" + filepath.Join(t.TempDir(), "cards.db")
+	contacts, err := openStore(spec)
+	if !slices.Contains(sql.Drivers(), "sqlite") {
+		if err == nil || !strings.Contains(err.Error(), "-tags sqlite") {
+			t.Errorf("Expected a rebuild hint without the sqlite tag, got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("openStore(%q) returned error: %v", spec, err)
+	}
+	defer contacts.Close()
+
+	card := vcard.New().SetUID("jane").SetFormattedName("Jane Doe")
+	if err := contacts.Put(card); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if _, err := contacts.Get("jane"); err != nil {
+		t.Errorf("Get() returned error: %v", err)
+	}
+}
+
+func TestServeHandler(t *testing.T) {
+	contacts, err := openStore("dir:" + t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := serveHandler(contacts, "me", "secret")
+
+	content, _ := vcard.New().AddName("Jane", "Doe").SetUID("jane").String()
+	req := httptest.NewRequest(http.MethodPut, "/contacts/jane", strings.NewReader(content))
+	req.SetBasicAuth("me", "secret")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, server.AddressBookPath+"jane.vcf", nil)
+	req.SetBasicAuth("me", "secret")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Body.String() != content {
+		t.Errorf("Expected the card over CardDAV, got %d\n%s", rr.Code, rr.Body)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/contacts/jane", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", rr.Code)
+	}
+}
+
+func TestServeFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"serve", "-tls-cert", "cert.pem"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a certificate without key, got %d", code)
+	}
+	t.Setenv(PasswordEnv, "")
+	if code := run([]string{"serve", "-user", "me"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for -user without password, got %d", code)
+	}
+
+	for _, addr := range []string{":8080", "0.0.0.0:8080", "192.0.2.1:8080", "[::]:8080", "contacts.example.com:8080"} {
+		stderr.Reset()
+		if code := run([]string{"serve", "-addr", addr}, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2 for unauthenticated %s, got %d", addr, code)
+		}
+		if !strings.Contains(stderr.String(), "-insecure") {
+			t.Errorf("Expected the error to mention -insecure, got %q", stderr.String())
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080":   true,
+		"127.1.2.3:80":     true,
+		"[::1]:8080":       true,
+		"localhost:8080":   true,
+		":8080":            false,
+		"0.0.0.0:8080":     false,
+		"[::]:8080":        false,
+		"192.0.2.1:8080":   false,
+		"example.com:8080": false,
+		"127.0.0.1":        false,
+	}
+	for addr, want := range tests {
+		if got := isLoopback(addr); got != want {
+			t.Errorf("isLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+// writeBook writes the cards to a .vcf file in dir and returns its path
+func writeBook(t *testing.T, dir, name string, cards ...*vcard.VCard) string {
+	t.Helper()
+	var content strings.Builder
+	for _, card := range cards {
+		text, err := card.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content.WriteString(text)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	jane := vcard.New().AddName("Jane", "Doe").SetUID("jane").AddEmail("jane@example.com")
+	john := vcard.New().AddName("John", "Doe").SetUID("john")
+	old := writeBook(t, dir, "old.vcf", jane, john)
+	current := writeBook(t, dir, "new.vcf", jane.Clone().AddEmail("jane@work.example.com"), vcard.New().AddName("Jack", "Doe"))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"diff", old, current}, &stdout, &stderr); code != 1 {
+		t.Fatalf("Expected exit code 1, got %d: %s", code, stderr.String())
+	}
+	for _, expected := range []string{"- john (John Doe)", "~ jane (Jane Doe)", "    +EMAIL: jane@work.example.com", "+ FN:Jack Doe (Jack Doe)"} {
+		if !strings.Contains(stdout.String(), expected+"\n") {
+			t.Errorf("Expected %q in output:\n%s", expected, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := run([]string{"diff", old, old}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("Expected no differences, got %d:\n%s", code, stdout.String())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	jane := vcard.New().AddName("Jane", "Doe").SetUID("jane").AddEmail("jane@example.com")
+	john := vcard.New().AddName("John", "Doe").SetUID("john")
+	jack := vcard.New().AddName("Jack", "Doe").SetUID("jack")
+
+	base := writeBook(t, dir, "base.vcf", jane, john)
+	ours := writeBook(t, dir, "ours.vcf", jane.Clone().AddEmail("jane@work.example.com"), john, jack)
+	theirs := writeBook(t, dir, "theirs.vcf", jane.Clone().AddPhone("+1 555 0100"))
+	output := filepath.Join(dir, "merged.vcf")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"merge", "-o", output, base, ours, theirs}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	merged, err := readBook(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(merged.keys, ",") != "jane,jack" {
+		t.Errorf("Expected jane and jack, with john deleted by theirs, got %v", merged.keys)
+	}
+	if card := merged.cards["jane"]; len(card.GetEmails()) != 2 || len(card.GetPhones()) != 1 {
+		t.Errorf("Expected both sides' changes to jane, got %v", vcard.Diff(jane, card))
+	}
+
+	dryRun := filepath.Join(dir, "dry-run.vcf")
+	stdout.Reset()
+	if code := run([]string{"merge", "-n", "-o", dryRun, base, ours, theirs}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0 for a dry run, got %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(dryRun); !os.IsNotExist(err) {
+		t.Errorf("Expected a dry run not to write %s, got %v", dryRun, err)
+	}
+	for _, expected := range []string{"~ jane (Jane Doe)\n    +TEL: +1 555 0100\n", "- john (John Doe)\n", "0 added, 1 updated, 1 deleted\n"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("Expected %q in dry run report:\n%s", expected, stdout.String())
+		}
+	}
+
+	conflicting := writeBook(t, dir, "conflict.vcf", jane.Clone().AddName("Janet", "Doe"), john)
+	renamed := writeBook(t, dir, "renamed.vcf", jane.Clone().AddName("Jane", "Smith"), john)
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"merge", base, conflicting, renamed}, &stdout, &stderr); code != 1 {
+		t.Fatalf("Expected exit code 1 for conflicts, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "CONFLICT jane: FN") || !strings.Contains(stdout.String(), "FN:Janet Doe") {
+		t.Errorf("Unexpected merge output:\n%s\n%s", stdout.String(), stderr.String())
+	}
+}
+
+func TestMergeWithoutBase(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.vcf")
+	if err := os.WriteFile(base, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	ours := writeBook(t, dir, "ours.vcf", vcard.New().SetUID("jane").AddName("Jane", "Doe"))
+	theirs := writeBook(t, dir, "theirs.vcf", vcard.New().SetUID("john").AddName("John", "Doe"))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"merge", base, ours, theirs}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected an empty base to merge, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "FN:Jane Doe") || !strings.Contains(stdout.String(), "FN:John Doe") {
+		t.Errorf("Expected both cards, got:\n%s", stdout.String())
+	}
+
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("not a vCard\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"diff", notes, ours}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a file without cards, got %d", code)
+	}
+}
+
+func TestMergeKeepsUntouchedCards(t *testing.T) {
+	dir := t.TempDir()
+	// Two UID-less cards share the FN, and the first is written by hand
+	handmade := "BEGIN:VCARD\nVERSION:3.0\nFN:Sam Lee\nN:Lee;Sam;;;\nitem1.EMAIL;type=INTERNET:sam@example.com\nEND:VCARD\n"
+	other, _ := vcard.New().AddName("Sam", "Lee").AddEmail("sam@work.example.com").String()
+	edited, _ := vcard.New().AddName("Sam", "Lee").AddEmail("sam@work.example.com").AddPhone("+1 555 0100").String()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("base.vcf", handmade+other)
+	ours := write("ours.vcf", handmade+other)
+	theirs := write("theirs.vcf", handmade+edited)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"merge", base, ours, theirs}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if stdout.String() != handmade+edited {
+		t.Errorf("Expected the untouched card byte for byte and their edit, got:\n%s", stdout.String())
+	}
+}
+
+func TestVectors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "vectors")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"vectors", "-seed", "9", "-n", "6", "-o", dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 || entries[0].Name() != "000-folding.vcf" {
+		t.Fatalf("Expected 6 vector files, got %v", entries)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "005-folding.vcf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != vcardtest.Vectors(9, 6)[5].Content {
+		t.Error("Expected the file to hold the vector content")
+	}
+
+	if code := run([]string{"vectors", "-n", "-1"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a negative count, got %d", code)
+	}
+}
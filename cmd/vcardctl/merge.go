@@ -0,0 +1,248 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/bundle"
+)
+
+// book is the cards of a .vcf file by key, in file order, with the text
+// they were read from
+type book struct {
+	keys  []string
+	cards map[string]*vcard.VCard
+	texts map[string]string
+}
+
+// readBook reads the cards of a .vcf file, directory or zip archive. Cards
+// are matched across files by UID, or by FN when they have none; the second
+// and later UID-less cards with the same FN are told apart by their position
+// among them.
+func readBook(name string) (*book, error) {
+	b := &book{cards: make(map[string]*vcard.VCard), texts: make(map[string]string)}
+	// git passes an empty file as the base of files added on both sides
+	if info, err := os.Stat(name); err == nil && info.Mode().IsRegular() && info.Size() == 0 {
+		return b, nil
+	}
+
+	found, err := bundle.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range found {
+		card, err := vcard.Parse(entry.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: card %d: %w", name, i+1, err)
+		}
+		key := cardKey(card)
+		if _, ok := b.cards[key]; ok && card.GetUID() != "" {
+			return nil, fmt.Errorf("%s: duplicate card %s", name, key)
+		}
+		for n := 2; b.cards[key] != nil; n++ {
+			key = fmt.Sprintf("%s#%d", cardKey(card), n)
+		}
+		b.keys = append(b.keys, key)
+		b.cards[key] = card
+		b.texts[key] = entry.Content
+	}
+	return b, nil
+}
+
+// cardKey identifies a card across versions of a book
+func cardKey(card *vcard.VCard) string {
+	if uid := card.GetUID(); uid != "" {
+		return uid
+	}
+	return "FN:" + card.GetFormattedName()
+}
+
+// diff prints the changes between two books and exits with 1 when they
+// differ, like diff(1)
+func diff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(stderr, "usage: vcardctl diff OLD NEW")
+		return 2
+	}
+
+	old, err := readBook(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "vcardctl: %v\n", err)
+		return 2
+	}
+	current, err := readBook(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "vcardctl: %v\n", err)
+		return 2
+	}
+
+	differ := false
+	for _, key := range old.keys {
+		if _, ok := current.cards[key]; !ok {
+			differ = true
+			printChange(stdout, "-", key, old.cards[key], nil)
+		}
+	}
+	for _, key := range current.keys {
+		card := current.cards[key]
+		changes := vcard.Diff(old.cards[key], card)
+		switch {
+		case old.cards[key] == nil:
+			printChange(stdout, "+", key, card, changes)
+		case len(changes) > 0:
+			printChange(stdout, "~", key, card, changes)
+		default:
+			continue
+		}
+		differ = true
+	}
+
+	if differ {
+		return 1
+	}
+	return 0
+}
+
+// printChange prints an added (+), changed (~) or removed (-) card with its
+// property changes
+func printChange(w io.Writer, marker, key string, card *vcard.VCard, changes []vcard.Change) {
+	fmt.Fprintf(w, "%s %s (%s)\n", marker, key, card.GetFormattedName())
+	for _, change := range changes {
+		for _, value := range change.Removed {
+			fmt.Fprintf(w, "    -%s: %s\n", change.Property, value)
+		}
+		for _, value := range change.Added {
+			fmt.Fprintf(w, "    +%s: %s\n", change.Property, value)
+		}
+	}
+}
+
+// merge merges the changes from BASE to OURS and from BASE to THEIRS and
+// exits with 1 when there are conflicts. The signature suits git merge
+// drivers: vcardctl merge -o %A %O %A %B. With -n nothing is written; the
+// changes the merge makes to OURS are listed as diff does instead.
+func merge(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", "", "write the merged book to this file instead of stdout")
+	dryRun := flags.Bool("n", false, "dry run: list the changes to OURS without writing the merged book")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 3 {
+		fmt.Fprintln(stderr, "usage: vcardctl merge [-n] [-o FILE] BASE OURS THEIRS")
+		return 2
+	}
+
+	var books [3]*book
+	for i := range books {
+		var err error
+		if books[i], err = readBook(flags.Arg(i)); err != nil {
+			fmt.Fprintf(stderr, "vcardctl: %v\n", err)
+			return 2
+		}
+	}
+	base, ours, theirs := books[0], books[1], books[2]
+
+	var merged, report strings.Builder
+	conflicts, added, updated, deleted := 0, 0, 0, 0
+	// write keeps the text of a card either side has unchanged, so merging
+	// does not reformat cards nobody edited; like the rest of the output it
+	// has LF line endings
+	write := func(key string, card *vcard.VCard) bool {
+		for _, side := range []*book{ours, theirs} {
+			if original := side.cards[key]; original != nil && len(vcard.Diff(original, card)) == 0 {
+				merged.WriteString(side.texts[key])
+				return true
+			}
+		}
+		content, err := card.String()
+		if err != nil {
+			fmt.Fprintf(stderr, "vcardctl: %s: %v\n", key, err)
+			return false
+		}
+		merged.WriteString(content)
+		return true
+	}
+	conflict := func(key, format string, args ...any) {
+		conflicts++
+		fmt.Fprintf(stderr, "CONFLICT %s: "+format+"\n", append([]any{key}, args...)...)
+	}
+
+	for _, key := range ours.keys {
+		our, their, ancestor := ours.cards[key], theirs.cards[key], base.cards[key]
+		switch {
+		case their == nil && ancestor == nil:
+			// Added by us
+		case their == nil && len(vcard.Diff(ancestor, our)) == 0:
+			// Deleted by them
+			deleted++
+			printChange(&report, "-", key, our, nil)
+			continue
+		case their == nil:
+			conflict(key, "deleted in theirs, modified in ours; keeping ours")
+		default:
+			result, cardConflicts, err := vcard.Merge3(ancestor, our, their)
+			if err != nil {
+				fmt.Fprintf(stderr, "vcardctl: %s: %v\n", key, err)
+				return 2
+			}
+			for _, c := range cardConflicts {
+				conflict(key, "%s: base %q, ours %q, theirs %q; keeping ours", c.Property, c.Base, c.Ours, c.Theirs)
+			}
+			if changes := vcard.Diff(our, result); len(changes) > 0 {
+				updated++
+				printChange(&report, "~", key, result, changes)
+			}
+			our = result
+		}
+		if !write(key, our) {
+			return 2
+		}
+	}
+	for _, key := range theirs.keys {
+		their, ancestor := theirs.cards[key], base.cards[key]
+		switch {
+		case ours.cards[key] != nil:
+			// Merged above
+			continue
+		case ancestor != nil && len(vcard.Diff(ancestor, their)) == 0:
+			// Deleted by us
+			continue
+		case ancestor != nil:
+			conflict(key, "deleted in ours, modified in theirs; keeping theirs")
+		}
+		added++
+		printChange(&report, "+", key, their, vcard.Diff(nil, their))
+		if !write(key, their) {
+			return 2
+		}
+	}
+
+	if *dryRun {
+		io.WriteString(stdout, report.String())
+		fmt.Fprintf(stdout, "%d added, %d updated, %d deleted\n", added, updated, deleted)
+	} else if *output != "" {
+		if err := os.WriteFile(*output, []byte(merged.String()), 0644); err != nil {
+			fmt.Fprintf(stderr, "vcardctl: %v\n", err)
+			return 2
+		}
+	} else {
+		io.WriteString(stdout, merged.String())
+	}
+
+	if conflicts > 0 {
+		fmt.Fprintf(stderr, "%d conflicts\n", conflicts)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.rumenx.com/vcard/server"
+	"go.rumenx.com/vcard/store"
+)
+
+// PasswordEnv holds the password for -user, kept out of the process list
+const PasswordEnv = "VCARDCTL_PASSWORD"
+
+// serve runs the REST and CardDAV server over a contact store until
+// interrupted
+func serve(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "127.0.0.1:8080", "listen address; addresses other than loopback need -user or -insecure")
+	spec := flags.String("store", "dir:contacts", "contact store: dir:PATH, file:PATH or sqlite:PATH")
+	certFile := flags.String("tls-cert", "", "TLS certificate file; serves HTTPS with -tls-key")
+	keyFile := flags.String("tls-key", "", "TLS private key file")
+	user := flags.String("user", "", "require basic authentication as this user, with the password in $"+PasswordEnv)
+	insecure := flags.Bool("insecure", false, "serve without authentication on addresses other than loopback")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*certFile == "") != (*keyFile == "") {
+		fmt.Fprintln(stderr, "vcardctl: -tls-cert and -tls-key must be given together")
+		return 2
+	}
+	password := os.Getenv(PasswordEnv)
+	if *user != "" && password == "" {
+		fmt.Fprintf(stderr, "vcardctl: -user needs a password in $%s\n", PasswordEnv)
+		return 2
+	}
+	// Anyone reaching the server can change the contacts without -user
+	if *user == "" && !*insecure && !isLoopback(*addr) {
+		fmt.Fprintf(stderr, "vcardctl: refusing to serve %s without authentication, set -user or pass -insecure\n", *addr)
+		return 2
+	}
+
+	contacts, err := openStore(*spec)
+	if err != nil {
+		fmt.Fprintf(stderr, "vcardctl: %v\n", err)
+		return 1
+	}
+	defer contacts.Close()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           serveHandler(contacts, *user, password),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	scheme := "http"
+	if *certFile != "" {
+		scheme = "https"
+	}
+	fmt.Fprintf(stdout, "serving %s on %s://%s (REST under /contacts/, CardDAV at %s)\n", *spec, scheme, *addr, server.AddressBookPath)
+
+	if *certFile != "" {
+		err = srv.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "vcardctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// serveHandler mounts the REST API under /contacts/ and CardDAV everywhere
+// else, behind basic authentication when user is set
+func serveHandler(contacts store.ContactStore, user, password string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/contacts/", http.StripPrefix("/contacts", server.Contacts(contacts)))
+	mux.Handle("/", server.CardDAV(contacts))
+
+	if user == "" {
+		return mux
+	}
+	return server.BasicAuth(user, password)(mux)
+}
+
+// isLoopback reports whether the listen address only accepts connections
+// from the local machine. An empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// openStore opens the store described by a KIND:PATH spec
+func openStore(spec string) (store.ContactStore, error) {
+	kind, path, ok := strings.Cut(spec, ":")
+	if !ok || path == "" {
+		return nil, fmt.Errorf("invalid store %q, expected dir:PATH, file:PATH or sqlite:PATH", spec)
+	}
+
+	switch kind {
+	case "dir":
+		return store.OpenDir(path)
+	case "file":
+		return store.Open(path)
+	case "sqlite":
+		// The driver is registered by the build-tagged sqlite.go
+		if !slices.Contains(sql.Drivers(), "sqlite") {
+			return nil, errors.New("sqlite support is not compiled in, rebuild with -tags sqlite")
+		}
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			return nil, err
+		}
+		contacts, err := store.NewSQLStore(db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		return contacts, nil
+	default:
+		return nil, fmt.Errorf("unknown store kind %q, expected dir, file or sqlite", kind)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build sqlite
+
+package main
+
+// The sqlite store needs a database/sql driver registered as "sqlite". It is
+// kept behind a build tag so default builds of vcardctl do not link a SQLite
+// engine:
+//
+//	go build -tags sqlite ./cmd/vcardctl
+import _ "modernc.org/sqlite"
@@ -0,0 +1,67 @@
+package vcard
+
+// CompletenessWeights sets how much each field contributes to Completeness.
+// Weights are scaled to add up to one; fields with a zero weight are ignored.
+type CompletenessWeights struct {
+	Name         float64
+	Email        float64
+	Phone        float64
+	Address      float64
+	Organization float64
+	Photo        float64
+	URL          float64
+	Birthday     float64
+}
+
+// DefaultCompletenessWeights favours the fields used to reach a contact
+var DefaultCompletenessWeights = CompletenessWeights{
+	Name:         0.2,
+	Email:        0.2,
+	Phone:        0.2,
+	Address:      0.1,
+	Organization: 0.1,
+	Photo:        0.1,
+	URL:          0.05,
+	Birthday:     0.05,
+}
+
+// Completeness scores how complete the card is, from 0 (no fields) to 1
+// (every weighted field set), using DefaultCompletenessWeights. Low scores
+// point to records worth enriching.
+func (v *VCard) Completeness() float64 {
+	return v.CompletenessWithWeights(DefaultCompletenessWeights)
+}
+
+// CompletenessWithWeights scores the card like Completeness with custom
+// weights
+func (v *VCard) CompletenessWithWeights(weights CompletenessWeights) float64 {
+	fields := []struct {
+		weight float64
+		set    bool
+	}{
+		{weights.Name, v.name.First != "" || v.name.Last != "" || v.fn != ""},
+		{weights.Email, len(v.emails) > 0},
+		{weights.Phone, len(v.phones) > 0},
+		{weights.Address, len(v.addresses) > 0},
+		{weights.Organization, v.organization.Name != ""},
+		{weights.Photo, v.photo != ""},
+		{weights.URL, len(v.urls) > 0},
+		{weights.Birthday, v.birthday != nil || v.bdayText != ""},
+	}
+
+	var score, total float64
+	for _, field := range fields {
+		if field.weight <= 0 {
+			continue
+		}
+		total += field.weight
+		if field.set {
+			score += field.weight
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return score / total
+}
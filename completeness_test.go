@@ -0,0 +1,38 @@
+package vcard
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompleteness(t *testing.T) {
+	if score := New().Completeness(); score != 0 {
+		t.Errorf("Expected empty card to score 0, got %v", score)
+	}
+
+	card := New().AddName("Jane", "Doe").AddEmail("jane@example.com").AddPhone("+15551234567")
+	if score := card.Completeness(); math.Abs(score-0.6) > 1e-9 {
+		t.Errorf("Expected 0.6, got %v", score)
+	}
+
+	card.AddAddress("1 Main St", "Springfield", "", "", "").
+		AddOrganization("Acme").
+		AddPhoto("https://example.com/jane.jpg").
+		AddURL("https://example.com").
+		SetBirthdayText("circa 1990")
+	if score := card.Completeness(); math.Abs(score-1) > 1e-9 {
+		t.Errorf("Expected complete card to score 1, got %v", score)
+	}
+}
+
+func TestCompletenessWithWeights(t *testing.T) {
+	card := New().AddName("Jane", "Doe").AddEmail("jane@example.com")
+
+	weights := CompletenessWeights{Email: 3, Phone: 1}
+	if score := card.CompletenessWithWeights(weights); score != 0.75 {
+		t.Errorf("Expected 0.75, got %v", score)
+	}
+	if score := card.CompletenessWithWeights(CompletenessWeights{}); score != 0 {
+		t.Errorf("Expected 0 without weights, got %v", score)
+	}
+}
@@ -0,0 +1,212 @@
+package vcard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// ContentLine is a parsed vCard content line (RFC 6350 section 3.3):
+// [group "."] name *(";" param) ":" value
+type ContentLine struct {
+	// Group is the upper-cased property group, e.g. "ITEM1"; empty when the
+	// line has none
+	Group string
+
+	// Name is the upper-cased property name, e.g. "TEL"
+	Name string
+
+	// Params are the decoded parameters
+	Params Params
+
+	// Value is the raw value, still escaped
+	Value string
+}
+
+// Text returns the unescaped value
+func (c ContentLine) Text() string {
+	return unescapeValue(c.Value)
+}
+
+// Components splits a structured value (N, ADR, ORG) on unescaped
+// semicolons and unescapes each component
+func (c ContentLine) Components() []string {
+	return splitComponents(c.Value)
+}
+
+// UnfoldLines returns an iterator over the logical lines of vCard text,
+// joining folded continuation lines (a line break followed by a space or
+// tab) and skipping empty lines. CRLF, LF and bare CR line breaks are
+// accepted, also mixed. Lines are joined before values are unescaped, so
+// folds inside escape sequences and multi-byte characters written by other
+// libraries are read correctly. A read error is yielded after the lines
+// read before it and ends the iteration:
+//
+//	for line, err := range vcard.UnfoldLines(file) {
+//		if err != nil {
+//			return err
+//		}
+//		property, err := vcard.ParseProperty(line)
+//		...
+//	}
+func UnfoldLines(r io.Reader) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		reader := bufio.NewReader(r)
+		var current strings.Builder
+		pending := false
+
+		// emit handles one physical line, yielding the previous logical line
+		// when this one does not continue it
+		emit := func(line string) bool {
+			switch {
+			case line == "":
+				return true
+			case (line[0] == ' ' || line[0] == '\t') && pending:
+				current.WriteString(line[1:])
+				return true
+			case pending && !yield(current.String(), nil):
+				return false
+			}
+			current.Reset()
+			current.WriteString(line)
+			pending = true
+			return true
+		}
+
+		for {
+			chunk, err := reader.ReadString('\n')
+			chunk = strings.TrimSuffix(strings.TrimSuffix(chunk, "\n"), "\r")
+			for _, line := range strings.Split(chunk, "\r") {
+				if !emit(line) {
+					return
+				}
+			}
+
+			if err != nil {
+				if pending && !yield(current.String(), nil) {
+					return
+				}
+				if !errors.Is(err, io.EOF) {
+					yield("", err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// unfoldLines returns the logical lines of vCard text (see UnfoldLines)
+func unfoldLines(text string) []string {
+	var lines []string
+	for line := range UnfoldLines(strings.NewReader(text)) {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// ParseProperty parses an unfolded content line, e.g. one returned by
+// UnfoldLines. Property and parameter names are upper-cased; parameter
+// values are decoded (RFC 6868) and split on commas outside quotes.
+// Parameters without a value (vCard 2.1 style, e.g. EMAIL;PREF) are treated
+// as TYPE values. The value is kept escaped; see Text and Components.
+func ParseProperty(line string) (ContentLine, error) {
+	colon := -1
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		if line[i] == '"' {
+			quoted = !quoted
+		} else if line[i] == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return ContentLine{}, fmt.Errorf("invalid content line %q: missing ':'", line)
+	}
+
+	parts := splitUnquoted(line[:colon], ';')
+	property := ContentLine{Name: strings.ToUpper(parts[0]), Value: line[colon+1:]}
+	if dot := strings.LastIndex(property.Name, "."); dot >= 0 {
+		property.Group, property.Name = property.Name[:dot], property.Name[dot+1:]
+	}
+	if !isParamName(property.Name) {
+		return ContentLine{}, fmt.Errorf("invalid property name in content line %q", line)
+	}
+
+	for _, part := range parts[1:] {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			name, value = "TYPE", part
+		}
+		var values []string
+		for _, v := range splitUnquoted(value, ',') {
+			v = decodeParamValue(strings.Trim(v, `"`))
+			if strings.EqualFold(name, "TYPE") {
+				// TYPE="work,voice" lists several types (RFC 6350 section 5.6)
+				values = append(values, strings.Split(v, ",")...)
+			} else {
+				values = append(values, v)
+			}
+		}
+		property.Params.Add(name, values...)
+	}
+
+	return property, nil
+}
+
+// splitUnquoted splits s on sep outside double quotes
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// paramDecoder reverses paramEscaper (RFC 6868)
+var paramDecoder = strings.NewReplacer("^^", "^", "^n", "\n", "^N", "\n", "^'", `"`)
+
+// decodeParamValue decodes the RFC 6868 escapes of a parameter value
+func decodeParamValue(value string) string {
+	return paramDecoder.Replace(value)
+}
+
+// splitComponents splits a structured value (N, ADR, ORG) on semicolons that
+// are not escaped and unescapes each component
+func splitComponents(value string) []string {
+	var components []string
+	var current strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			current.WriteByte(value[i])
+			current.WriteByte(value[i+1])
+			i++
+		case value[i] == ';':
+			components = append(components, unescapeValue(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	return append(components, unescapeValue(current.String()))
+}
+
+// component returns the component at index i, or an empty string
+func component(components []string, i int) string {
+	if i < len(components) {
+		return components[i]
+	}
+	return ""
+}
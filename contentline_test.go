@@ -0,0 +1,121 @@
+package vcard
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestUnfoldLines(t *testing.T) {
+	lines := unfoldLines("BEGIN:VCARD\r\nNOTE:first\r\n  second\r\n\tthird\nFN:Jane\n\nEND:VCARD\r\n")
+	expected := []string{"BEGIN:VCARD", "NOTE:first second" + "third", "FN:Jane", "END:VCARD"}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+}
+
+func TestParseContentLine(t *testing.T) {
+	property, err := ParseProperty(`item1.tel;type=cell;TYPE="work,voice";PREF=1;X-NOTE="a:b;c^'d^'":tel:+1-555;ext=1`)
+	if err != nil {
+		t.Fatalf("ParseProperty() returned error: %v", err)
+	}
+	if property.Group != "ITEM1" || property.Name != "TEL" || property.Value != "tel:+1-555;ext=1" {
+		t.Errorf("Unexpected property: %+v", property)
+	}
+	if types := property.Params.Get("TYPE"); !slices.Equal(types, []string{"cell", "work", "voice"}) {
+		t.Errorf("Unexpected types: %q", types)
+	}
+	if note := property.Params.Get("X-NOTE"); len(note) != 1 || note[0] != `a:b;c"d"` {
+		t.Errorf("Unexpected quoted parameter: %q", note)
+	}
+
+	legacy, err := ParseProperty("EMAIL;PREF;INTERNET:jane@example.com")
+	if err != nil || !slices.Equal(legacy.Params.Get("TYPE"), []string{"PREF", "INTERNET"}) {
+		t.Errorf("Expected bare parameters as types, got %+v, %v", legacy, err)
+	}
+
+	for _, line := range []string{"NO VALUE", "BAD NAME:x", `X-Q;A="open:value`} {
+		if _, err := ParseProperty(line); err == nil {
+			t.Errorf("Expected error for %q", line)
+		}
+	}
+}
+
+func TestSplitComponents(t *testing.T) {
+	components := splitComponents(`;;1 Main St\; Suite 2;Sofia\, BG;;1000;`)
+	expected := []string{"", "", "1 Main St; Suite 2", "Sofia, BG", "", "1000", ""}
+	if !slices.Equal(components, expected) {
+		t.Errorf("Expected %q, got %q", expected, components)
+	}
+}
+
+func TestUnfoldLinesTolerant(t *testing.T) {
+	// Other writers fold inside escape sequences and multi-byte characters,
+	// and some end lines with bare CR
+	text := "BEGIN:VCARD\rVERSION:3.0\rFN:Ren\xc3\r\n \xa9e Dupont\r\nNOTE:one\\\r\n ,two\\\n\t;three\\\r\n \\four\rEND:VCARD\r"
+
+	card, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if card.GetFormattedName() != "Renée Dupont" {
+		t.Errorf("Expected a joined multi-byte character, got %q", card.GetFormattedName())
+	}
+	if card.GetNote() != `one,two;three\four` {
+		t.Errorf("Expected joined escape sequences, got %q", card.GetNote())
+	}
+}
+
+func TestUnfoldLinesReader(t *testing.T) {
+	text := "BEGIN:VCARD\r\nVERSION:4.0\r\nEMAIL:jane@\r\n example.com\r\nN:Doe;Jane\\, Jr.;;;\r\nEND:VCARD"
+
+	var emails []string
+	for line, err := range UnfoldLines(strings.NewReader(text)) {
+		if err != nil {
+			t.Fatalf("UnfoldLines() returned error: %v", err)
+		}
+		property, err := ParseProperty(line)
+		if err != nil {
+			t.Fatalf("ParseProperty(%q) returned error: %v", line, err)
+		}
+		switch property.Name {
+		case "EMAIL":
+			emails = append(emails, property.Text())
+		case "N":
+			if components := property.Components(); len(components) != 5 || components[1] != "Jane, Jr." {
+				t.Errorf("Unexpected components %q", components)
+			}
+		}
+	}
+	if !slices.Equal(emails, []string{"jane@example.com"}) {
+		t.Errorf("Unexpected emails %q", emails)
+	}
+
+	// Iteration stops early
+	var first []string
+	for line := range UnfoldLines(strings.NewReader(text)) {
+		first = append(first, line)
+		break
+	}
+	if !slices.Equal(first, []string{"BEGIN:VCARD"}) {
+		t.Errorf("Expected one line, got %q", first)
+	}
+
+	// Read errors end the iteration
+	failure := errors.New("disk failure")
+	var got []string
+	var err error
+	for line, lineErr := range UnfoldLines(io.MultiReader(strings.NewReader("BEGIN:VCARD\r\nFN:Jane"), iotest.ErrReader(failure))) {
+		if lineErr != nil {
+			err = lineErr
+			break
+		}
+		got = append(got, line)
+	}
+	if !slices.Equal(got, []string{"BEGIN:VCARD", "FN:Jane"}) || !errors.Is(err, failure) {
+		t.Errorf("Expected the lines read before %v, got %q, %v", failure, got, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 // Test additional methods that aren't covered
@@ -411,6 +412,10 @@ func TestUnescapeValue(t *testing.T) {
 		{"Line1\\nLine2", "Line1\nLine2"},
 		{"Tab\\tSeparated", "Tab\tSeparated"},
 		{"Back\\\\slash", "Back\\slash"},
+		{"C:\\\\new", "C:\\new"},
+		{"Upper\\NCase", "Upper\nCase"},
+		{"https\\://example.com", "https://example.com"},
+		{"Trailing\\", "Trailing\\"},
 		{"Normal text", "Normal text"},
 	}
 
@@ -441,3 +446,83 @@ func TestFoldLine(t *testing.T) {
 }
 
 // Test additional methods that aren't covered
+
+func TestWriteFolded(t *testing.T) {
+	long := strings.Repeat("ABCDEFGHIJ", 40)
+	tests := [][]string{
+		{"PHOTO;VALUE=uri:", "https://example.com/photo.jpg"},
+		{"PHOTO;ENCODING=b;TYPE=JPEG:", long},
+		{"", long[:75]},
+		{long[:74], long[:3], long},
+	}
+
+	for _, parts := range tests {
+		var builder strings.Builder
+		writeFolded(&builder, parts...)
+		if expected := foldLine(strings.Join(parts, "")); builder.String() != expected {
+			t.Errorf("writeFolded(%q) = %q, want %q", parts, builder.String(), expected)
+		}
+	}
+
+	// Multi-byte characters are never split
+	var builder strings.Builder
+	writeFolded(&builder, strings.Repeat("a", 74), "éé")
+	if builder.String() != strings.Repeat("a", 74)+"\r\n éé" {
+		t.Errorf("Expected fold before multi-byte character, got %q", builder.String())
+	}
+
+	// Invalid UTF-8 still terminates
+	builder.Reset()
+	writeFolded(&builder, strings.Repeat("\x80", 100))
+	if len(builder.String()) != 103 {
+		t.Errorf("Expected invalid UTF-8 to be folded at 75 octets, got %q", builder.String())
+	}
+}
+
+func TestFoldRoundTrip(t *testing.T) {
+	values := []string{
+		strings.Repeat(`a\,`, 60),
+		strings.Repeat(`\\`, 80),
+		strings.Repeat("é;ü,\n", 50),
+		strings.Repeat("日本語\\", 40),
+		strings.Repeat("👩‍💻 ", 30),
+	}
+
+	for _, value := range values {
+		line := "NOTE:" + escapeValue(value)
+		for offset := 0; offset < 5; offset++ {
+			shifted := strings.Repeat("X", offset) + line
+			folded := foldLine(shifted)
+
+			for _, physical := range strings.Split(folded, "\r\n") {
+				if len(physical) > 75 {
+					t.Errorf("Line of %d octets: %q", len(physical), physical)
+				}
+				if !utf8.ValidString(physical) {
+					t.Errorf("Fold split a character: %q", physical)
+				}
+				if trailing := len(physical) - len(strings.TrimRight(physical, `\`)); trailing%2 == 1 {
+					t.Errorf("Fold split an escape sequence: %q", physical)
+				}
+			}
+
+			if lines := unfoldLines(folded); len(lines) != 1 || lines[0] != shifted {
+				t.Errorf("Unfolding %q gave %q", folded, lines)
+			}
+		}
+
+		card, err := Parse("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Jane\r\n" + foldLine(line) + "\r\nEND:VCARD\r\n")
+		if err != nil {
+			t.Fatalf("Parse() returned error: %v", err)
+		}
+		if card.GetNote() != value {
+			t.Errorf("Expected %q, got %q", value, card.GetNote())
+		}
+	}
+
+	// Escape sequences move to the next line as a whole
+	folded := foldLine(strings.Repeat("a", 74) + `\,b`)
+	if folded != strings.Repeat("a", 74)+"\r\n \\,b" {
+		t.Errorf("Expected fold before the escape sequence, got %q", folded)
+	}
+}
@@ -0,0 +1,72 @@
+package vcard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxDataURISize is the largest data URI, in bytes, accepted by DecodeDataURI
+const maxDataURISize = 10 << 20
+
+// EncodeDataURI returns a base64 data URI (RFC 2397) for the data, e.g.
+// "data:image/png;base64,iVBORw0K...". An empty media type uses
+// application/octet-stream.
+func EncodeDataURI(mediaType string, data []byte) string {
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodeDataURI parses a data URI and returns its media type and decoded
+// data. Both base64 and percent-encoded payloads are supported; URIs larger
+// than 10 MiB are rejected before decoding.
+func DecodeDataURI(s string) (string, []byte, error) {
+	if len(s) > maxDataURISize {
+		return "", nil, fmt.Errorf("data URI too large: %d bytes (max %d)", len(s), maxDataURISize)
+	}
+
+	mediaType, payload, isBase64, ok := splitDataURI(s)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid data URI: missing \"data:\" prefix or \",\" separator")
+	}
+
+	if isBase64 {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid data URI: %w", err)
+		}
+		return mediaType, data, nil
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid data URI: %w", err)
+	}
+	return mediaType, []byte(data), nil
+}
+
+// splitDataURI splits a data URI into its media type (defaulting to
+// text/plain as per RFC 2397), raw payload and base64 flag
+func splitDataURI(s string) (mediaType, payload string, isBase64, ok bool) {
+	rest, ok := strings.CutPrefix(s, "data:")
+	if !ok {
+		return "", "", false, false
+	}
+
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", "", false, false
+	}
+
+	if trimmed, found := strings.CutSuffix(header, ";base64"); found {
+		header, isBase64 = trimmed, true
+	}
+	if header == "" || strings.HasPrefix(header, ";") {
+		header = "text/plain" + header
+	}
+
+	return header, payload, isBase64, true
+}
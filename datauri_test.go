@@ -0,0 +1,59 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataURIRoundTrip(t *testing.T) {
+	uri := EncodeDataURI("image/png", []byte("png data"))
+	if uri != "data:image/png;base64,cG5nIGRhdGE=" {
+		t.Errorf("Unexpected data URI: %s", uri)
+	}
+
+	mediaType, data, err := DecodeDataURI(uri)
+	if err != nil {
+		t.Fatalf("DecodeDataURI() returned error: %v", err)
+	}
+	if mediaType != "image/png" || string(data) != "png data" {
+		t.Errorf("DecodeDataURI() = %q, %q", mediaType, data)
+	}
+
+	if got := EncodeDataURI("", nil); got != "data:application/octet-stream;base64," {
+		t.Errorf("Expected default media type, got %s", got)
+	}
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	tests := []struct {
+		input     string
+		mediaType string
+		data      string
+		wantErr   bool
+	}{
+		{"data:,Hello%2C%20World", "text/plain", "Hello, World", false},
+		{"data:;charset=utf-8,caf%C3%A9", "text/plain;charset=utf-8", "café", false},
+		{"data:image/svg+xml;charset=utf-8;base64,PHN2Zy8+", "image/svg+xml;charset=utf-8", "<svg/>", false},
+		{"image/png;base64,AAAA", "", "", true},
+		{"data:image/png;base64", "", "", true},
+		{"data:image/png;base64,not base64!", "", "", true},
+	}
+
+	for _, tt := range tests {
+		mediaType, data, err := DecodeDataURI(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DecodeDataURI(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if mediaType != tt.mediaType || string(data) != tt.data {
+			t.Errorf("DecodeDataURI(%q) = %q, %q; want %q, %q", tt.input, mediaType, data, tt.mediaType, tt.data)
+		}
+	}
+}
+
+func TestDecodeDataURITooLarge(t *testing.T) {
+	_, _, err := DecodeDataURI(EncodeDataURI("image/png", make([]byte, maxDataURISize)))
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Expected size error, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package vcard
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Decoder reads vCards one at a time from an input stream holding any
+// number of cards, such as a .vcf export. Text outside BEGIN:VCARD ...
+// END:VCARD blocks is skipped.
+type Decoder struct {
+	reader  *bufio.Reader
+	limits  DecoderLimits
+	card    bytes.Buffer
+	long    []byte
+	lines   []string
+	arena   *arena
+	decoded int
+	options CardOptions
+}
+
+// DecoderLimits bound the input a Decoder accepts, so untrusted streams
+// can't exhaust memory. Zero values mean no limit.
+type DecoderLimits struct {
+	// MaxDecodedCards is the maximum number of cards decoded from the stream
+	MaxDecodedCards int
+
+	// MaxLineLength is the maximum length in bytes of a content line,
+	// unfolded; it bounds inline photos too
+	MaxLineLength int
+}
+
+var (
+	// DefaultDecoderLimits are the limits of a new Decoder. They suit
+	// trusted batch imports: large exports with inline photos fit.
+	DefaultDecoderLimits = DecoderLimits{
+		MaxDecodedCards: 1_000_000,
+		MaxLineLength:   16 << 20,
+	}
+
+	// UploadDecoderLimits suit public upload endpoints, matching the
+	// defaults of server.DefaultUploadLimits
+	UploadDecoderLimits = DecoderLimits{
+		MaxDecodedCards: 5000,
+		MaxLineLength:   1 << 20,
+	}
+)
+
+// ErrLimitExceeded matches every LimitError with errors.Is
+var ErrLimitExceeded = errors.New("vcard decoder limit exceeded")
+
+// LimitError is returned by a Decoder for input exceeding one of its
+// DecoderLimits
+type LimitError struct {
+	// Limit is the name of the exceeded limit, "MaxDecodedCards" or
+	// "MaxLineLength", or "MaxFileSize" or "MaxTotalSize" of ArchiveLimits
+	Limit string
+
+	// Max is the value of the limit
+	Max int
+
+	// Card is the number of the card being read, counting from one; zero
+	// for the size limits of archives
+	Card int
+}
+
+// Error implements the error interface
+func (e *LimitError) Error() string {
+	if e.Card == 0 {
+		return fmt.Sprintf("%s of %d exceeded", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("card %d: %s of %d exceeded", e.Card, e.Limit, e.Max)
+}
+
+// Is reports whether target is ErrLimitExceeded
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// NewDecoder returns a decoder that reads from r with the
+// DefaultDecoderLimits
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: bufio.NewReader(lineBreaks{r}), limits: DefaultDecoderLimits}
+}
+
+// Limits replaces the limits of the decoder, e.g. with UploadDecoderLimits
+// for untrusted uploads
+func (d *Decoder) Limits(limits DecoderLimits) *Decoder {
+	d.limits = limits
+	return d
+}
+
+// CardOptions sets the options of the decoded cards, e.g. a NameEmoji
+// policy applied to the names read
+func (d *Decoder) CardOptions(options CardOptions) *Decoder {
+	d.options = options
+	d.options.URLSchemes = slices.Clone(options.URLSchemes)
+	return d
+}
+
+// lineBreaks reads a stream with every CR turned into LF, so that CRLF and
+// bare CR line breaks end lines like LF does. The empty lines this leaves
+// are skipped when unfolding.
+type lineBreaks struct {
+	r io.Reader
+}
+
+func (l lineBreaks) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i, c := range p[:n] {
+		if c == '\r' {
+			p[i] = '\n'
+		}
+	}
+	return n, err
+}
+
+// Arena enables arena allocation for very large imports: the unfolded lines
+// of the cards, and so most parsed property values, are carved out of
+// shared blocks of size bytes (DefaultArenaSize when size is less than 1)
+// instead of being allocated one by one. This cuts the number of
+// allocations and the GC work per card, at the cost of memory retention: a
+// block, freed as a whole, stays alive as long as any card decoded into it.
+// Use it for batch imports whose cards are stored or converted and then
+// dropped together, not for caching a few cards of a large stream.
+func (d *Decoder) Arena(size int) *Decoder {
+	if size < 1 {
+		size = DefaultArenaSize
+	}
+	d.arena = &arena{size: size}
+	return d
+}
+
+// Decode reads the next card. It returns io.EOF when there are no more
+// cards, io.ErrUnexpectedEOF for a card missing its END:VCARD line and a
+// *LimitError for input exceeding the limits.
+func (d *Decoder) Decode() (*VCard, error) {
+	if err := d.readCard(); err != nil {
+		return nil, err
+	}
+	if max := d.limits.MaxDecodedCards; max > 0 && d.decoded == max {
+		return nil, &LimitError{Limit: "MaxDecodedCards", Max: max, Card: d.decoded + 1}
+	}
+	d.decoded++
+
+	var card *VCard
+	var err error
+	if d.arena != nil {
+		d.lines = d.arena.unfold(d.card.Bytes(), d.lines[:0])
+		card, err = readLines(d.lines, d.options)
+		clear(d.lines)
+	} else {
+		card, err = readCard(d.card.String(), d.options)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("card %d: %w", d.decoded, err)
+	}
+	return card, nil
+}
+
+// DecodeAddressBook reads all remaining cards into an address book
+func (d *Decoder) DecodeAddressBook() (*AddressBook, error) {
+	book := NewAddressBook()
+	for {
+		card, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return book, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		book.Add(card)
+	}
+}
+
+// readCard reads the physical lines of the next card into d.card
+func (d *Decoder) readCard() error {
+	d.card.Reset()
+	depth, length := 0, 0
+	for {
+		line, err := d.readLine()
+		if max := d.limits.MaxLineLength; max > 0 && len(line) > max {
+			return &LimitError{Limit: "MaxLineLength", Max: max, Card: d.decoded + 1}
+		}
+		if len(line) > 0 || err == nil {
+			if d.decoded == 0 && depth == 0 {
+				// Drop a UTF-8 byte order mark at the start of the stream
+				line = bytes.TrimPrefix(line, []byte("\ufeff"))
+			}
+
+			switch {
+			case bytes.EqualFold(line, []byte("BEGIN:VCARD")):
+				depth++
+			case depth == 0:
+				// Text outside a card
+				continue
+			}
+
+			// Track the unfolded length of the content line
+			switch {
+			case len(line) == 0:
+			case (line[0] == ' ' || line[0] == '\t') && length > 0:
+				length += len(line) - 1
+			default:
+				length = len(line)
+			}
+			if max := d.limits.MaxLineLength; max > 0 && length > max {
+				return &LimitError{Limit: "MaxLineLength", Max: max, Card: d.decoded + 1}
+			}
+
+			d.card.Write(line)
+			d.card.WriteByte('\n')
+			if bytes.EqualFold(line, []byte("END:VCARD")) {
+				depth--
+				if depth == 0 {
+					return nil
+				}
+			}
+		}
+
+		switch {
+		case err == nil:
+		case errors.Is(err, io.EOF) && depth > 0:
+			return io.ErrUnexpectedEOF
+		default:
+			return err
+		}
+	}
+}
+
+// readLine returns the next physical line without its line break. The line
+// is only valid until the next call.
+func (d *Decoder) readLine() ([]byte, error) {
+	line, err := d.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		d.long = append(d.long[:0], line...)
+		for errors.Is(err, bufio.ErrBufferFull) {
+			if max := d.limits.MaxLineLength; max > 0 && len(d.long) > max {
+				// Stop buffering; the caller reports the limit
+				return d.long, nil
+			}
+			line, err = d.reader.ReadSlice('\n')
+			d.long = append(d.long, line...)
+		}
+		line = d.long
+	}
+	return bytes.TrimSuffix(line, []byte("\n")), err
+}
@@ -0,0 +1,221 @@
+package vcard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// decoderStream is a .vcf export with folding, bare CR line breaks, a byte
+// order mark and text between the cards
+const decoderStream = "\ufeffBEGIN:VCARD\r\nVERSION:4.0\r\nFN:Jane Doe\r\nN:Doe;Jane;;;\r\nNOTE:A long note fol\r\n ded over\r\n\t two lines\r\nEMAIL;TYPE=work:jane@example.com\r\nEND:VCARD\r\n" +
+	"garbage between cards\n" +
+	"BEGIN:VCARD\rVERSION:3.0\rFN:John Doe\rN:Doe;John;;;\rTEL;TYPE=CELL:+1555\rEND:VCARD\n" +
+	"BEGIN:VCARD\nVERSION:4.0\nFN:Anna\nEND:VCARD"
+
+func TestDecoder(t *testing.T) {
+	for _, arena := range []bool{false, true} {
+		t.Run(fmt.Sprintf("arena=%v", arena), func(t *testing.T) {
+			decoder := NewDecoder(strings.NewReader(decoderStream))
+			if arena {
+				// Tiny blocks make every card start a new one
+				decoder.Arena(16)
+			}
+
+			book, err := decoder.DecodeAddressBook()
+			if err != nil {
+				t.Fatalf("DecodeAddressBook() error = %v", err)
+			}
+			cards := book.Cards()
+			if len(cards) != 3 {
+				t.Fatalf("Expected 3 cards, got %d", len(cards))
+			}
+
+			// Collect so that freed blocks would show up as corrupted values
+			runtime.GC()
+
+			if got := cards[0].GetNote(); got != "A long note folded over two lines" {
+				t.Errorf("note = %q", got)
+			}
+			if got := cards[0].GetEmails(); len(got) != 1 || got[0].Address != "jane@example.com" {
+				t.Errorf("emails = %v", got)
+			}
+			if got := cards[1].GetFormattedName(); got != "John Doe" || cards[1].GetVersion() != Version30 {
+				t.Errorf("second card = %q %s", got, cards[1].GetVersion())
+			}
+			if got := cards[2].GetFormattedName(); got != "Anna" {
+				t.Errorf("third card = %q", got)
+			}
+
+			if _, err := decoder.Decode(); !errors.Is(err, io.EOF) {
+				t.Errorf("Decode() after the last card error = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestDecoderArenaMatchesParse(t *testing.T) {
+	for i, text := range []string{
+		strings.TrimPrefix(decoderStream[:strings.Index(decoderStream, "garbage")], "\ufeff"),
+		"BEGIN:VCARD\nVERSION:4.0\nFN:Émile Zoë\nADR;TYPE=home:;;1 Rue\\; Bis;Paris;;75001;France\nX-CUSTOM;X-PARAM=\"a,b\":value\nEND:VCARD\n",
+	} {
+		want, err := Parse(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := NewDecoder(strings.NewReader(text)).Arena(0).Decode()
+		if err != nil {
+			t.Fatalf("case %d: Decode() error = %v", i, err)
+		}
+		if changes := Diff(want, got); len(changes) > 0 {
+			t.Errorf("case %d: arena card differs from Parse: %v", i, changes)
+		}
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	_, err := NewDecoder(strings.NewReader("BEGIN:VCARD\nVERSION:4.0\nFN:Jane\n")).Decode()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated card error = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	decoder := NewDecoder(strings.NewReader("BEGIN:VCARD\nVERSION:4.0\nFN:Jane\nEND:VCARD\nBEGIN:VCARD\nFN:No version\nEND:VCARD\n"))
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoder.Decode(); err == nil || !strings.HasPrefix(err.Error(), "card 2:") {
+		t.Errorf("invalid card error = %v, want it to name card 2", err)
+	}
+
+	if _, err := NewDecoder(strings.NewReader("no cards here\n")).Decode(); !errors.Is(err, io.EOF) {
+		t.Errorf("empty stream error = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	card := "BEGIN:VCARD\nVERSION:4.0\nFN:Jane\nEND:VCARD\n"
+	folded := "BEGIN:VCARD\nVERSION:4.0\nFN:Jane\nNOTE:" + strings.Repeat("x", 60) + "\n " + strings.Repeat("y", 60) + "\nEND:VCARD\n"
+
+	tests := []struct {
+		name   string
+		input  string
+		limits DecoderLimits
+		cards  int
+		limit  string
+	}{
+		{"cards within limit", strings.Repeat(card, 3), DecoderLimits{MaxDecodedCards: 3}, 3, ""},
+		{"too many cards", strings.Repeat(card, 4), DecoderLimits{MaxDecodedCards: 3}, 3, "MaxDecodedCards"},
+		{"long physical line", "BEGIN:VCARD\nNOTE:" + strings.Repeat("x", 10000) + "\nEND:VCARD\n", DecoderLimits{MaxLineLength: 100}, 0, "MaxLineLength"},
+		{"long unfolded line", folded, DecoderLimits{MaxLineLength: 100}, 0, "MaxLineLength"},
+		{"no limits", folded + strings.Repeat(card, 4), DecoderLimits{}, 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := NewDecoder(strings.NewReader(tt.input)).Limits(tt.limits)
+			cards := 0
+			var err error
+			for {
+				if _, err = decoder.Decode(); err != nil {
+					break
+				}
+				cards++
+			}
+
+			if cards != tt.cards {
+				t.Errorf("Decoded %d cards, want %d", cards, tt.cards)
+			}
+			var limitErr *LimitError
+			switch {
+			case tt.limit == "" && !errors.Is(err, io.EOF):
+				t.Errorf("Decode() error = %v, want io.EOF", err)
+			case tt.limit != "" && (!errors.As(err, &limitErr) || limitErr.Limit != tt.limit || !errors.Is(err, ErrLimitExceeded)):
+				t.Errorf("Decode() error = %v, want a %s LimitError", err, tt.limit)
+			case tt.limit != "" && limitErr.Card != tt.cards+1:
+				t.Errorf("LimitError.Card = %d, want %d", limitErr.Card, tt.cards+1)
+			}
+		})
+	}
+}
+
+func TestDecoderDefaultLimits(t *testing.T) {
+	if got := NewDecoder(strings.NewReader("")).limits; got != DefaultDecoderLimits {
+		t.Errorf("NewDecoder limits = %+v, want DefaultDecoderLimits", got)
+	}
+	if UploadDecoderLimits.MaxDecodedCards >= DefaultDecoderLimits.MaxDecodedCards ||
+		UploadDecoderLimits.MaxLineLength >= DefaultDecoderLimits.MaxLineLength {
+		t.Error("Expected the upload limits to be tighter than the defaults")
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Add(decoderStream)
+	f.Add("BEGIN:VCARD\nBEGIN:VCARD\nEND:VCARD\n")
+	f.Add("BEGIN:VCARD\r\nVERSION:3.0\r\nN:\\;;;;\r\n\t\r\nEND:VCARD")
+	limits := DecoderLimits{MaxDecodedCards: 4, MaxLineLength: 256}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		heap := NewDecoder(strings.NewReader(input)).Limits(limits)
+		arena := NewDecoder(strings.NewReader(input)).Limits(limits).Arena(64)
+		for i := 0; ; i++ {
+			want, wantErr := heap.Decode()
+			got, gotErr := arena.Decode()
+			if (wantErr == nil) != (gotErr == nil) {
+				t.Fatalf("card %d: heap error %v, arena error %v", i+1, wantErr, gotErr)
+			}
+			if wantErr != nil {
+				if i > limits.MaxDecodedCards {
+					t.Fatalf("decoded %d cards past MaxDecodedCards", i)
+				}
+				return
+			}
+			if changes := Diff(want, got); len(changes) > 0 {
+				t.Fatalf("card %d: arena card differs: %v", i+1, changes)
+			}
+			if _, err := got.String(); err != nil && !strings.Contains(err.Error(), "validation") {
+				t.Fatalf("card %d: String() error = %v", i+1, err)
+			}
+		}
+	})
+}
+
+// benchmarkStream returns a .vcf export of n typical cards
+func benchmarkStream(n int) string {
+	var builder strings.Builder
+	for i := 0; i < n; i++ {
+		card := New().
+			AddName("John", fmt.Sprintf("Doe %d", i)).
+			AddEmail(fmt.Sprintf("john.doe%d@example.com", i)).
+			AddPhone("+1234567890", PhoneWork).
+			AddAddress("1 Main St", "Springfield", "IL", "62701", "USA", AddressWork).
+			SetOrganization(Organization{Name: "Acme", Department: "R&D", Title: "Engineer"}).
+			AddNote(strings.Repeat("A note long enough to be folded. ", 4)).
+			SetUID(fmt.Sprintf("urn:uuid:%08d", i))
+		content, _ := card.String()
+		builder.WriteString(content)
+	}
+	return builder.String()
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	stream := benchmarkStream(10000)
+
+	for _, arena := range []bool{false, true} {
+		b.Run(fmt.Sprintf("arena=%v", arena), func(b *testing.B) {
+			b.SetBytes(int64(len(stream)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				decoder := NewDecoder(strings.NewReader(stream))
+				if arena {
+					decoder.Arena(DefaultArenaSize)
+				}
+				if _, err := decoder.DecodeAddressBook(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package vcard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change describes how the values of a property differ between two cards.
+// Values are given in their text form (see Get), so a change to a value's
+// type or preference lists the same text as removed and added.
+type Change struct {
+	// Property is the property name, e.g. "EMAIL"
+	Property string `json:"property"`
+
+	// Removed are the values only the old card holds
+	Removed []string `json:"removed,omitempty"`
+
+	// Added are the values only the current card holds
+	Added []string `json:"added,omitempty"`
+}
+
+// Diff returns the changes from the old to the current card, sorted by property
+// name. A nil card has no properties, so Diff(nil, card) lists every value
+// of card as added.
+func Diff(old, current *VCard) []Change {
+	names := make(map[string]bool, len(propertyValues))
+	for name := range propertyValues {
+		names[name] = true
+	}
+	for _, card := range []*VCard{old, current} {
+		if card == nil {
+			continue
+		}
+		for name := range card.customProps {
+			names[strings.ToUpper(name)] = true
+		}
+	}
+
+	var changes []Change
+	for name := range names {
+		if change := diffProperty(name, old, current); len(change.Removed) > 0 || len(change.Added) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Property < changes[j].Property })
+	return changes
+}
+
+// diffProperty compares the values of one property
+func diffProperty(name string, old, current *VCard) Change {
+	change := Change{Property: name}
+	oldValues, newValues := diffValues(old, name), diffValues(current, name)
+
+	remaining := make(map[string]int, len(newValues))
+	for _, value := range newValues {
+		remaining[value.key]++
+	}
+	for _, value := range oldValues {
+		if remaining[value.key] > 0 {
+			remaining[value.key]--
+		} else {
+			change.Removed = append(change.Removed, value.text)
+		}
+	}
+
+	matched := make(map[string]int, len(oldValues))
+	for _, value := range oldValues {
+		matched[value.key]++
+	}
+	for _, value := range newValues {
+		if matched[value.key] > 0 {
+			matched[value.key]--
+		} else {
+			change.Added = append(change.Added, value.text)
+		}
+	}
+	return change
+}
+
+// diffValue is a property value with its comparison key and text form
+type diffValue struct {
+	key  string
+	text string
+}
+
+// diffValues returns the values of the named property on the card
+func diffValues(card *VCard, name string) []diffValue {
+	if card == nil {
+		return nil
+	}
+
+	var values []any
+	if accessor, ok := propertyValues[name]; ok {
+		values = accessor(card)
+	} else {
+		values = anyValues(Get[string](card, name))
+	}
+
+	result := make([]diffValue, 0, len(values))
+	for _, value := range values {
+		text := propertyText(value, Version30)
+		key := text
+		if _, isTime := value.(time.Time); !isTime {
+			key = fmt.Sprintf("%#v", value)
+		}
+		result = append(result, diffValue{key: key, text: text})
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package vcard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := New().
+		AddName("Jane", "Doe").
+		AddEmail("jane@example.com", EmailWork).
+		AddEmail("jane@home.example", EmailHome).
+		AddPhone("+15551234567").
+		AddCustomProperty("X-Team", "Platform")
+	current := New().
+		AddName("Jane", "Doe").
+		AddMiddleName("Q").
+		AddEmail("jane@example.com", EmailWork).
+		AddEmail("jane.doe@example.com", EmailHome).
+		AddPhone("+15551234567").
+		AddCustomProperty("X-TEAM", "Payments")
+
+	expected := []Change{
+		{Property: "EMAIL", Removed: []string{"jane@home.example"}, Added: []string{"jane.doe@example.com"}},
+		{Property: "FN", Removed: []string{"Jane Doe"}, Added: []string{"Jane Q Doe"}},
+		{Property: "N", Removed: []string{"Jane Doe"}, Added: []string{"Jane Q Doe"}},
+		{Property: "X-TEAM", Removed: []string{"Platform"}, Added: []string{"Payments"}},
+	}
+	if changes := Diff(old, current); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff() = %+v, expected %+v", changes, expected)
+	}
+
+	if changes := Diff(old, old.Clone()); len(changes) != 0 {
+		t.Errorf("Expected no changes for equal cards, got %+v", changes)
+	}
+}
+
+func TestDiffTypesAndNil(t *testing.T) {
+	old := New().AddName("Jane", "Doe").AddPhone("+15551234567", PhoneWork).SetBirthdayValue("1990-05-15")
+	current := New().AddName("Jane", "Doe").AddPhone("+15551234567", PhoneHome).SetBirthdayValue("1990-05-15")
+
+	expected := []Change{{Property: "TEL", Removed: []string{"+15551234567"}, Added: []string{"+15551234567"}}}
+	if changes := Diff(old, current); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Diff() = %+v, expected %+v", changes, expected)
+	}
+
+	added := Diff(nil, old)
+	if len(added) != 4 || added[0].Property != "BDAY" || added[0].Added[0] != "1990-05-15" || len(added[0].Removed) != 0 {
+		t.Errorf("Expected every property to be added, got %+v", added)
+	}
+	if removed := Diff(old, nil); len(removed) != 4 || len(removed[3].Removed) != 1 {
+		t.Errorf("Expected every property to be removed, got %+v", removed)
+	}
+}
@@ -0,0 +1,302 @@
+// Package document converts between vCards and a JSON document layout for
+// NoSQL stores such as Firebase Firestore, so mobile backends can keep
+// contacts as documents and still emit .vcf files.
+//
+// Contact carries both json and firestore struct tags, so it can be stored
+// with the Firestore client directly:
+//
+//	_, err := client.Collection("contacts").Doc(card.GetUID()).Set(ctx, document.FromCard(card))
+//
+//	var contact document.Contact
+//	if err := snapshot.DataTo(&contact); err != nil {
+//		return err
+//	}
+//	card, err := contact.Card()
+//
+// The layout, with all fields but schemaVersion optional:
+//
+//	{
+//	  "schemaVersion": 1,
+//	  "version": "4.0",
+//	  "uid": "urn:uuid:...",
+//	  "kind": "individual",
+//	  "name": {"first": "Jane", "last": "Doe", "middle": "", "prefix": "", "suffix": ""},
+//	  "displayName": "Jane Doe",
+//	  "emails": [{"address": "jane@example.com", "type": "work", "preferred": true}],
+//	  "phones": [{"number": "+15551234567", "type": "mobile"}],
+//	  "addresses": [{"street": "", "extended": "", "city": "", "region": "", "postalCode": "", "country": "", "type": "home"}],
+//	  "organization": {"name": "Acme", "department": "", "units": [], "title": "", "role": ""},
+//	  "urls": [{"address": "https://example.com", "type": "work"}],
+//	  "geo": {"latitude": 42.69, "longitude": 23.32},
+//	  "photo": "https://... or data:image/jpeg;base64,...",
+//	  "logo": "",
+//	  "note": "",
+//	  "birthday": "1990-05-15",
+//	  "anniversary": "",
+//	  "custom": {"X-EMPLOYEE-ID": "1042"}
+//	}
+//
+// Types are written lower-case. Dates use YYYY-MM-DD, --MM-DD without a
+// year, or free text. Decode rejects fields outside the layout and newer
+// schema versions, so drift between writers is caught rather than dropped.
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.rumenx.com/vcard"
+)
+
+// SchemaVersion is the version of the document layout written by FromCard
+const SchemaVersion = 1
+
+// ErrSchemaVersion is returned for documents written with a newer layout
+var ErrSchemaVersion = errors.New("document: unsupported schema version")
+
+// Contact is a contact document
+type Contact struct {
+	SchemaVersion int               `json:"schemaVersion" firestore:"schemaVersion"`
+	Version       string            `json:"version,omitempty" firestore:"version,omitempty"`
+	UID           string            `json:"uid,omitempty" firestore:"uid,omitempty"`
+	Kind          string            `json:"kind,omitempty" firestore:"kind,omitempty"`
+	Name          Name              `json:"name" firestore:"name"`
+	DisplayName   string            `json:"displayName,omitempty" firestore:"displayName,omitempty"`
+	Emails        []Email           `json:"emails,omitempty" firestore:"emails,omitempty"`
+	Phones        []Phone           `json:"phones,omitempty" firestore:"phones,omitempty"`
+	Addresses     []Address         `json:"addresses,omitempty" firestore:"addresses,omitempty"`
+	Organization  *Organization     `json:"organization,omitempty" firestore:"organization,omitempty"`
+	URLs          []URL             `json:"urls,omitempty" firestore:"urls,omitempty"`
+	Geo           *Geo              `json:"geo,omitempty" firestore:"geo,omitempty"`
+	Photo         string            `json:"photo,omitempty" firestore:"photo,omitempty"`
+	Logo          string            `json:"logo,omitempty" firestore:"logo,omitempty"`
+	Note          string            `json:"note,omitempty" firestore:"note,omitempty"`
+	Birthday      string            `json:"birthday,omitempty" firestore:"birthday,omitempty"`
+	Anniversary   string            `json:"anniversary,omitempty" firestore:"anniversary,omitempty"`
+	Custom        map[string]string `json:"custom,omitempty" firestore:"custom,omitempty"`
+}
+
+// Name is the structured name
+type Name struct {
+	First  string `json:"first,omitempty" firestore:"first,omitempty"`
+	Last   string `json:"last,omitempty" firestore:"last,omitempty"`
+	Middle string `json:"middle,omitempty" firestore:"middle,omitempty"`
+	Prefix string `json:"prefix,omitempty" firestore:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty" firestore:"suffix,omitempty"`
+}
+
+// Email is an email address
+type Email struct {
+	Address   string `json:"address" firestore:"address"`
+	Type      string `json:"type,omitempty" firestore:"type,omitempty"`
+	Preferred bool   `json:"preferred,omitempty" firestore:"preferred,omitempty"`
+}
+
+// Phone is a phone number
+type Phone struct {
+	Number    string `json:"number" firestore:"number"`
+	Type      string `json:"type,omitempty" firestore:"type,omitempty"`
+	Preferred bool   `json:"preferred,omitempty" firestore:"preferred,omitempty"`
+}
+
+// Address is a postal address
+type Address struct {
+	Street     string `json:"street,omitempty" firestore:"street,omitempty"`
+	Extended   string `json:"extended,omitempty" firestore:"extended,omitempty"`
+	City       string `json:"city,omitempty" firestore:"city,omitempty"`
+	Region     string `json:"region,omitempty" firestore:"region,omitempty"`
+	PostalCode string `json:"postalCode,omitempty" firestore:"postalCode,omitempty"`
+	Country    string `json:"country,omitempty" firestore:"country,omitempty"`
+	Type       string `json:"type,omitempty" firestore:"type,omitempty"`
+	Preferred  bool   `json:"preferred,omitempty" firestore:"preferred,omitempty"`
+}
+
+// Organization is the organization and job information
+type Organization struct {
+	Name       string   `json:"name,omitempty" firestore:"name,omitempty"`
+	Department string   `json:"department,omitempty" firestore:"department,omitempty"`
+	Units      []string `json:"units,omitempty" firestore:"units,omitempty"`
+	Title      string   `json:"title,omitempty" firestore:"title,omitempty"`
+	Role       string   `json:"role,omitempty" firestore:"role,omitempty"`
+}
+
+// URL is a website address
+type URL struct {
+	Address   string `json:"address" firestore:"address"`
+	Type      string `json:"type,omitempty" firestore:"type,omitempty"`
+	Preferred bool   `json:"preferred,omitempty" firestore:"preferred,omitempty"`
+}
+
+// Geo is a geographic position in decimal degrees
+type Geo struct {
+	Latitude  float64 `json:"latitude" firestore:"latitude"`
+	Longitude float64 `json:"longitude" firestore:"longitude"`
+}
+
+// FromCard converts the card to a document
+func FromCard(card *vcard.VCard) Contact {
+	name := card.GetName()
+	contact := Contact{
+		SchemaVersion: SchemaVersion,
+		Version:       card.GetVersion().String(),
+		UID:           card.GetUID(),
+		Kind:          string(card.GetKind()),
+		Name:          Name{First: name.First, Last: name.Last, Middle: name.Middle, Prefix: name.Prefix, Suffix: name.Suffix},
+		DisplayName:   card.GetFormattedName(),
+		Photo:         card.GetPhoto(),
+		Logo:          card.GetLogo(),
+		Note:          card.GetNote(),
+		Birthday:      formatDate(card.GetBirthday(), card.GetBirthdayText()),
+		Anniversary:   formatDate(card.GetAnniversary(), card.GetAnniversaryText()),
+	}
+
+	for _, email := range card.GetEmails() {
+		contact.Emails = append(contact.Emails, Email{Address: email.Address, Type: lower(email.Type), Preferred: email.Preferred})
+	}
+	for _, phone := range card.GetPhones() {
+		contact.Phones = append(contact.Phones, Phone{Number: phone.Number, Type: lower(phone.Type), Preferred: phone.Preferred})
+	}
+	for _, address := range card.GetAddresses() {
+		contact.Addresses = append(contact.Addresses, Address{
+			Street:     address.Street,
+			Extended:   address.Extended,
+			City:       address.City,
+			Region:     address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+			Type:       lower(address.Type),
+			Preferred:  address.Preferred,
+		})
+	}
+	if org := card.GetOrganization(); org.Name != "" || org.Department != "" || len(org.Units) > 0 || org.Title != "" || org.Role != "" {
+		contact.Organization = &Organization{Name: org.Name, Department: org.Department, Units: org.Units, Title: org.Title, Role: org.Role}
+	}
+	for _, url := range card.GetURLs() {
+		contact.URLs = append(contact.URLs, URL{Address: url.Address, Type: lower(url.Type), Preferred: url.Preferred})
+	}
+	if geo := card.GetGeo(); geo != nil {
+		contact.Geo = &Geo{Latitude: geo.Latitude, Longitude: geo.Longitude}
+	}
+	if custom := card.GetCustomProperties(); len(custom) > 0 {
+		contact.Custom = custom
+	}
+
+	return contact
+}
+
+// Card converts the document to a card. Documents without a version give
+// vCard 3.0 cards, like vcard.New.
+func (c Contact) Card() (*vcard.VCard, error) {
+	if c.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSchemaVersion, c.SchemaVersion)
+	}
+
+	card := vcard.New()
+	if c.Version != "" {
+		version, err := vcard.ParseVersion(c.Version)
+		if err != nil {
+			return nil, fmt.Errorf("document: %w", err)
+		}
+		card.SetVersion(version)
+	}
+
+	name := vcard.Name{First: c.Name.First, Last: c.Name.Last, Middle: c.Name.Middle, Prefix: c.Name.Prefix, Suffix: c.Name.Suffix}
+	card.SetName(name).SetKind(vcard.Kind(c.Kind))
+	if c.DisplayName != "" && c.DisplayName != card.GetFormattedName() {
+		card.SetFormattedName(c.DisplayName)
+	}
+
+	for _, email := range c.Emails {
+		card.AddEmailWithPreference(email.Address, vcard.EmailType(strings.ToUpper(email.Type)), email.Preferred)
+	}
+	for _, phone := range c.Phones {
+		card.AddPhoneWithPreference(phone.Number, vcard.PhoneType(strings.ToUpper(phone.Type)), phone.Preferred)
+	}
+	for _, address := range c.Addresses {
+		card.AddAddresses([]vcard.Address{{
+			Street:     address.Street,
+			Extended:   address.Extended,
+			City:       address.City,
+			State:      address.Region,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+			Type:       vcard.AddressType(strings.ToUpper(address.Type)),
+			Preferred:  address.Preferred,
+		}})
+	}
+	if org := c.Organization; org != nil {
+		card.SetOrganization(vcard.Organization{Name: org.Name, Department: org.Department, Units: org.Units, Title: org.Title, Role: org.Role})
+	}
+	for _, url := range c.URLs {
+		card.AddURLWithPreference(url.Address, vcard.URLType(strings.ToUpper(url.Type)), url.Preferred)
+	}
+	if c.Geo != nil {
+		card.SetGeo(c.Geo.Latitude, c.Geo.Longitude)
+	}
+	if c.Photo != "" {
+		card.AddPhoto(c.Photo)
+	}
+	if c.Logo != "" {
+		card.AddLogo(c.Logo)
+	}
+	if c.Note != "" {
+		card.AddNote(c.Note)
+	}
+	if c.Birthday != "" {
+		card.SetBirthdayValue(c.Birthday)
+	}
+	if c.Anniversary != "" {
+		card.SetAnniversaryValue(c.Anniversary)
+	}
+	if c.UID != "" {
+		card.SetUID(c.UID)
+	}
+	if len(c.Custom) > 0 {
+		card.AddCustomProperties(c.Custom)
+	}
+
+	return card, nil
+}
+
+// Decode reads a JSON document, rejecting fields outside the layout and
+// newer schema versions
+func Decode(data []byte) (Contact, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var contact Contact
+	if err := decoder.Decode(&contact); err != nil {
+		return Contact{}, fmt.Errorf("document: %w", err)
+	}
+	if contact.SchemaVersion > SchemaVersion {
+		return Contact{}, fmt.Errorf("%w: %d", ErrSchemaVersion, contact.SchemaVersion)
+	}
+	return contact, nil
+}
+
+// formatDate formats a date as YYYY-MM-DD (--MM-DD without a year, with the
+// time of day when set and the offset unless floating), or returns the free
+// text
+func formatDate(date *time.Time, text string) string {
+	switch {
+	case date == nil:
+		return text
+	case date.Year() == 0:
+		return date.Format("--01-02")
+	case vcard.IsFloating(*date):
+		return date.Format("2006-01-02T15:04:05")
+	case date.Hour() != 0 || date.Minute() != 0 || date.Second() != 0:
+		return date.Format(time.RFC3339)
+	default:
+		return date.Format(time.DateOnly)
+	}
+}
+
+// lower returns a type value in the lower case used by documents
+func lower[T ~string](value T) string {
+	return strings.ToLower(string(value))
+}
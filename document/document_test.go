@@ -0,0 +1,116 @@
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func testCard() *vcard.VCard {
+	return vcard.NewWithVersion(vcard.Version40).
+		AddName("Jane", "Doe").
+		AddPrefix("Dr.").
+		AddEmailWithPreference("jane@example.com", vcard.EmailWork, true).
+		AddPhone("+15551234567", vcard.PhoneMobile).
+		AddAddress("1 Main St", "Springfield", "IL", "62701", "USA", vcard.AddressHome).
+		SetOrganization(vcard.Organization{Name: "Acme", Department: "R&D", Units: []string{"Labs"}, Title: "Engineer"}).
+		AddURL("https://example.com", vcard.URLWork).
+		SetGeo(42.69, 23.32).
+		AddPhoto("https://example.com/jane.jpg").
+		AddNote("Met at GopherCon").
+		SetBirthdayValue("--05-15").
+		SetAnniversaryText("spring 2010").
+		SetUID("urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1").
+		AddCustomProperty("X-EMPLOYEE-ID", "1042")
+}
+
+func TestFromCard(t *testing.T) {
+	contact := FromCard(testCard())
+
+	if contact.SchemaVersion != SchemaVersion || contact.Version != "4.0" || contact.DisplayName != "Dr. Jane Doe" {
+		t.Errorf("Unexpected document header: %+v", contact)
+	}
+	if len(contact.Emails) != 1 || contact.Emails[0] != (Email{Address: "jane@example.com", Type: "work", Preferred: true}) {
+		t.Errorf("Unexpected emails: %+v", contact.Emails)
+	}
+	if len(contact.Addresses) != 1 || contact.Addresses[0].Region != "IL" || contact.Addresses[0].Type != "home" {
+		t.Errorf("Unexpected addresses: %+v", contact.Addresses)
+	}
+	if contact.Birthday != "--05-15" || contact.Anniversary != "spring 2010" {
+		t.Errorf("Unexpected dates: %q, %q", contact.Birthday, contact.Anniversary)
+	}
+
+	data, err := json.Marshal(contact)
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+	for _, expected := range []string{`"schemaVersion":1`, `"phones":[{"number":"+15551234567","type":"mobile"}]`, `"geo":{"latitude":42.69,"longitude":23.32}`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %s in %s", expected, data)
+		}
+	}
+	if strings.Contains(string(data), `"logo"`) {
+		t.Errorf("Expected empty fields to be omitted: %s", data)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	original := testCard()
+	data, err := json.Marshal(FromCard(original))
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+
+	contact, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() returned error: %v", err)
+	}
+	card, err := contact.Card()
+	if err != nil {
+		t.Fatalf("Card() returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(FromCard(card), FromCard(original)) {
+		t.Errorf("Round trip changed the document:\n%+v\n%+v", FromCard(card), FromCard(original))
+	}
+	expected, _ := original.String()
+	got, _ := card.String()
+	if len(got) != len(expected) {
+		t.Errorf("Round trip changed the card:\n%s\n%s", got, expected)
+	}
+}
+
+func TestCardDisplayName(t *testing.T) {
+	card, err := Contact{Name: Name{First: "Jane", Last: "Doe"}, DisplayName: "Jane Doe"}.Card()
+	if err != nil || card.GetVersion() != vcard.Version30 {
+		t.Fatalf("Expected a 3.0 card, got %v", err)
+	}
+	card.AddName("Janet", "Doe")
+	if card.GetFormattedName() != "Janet Doe" {
+		t.Error("Expected a derived display name not to be pinned")
+	}
+
+	card, _ = Contact{Name: Name{First: "Jane", Last: "Doe"}, DisplayName: "JD"}.Card()
+	if card.GetFormattedName() != "JD" {
+		t.Errorf("Expected explicit display name, got %q", card.GetFormattedName())
+	}
+}
+
+func TestDecodeDrift(t *testing.T) {
+	if _, err := Decode([]byte(`{"schemaVersion": 1, "name": {"first": "Jane"}, "nickname": "JD"}`)); err == nil {
+		t.Error("Expected error for a field outside the layout")
+	}
+	if _, err := Decode([]byte(`{"schemaVersion": 2}`)); !errors.Is(err, ErrSchemaVersion) {
+		t.Errorf("Expected ErrSchemaVersion, got %v", err)
+	}
+	if _, err := (Contact{SchemaVersion: 2}).Card(); !errors.Is(err, ErrSchemaVersion) {
+		t.Errorf("Expected ErrSchemaVersion, got %v", err)
+	}
+	if _, err := (Contact{Version: "2.1"}).Card(); err == nil {
+		t.Error("Expected error for an unsupported version")
+	}
+}
@@ -0,0 +1,137 @@
+package vcard
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// EmojiPolicy selects how emoji and other pictographic symbols in name
+// fields are handled. Some importers, notably older Outlook and CRM
+// versions, reject cards with emoji in N or FN.
+type EmojiPolicy int
+
+const (
+	// EmojiPreserve keeps emoji and symbols unchanged
+	EmojiPreserve EmojiPolicy = iota
+
+	// EmojiStrip removes emoji and symbols
+	EmojiStrip
+
+	// EmojiTransliterate replaces emoji and symbols with text (see
+	// TransliterateEmoji)
+	EmojiTransliterate
+)
+
+// emojiText maps emoji and symbols to the text TransliterateEmoji writes for
+// them; CardOptions.EmojiText overrides it. Symbols not listed use their
+// Unicode compatibility decomposition when it is plain text (™ becomes "TM")
+// and are removed otherwise.
+var emojiText = map[rune]string{
+	'©': "(C)",
+	'®': "(R)",
+	'♥': "<3",
+	'❤': "<3",
+	'★': "*",
+	'☆': "*",
+	'✓': "v",
+	'✔': "v",
+	'☺': ":)",
+	'🙂': ":)",
+	'😊': ":)",
+	'😀': ":D",
+	'😃': ":D",
+	'😄': ":D",
+	'😉': ";)",
+	'😛': ":P",
+	'🙁': ":(",
+	'☹': ":(",
+	'😢': ":'(",
+}
+
+// StripEmoji removes emoji and other pictographic symbols, including their
+// joiners, variation selectors and skin tone modifiers, and collapses the
+// spaces left behind
+func StripEmoji(s string) string {
+	return replaceEmoji(s, func(rune) string { return "" })
+}
+
+// TransliterateEmoji replaces emoji and other pictographic symbols with
+// text, such as "<3" for ❤ or "TM" for ™, and removes the others like
+// StripEmoji
+func TransliterateEmoji(s string) string {
+	return transliterateEmoji(s, nil)
+}
+
+// transliterateEmoji transliterates like TransliterateEmoji, taking the
+// text of the symbols in overrides from there
+func transliterateEmoji(s string, overrides map[rune]string) string {
+	return replaceEmoji(s, func(r rune) string {
+		if text, ok := overrides[r]; ok {
+			return text
+		}
+		if text, ok := emojiText[r]; ok {
+			return text
+		}
+		if text := norm.NFKC.String(string(r)); strings.IndexFunc(text, isEmojiRune) < 0 {
+			return text
+		}
+		return ""
+	})
+}
+
+// ContainsEmoji reports whether s holds emoji or other pictographic symbols
+func ContainsEmoji(s string) bool {
+	return strings.IndexFunc(s, isEmojiRune) >= 0
+}
+
+// replaceEmoji replaces every emoji with the text returned by replace.
+// Joiners and tags are only removed within emoji sequences, as zero width
+// joiners are also used by scripts such as Devanagari and Persian.
+func replaceEmoji(s string, replace func(r rune) string) string {
+	if !ContainsEmoji(s) && !strings.ContainsRune(s, 0x20e3) {
+		return s
+	}
+
+	var builder strings.Builder
+	inEmoji := false
+	for _, r := range s {
+		switch {
+		case isEmojiRune(r):
+			builder.WriteString(replace(r))
+			inEmoji = true
+		case r == 0xfe0e || r == 0xfe0f || r == 0x20e3:
+			// Variation selectors and the keycap mark
+		case inEmoji && (r == 0x200d || r >= 0xe0020 && r <= 0xe007f):
+			// Zero width joiners and tag characters of emoji sequences
+		default:
+			builder.WriteRune(r)
+			inEmoji = false
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// isEmojiRune reports whether r is an emoji, a pictographic symbol or a
+// skin tone modifier
+func isEmojiRune(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff || r > 0x7f && unicode.Is(unicode.So, r)
+}
+
+// emojiWarnings reports name fields holding emoji while they are preserved
+func (v *VCard) emojiWarnings() []Warning {
+	if v.options.NameEmoji != EmojiPreserve {
+		return nil
+	}
+
+	var warnings []Warning
+	name := v.name.First + v.name.Middle + v.name.Last + v.name.Prefix + v.name.Suffix
+	if ContainsEmoji(name) {
+		warnings = append(warnings, Warning{Property: "N", Message: "emoji in names are rejected by some importers"})
+	}
+	if ContainsEmoji(v.fn) {
+		warnings = append(warnings, Warning{Property: "FN", Message: "emoji in names are rejected by some importers"})
+	}
+	return warnings
+}
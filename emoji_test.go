@@ -0,0 +1,98 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Jane Doe", "Jane Doe"},
+		{"Jane 🎂 Doe", "Jane Doe"},
+		{"👩🏽‍💻 Jane", "Jane"},
+		{"Mom ❤️", "Mom"},
+		{"Team 🏴󠁧󠁢󠁳󠁣󠁴󠁿 Scotland", "Team Scotland"},
+		{"Flag 🇧🇬", "Flag"},
+		{"Room 1️⃣", "Room 1"},
+		{"Acme™", "Acme"},
+		{"José", "José"},
+		// Zero width joiners outside emoji sequences belong to the script
+		{"क्‍ष", "क्‍ष"},
+	}
+	for _, test := range tests {
+		if got := StripEmoji(test.input); got != test.expected {
+			t.Errorf("StripEmoji(%q) = %q, expected %q", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestTransliterateEmoji(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Mom ❤️", "Mom <3"},
+		{"Acme™", "AcmeTM"},
+		{"Acme®", "Acme(R)"},
+		{"Bob 🙂", "Bob :)"},
+		{"Bob 🍕", "Bob"},
+		{"㈱ Tanaka", "(株) Tanaka"},
+	}
+	for _, test := range tests {
+		if got := TransliterateEmoji(test.input); got != test.expected {
+			t.Errorf("TransliterateEmoji(%q) = %q, expected %q", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestNameEmojiPolicy(t *testing.T) {
+	card := New().AddName("Jane 🌸", "Doe").SetFormattedName("Jane 🌸 Doe")
+	if card.GetName().First != "Jane 🌸" || card.GetFormattedName() != "Jane 🌸 Doe" {
+		t.Errorf("Expected emoji to be preserved by default, got %+v", card.GetName())
+	}
+	warnings := card.Lint()
+	if len(warnings) != 2 || warnings[0].Property != "N" || warnings[1].Property != "FN" {
+		t.Errorf("Expected N and FN warnings, got %v", warnings)
+	}
+
+	card = New().SetOptions(CardOptions{NameEmoji: EmojiStrip}).SetName(Name{First: "Jane 🌸", Last: "Doe", Suffix: "✨"}).AddNote("🌸 stays in notes")
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(content, "N:Doe;Jane;;;\n") || !strings.Contains(content, "FN:Jane Doe\n") {
+		t.Errorf("Expected emoji to be stripped from names:\n%s", content)
+	}
+	if !strings.Contains(content, "NOTE:🌸 stays in notes") {
+		t.Errorf("Expected other properties to be unchanged:\n%s", content)
+	}
+	if warnings := card.Lint(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	if first := New().SetOptions(CardOptions{NameEmoji: EmojiTransliterate}).AddName("Mom ❤️", "").GetName().First; first != "Mom <3" {
+		t.Errorf("Expected transliterated name, got %q", first)
+	}
+	overrides := map[rune]string{'❤': "love", '🙂': ""}
+	card = New().SetOptions(CardOptions{NameEmoji: EmojiTransliterate, EmojiText: overrides})
+	overrides['❤'] = "changed"
+	if first := card.AddName("Mom ❤️ 🙂 ★", "").GetName().First; first != "Mom love *" {
+		t.Errorf("Expected the EmojiText overrides, got %q", first)
+	}
+
+	read, err := NewDecoder(strings.NewReader("BEGIN:VCARD\nVERSION:3.0\nN:Doe;Jane 🌸;;;\nFN:Jane 🌸 Doe\nEND:VCARD\n")).
+		CardOptions(CardOptions{NameEmoji: EmojiStrip}).
+		Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.GetName().First != "Jane" || read.GetFormattedName() != "Jane Doe" {
+		t.Errorf("Expected the decoder to apply the policy, got %+v %q", read.GetName(), read.GetFormattedName())
+	}
+	if New().GetName().First != "" || New().AddName("Jane 🌸", "").GetName().First != "Jane 🌸" {
+		t.Error("Expected other cards to keep the default policy")
+	}
+}
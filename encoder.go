@@ -0,0 +1,187 @@
+package vcard
+
+import (
+	"io"
+	"strings"
+)
+
+// Encoder writes vCards to an output stream
+type Encoder struct {
+	w          io.Writer
+	emitEmpty  map[string]bool
+	profile    *Profile
+	provenance bool
+	canonical  bool
+	redaction  *Redaction
+	onWarning  func(EncodeWarning)
+	warnings   []EncodeWarning
+	counts     map[Warning]int
+	encoded    int
+}
+
+// EncodeWarning is a non-fatal issue found while encoding a card, such as a
+// value dropped for the card's version or profile
+type EncodeWarning struct {
+	Warning
+
+	// Card is the position of the card in the stream, counting from zero
+	Card int
+}
+
+// NewEncoder returns an encoder that writes to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:         w,
+		emitEmpty: defaultEmitEmpty(),
+		counts:    make(map[Warning]int),
+	}
+}
+
+// defaultEmitEmpty returns the RFC-conformant empty value policy: N is always
+// written for 3.0 (where it is mandatory) and by default for 4.0, while other
+// empty properties are omitted. FN is mandatory in both versions and is
+// always written.
+func defaultEmitEmpty() map[string]bool {
+	return map[string]bool{
+		"N": true,
+	}
+}
+
+// EmitEmpty sets whether the named property is written when its value is
+// empty. Supported properties are N (4.0 only, mandatory in 3.0), ORG, TITLE,
+// ROLE and NOTE.
+func (e *Encoder) EmitEmpty(property string, emit bool) *Encoder {
+	e.emitEmpty[strings.ToUpper(property)] = emit
+	return e
+}
+
+// Profile adapts the output to a client or server, e.g. ProfileNextcloud
+func (e *Encoder) Profile(profile Profile) *Encoder {
+	e.profile = &profile
+	return e
+}
+
+// Provenance sets whether the provenance recorded with SetProvenance is
+// written as X-GOVCARD-SOURCE and X-GOVCARD-IMPORTED-AT properties, so
+// address books merged from several systems can trace where each card came
+// from
+func (e *Encoder) Provenance(emit bool) *Encoder {
+	e.provenance = emit
+	return e
+}
+
+// Redaction exports only the fields the redaction selects, e.g.
+// RedactionPublicWeb for a public directory
+func (e *Encoder) Redaction(redaction Redaction) *Encoder {
+	e.redaction = &redaction
+	return e
+}
+
+// Canonical sets whether cards are written in a canonical text format meant
+// for version control rather than exchange: properties sorted by name, lines
+// never folded and LF line endings, so unrelated edits don't produce noisy
+// diffs. Convert the files back with FromCanonical before handing them to
+// other software.
+func (e *Encoder) Canonical(enabled bool) *Encoder {
+	e.canonical = enabled
+	return e
+}
+
+// OnWarning sets a callback receiving every warning as the card raising it
+// is written, e.g. to log issues of a long-running export
+func (e *Encoder) OnWarning(fn func(EncodeWarning)) *Encoder {
+	e.onWarning = fn
+	return e
+}
+
+// Warnings returns the warnings of all cards written so far: the card's Lint
+// warnings after the profile was applied and the values the profile dropped
+// or truncated
+func (e *Encoder) Warnings() []EncodeWarning {
+	return append([]EncodeWarning(nil), e.warnings...)
+}
+
+// WarningCounts returns how often each warning was raised, for summaries
+// such as "1234 x PHOTO: remote photo URL ... is dropped"
+func (e *Encoder) WarningCounts() map[Warning]int {
+	counts := make(map[Warning]int, len(e.counts))
+	for warning, count := range e.counts {
+		counts[warning] = count
+	}
+	return counts
+}
+
+// Encode validates the card and writes it to the stream
+func (e *Encoder) Encode(card *VCard) error {
+	index := e.encoded
+	e.encoded++
+
+	if e.provenance {
+		card = card.withProvenance()
+	}
+	// Redact after adding provenance, which is a custom property too
+	if e.redaction != nil {
+		card = card.Redacted(*e.redaction)
+	}
+
+	var warnings []Warning
+	if e.profile != nil {
+		var dropped, truncated []Warning
+		card, dropped = e.profile.apply(card)
+
+		var err error
+		if truncated, err = e.profile.limit(card); err != nil {
+			return err
+		}
+		warnings = append(dropped, truncated...)
+	}
+
+	noteChunk := 0
+	if e.profile != nil && e.profile.ChunkNotes {
+		noteChunk = e.profile.maxLength("NOTE")
+	}
+
+	content, err := card.encode(e.emitEmpty, noteChunk)
+	if err != nil {
+		return err
+	}
+
+	if e.profile != nil {
+		var dropped []Warning
+		content, dropped = e.profile.order(content)
+		warnings = append(warnings, dropped...)
+		content = e.profile.finish(content)
+	}
+	if e.canonical {
+		content = canonicalize(content)
+	}
+
+	if _, err = io.WriteString(e.w, content); err != nil {
+		return err
+	}
+
+	e.warn(index, append(warnings, card.Lint()...))
+	return nil
+}
+
+// warn records the warnings of the card at index
+func (e *Encoder) warn(index int, warnings []Warning) {
+	for _, warning := range warnings {
+		encodeWarning := EncodeWarning{Warning: warning, Card: index}
+		e.warnings = append(e.warnings, encodeWarning)
+		e.counts[warning]++
+		if e.onWarning != nil {
+			e.onWarning(encodeWarning)
+		}
+	}
+}
+
+// EncodeAddressBook writes every card of the address book to the stream
+func (e *Encoder) EncodeAddressBook(book *AddressBook) error {
+	for _, card := range book.Cards() {
+		if err := e.Encode(card); err != nil {
+			return err
+		}
+	}
+	return nil
+}
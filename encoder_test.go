@@ -0,0 +1,90 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncoder(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+
+	expected, _ := card.String()
+	if buf.String() != expected {
+		t.Errorf("Encode() output differs from String():\n%s\n%s", buf.String(), expected)
+	}
+
+	if err := NewEncoder(&buf).Encode(New()); err == nil {
+		t.Error("Expected validation error for empty card")
+	}
+}
+
+func TestEncoderEmitEmpty(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+
+	var buf bytes.Buffer
+	NewEncoder(&buf).Encode(card)
+	if strings.Contains(buf.String(), "NOTE:") || strings.Contains(buf.String(), "TITLE:") {
+		t.Errorf("Empty properties should be omitted by default: %s", buf.String())
+	}
+
+	buf.Reset()
+	NewEncoder(&buf).EmitEmpty("note", true).EmitEmpty("TITLE", true).Encode(card)
+	if !strings.Contains(buf.String(), "NOTE:\n") || !strings.Contains(buf.String(), "TITLE:\n") {
+		t.Errorf("Expected empty NOTE and TITLE: %s", buf.String())
+	}
+}
+
+func TestEncoderAddressBook(t *testing.T) {
+	book := NewAddressBook(
+		New().AddName("John", "Doe"),
+		New().AddName("Jane", "Smith"),
+	)
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeAddressBook(book); err != nil {
+		t.Fatalf("EncodeAddressBook() returned error: %v", err)
+	}
+
+	if strings.Count(buf.String(), "BEGIN:VCARD") != 2 {
+		t.Errorf("Expected 2 cards, got %s", buf.String())
+	}
+}
+
+func TestEncoderWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	var received []EncodeWarning
+	encoder := NewEncoder(&buf).Profile(ProfileNextcloud).OnWarning(func(w EncodeWarning) {
+		received = append(received, w)
+	})
+
+	cards := []*VCard{
+		New().AddName("Jane", "Doe").AddPhoto("https://example.com/jane.jpg"),
+		New().AddName("John", "Doe"),
+		New().AddName("Max", "Doe").AddPhoto("https://example.com/max.jpg").AddAnniversary(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	if err := encoder.EncodeAddressBook(NewAddressBook(cards...)); err != nil {
+		t.Fatalf("EncodeAddressBook() returned error: %v", err)
+	}
+
+	warnings := encoder.Warnings()
+	if len(warnings) != 3 || len(received) != 3 {
+		t.Fatalf("Expected 3 warnings, got %v (callback %v)", warnings, received)
+	}
+	if warnings[0].Card != 0 || warnings[0].Property != "PHOTO" || warnings[1].Card != 2 || warnings[2].Property != "ANNIVERSARY" {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+
+	photo := Warning{Property: "PHOTO", Message: "remote photo URL is not loaded by the client and is dropped"}
+	if counts := encoder.WarningCounts(); counts[photo] != 2 || len(counts) != 2 {
+		t.Errorf("Unexpected warning counts: %v", counts)
+	}
+}
@@ -0,0 +1,164 @@
+package vcard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Enricher fills in card data from an outside source, such as a CRM, a
+// company directory or the card's own email domain. Enrichers should only
+// add missing values and leave existing ones unchanged.
+type Enricher interface {
+	Enrich(ctx context.Context, card *VCard) error
+}
+
+// EnricherFunc adapts a function to the Enricher interface
+type EnricherFunc func(ctx context.Context, card *VCard) error
+
+// Enrich calls f(ctx, card)
+func (f EnricherFunc) Enrich(ctx context.Context, card *VCard) error {
+	return f(ctx, card)
+}
+
+// Enrichers combines enrichers into one that runs them in order. Every
+// enricher runs even when an earlier one fails; the errors are joined.
+func Enrichers(enrichers ...Enricher) Enricher {
+	return EnricherFunc(func(ctx context.Context, card *VCard) error {
+		var errs []error
+		for _, enricher := range enrichers {
+			if err := enricher.Enrich(ctx, card); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Enrich runs the enricher on every card in the address book. Failures are
+// reported by card index; the remaining cards are still enriched unless the
+// context is done.
+func (b *AddressBook) Enrich(ctx context.Context, enricher Enricher) []ItemError {
+	var errs []ItemError
+	for i, card := range b.cards {
+		if err := ctx.Err(); err != nil {
+			return append(errs, ItemError{Index: i, Message: err.Error()})
+		}
+		if err := enricher.Enrich(ctx, card); err != nil {
+			errs = append(errs, ItemError{Index: i, Message: err.Error()})
+		}
+	}
+	return errs
+}
+
+// freeEmailDomains are email providers whose domain says nothing about the
+// contact's organization
+var freeEmailDomains = map[string]bool{
+	"aol.com":        true,
+	"gmail.com":      true,
+	"googlemail.com": true,
+	"gmx.com":        true,
+	"gmx.net":        true,
+	"hotmail.com":    true,
+	"icloud.com":     true,
+	"live.com":       true,
+	"mail.com":       true,
+	"me.com":         true,
+	"outlook.com":    true,
+	"proton.me":      true,
+	"protonmail.com": true,
+	"yahoo.com":      true,
+	"yandex.com":     true,
+}
+
+// IsFreeEmailDomain reports whether the domain belongs to a free email
+// provider, such as gmail.com, rather than to the contact's organization
+func IsFreeEmailDomain(domain string) bool {
+	return freeEmailDomains[strings.ToLower(strings.TrimSpace(domain))]
+}
+
+// secondLevelDomains are registry labels placed before a country code, as
+// in example.co.uk
+var secondLevelDomains = map[string]bool{
+	"ac":  true,
+	"co":  true,
+	"com": true,
+	"edu": true,
+	"gov": true,
+	"net": true,
+	"org": true,
+}
+
+// DomainEnricher fills ORG and URL from the domain of the card's first email
+// address that is not a free email provider. The organization name is
+// derived from the domain (acme-corp.co.uk becomes "Acme Corp") unless
+// Organizations names it, and the URL is https://domain. Values the card
+// already has are kept.
+type DomainEnricher struct {
+	// Organizations maps lower-case domains to organization names
+	Organizations map[string]string
+}
+
+// Enrich implements Enricher
+func (e DomainEnricher) Enrich(ctx context.Context, card *VCard) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	domain := organizationDomain(card)
+	if domain == "" {
+		return nil
+	}
+
+	if card.organization.Name == "" {
+		name, ok := e.Organizations[domain]
+		if !ok {
+			name = domainOrganization(domain)
+		}
+		if name != "" {
+			card.AddOrganization(name)
+		}
+	}
+	if len(card.urls) == 0 {
+		card.AddURL("https://"+domain, URLWork)
+	}
+
+	return nil
+}
+
+// organizationDomain returns the lower-case domain of the card's first
+// email address that is not a free email provider
+func organizationDomain(card *VCard) string {
+	for _, email := range card.emails {
+		at := strings.LastIndex(email.Address, "@")
+		if at < 0 {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimSpace(email.Address[at+1:]))
+		if strings.Contains(domain, ".") && !IsFreeEmailDomain(domain) {
+			return domain
+		}
+	}
+	return ""
+}
+
+// domainOrganization derives an organization name from the registrable
+// label of a domain, e.g. "Acme Corp" for mail.acme-corp.co.uk
+func domainOrganization(domain string) string {
+	labels := strings.Split(domain, ".")
+	labels = labels[:len(labels)-1]
+	if len(labels) > 1 && secondLevelDomains[labels[len(labels)-1]] {
+		labels = labels[:len(labels)-1]
+	}
+
+	words := strings.FieldsFunc(labels[len(labels)-1], func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
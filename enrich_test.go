@@ -0,0 +1,101 @@
+package vcard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDomainEnricher(t *testing.T) {
+	tests := []struct {
+		name         string
+		card         *VCard
+		organization string
+		url          string
+	}{
+		{"derived", New().AddEmail("jane@acme-corp.com"), "Acme Corp", "https://acme-corp.com"},
+		{"country code", New().AddEmail("jane@mail.globex.co.uk"), "Globex", "https://mail.globex.co.uk"},
+		{"free email skipped", New().AddEmail("jane@gmail.com").AddEmail("jane@Initech.IO"), "Initech", "https://initech.io"},
+		{"free email only", New().AddEmail("jane@Yahoo.com"), "", ""},
+		{"no email", New().AddName("Jane", "Doe"), "", ""},
+		{"existing kept", New().AddEmail("jane@acme.com").AddOrganization("Acme Inc.").AddURL("https://acme.example"), "Acme Inc.", "https://acme.example"},
+		{"mapped", New().AddEmail("jane@ibm.com"), "IBM", "https://ibm.com"},
+	}
+
+	enricher := DomainEnricher{Organizations: map[string]string{"ibm.com": "IBM"}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := enricher.Enrich(context.Background(), test.card); err != nil {
+				t.Fatalf("Enrich() returned error: %v", err)
+			}
+			if org := test.card.GetOrganization().Name; org != test.organization {
+				t.Errorf("Expected organization %q, got %q", test.organization, org)
+			}
+			url := ""
+			if urls := test.card.GetURLs(); len(urls) > 0 {
+				url = urls[0].Address
+			}
+			if url != test.url {
+				t.Errorf("Expected URL %q, got %q", test.url, url)
+			}
+		})
+	}
+}
+
+func TestEnrichers(t *testing.T) {
+	failure := errors.New("lookup failed")
+	var calls []string
+	enricher := Enrichers(
+		EnricherFunc(func(ctx context.Context, card *VCard) error {
+			calls = append(calls, "first")
+			return failure
+		}),
+		EnricherFunc(func(ctx context.Context, card *VCard) error {
+			calls = append(calls, "second")
+			card.AddNote("enriched")
+			return nil
+		}),
+	)
+
+	card := New()
+	if err := enricher.Enrich(context.Background(), card); !errors.Is(err, failure) {
+		t.Errorf("Expected joined error, got %v", err)
+	}
+	if len(calls) != 2 || card.GetNote() != "enriched" {
+		t.Errorf("Expected every enricher to run, got %v", calls)
+	}
+}
+
+func TestAddressBookEnrich(t *testing.T) {
+	book := NewAddressBook(
+		New().AddName("Jane", "Doe").AddEmail("jane@acme.com"),
+		New().AddName("John", "Doe").AddEmail("john@fail.com"),
+	)
+	enricher := Enrichers(DomainEnricher{}, EnricherFunc(func(ctx context.Context, card *VCard) error {
+		if card.GetOrganization().Name == "Fail" {
+			return errors.New("rejected")
+		}
+		return nil
+	}))
+
+	errs := book.Enrich(context.Background(), enricher)
+	if len(errs) != 1 || errs[0].Index != 1 || errs[0].Message != "rejected" {
+		t.Errorf("Unexpected errors: %+v", errs)
+	}
+	if org := book.Cards()[0].GetOrganization().Name; org != "Acme" {
+		t.Errorf("Expected Acme, got %q", org)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = NewAddressBook(New(), New()).Enrich(ctx, DomainEnricher{})
+	if len(errs) != 1 || errs[0].Index != 0 {
+		t.Errorf("Expected enrichment to stop on a done context, got %+v", errs)
+	}
+}
+
+func TestIsFreeEmailDomain(t *testing.T) {
+	if !IsFreeEmailDomain(" GMail.com ") || IsFreeEmailDomain("acme.com") {
+		t.Error("Unexpected free email detection")
+	}
+}
@@ -0,0 +1,166 @@
+// Package events wraps contact changes in versioned envelopes for message
+// brokers such as Kafka and NATS.
+//
+// An envelope carries the event type, the card's UID (also the partition
+// key, so events of one contact stay ordered), the vCard text and, for
+// updates, the property changes:
+//
+//	event, err := events.Updated(before, after)
+//	event.Source = "crm"
+//	payload, err := event.Marshal()
+//	err = nc.PublishMsg(&nats.Msg{Subject: "contacts." + event.Type, Data: payload, Header: nats.Header(event.Headers())})
+//
+// Consumers decode payloads with Unmarshal, which rejects envelopes with a
+// newer schema version. The vcardpb module holds the matching Protocol
+// Buffers message.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.rumenx.com/vcard"
+)
+
+// SchemaVersion is the envelope version written by this package
+const SchemaVersion = 1
+
+// ContentType is the media type of marshaled envelopes
+const ContentType = "application/json"
+
+// Event types
+const (
+	ContactCreated = "contact.created"
+	ContactUpdated = "contact.updated"
+	ContactDeleted = "contact.deleted"
+)
+
+// ErrSchemaVersion is returned for envelopes with a newer schema version
+var ErrSchemaVersion = errors.New("events: unsupported schema version")
+
+// Envelope is a contact change event
+type Envelope struct {
+	// SchemaVersion is the envelope version (SchemaVersion)
+	SchemaVersion int `json:"schemaVersion"`
+
+	// ID uniquely identifies the event, for deduplication by consumers
+	ID string `json:"id"`
+
+	// Type is ContactCreated, ContactUpdated or ContactDeleted
+	Type string `json:"type"`
+
+	// Source optionally names the producing system
+	Source string `json:"source,omitempty"`
+
+	// Time is when the event was created, in UTC
+	Time time.Time `json:"time"`
+
+	// UID is the UID of the contact
+	UID string `json:"uid,omitempty"`
+
+	// Version is the vCard version of Card
+	Version string `json:"version,omitempty"`
+
+	// Card is the vCard text of the contact after the change; it is empty
+	// for deletions
+	Card string `json:"card,omitempty"`
+
+	// Changes lists the changed properties of updates
+	Changes []vcard.Change `json:"changes,omitempty"`
+}
+
+// Created returns the event for a new contact. It fails when the card
+// can't be generated.
+func Created(card *vcard.VCard) (Envelope, error) {
+	return newEnvelope(ContactCreated, card)
+}
+
+// Updated returns the event for a changed contact, with the changes from
+// the old to the current card. It fails when the current card can't be
+// generated.
+func Updated(old, current *vcard.VCard) (Envelope, error) {
+	event, err := newEnvelope(ContactUpdated, current)
+	if err != nil {
+		return Envelope{}, err
+	}
+	event.Changes = vcard.Diff(old, current)
+	return event, nil
+}
+
+// Deleted returns the event for a removed contact
+func Deleted(card *vcard.VCard) Envelope {
+	return Envelope{
+		SchemaVersion: SchemaVersion,
+		ID:            newID(),
+		Type:          ContactDeleted,
+		Time:          time.Now().UTC(),
+		UID:           card.GetUID(),
+	}
+}
+
+// newEnvelope returns an event carrying the card
+func newEnvelope(eventType string, card *vcard.VCard) (Envelope, error) {
+	content, err := card.String()
+	if err != nil {
+		return Envelope{}, fmt.Errorf("events: %w", err)
+	}
+	return Envelope{
+		SchemaVersion: SchemaVersion,
+		ID:            newID(),
+		Type:          eventType,
+		Time:          time.Now().UTC(),
+		UID:           card.GetUID(),
+		Version:       card.GetVersion().String(),
+		Card:          content,
+	}, nil
+}
+
+// newID returns a random version 4 UUID
+func newID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+
+	encoded := hex.EncodeToString(id[:])
+	return encoded[:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
+
+// Key returns the message key: the contact's UID, so brokers partitioning
+// by key deliver the events of one contact in order
+func (e Envelope) Key() []byte {
+	return []byte(e.UID)
+}
+
+// Headers returns message headers describing the envelope, for consumers
+// routing events without decoding the payload
+func (e Envelope) Headers() map[string][]string {
+	return map[string][]string{
+		"Content-Type":   {ContentType},
+		"Event-Type":     {e.Type},
+		"Event-Id":       {e.ID},
+		"Schema-Version": {strconv.Itoa(e.SchemaVersion)},
+	}
+}
+
+// Marshal encodes the envelope as JSON
+func (e Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// Unmarshal decodes a JSON envelope, rejecting newer schema versions
+func Unmarshal(data []byte) (Envelope, error) {
+	var event Envelope
+	if err := json.Unmarshal(data, &event); err != nil {
+		return Envelope{}, fmt.Errorf("events: %w", err)
+	}
+	if event.SchemaVersion > SchemaVersion {
+		return Envelope{}, fmt.Errorf("%w: %d", ErrSchemaVersion, event.SchemaVersion)
+	}
+	return event, nil
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestCreated(t *testing.T) {
+	card := vcard.New().AddName("Jane", "Doe").SetUID("urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1")
+	event, err := Created(card)
+	if err != nil {
+		t.Fatalf("Created() returned error: %v", err)
+	}
+
+	if event.SchemaVersion != SchemaVersion || event.Type != ContactCreated || event.Version != "3.0" {
+		t.Errorf("Unexpected envelope: %+v", event)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(event.ID) {
+		t.Errorf("Expected a UUID, got %q", event.ID)
+	}
+	if again, _ := Created(card); event.ID == again.ID {
+		t.Error("Expected unique event IDs")
+	}
+	if time.Since(event.Time) > time.Minute || event.Time.Location() != time.UTC {
+		t.Errorf("Unexpected time %v", event.Time)
+	}
+	if !strings.Contains(event.Card, "FN:Jane Doe") || string(event.Key()) != card.GetUID() {
+		t.Errorf("Unexpected card or key: %q, %q", event.Card, event.Key())
+	}
+
+	headers := event.Headers()
+	if headers["Event-Type"][0] != ContactCreated || headers["Schema-Version"][0] != "1" || headers["Content-Type"][0] != ContentType {
+		t.Errorf("Unexpected headers: %v", headers)
+	}
+}
+
+func TestUpdatedAndDeleted(t *testing.T) {
+	old := vcard.New().AddName("Jane", "Doe").AddEmail("jane@example.com").SetUID("jane")
+	current := vcard.New().AddName("Jane", "Doe").AddEmail("jane.doe@example.com").SetUID("jane")
+
+	event, err := Updated(old, current)
+	if err != nil {
+		t.Fatalf("Updated() returned error: %v", err)
+	}
+	if event.Type != ContactUpdated || len(event.Changes) != 1 || event.Changes[0].Property != "EMAIL" {
+		t.Errorf("Unexpected update: %+v", event)
+	}
+	if !strings.Contains(event.Card, "jane.doe@example.com") {
+		t.Errorf("Expected the current card, got %q", event.Card)
+	}
+
+	deleted := Deleted(old)
+	if deleted.Type != ContactDeleted || deleted.UID != "jane" || deleted.Card != "" {
+		t.Errorf("Unexpected deletion: %+v", deleted)
+	}
+}
+
+func TestInvalidCard(t *testing.T) {
+	card := vcard.NewWithVersion(vcard.Version40).SetFormattedName("Jane").AddAlternate("FN", "en:x", "Jane")
+
+	if _, err := Created(card); err == nil {
+		t.Error("Expected Created to fail for a card that can't be generated")
+	}
+	if _, err := Updated(vcard.New(), card); err == nil {
+		t.Error("Expected Updated to fail for a card that can't be generated")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	old := vcard.New().AddName("Jane", "Doe").SetUID("jane")
+	event, err := Updated(old, vcard.New().AddName("Janet", "Doe").SetUID("jane"))
+	if err != nil {
+		t.Fatalf("Updated() returned error: %v", err)
+	}
+	event.Source = "crm"
+
+	payload, err := event.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	if !strings.Contains(string(payload), `"changes":[{"property":"FN","removed":["Jane Doe"],"added":["Janet Doe"]}`) {
+		t.Errorf("Unexpected payload: %s", payload)
+	}
+
+	decoded, err := Unmarshal(payload)
+	if err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+	if decoded.ID != event.ID || decoded.Source != "crm" || !decoded.Time.Equal(event.Time) || len(decoded.Changes) != 2 {
+		t.Errorf("Round trip changed the envelope: %+v", decoded)
+	}
+
+	if _, err := Unmarshal([]byte(`{"schemaVersion": 2, "type": "contact.merged"}`)); !errors.Is(err, ErrSchemaVersion) {
+		t.Errorf("Expected ErrSchemaVersion, got %v", err)
+	}
+	if _, err := Unmarshal([]byte(`not json`)); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
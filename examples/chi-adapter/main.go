@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	vcard "go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/server"
 )
 
 // VCardResponse represents the JSON response structure
@@ -22,23 +23,6 @@ type VCardResponse struct {
 	Title        string `json:"title,omitempty"`
 }
 
-// VCardMiddleware is a Chi middleware that adds vCard creation functionality
-func VCardMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Add CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 // CreateVCardFromParams creates a vCard from URL parameters
 func CreateVCardFromParams(r *http.Request) (*vcard.VCard, error) {
 	query := r.URL.Query()
@@ -80,7 +64,10 @@ func main() {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
-	r.Use(VCardMiddleware)
+	r.Use(server.CORS(server.CORSOptions{
+		AllowedOrigins: []string{"http://localhost:3000"},
+		MaxAge:         time.Hour,
+	}))
 
 	// Routes
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
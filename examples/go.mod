@@ -5,3 +5,5 @@ go 1.23.6
 replace go.rumenx.com/vcard => ../
 
 require go.rumenx.com/vcard v0.0.0-00010101000000-000000000000
+
+require golang.org/x/text v0.28.0 // indirect
@@ -0,0 +1,104 @@
+package vcard
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultFilename is used when a filename template expands to nothing
+const DefaultFilename = "contact.vcf"
+
+// DefaultBulkFilename is used for responses containing multiple cards
+const DefaultBulkFilename = "contacts.vcf"
+
+// Filename expands a filename template using the card's fields and returns a
+// file-system safe name ending in ".vcf".
+//
+// Supported tokens:
+//
+//	{first}, {last}, {middle}, {prefix}, {suffix} - name parts
+//	{name}    - formatted full name
+//	{org}     - organization name
+//	{email}   - first email address
+//	{phone}   - first phone number
+//	{version} - vCard version
+//
+// For example "{first}-{last}" produces "John-Doe.vcf".
+func (v *VCard) Filename(template string) string {
+	replacer := strings.NewReplacer(
+		"{first}", v.name.First,
+		"{last}", v.name.Last,
+		"{middle}", v.name.Middle,
+		"{prefix}", v.name.Prefix,
+		"{suffix}", v.name.Suffix,
+		"{name}", v.name.FormattedName(),
+		"{org}", v.organization.Name,
+		"{email}", v.GetEmail(),
+		"{phone}", v.GetPhone(),
+		"{version}", v.version.String(),
+	)
+
+	name := sanitizeFilename(replacer.Replace(template))
+	name = strings.TrimSuffix(name, FileExtension)
+	if strings.Trim(name, "-_.") == "" {
+		return DefaultFilename
+	}
+
+	return name + FileExtension
+}
+
+// UniqueFilename returns name, or when it is already used the first of
+// "name-2.vcf", "name-3.vcf" and so on that is not, and marks the result as
+// used. It keeps a set of file names such as those of an export free of
+// collisions, including with names that already end in a counter.
+func UniqueFilename(used map[string]bool, name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	unique := name
+	for n := 2; used[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+	used[unique] = true
+	return unique
+}
+
+// sanitizeFilename replaces characters that are unsafe in file names or
+// Content-Disposition headers
+func sanitizeFilename(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			continue
+		case strings.ContainsRune(`/\:*?"<>|;`, r):
+			builder.WriteRune('_')
+		case r == ' ':
+			builder.WriteRune('-')
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// Filename returns the filename for the cards of the address book: the name
+// the template gives each card (see VCard.Filename) when all cards give the
+// same one, e.g. "{org}" for the members of one organization, and
+// DefaultBulkFilename otherwise
+func (b *AddressBook) Filename(template string) string {
+	if template == "" || len(b.cards) == 0 {
+		return DefaultBulkFilename
+	}
+
+	name := b.cards[0].Filename(template)
+	for _, card := range b.cards[1:] {
+		if card.Filename(template) != name {
+			return DefaultBulkFilename
+		}
+	}
+	if name == DefaultFilename {
+		return DefaultBulkFilename
+	}
+	return name
+}
@@ -0,0 +1,74 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilename(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddOrganization("Acme Corp")
+	card.AddEmail("john@example.com")
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"{first}-{last}", "John-Doe.vcf"},
+		{"{name}", "John-Doe.vcf"},
+		{"{org}/{last}", "Acme-Corp_Doe.vcf"},
+		{"{email}.vcf", "john@example.com.vcf"},
+		{"card-v{version}", "card-v3.0.vcf"},
+		{"{middle}", DefaultFilename},
+		{"", DefaultFilename},
+	}
+
+	for _, tt := range tests {
+		if got := card.Filename(tt.template); got != tt.expected {
+			t.Errorf("Filename(%q) = %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestAddressBookFilename(t *testing.T) {
+	john := New().AddName("John", "Doe").AddOrganization("Acme Corp")
+	jane := New().AddName("Jane", "Roe").AddOrganization("Acme Corp")
+
+	tests := []struct {
+		book     *AddressBook
+		template string
+		expected string
+	}{
+		{NewAddressBook(john, jane), "{org}", "Acme-Corp.vcf"},
+		{NewAddressBook(john, jane), "{last}", DefaultBulkFilename},
+		{NewAddressBook(john), "{first}-{last}", "John-Doe.vcf"},
+		{NewAddressBook(john), "{middle}", DefaultBulkFilename},
+		{NewAddressBook(john, jane), "", DefaultBulkFilename},
+		{NewAddressBook(), "{org}", DefaultBulkFilename},
+	}
+
+	for _, tt := range tests {
+		if got := tt.book.Filename(tt.template); got != tt.expected {
+			t.Errorf("Filename(%q) = %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	if got := sanitizeFilename("a\"b\r\nc d"); got != "a_bc-d" {
+		t.Errorf("sanitizeFilename() = %q, want %q", got, "a_bc-d")
+	}
+}
+
+func TestUniqueFilename(t *testing.T) {
+	used := map[string]bool{}
+	var names []string
+	for _, name := range []string{"jane.vcf", "jane.vcf", "jane-2.vcf", "jane.vcf", "card"} {
+		names = append(names, UniqueFilename(used, name))
+	}
+	expected := "jane.vcf jane-2.vcf jane-2-2.vcf jane-3.vcf card"
+	if got := strings.Join(names, " "); got != expected {
+		t.Errorf("UniqueFilename() gave %s, want %s", got, expected)
+	}
+}
@@ -0,0 +1,91 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+// getterCard returns a card with every field that has a getter set
+func getterCard() *VCard {
+	return NewWithVersion(Version40).
+		SetKind(KindGroup).
+		AddName("Jane", "Doe").
+		AddEmail("jane@example.com").
+		AddPhone("+15551234567").
+		AddAddress("1 Main St", "Springfield", "IL", "62701", "USA").
+		AddOrganization("Acme").
+		AddDepartment("Engineering").
+		AddOrgUnit("Platform").
+		AddURL("https://example.com").
+		SetGeo(42.1, -71.2).
+		AddBirthday(time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC)).
+		AddAnniversary(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)).
+		AddMember("urn:uuid:1").
+		AddCustomProperty("X-TEST", "value")
+}
+
+func TestGettersReturnCopies(t *testing.T) {
+	card := getterCard()
+	before, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+
+	card.GetEmails()[0].Address = "changed@example.com"
+	card.GetPhones()[0].Number = "0"
+	card.GetAddresses()[0].City = "Changed"
+	card.GetURLs()[0].Address = "https://changed.example.com"
+	card.GetOrganization().Units[0] = "Changed"
+	card.GetOrgUnits()[0] = "Changed"
+	card.GetGeo().Latitude = 0
+	*card.GetBirthday() = time.Time{}
+	*card.GetAnniversary() = time.Time{}
+	card.GetMembers()[0] = "changed"
+	card.GetCustomProperties()["X-TEST"] = "changed"
+	card.GetAddress().Street = "Changed"
+
+	after, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if after != before {
+		t.Errorf("Mutating getter results changed the card:\n%s\nwant:\n%s", after, before)
+	}
+}
+
+func TestGettersKeepEmptySlices(t *testing.T) {
+	card := New()
+	if card.GetEmails() == nil || card.GetPhones() == nil || card.GetAddresses() == nil || card.GetURLs() == nil {
+		t.Error("Expected empty, non-nil slices for a new card")
+	}
+	if card.GetGeo() != nil || card.GetBirthday() != nil || card.GetAnniversary() != nil || card.GetAddress() != nil {
+		t.Error("Expected nil for unset fields")
+	}
+}
+
+func TestAddressBookCardsCopiesList(t *testing.T) {
+	book := NewAddressBook(New().AddName("Jane", "Doe"))
+	book.Cards()[0] = nil
+	if book.Cards()[0] == nil {
+		t.Error("Expected Cards to return a copy of the list")
+	}
+}
+
+func BenchmarkGetEmails(b *testing.B) {
+	card := getterCard()
+	for i := 0; i < 4; i++ {
+		card.AddEmail("extra@example.com")
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = card.GetEmails()
+	}
+}
+
+func BenchmarkGetOrganization(b *testing.B) {
+	card := getterCard()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = card.GetOrganization()
+	}
+}
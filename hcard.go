@@ -0,0 +1,85 @@
+package vcard
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+)
+
+// hCardTemplate renders a microformats2 h-card. html/template escapes every
+// value for its context, so card data cannot inject markup or script.
+var hCardTemplate = template.Must(template.New("h-card").Parse(`<div class="h-card">
+{{- with .Avatar}}
+<img class="u-photo" src="{{.}}" alt="">
+{{- end}}
+<span class="p-name">{{.Name}}</span>
+{{- with .Title}}
+<span class="p-job-title">{{.}}</span>
+{{- end}}
+{{- with .Organization}}
+<span class="p-org">{{.}}</span>
+{{- end}}
+{{- with .Email}}
+<a class="u-email" href="mailto:{{.}}">{{.}}</a>
+{{- end}}
+{{- if .Tel}}
+<a class="p-tel" href="{{.Tel}}">{{.Phone}}</a>
+{{- else if .Phone}}
+<span class="p-tel">{{.Phone}}</span>
+{{- end}}
+{{- with .URL}}
+<a class="u-url" href="{{.}}">{{.}}</a>
+{{- end}}
+{{- with .Address}}
+<span class="p-adr">{{.}}</span>
+{{- end}}
+{{- with .Note}}
+<p class="p-note">{{.}}</p>
+{{- end}}
+</div>
+`))
+
+// HCard renders the card as an escaped microformats2 h-card HTML fragment,
+// safe to embed in a page or html/template even when the card holds
+// untrusted values. Fields follow the preference and fallback rules of
+// Preview.
+func (v *VCard) HCard() template.HTML {
+	preview := v.Preview()
+
+	data := struct {
+		Avatar       template.URL
+		Name         string
+		Title        string
+		Organization string
+		Email        string
+		Phone        string
+		Tel          template.URL
+		URL          string
+		Address      string
+		Note         string
+	}{
+		// Preview only returns web and image data URIs, which html/template
+		// would otherwise replace in src attributes
+		Avatar:  template.URL(preview.Avatar),
+		Name:    preview.DisplayName,
+		Title:   v.organization.Title,
+		Email:   preview.Email,
+		Phone:   preview.Phone,
+		URL:     preview.URL,
+		Address: preview.Address,
+		Note:    v.note,
+	}
+	if v.organization.Name != preview.DisplayName {
+		data.Organization = v.organization.Name
+	}
+	if tel := (Phone{Number: preview.Phone}).Format(PhoneFormatRFC3966); strings.HasPrefix(tel, "tel:") {
+		data.Tel = template.URL(tel)
+	}
+
+	var buf bytes.Buffer
+	if err := hCardTemplate.Execute(&buf, data); err != nil {
+		// writing to a bytes.Buffer does not fail
+		return ""
+	}
+	return template.HTML(buf.String())
+}
@@ -0,0 +1,56 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHCard(t *testing.T) {
+	card := New().AddName("John", "Doe").
+		AddEmail("john@example.com").
+		AddPhone("+44 20 7946 0958").
+		AddOrganization("Acme").AddTitle("Engineer").
+		AddURL("https://example.com").
+		AddPhoto("https://example.com/john.jpg").
+		AddNote("Met at GopherCon")
+
+	html := string(card.HCard())
+	for _, expected := range []string{
+		`<div class="h-card">`,
+		`<img class="u-photo" src="https://example.com/john.jpg" alt="">`,
+		`<span class="p-name">John Doe</span>`,
+		`<span class="p-job-title">Engineer</span>`,
+		`<span class="p-org">Acme</span>`,
+		`<a class="u-email" href="mailto:john@example.com">john@example.com</a>`,
+		`<a class="p-tel" href="tel:&#43;44-20-7946-0958">&#43;44 20 7946 0958</a>`,
+		`<a class="u-url" href="https://example.com">https://example.com</a>`,
+		`<p class="p-note">Met at GopherCon</p>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %q in h-card:\n%s", expected, html)
+		}
+	}
+}
+
+func TestHCardEscaping(t *testing.T) {
+	card := New().
+		SetFormattedName(`<img src=x onerror="alert(1)">`).
+		AddName("John", "Doe").
+		AddNote("</p><script>alert(2)</script>").
+		AddPhone("javascript:alert(3)").
+		AddPhoto("data:text/html;base64,PHNjcmlwdD4=")
+	card.urls = append(card.urls, URL{Address: "javascript:alert(4)"})
+
+	html := string(card.HCard())
+	for _, unexpected := range []string{"<script>", "<img src=x", `href="javascript:`, "data:text/html"} {
+		if strings.Contains(html, unexpected) {
+			t.Errorf("Did not expect %q in h-card:\n%s", unexpected, html)
+		}
+	}
+	if !strings.Contains(html, `<span class="p-tel">javascript:alert(3)</span>`) {
+		t.Errorf("Expected unparseable phone as plain text:\n%s", html)
+	}
+	if !strings.Contains(html, `src="data:image/svg&#43;xml;base64,`) {
+		t.Errorf("Expected monogram in place of the unsafe photo:\n%s", html)
+	}
+}
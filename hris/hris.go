@@ -0,0 +1,120 @@
+// Package hris maps employee exports of HR information systems to contacts.
+//
+// Profiles cover the JSON shapes of the BambooHR employee directory and the
+// Workday workers API; Contacts decodes an export and maps every employee:
+//
+//	contacts, err := hris.Contacts(hris.BambooHR, body)
+//	if err != nil {
+//		return err
+//	}
+//	book, errs := vcard.NewAddressBookFromContacts(contacts, vcard.Version40)
+//
+// Custom profiles can be built with the mapping package, using
+// dot-separated attribute names for nested fields ("location.descriptor").
+package hris
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/mapping"
+)
+
+// ErrNoEmployees is returned when an export holds no employee list
+var ErrNoEmployees = errors.New("hris: no employee list found")
+
+// ListKeys are the object keys holding the employee list in exports that
+// wrap it in an object: "employees" (BambooHR), "data" (Workday REST) and
+// "Report_Entry" (Workday reports)
+var ListKeys = []string{"employees", "data", "Report_Entry"}
+
+// EmployeeID is the custom property holding the employee number
+const EmployeeID = "X-EMPLOYEE-ID"
+
+// BambooHR maps the BambooHR employee directory and employee fields
+var BambooHR = mapping.Profile{
+	Name: "bamboohr",
+	Fields: []mapping.Field{
+		{Attribute: "firstName", Target: mapping.FirstName},
+		{Attribute: "middleName", Target: mapping.MiddleName},
+		{Attribute: "lastName", Target: mapping.LastName},
+		{Attribute: "preferredName", Target: mapping.Custom("NICKNAME")},
+		{Attribute: "pronouns", Target: mapping.Custom("PRONOUNS")},
+		{Attribute: "workEmail", Target: mapping.Email(vcard.EmailWork)},
+		{Attribute: "homeEmail", Target: mapping.Email(vcard.EmailHome)},
+		{Attribute: "workPhone", Target: mapping.Phone(vcard.PhoneWork)},
+		{Attribute: "mobilePhone", Target: mapping.Phone(vcard.PhoneMobile)},
+		{Attribute: "homePhone", Target: mapping.Phone(vcard.PhoneHome)},
+		{Attribute: "jobTitle", Target: mapping.Title},
+		{Attribute: "department", Target: mapping.Department},
+		{Attribute: "division", Target: mapping.Unit},
+		{Attribute: "location", Target: mapping.Extended(vcard.AddressWork)},
+		{Attribute: "linkedIn", Target: mapping.URL(vcard.URLSocial)},
+		{Attribute: "photoUrl", Target: mapping.Photo},
+		{Attribute: "employeeNumber", Target: mapping.Custom(EmployeeID)},
+	},
+}
+
+// Workday maps the workers of the Workday REST API
+var Workday = mapping.Profile{
+	Name: "workday",
+	Fields: []mapping.Field{
+		{Attribute: "descriptor", Target: mapping.FullName},
+		{Attribute: "person.legalName.firstName", Target: mapping.FirstName},
+		{Attribute: "person.legalName.lastName", Target: mapping.LastName},
+		{Attribute: "primaryWorkEmail", Target: mapping.Email(vcard.EmailWork)},
+		{Attribute: "primaryWorkPhone", Target: mapping.Phone(vcard.PhoneWork)},
+		{Attribute: "businessTitle", Target: mapping.Title},
+		{Attribute: "primarySupervisoryOrganization.descriptor", Target: mapping.Department},
+		{Attribute: "location.descriptor", Target: mapping.Extended(vcard.AddressWork)},
+		{Attribute: "workerId", Target: mapping.Custom(EmployeeID)},
+	},
+}
+
+// Contacts decodes an HRIS JSON export and maps every employee with the
+// profile. The export is either an array of employee objects or an object
+// holding the array under one of ListKeys.
+func Contacts(profile mapping.Profile, data []byte) ([]vcard.Contact, error) {
+	employees, err := employeeList(data)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]vcard.Contact, 0, len(employees))
+	for i, employee := range employees {
+		record, err := mapping.RecordFromJSON(employee)
+		if err != nil {
+			return nil, fmt.Errorf("hris: employee %d: %w", i, err)
+		}
+		contacts = append(contacts, profile.Contact(record))
+	}
+	return contacts, nil
+}
+
+// employeeList returns the raw employee objects of an export
+func employeeList(data []byte) ([]json.RawMessage, error) {
+	var employees []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &employees); err != nil {
+			return nil, fmt.Errorf("hris: decoding export: %w", err)
+		}
+		return employees, nil
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("hris: decoding export: %w", err)
+	}
+	for _, key := range ListKeys {
+		if list, ok := wrapper[key]; ok {
+			if err := json.Unmarshal(list, &employees); err != nil {
+				return nil, fmt.Errorf("hris: decoding %s: %w", key, err)
+			}
+			return employees, nil
+		}
+	}
+	return nil, ErrNoEmployees
+}
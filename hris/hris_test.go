@@ -0,0 +1,136 @@
+package hris
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+const bambooDirectory = `{
+	"fields": [{"id": "displayName", "type": "text", "name": "Display name"}],
+	"employees": [
+		{
+			"id": "123",
+			"displayName": "John Doe",
+			"firstName": "John",
+			"lastName": "Doe",
+			"preferredName": null,
+			"jobTitle": "Customer Service Representative",
+			"workPhone": "555-555-5555",
+			"mobilePhone": "555-555-1234",
+			"workEmail": "jdoe@company.com",
+			"department": "Customer Service",
+			"location": "Lindon, Utah",
+			"division": "North America",
+			"linkedIn": "https://www.linkedin.com/in/jdoe",
+			"photoUploaded": true,
+			"photoUrl": "https://images.example.com/photos/123.jpg"
+		},
+		{"id": "124", "firstName": "Jane", "lastName": "Roe", "preferredName": "JR", "employeeNumber": 1042}
+	]
+}`
+
+const workdayWorkers = `{
+	"total": 1,
+	"data": [
+		{
+			"id": "3aa5550b7fe348b98d7b5741afc65534",
+			"descriptor": "Logan McNeil",
+			"workerId": "21001",
+			"primaryWorkEmail": "lmcneil@workday.net",
+			"primaryWorkPhone": "+1 (925) 555-0101",
+			"businessTitle": "Chief Human Resources Officer",
+			"isManager": true,
+			"location": {"id": "9f9", "descriptor": "San Francisco"},
+			"primarySupervisoryOrganization": {"id": "a1b", "descriptor": "Human Resources"}
+		}
+	]
+}`
+
+func TestBambooHR(t *testing.T) {
+	contacts, err := Contacts(BambooHR, []byte(bambooDirectory))
+	if err != nil {
+		t.Fatalf("Contacts() returned error: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("Expected 2 contacts, got %d", len(contacts))
+	}
+
+	john := contacts[0]
+	if john.Name.First != "John" || john.Name.Last != "Doe" {
+		t.Errorf("Unexpected name: %+v", john.Name)
+	}
+	if len(john.Phones) != 2 || john.Phones[1].Type != vcard.PhoneMobile {
+		t.Errorf("Unexpected phones: %+v", john.Phones)
+	}
+	if org := john.Organization; org.Department != "Customer Service" || org.Title != "Customer Service Representative" || len(org.Units) != 1 {
+		t.Errorf("Unexpected organization: %+v", org)
+	}
+	if len(john.Addresses) != 1 || john.Addresses[0].Extended != "Lindon, Utah" {
+		t.Errorf("Unexpected addresses: %+v", john.Addresses)
+	}
+	if len(john.URLs) != 1 || john.URLs[0].Type != vcard.URLSocial || john.Photo == "" {
+		t.Errorf("Unexpected URLs or photo: %+v, %q", john.URLs, john.Photo)
+	}
+
+	jane := contacts[1]
+	if jane.CustomProps["NICKNAME"] != "JR" || jane.CustomProps[EmployeeID] != "1042" {
+		t.Errorf("Unexpected custom properties: %v", jane.CustomProps)
+	}
+
+	book, errs := vcard.NewAddressBookFromContacts(contacts, vcard.Version40)
+	if len(errs) > 0 || book.Len() != 2 {
+		t.Errorf("Expected valid cards, got %+v", errs)
+	}
+}
+
+func TestWorkday(t *testing.T) {
+	contacts, err := Contacts(Workday, []byte(workdayWorkers))
+	if err != nil {
+		t.Fatalf("Contacts() returned error: %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Fatalf("Expected 1 contact, got %d", len(contacts))
+	}
+
+	card := vcard.New().AddContact(contacts[0])
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	for _, expected := range []string{
+		"N:McNeil;Logan;;;",
+		"EMAIL;TYPE=WORK:lmcneil@workday.net",
+		"ORG:;Human Resources",
+		"TITLE:Chief Human Resources Officer",
+		"ADR;TYPE=WORK:;San Francisco;;;;;",
+		"X-EMPLOYEE-ID:21001",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
+func TestContactsShapes(t *testing.T) {
+	contacts, err := Contacts(BambooHR, []byte(`[{"firstName": "Jane"}, {"firstName": "John"}]`))
+	if err != nil || len(contacts) != 2 || contacts[1].Name.First != "John" {
+		t.Errorf("Expected a plain array to be read, got %+v, %v", contacts, err)
+	}
+
+	report, err := Contacts(Workday, []byte(`{"Report_Entry": [{"descriptor": "Ann Lee"}]}`))
+	if err != nil || len(report) != 1 || report[0].Name.Last != "Lee" {
+		t.Errorf("Expected a report to be read, got %+v, %v", report, err)
+	}
+
+	if _, err := Contacts(BambooHR, []byte(`{"people": []}`)); !errors.Is(err, ErrNoEmployees) {
+		t.Errorf("Expected ErrNoEmployees, got %v", err)
+	}
+	for _, data := range []string{`not json`, `{"employees": {"a": 1}}`, `[1, 2]`} {
+		if _, err := Contacts(BambooHR, []byte(data)); err == nil {
+			t.Errorf("Expected error for %s", data)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package vcard
+
+import "sort"
+
+// LibraryVersion is the go-vcard release reported by GetInfo
+const LibraryVersion = "0.1.0"
+
+// Feature flags reported by GetInfo
+const (
+	FeatureCustomProperties   = "custom-properties"
+	FeatureFilenameTemplate   = "filename-template"
+	FeaturePhotoDataURI       = "photo-data-uri"
+	FeatureVersionNegotiation = "version-negotiation"
+)
+
+// Info describes the library and its capabilities, suitable for serving from
+// health or metadata endpoints
+type Info struct {
+	// Library is the module path
+	Library string `json:"library"`
+
+	// Version is the library release
+	Version string `json:"version"`
+
+	// SupportedVersions lists the vCard versions that can be generated
+	SupportedVersions []Version `json:"supportedVersions"`
+
+	// Features lists the optional capabilities available in this release
+	Features []string `json:"features"`
+
+	// Capabilities lists the properties and formats, see Features
+	Capabilities FeatureSet `json:"capabilities"`
+}
+
+// FeatureSet is a machine-readable description of what the library writes,
+// for services negotiating capabilities with each other
+type FeatureSet struct {
+	// Versions lists the vCard versions that can be generated
+	Versions []Version `json:"versions"`
+
+	// Properties lists, per version, the properties the library writes:
+	// those with dedicated setters and the registered properties accepted as
+	// custom properties. X- extension properties are written by every
+	// version and are not listed.
+	Properties map[Version][]string `json:"properties"`
+
+	// Formats lists the output formats
+	Formats []Format `json:"formats"`
+}
+
+// Format is an output format
+type Format struct {
+	// Name identifies the format, e.g. "hcard"
+	Name string `json:"name"`
+
+	// MediaType is the MIME type of the output
+	MediaType string `json:"mediaType"`
+}
+
+// SupportedVersions returns the vCard versions this library can generate
+func SupportedVersions() []Version {
+	return []Version{Version30, Version40}
+}
+
+// GetInfo returns the library metadata
+func GetInfo() Info {
+	return Info{
+		Library:           "go.rumenx.com/vcard",
+		Version:           LibraryVersion,
+		SupportedVersions: SupportedVersions(),
+		Features: []string{
+			FeatureCustomProperties,
+			FeatureFilenameTemplate,
+			FeaturePhotoDataURI,
+			FeatureVersionNegotiation,
+		},
+		Capabilities: Features(),
+	}
+}
+
+// Features returns the versions, properties and formats the library
+// supports
+func Features() FeatureSet {
+	features := FeatureSet{
+		Versions:   SupportedVersions(),
+		Properties: make(map[Version][]string),
+		Formats: []Format{
+			{Name: "vcard", MediaType: MIMEType},
+			{Name: "hcard", MediaType: "text/html"},
+		},
+	}
+
+	for _, version := range features.Versions {
+		// LABEL is derived from ADR
+		names := map[string]bool{"BEGIN": true, "END": true, "VERSION": true, "LABEL": true}
+		for _, set := range []map[string]bool{modeledProperties, registeredProperties} {
+			for name := range set {
+				if Supports(version, name) {
+					names[name] = true
+				}
+			}
+		}
+
+		properties := make([]string, 0, len(names))
+		for name := range names {
+			properties = append(properties, name)
+		}
+		sort.Strings(properties)
+		features.Properties[version] = properties
+	}
+	return features
+}
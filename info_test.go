@@ -0,0 +1,66 @@
+package vcard
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetInfo(t *testing.T) {
+	info := GetInfo()
+
+	if info.Version != LibraryVersion {
+		t.Errorf("Expected version %s, got %s", LibraryVersion, info.Version)
+	}
+
+	if len(info.SupportedVersions) != 2 {
+		t.Errorf("Expected 2 supported versions, got %d", len(info.SupportedVersions))
+	}
+
+	for _, version := range info.SupportedVersions {
+		if _, err := ParseVersion(version.String()); err != nil {
+			t.Errorf("Supported version %s should parse: %v", version, err)
+		}
+	}
+
+	if len(info.Features) == 0 {
+		t.Error("Expected feature flags to be reported")
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	features := Features()
+
+	if !slices.Equal(features.Versions, SupportedVersions()) {
+		t.Errorf("Expected versions %v, got %v", SupportedVersions(), features.Versions)
+	}
+
+	v3, v4 := features.Properties[Version30], features.Properties[Version40]
+	for _, property := range []string{"FN", "N", "EMAIL", "TEL", "PHOTO", "NOTE", "CATEGORIES", "VERSION"} {
+		if !slices.Contains(v3, property) || !slices.Contains(v4, property) {
+			t.Errorf("Expected %s in both versions", property)
+		}
+	}
+	if slices.Contains(v3, "KIND") || !slices.Contains(v4, "KIND") {
+		t.Error("Expected KIND in vCard 4.0 only")
+	}
+	if !slices.Contains(v3, "SORT-STRING") || slices.Contains(v4, "SORT-STRING") {
+		t.Error("Expected SORT-STRING in vCard 3.0 only")
+	}
+	if !slices.IsSorted(v4) || len(slices.Compact(slices.Clone(v4))) != len(v4) {
+		t.Errorf("Expected sorted unique properties, got %v", v4)
+	}
+
+	if len(features.Formats) == 0 || features.Formats[0].MediaType != "text/vcard" {
+		t.Errorf("Unexpected formats %v", features.Formats)
+	}
+
+	data, err := json.Marshal(GetInfo())
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+	if !strings.Contains(string(data), `"capabilities":{"versions":["3.0","4.0"],"properties":{"3.0":["ADR",`) {
+		t.Errorf("Unexpected info JSON: %s", data)
+	}
+}
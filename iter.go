@@ -0,0 +1,61 @@
+package vcard
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// All returns an iterator over the cards in the address book
+func (b *AddressBook) All() iter.Seq[*VCard] {
+	return func(yield func(*VCard) bool) {
+		for _, card := range b.cards {
+			if !yield(card) {
+				return
+			}
+		}
+	}
+}
+
+// Emails returns an iterator over the email addresses
+func (v *VCard) Emails() iter.Seq[Email] {
+	return slices.Values(v.emails)
+}
+
+// Phones returns an iterator over the phone numbers
+func (v *VCard) Phones() iter.Seq[Phone] {
+	return slices.Values(v.phones)
+}
+
+// Addresses returns an iterator over the addresses
+func (v *VCard) Addresses() iter.Seq[Address] {
+	return slices.Values(v.addresses)
+}
+
+// URLs returns an iterator over the URLs
+func (v *VCard) URLs() iter.Seq[URL] {
+	return slices.Values(v.urls)
+}
+
+// Members returns an iterator over the URIs of the group members
+func (v *VCard) Members() iter.Seq[string] {
+	return slices.Values(v.members)
+}
+
+// CustomProperties returns an iterator over the custom properties as name
+// and value pairs, ordered by name
+func (v *VCard) CustomProperties() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		names := make([]string, 0, len(v.customProps))
+		for name := range v.customProps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !yield(name, v.customProps[name]) {
+				return
+			}
+		}
+	}
+}
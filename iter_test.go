@@ -0,0 +1,65 @@
+package vcard
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddressBookAll(t *testing.T) {
+	jane, john := New().AddName("Jane", "Doe"), New().AddName("John", "Doe")
+	book := NewAddressBook(jane, john)
+
+	cards := slices.Collect(book.All())
+	if len(cards) != 2 || cards[0] != jane || cards[1] != john {
+		t.Errorf("Unexpected cards: %v", cards)
+	}
+
+	for card := range book.All() {
+		if card != jane {
+			t.Error("Expected iteration to stop after the first card")
+		}
+		break
+	}
+}
+
+func TestCardIterators(t *testing.T) {
+	card := NewWithVersion(Version40).
+		SetKind(KindGroup).
+		SetFormattedName("Team").
+		AddEmail("a@example.com").
+		AddEmail("b@example.com").
+		AddPhone("+15551234567").
+		AddAddress("1 Main St", "Springfield", "", "", "").
+		AddURL("https://example.com").
+		AddMember("urn:uuid:1").
+		AddCustomProperty("X-B", "2").
+		AddCustomProperty("X-A", "1")
+
+	var addresses []string
+	for email := range card.Emails() {
+		addresses = append(addresses, email.Address)
+	}
+	if !slices.Equal(addresses, []string{"a@example.com", "b@example.com"}) {
+		t.Errorf("Unexpected emails: %v", addresses)
+	}
+
+	if len(slices.Collect(card.Phones())) != 1 || len(slices.Collect(card.Addresses())) != 1 ||
+		len(slices.Collect(card.URLs())) != 1 || !slices.Equal(slices.Collect(card.Members()), []string{"urn:uuid:1"}) {
+		t.Error("Unexpected iterator lengths")
+	}
+
+	var names []string
+	for name, value := range card.CustomProperties() {
+		names = append(names, name+"="+value)
+	}
+	if !slices.Equal(names, []string{"X-A=1", "X-B=2"}) {
+		t.Errorf("Expected custom properties ordered by name, got %v", names)
+	}
+
+	for email := range card.Emails() {
+		email.Address = "changed@example.com"
+	}
+	if card.GetEmail() != "a@example.com" {
+		t.Error("Expected iterator values to be copies")
+	}
+}
@@ -0,0 +1,282 @@
+// Package jws signs served vCards with detached JSON Web Signatures (RFC 7515
+// appendix F), so clients can confirm a card really came from the directory
+// service and was not altered on the way.
+//
+// A detached signature is a compact JWS with an empty payload section,
+// "header..signature", computed over the exact bytes of the response body.
+// The adapters send it in the Header response header when a Signer is
+// configured:
+//
+//	signer, err := jws.New(privateKey, "directory-2026")
+//	router.Get("/contact", chi.VCard(handler, chi.Options{Signer: signer}))
+//
+// Verifiers check the header against the body they received with the
+// matching public key:
+//
+//	err := jws.Verify(resp.Header.Get(jws.Header), body, publicKey)
+package jws
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash"
+	"math/big"
+	"strings"
+)
+
+// Header is the response header carrying the detached signature
+const Header = "X-JWS-Signature"
+
+var (
+	// ErrInvalidSignature is returned for malformed signatures and
+	// signatures that don't match the payload
+	ErrInvalidSignature = errors.New("jws: invalid signature")
+
+	// ErrUnsupportedKey is returned for keys of an unsupported type or curve
+	ErrUnsupportedKey = errors.New("jws: unsupported key")
+
+	// ErrShortSecret is returned for HMAC secrets shorter than
+	// MinSecretSize
+	ErrShortSecret = errors.New("jws: secret shorter than 32 bytes")
+)
+
+// MinSecretSize is the minimum length of an HS256 secret in bytes, the size
+// of the SHA-256 output (RFC 7518 section 3.2)
+const MinSecretSize = 32
+
+var encoding = base64.RawURLEncoding
+
+// Signer signs JWS signing input with a private or secret key
+type Signer interface {
+	// Algorithm returns the JWS "alg" value, e.g. "ES256"
+	Algorithm() string
+
+	// KeyID returns the "kid" value identifying the key to verifiers; empty
+	// omits it
+	KeyID() string
+
+	// Sign returns the JWS signature of the signing input
+	Sign(input []byte) ([]byte, error)
+}
+
+// header is the protected header of a signature
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"cty,omitempty"`
+}
+
+// contentType is the "cty" of signed vCards
+const contentType = "text/vcard"
+
+// Detached returns the detached compact JWS of the payload,
+// "header..signature"
+func Detached(s Signer, payload []byte) (string, error) {
+	protected, err := json.Marshal(header{Algorithm: s.Algorithm(), KeyID: s.KeyID(), Type: contentType})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := encoding.EncodeToString(protected)
+	signature, err := s.Sign(signingInput(encoded, payload))
+	if err != nil {
+		return "", err
+	}
+	return encoded + ".." + encoding.EncodeToString(signature), nil
+}
+
+// Verify checks the detached signature of the payload with the key: a
+// []byte secret for HS256, or an *ecdsa.PublicKey, ed25519.PublicKey or
+// *rsa.PublicKey. The "alg" of the signature must be the one of the key.
+func Verify(signature string, payload []byte, key any) error {
+	encoded, sig, ok := strings.Cut(signature, "..")
+	if !ok || strings.Contains(sig, ".") {
+		return ErrInvalidSignature
+	}
+	protected, err := encoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	var h header
+	if err := json.Unmarshal(protected, &h); err != nil {
+		return ErrInvalidSignature
+	}
+	raw, err := encoding.DecodeString(sig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	algorithm, err := keyAlgorithm(key)
+	if err != nil {
+		return err
+	}
+	if h.Algorithm != algorithm {
+		return ErrInvalidSignature
+	}
+
+	input := signingInput(encoded, payload)
+	switch key := key.(type) {
+	case []byte:
+		ok = hmac.Equal(raw, hmacSum(key, input))
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(raw) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(raw[:size])
+		s := new(big.Int).SetBytes(raw[size:])
+		ok = ecdsa.Verify(key, digest(curveHash(key.Curve), input), r, s)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, input, raw)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest(crypto.SHA256, input), raw) == nil
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// HMAC returns an HS256 signer with the shared secret, which must be at
+// least MinSecretSize bytes; shorter secrets give ErrShortSecret
+func HMAC(secret []byte, keyID string) (Signer, error) {
+	if len(secret) < MinSecretSize {
+		return nil, ErrShortSecret
+	}
+	return &hmacSigner{secret: bytes.Clone(secret), keyID: keyID}, nil
+}
+
+// New returns a signer for the private key: ES256, ES384 or ES512 for ECDSA
+// keys on P-256, P-384 and P-521, EdDSA for Ed25519 keys and RS256 for RSA
+// keys
+func New(key crypto.Signer, keyID string) (Signer, error) {
+	algorithm, err := keyAlgorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &keySigner{key: key, algorithm: algorithm, keyID: keyID}, nil
+}
+
+// keyAlgorithm returns the JWS algorithm of the public or secret key
+func keyAlgorithm(key any) (string, error) {
+	switch key := key.(type) {
+	case []byte:
+		if len(key) < MinSecretSize {
+			return "", ErrShortSecret
+		}
+		return "HS256", nil
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return "ES256", nil
+		case elliptic.P384():
+			return "ES384", nil
+		case elliptic.P521():
+			return "ES512", nil
+		}
+	case ed25519.PublicKey:
+		return "EdDSA", nil
+	case *rsa.PublicKey:
+		return "RS256", nil
+	}
+	return "", ErrUnsupportedKey
+}
+
+// hmacSigner signs with HMAC-SHA256
+type hmacSigner struct {
+	secret []byte
+	keyID  string
+}
+
+func (s *hmacSigner) Algorithm() string { return "HS256" }
+func (s *hmacSigner) KeyID() string     { return s.keyID }
+
+func (s *hmacSigner) Sign(input []byte) ([]byte, error) {
+	return hmacSum(s.secret, input), nil
+}
+
+// keySigner signs with an asymmetric private key
+type keySigner struct {
+	key       crypto.Signer
+	algorithm string
+	keyID     string
+}
+
+func (s *keySigner) Algorithm() string { return s.algorithm }
+func (s *keySigner) KeyID() string     { return s.keyID }
+
+func (s *keySigner) Sign(input []byte) ([]byte, error) {
+	switch public := s.key.Public().(type) {
+	case ed25519.PublicKey:
+		return s.key.Sign(rand.Reader, input, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		// Signers return ASN.1; JWS uses the fixed-size R || S form
+		h := curveHash(public.Curve)
+		der, err := s.key.Sign(rand.Reader, digest(h, input), h)
+		if err != nil {
+			return nil, err
+		}
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(der, &sig); err != nil {
+			return nil, err
+		}
+		size := (public.Curve.Params().BitSize + 7) / 8
+		out := make([]byte, 2*size)
+		sig.R.FillBytes(out[:size])
+		sig.S.FillBytes(out[size:])
+		return out, nil
+	default:
+		return s.key.Sign(rand.Reader, digest(crypto.SHA256, input), crypto.SHA256)
+	}
+}
+
+// signingInput returns the JWS signing input of the encoded header and the
+// payload
+func signingInput(header string, payload []byte) []byte {
+	return []byte(header + "." + encoding.EncodeToString(payload))
+}
+
+// curveHash returns the hash JWS pairs with the curve
+func curveHash(curve elliptic.Curve) crypto.Hash {
+	switch curve {
+	case elliptic.P384():
+		return crypto.SHA384
+	case elliptic.P521():
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+// digest returns the hash of the data
+func digest(h crypto.Hash, data []byte) []byte {
+	var fn hash.Hash
+	switch h {
+	case crypto.SHA384:
+		fn = sha512.New384()
+	case crypto.SHA512:
+		fn = sha512.New()
+	default:
+		fn = sha256.New()
+	}
+	fn.Write(data)
+	return fn.Sum(nil)
+}
+
+// hmacSum returns the HMAC-SHA256 of the data
+func hmacSum(secret, data []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
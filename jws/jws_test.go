@@ -0,0 +1,134 @@
+package jws
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var payload = []byte("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Jane Doe\r\nEND:VCARD\r\n")
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestDetachedRoundTrip(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	edPublic, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	tests := []struct {
+		algorithm string
+		signer    Signer
+		key       any
+	}{
+		{"HS256", mustHMAC(t, secret, "k1"), secret},
+		{"ES256", mustNew(t, ecKey), &ecKey.PublicKey},
+		{"ES384", mustNew(t, ec384Key), &ec384Key.PublicKey},
+		{"EdDSA", mustNew(t, edKey), edPublic},
+		{"RS256", mustNew(t, rsaKey), &rsaKey.PublicKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			if got := tt.signer.Algorithm(); got != tt.algorithm {
+				t.Fatalf("Algorithm() = %q, want %q", got, tt.algorithm)
+			}
+
+			signature, err := Detached(tt.signer, payload)
+			if err != nil {
+				t.Fatalf("Detached() error = %v", err)
+			}
+			if parts := strings.Split(signature, "."); len(parts) != 3 || parts[1] != "" {
+				t.Fatalf("signature %q is not detached", signature)
+			}
+			if err := Verify(signature, payload, tt.key); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+
+			tampered := append([]byte(nil), payload...)
+			tampered[len(tampered)-3] = 'X'
+			if err := Verify(signature, tampered, tt.key); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify(tampered) error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestDetachedHeader(t *testing.T) {
+	signature, err := Detached(mustHMAC(t, secret, "directory-2026"), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	protected, _ := encoding.DecodeString(strings.Split(signature, ".")[0])
+	var h map[string]string
+	if err := json.Unmarshal(protected, &h); err != nil {
+		t.Fatal(err)
+	}
+	if h["alg"] != "HS256" || h["kid"] != "directory-2026" || h["cty"] != "text/vcard" {
+		t.Errorf("header = %v", h)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signature, err := Detached(mustHMAC(t, secret, ""), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		key       any
+		want      error
+	}{
+		{"wrong secret", signature, []byte("fedcba9876543210fedcba9876543210"), ErrInvalidSignature},
+		{"short secret", signature, []byte("secret"), ErrShortSecret},
+		{"algorithm of another key", signature, &ecKey.PublicKey, ErrInvalidSignature},
+		{"attached payload", strings.Replace(signature, "..", ".cGF5bG9hZA.", 1), secret, ErrInvalidSignature},
+		{"malformed", "not-a-jws", secret, ErrInvalidSignature},
+		{"unsupported key", signature, "secret", ErrUnsupportedKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.signature, payload, tt.key); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func mustNew(t *testing.T, key crypto.Signer) Signer {
+	t.Helper()
+	signer, err := New(key, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return signer
+}
+
+func mustHMAC(t *testing.T, secret []byte, keyID string) Signer {
+	t.Helper()
+	signer, err := HMAC(secret, keyID)
+	if err != nil {
+		t.Fatalf("HMAC() error = %v", err)
+	}
+	return signer
+}
+
+func TestHMACRejectsShortSecrets(t *testing.T) {
+	for _, secret := range [][]byte{nil, []byte("secret"), secret[:MinSecretSize-1]} {
+		if _, err := HMAC(secret, ""); !errors.Is(err, ErrShortSecret) {
+			t.Errorf("HMAC(%q) error = %v, want ErrShortSecret", secret, err)
+		}
+	}
+}
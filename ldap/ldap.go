@@ -0,0 +1,135 @@
+// Package ldap converts between LDAP directory entries and vCards.
+//
+// Directory photos are stored as raw JPEG bytes in the jpegPhoto attribute
+// (RFC 2798) while vCards carry them in the PHOTO property. JPEGPhoto and
+// SetJPEGPhoto convert in both directions, and FormatAttribute writes the
+// bytes as an LDIF line for directory sync jobs:
+//
+//	data, err := ldap.JPEGPhoto(card)
+//	if err == nil {
+//		fmt.Fprint(w, ldap.FormatAttribute(ldap.AttributeJPEGPhoto, data))
+//	}
+//
+// ActiveDirectory and InetOrgPerson are mapping profiles turning directory
+// entries, collected as attribute names and values, into cards:
+//
+//	record := mapping.Record{"givenName": {"Jane"}, "sn": {"Doe"}, "thumbnailPhoto": {string(photo)}}
+//	card := ldap.ActiveDirectory.Card(record)
+package ldap
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+// AttributeJPEGPhoto is the inetOrgPerson photo attribute (RFC 2798)
+const AttributeJPEGPhoto = "jpegPhoto"
+
+// jpegMediaType is the media type of jpegPhoto values
+const jpegMediaType = "image/jpeg"
+
+var (
+	// ErrNoPhoto is returned when the card has no photo
+	ErrNoPhoto = errors.New("ldap: card has no photo")
+
+	// ErrRemotePhoto is returned when the photo is a URL rather than inline data
+	ErrRemotePhoto = errors.New("ldap: photo is a remote URL")
+
+	// ErrNotJPEG is returned when the photo data is not a JPEG image
+	ErrNotJPEG = errors.New("ldap: photo is not a JPEG image")
+)
+
+// JPEGPhoto returns the card's inline photo as jpegPhoto attribute bytes.
+// The photo may be a data URI or base64 data; remote URLs are not fetched.
+func JPEGPhoto(card *vcard.VCard) ([]byte, error) {
+	photo := card.GetPhoto()
+	switch {
+	case photo == "":
+		return nil, ErrNoPhoto
+	case strings.HasPrefix(photo, "http://") || strings.HasPrefix(photo, "https://"):
+		return nil, ErrRemotePhoto
+	}
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(photo, "data:") {
+		_, data, err = vcard.DecodeDataURI(photo)
+	} else {
+		data, err = base64.StdEncoding.DecodeString(photo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !isJPEG(data) {
+		return nil, ErrNotJPEG
+	}
+	return data, nil
+}
+
+// SetJPEGPhoto sets the card's photo from jpegPhoto attribute bytes,
+// embedded as an image/jpeg data URI. Empty values leave the card unchanged.
+func SetJPEGPhoto(card *vcard.VCard, jpegPhoto []byte) error {
+	if len(jpegPhoto) == 0 {
+		return nil
+	}
+	if !isJPEG(jpegPhoto) {
+		return ErrNotJPEG
+	}
+
+	card.AddPhoto(vcard.EncodeDataURI(jpegMediaType, jpegPhoto))
+	return nil
+}
+
+// isJPEG reports whether the data starts with the JPEG SOI marker
+func isJPEG(data []byte) bool {
+	return len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF
+}
+
+// ldifLineLength is the line length LDIF lines are folded at (RFC 2849)
+const ldifLineLength = 76
+
+// FormatAttribute writes an attribute value as an LDIF line, including the
+// trailing newline. Values that are not LDIF safe strings (binary data,
+// non-ASCII text, leading spaces, colons or "<") are base64 encoded with the
+// "::" separator. Long lines are folded at 76 characters.
+func FormatAttribute(name string, value []byte) string {
+	line := name + ": " + string(value)
+	if !isSafeString(value) {
+		line = name + ":: " + base64.StdEncoding.EncodeToString(value)
+	}
+
+	// Continuation lines start with a space, leaving room for 75 characters
+	var builder strings.Builder
+	for limit := ldifLineLength; len(line) > limit; limit = ldifLineLength - 1 {
+		builder.WriteString(line[:limit])
+		builder.WriteString("\n ")
+		line = line[limit:]
+	}
+	builder.WriteString(line)
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// isSafeString reports whether the value is an LDIF SAFE-STRING (RFC 2849)
+func isSafeString(value []byte) bool {
+	if len(value) == 0 {
+		return true
+	}
+	switch value[0] {
+	case ' ', ':', '<':
+		return false
+	}
+	if value[len(value)-1] == ' ' {
+		return false
+	}
+	for _, b := range value {
+		if b == 0 || b == '\n' || b == '\r' || b > 0x7F {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package ldap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+var jpeg = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00, 0xFF, 0xD9}
+
+func TestJPEGPhotoRoundTrip(t *testing.T) {
+	card := vcard.New().AddName("Jane", "Doe")
+	if err := SetJPEGPhoto(card, jpeg); err != nil {
+		t.Fatalf("SetJPEGPhoto() returned error: %v", err)
+	}
+	if !strings.HasPrefix(card.GetPhoto(), "data:image/jpeg;base64,") {
+		t.Errorf("Expected a JPEG data URI, got %q", card.GetPhoto())
+	}
+
+	data, err := JPEGPhoto(card)
+	if err != nil {
+		t.Fatalf("JPEGPhoto() returned error: %v", err)
+	}
+	if !bytes.Equal(data, jpeg) {
+		t.Errorf("Expected the original bytes, got %x", data)
+	}
+
+	content, _ := card.String()
+	if !strings.Contains(content, "PHOTO;ENCODING=b;TYPE=JPEG:") {
+		t.Errorf("Expected an inline JPEG photo in:\n%s", content)
+	}
+}
+
+func TestJPEGPhotoFormats(t *testing.T) {
+	raw := vcard.New().AddPhoto(base64.StdEncoding.EncodeToString(jpeg))
+	if data, err := JPEGPhoto(raw); err != nil || !bytes.Equal(data, jpeg) {
+		t.Errorf("Expected base64 photo to decode, got %x, %v", data, err)
+	}
+
+	tests := []struct {
+		name  string
+		photo string
+		err   error
+	}{
+		{"none", "", ErrNoPhoto},
+		{"remote", "https://example.com/jane.jpg", ErrRemotePhoto},
+		{"png", vcard.EncodeDataURI("image/png", []byte("\x89PNG\r\n")), ErrNotJPEG},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := JPEGPhoto(vcard.New().AddPhoto(test.photo)); !errors.Is(err, test.err) {
+				t.Errorf("Expected %v, got %v", test.err, err)
+			}
+		})
+	}
+
+	if _, err := JPEGPhoto(vcard.New().AddPhoto("not base64!")); err == nil {
+		t.Error("Expected error for invalid base64 data")
+	}
+}
+
+func TestSetJPEGPhotoRejectsOtherImages(t *testing.T) {
+	card := vcard.New().AddPhoto("https://example.com/jane.jpg")
+	if err := SetJPEGPhoto(card, []byte("GIF89a")); !errors.Is(err, ErrNotJPEG) {
+		t.Errorf("Expected ErrNotJPEG, got %v", err)
+	}
+	if err := SetJPEGPhoto(card, nil); err != nil || card.GetPhoto() != "https://example.com/jane.jpg" {
+		t.Errorf("Expected empty value to keep the photo, got %q, %v", card.GetPhoto(), err)
+	}
+}
+
+func TestFormatAttribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"cn", "Jane Doe", "cn: Jane Doe\n"},
+		{"cn", "", "cn: \n"},
+		{"cn", "Jörg", "cn:: SsO2cmc=\n"},
+		{"description", ":leading colon", "description:: OmxlYWRpbmcgY29sb24=\n"},
+		{"description", "two\nlines", "description:: dHdvCmxpbmVz\n"},
+	}
+	for _, test := range tests {
+		if got := FormatAttribute(test.name, []byte(test.value)); got != test.expected {
+			t.Errorf("FormatAttribute(%q, %q) = %q, expected %q", test.name, test.value, got, test.expected)
+		}
+	}
+
+	photo := FormatAttribute(AttributeJPEGPhoto, bytes.Repeat(jpeg, 20))
+	lines := strings.Split(strings.TrimSuffix(photo, "\n"), "\n")
+	if !strings.HasPrefix(lines[0], "jpegPhoto:: /9j/") || len(lines) < 3 {
+		t.Fatalf("Unexpected LDIF value:\n%s", photo)
+	}
+	for i, line := range lines {
+		if len(line) > ldifLineLength || i > 0 && line[0] != ' ' {
+			t.Errorf("Line %d is not folded correctly: %q", i, line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimPrefix(photo, "jpegPhoto:: "), "\n ", "")
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(unfolded, "\n"))
+	if err != nil || !bytes.Equal(data, bytes.Repeat(jpeg, 20)) {
+		t.Errorf("Expected folded value to decode to the photo, got %v", err)
+	}
+}
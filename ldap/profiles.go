@@ -0,0 +1,79 @@
+package ldap
+
+import (
+	"strings"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/mapping"
+)
+
+// ActiveDirectory maps the common Active Directory user attributes to
+// contact fields. The office (physicalDeliveryOfficeName) becomes the
+// extended part of the work address, and thumbnailPhoto, which holds JPEG
+// bytes, becomes an inline photo.
+var ActiveDirectory = mapping.Profile{
+	Name: "active-directory",
+	Fields: []mapping.Field{
+		{Attribute: "givenName", Target: mapping.FirstName},
+		{Attribute: "sn", Target: mapping.LastName},
+		{Attribute: "initials", Target: mapping.MiddleName},
+		{Attribute: "mail", Target: mapping.Email(vcard.EmailWork)},
+		{Attribute: "telephoneNumber", Target: mapping.Phone(vcard.PhoneWork)},
+		{Attribute: "mobile", Target: mapping.Phone(vcard.PhoneMobile)},
+		{Attribute: "homePhone", Target: mapping.Phone(vcard.PhoneHome)},
+		{Attribute: "facsimileTelephoneNumber", Target: mapping.Phone(vcard.PhoneFax)},
+		{Attribute: "company", Target: mapping.Organization},
+		{Attribute: "department", Target: mapping.Department},
+		{Attribute: "title", Target: mapping.Title},
+		{Attribute: "physicalDeliveryOfficeName", Target: mapping.Extended(vcard.AddressWork)},
+		{Attribute: "streetAddress", Target: mapping.Street(vcard.AddressWork)},
+		{Attribute: "l", Target: mapping.City(vcard.AddressWork)},
+		{Attribute: "st", Target: mapping.State(vcard.AddressWork)},
+		{Attribute: "postalCode", Target: mapping.PostalCode(vcard.AddressWork)},
+		{Attribute: "co", Target: mapping.Country(vcard.AddressWork)},
+		{Attribute: "wWWHomePage", Target: mapping.URL(vcard.URLWork)},
+		{Attribute: "info", Target: mapping.Note},
+		{Attribute: "thumbnailPhoto", Target: Photo},
+	},
+}
+
+// InetOrgPerson maps the inetOrgPerson attributes (RFC 2798) used by
+// OpenLDAP and most other directories to contact fields
+var InetOrgPerson = mapping.Profile{
+	Name: "inetorgperson",
+	Fields: []mapping.Field{
+		{Attribute: "givenName", Target: mapping.FirstName},
+		{Attribute: "sn", Target: mapping.LastName},
+		{Attribute: "mail", Target: mapping.Email(vcard.EmailWork)},
+		{Attribute: "telephoneNumber", Target: mapping.Phone(vcard.PhoneWork)},
+		{Attribute: "mobile", Target: mapping.Phone(vcard.PhoneMobile)},
+		{Attribute: "homePhone", Target: mapping.Phone(vcard.PhoneHome)},
+		{Attribute: "facsimileTelephoneNumber", Target: mapping.Phone(vcard.PhoneFax)},
+		{Attribute: "o", Target: mapping.Organization},
+		{Attribute: "ou", Target: mapping.Department},
+		{Attribute: "title", Target: mapping.Title},
+		{Attribute: "roomNumber", Target: mapping.Extended(vcard.AddressWork)},
+		{Attribute: "street", Target: mapping.Street(vcard.AddressWork)},
+		{Attribute: "l", Target: mapping.City(vcard.AddressWork)},
+		{Attribute: "st", Target: mapping.State(vcard.AddressWork)},
+		{Attribute: "postalCode", Target: mapping.PostalCode(vcard.AddressWork)},
+		{Attribute: "labeledURI", Target: labeledURI},
+		{Attribute: "description", Target: mapping.Note},
+		{Attribute: AttributeJPEGPhoto, Target: Photo},
+	},
+}
+
+// Photo is a mapping target for JPEG photo attributes (jpegPhoto,
+// thumbnailPhoto) holding raw image bytes. Values that are not JPEG images
+// are ignored.
+func Photo(contact *vcard.Contact, value string) {
+	if data := []byte(value); isJPEG(data) {
+		contact.Photo = vcard.EncodeDataURI(jpegMediaType, data)
+	}
+}
+
+// labeledURI adds the URI of a labeledURI value ("URI label") as a work URL
+func labeledURI(contact *vcard.Contact, value string) {
+	uri, _, _ := strings.Cut(value, " ")
+	mapping.URL(vcard.URLWork)(contact, uri)
+}
@@ -0,0 +1,69 @@
+package ldap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/mapping"
+)
+
+func TestActiveDirectory(t *testing.T) {
+	card := ActiveDirectory.Card(mapping.Record{
+		"givenName":                  {"Jane"},
+		"sn":                         {"Doe"},
+		"mail":                       {"jane.doe@corp.example"},
+		"mobile":                     {"+1 555 0100"},
+		"telephoneNumber":            {"+1 555 0199"},
+		"company":                    {"Contoso"},
+		"department":                 {"Engineering"},
+		"title":                      {"Architect"},
+		"physicalDeliveryOfficeName": {"Building 4, Room 210"},
+		"l":                          {"Redmond"},
+		"co":                         {"United States"},
+		"thumbnailPhoto":             {string(jpeg)},
+		"objectGUID":                 {"\x01\x02"},
+	})
+
+	if name := card.GetName(); name.First != "Jane" || name.Last != "Doe" {
+		t.Errorf("Unexpected name: %+v", name)
+	}
+	if emails := card.GetEmails(); len(emails) != 1 || emails[0].Type != vcard.EmailWork {
+		t.Errorf("Unexpected emails: %+v", emails)
+	}
+	if phones := card.GetPhones(); len(phones) != 2 || phones[0].Type != vcard.PhoneWork || phones[1].Type != vcard.PhoneMobile {
+		t.Errorf("Unexpected phones: %+v", phones)
+	}
+	addresses := card.GetAddresses()
+	if len(addresses) != 1 || addresses[0].Extended != "Building 4, Room 210" || addresses[0].City != "Redmond" || addresses[0].Type != vcard.AddressWork {
+		t.Errorf("Unexpected addresses: %+v", addresses)
+	}
+	if org := card.GetOrganization(); org.Name != "Contoso" || org.Department != "Engineering" || org.Title != "Architect" {
+		t.Errorf("Unexpected organization: %+v", org)
+	}
+	if data, err := JPEGPhoto(card); err != nil || !bytes.Equal(data, jpeg) {
+		t.Errorf("Expected the thumbnail photo, got %v", err)
+	}
+}
+
+func TestInetOrgPerson(t *testing.T) {
+	card := InetOrgPerson.Card(mapping.Record{
+		"givenname":  {"Jane"},
+		"sn":         {"Doe"},
+		"o":          {"Example Inc."},
+		"labeledURI": {"https://jane.example.com Personal page"},
+		"jpegPhoto":  {"GIF89a"},
+	})
+
+	if urls := card.GetURLs(); len(urls) != 1 || urls[0].Address != "https://jane.example.com" {
+		t.Errorf("Unexpected URLs: %+v", urls)
+	}
+	if card.GetPhoto() != "" {
+		t.Errorf("Expected a non-JPEG photo to be ignored, got %q", card.GetPhoto())
+	}
+	content, err := card.String()
+	if err != nil || !strings.Contains(content, "ORG:Example Inc.") {
+		t.Errorf("Unexpected card: %v\n%s", err, content)
+	}
+}
@@ -0,0 +1,47 @@
+package vcard
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Warning describes a non-fatal problem that affects the generated output
+type Warning struct {
+	// Property is the affected property name
+	Property string
+
+	// Message describes the problem
+	Message string
+}
+
+// String returns the warning as "PROPERTY: message"
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Property, w.Message)
+}
+
+// Lint returns warnings about data that is valid but will be dropped or
+// altered when the card is serialized, including data the card's version
+// does not support (see Supports)
+func (v *VCard) Lint() []Warning {
+	var warnings []Warning
+
+	names := make([]string, 0, len(v.customProps))
+	for name := range v.customProps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !isCustomPropertyAllowed(name) {
+			warnings = append(warnings, Warning{
+				Property: name,
+				Message:  "unknown property is not written; use an X- name or a registered property",
+			})
+		}
+	}
+
+	warnings = append(warnings, v.alternateWarnings(false)...)
+	warnings = append(warnings, v.sanitizeWarnings()...)
+	warnings = append(warnings, v.emojiWarnings()...)
+	return append(warnings, v.versionWarnings()...)
+}
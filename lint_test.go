@@ -0,0 +1,71 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisteredCustomProperties(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddCustomProperty("EXPERTISE", "Go programming")
+	card.AddCustomProperty("nickname", "Johnny")
+	card.AddCustomProperty("X-EMPLOYEE-ID", "EMP001")
+	card.AddCustomProperty("FOO", "dropped")
+	card.AddCustomProperty("FN", "Shadowed")
+	card.AddCustomProperty("MEMBER", "urn:uuid:shadowed")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+
+	for _, expected := range []string{"EXPERTISE:Go programming", "NICKNAME:Johnny", "X-EMPLOYEE-ID:EMP001"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in output", expected)
+		}
+	}
+
+	if strings.Contains(content, "FOO:") || strings.Contains(content, "FN:Shadowed") || strings.Contains(content, "MEMBER:") {
+		t.Errorf("Unknown and managed properties should not be written: %s", content)
+	}
+}
+
+func TestCustomPropertyNameInjection(t *testing.T) {
+	card := New().AddName("John", "Doe").
+		AddCustomProperty("X-A:b\nEMAIL", "evil@example.com").
+		AddCustomProperty("X-TYPE;TYPE=WORK", "value").
+		AddCustomProperty("X-", "empty")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if strings.Contains(content, "evil@example.com") || strings.Contains(content, "X-TYPE") || strings.Contains(content, "X-:") {
+		t.Errorf("Invalid property names should not be written:\n%s", content)
+	}
+	if len(card.Lint()) != 3 {
+		t.Errorf("Expected warnings for invalid names, got %v", card.Lint())
+	}
+}
+
+func TestLintUnknownCustomProperties(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddCustomProperty("X-OK", "value")
+	card.AddCustomProperty("FOO", "value")
+	card.AddCustomProperty("BAR", "value")
+
+	warnings := card.Lint()
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %v", warnings)
+	}
+
+	if warnings[0].Property != "BAR" || warnings[1].Property != "FOO" {
+		t.Errorf("Expected warnings sorted by property, got %v", warnings)
+	}
+
+	if !strings.HasPrefix(warnings[0].String(), "BAR: ") {
+		t.Errorf("Unexpected warning format: %s", warnings[0])
+	}
+}
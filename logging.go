@@ -0,0 +1,77 @@
+package vcard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+)
+
+// HashPII returns a short, stable digest of a personal value (email, phone)
+// so it can be correlated in logs without storing the raw data. The digest
+// is an HMAC-SHA256 under the caller's secret key, truncated to 64 bits: a
+// plain hash of an email or phone number is reversed by hashing candidates,
+// so keep the key out of the logs. Empty values and keys give an empty
+// digest.
+func HashPII(key []byte, value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" || len(key) == 0 {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// LogValue implements slog.LogValuer, logging a summary of the card with
+// the name omitted and only the number of emails and phone numbers; use
+// LogWithKey to log their digests
+func (v *VCard) LogValue() slog.Value {
+	return v.logValue(nil)
+}
+
+// LogWithKey returns a summary of the card for slog like LogValue, with the
+// emails and phone numbers as HashPII digests under the key. A nil key logs
+// their number only.
+func (v *VCard) LogWithKey(key []byte) slog.LogValuer {
+	return keyedLog{card: v, key: key}
+}
+
+// keyedLog logs a card with its personal values hashed under a key
+type keyedLog struct {
+	card *VCard
+	key  []byte
+}
+
+// LogValue implements slog.LogValuer
+func (l keyedLog) LogValue() slog.Value {
+	return l.card.logValue(l.key)
+}
+
+// logValue summarizes the card, hashing emails and phone numbers under the
+// key or counting them without one
+func (v *VCard) logValue(key []byte) slog.Value {
+	attrs := []slog.Attr{slog.String("version", v.version.String())}
+	if len(key) == 0 {
+		attrs = append(attrs, slog.Int("emails", len(v.emails)), slog.Int("phones", len(v.phones)))
+	} else {
+		emails := make([]string, 0, len(v.emails))
+		for _, email := range v.emails {
+			emails = append(emails, HashPII(key, email.Address))
+		}
+
+		phones := make([]string, 0, len(v.phones))
+		for _, phone := range v.phones {
+			phones = append(phones, HashPII(key, phone.Number))
+		}
+		attrs = append(attrs, slog.Any("emails", emails), slog.Any("phones", phones))
+	}
+
+	return slog.GroupValue(append(attrs,
+		slog.Int("addresses", len(v.addresses)),
+		slog.String("organization", v.organization.Name),
+		slog.Bool("photo", v.photo != ""),
+	)...)
+}
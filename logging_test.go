@@ -0,0 +1,61 @@
+package vcard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHashPII(t *testing.T) {
+	key := []byte("log-key")
+	if HashPII(key, "") != "" || HashPII(nil, "john@example.com") != "" {
+		t.Error("HashPII() of an empty value or key should be empty")
+	}
+
+	if HashPII(key, "John@Example.com") != HashPII(key, " john@example.com ") {
+		t.Error("HashPII() should normalize case and whitespace")
+	}
+
+	if got := HashPII(key, "john@example.com"); got != "0860924dcc51e1bb" {
+		t.Errorf("Expected the truncated HMAC-SHA256, got %q", got)
+	}
+
+	plain := sha256.Sum256([]byte("john@example.com"))
+	if HashPII(key, "john@example.com") == hex.EncodeToString(plain[:8]) {
+		t.Error("HashPII() should not be a plain hash")
+	}
+	if HashPII(key, "john@example.com") == HashPII([]byte("other-key"), "john@example.com") {
+		t.Error("HashPII() should depend on the key")
+	}
+}
+
+func TestLogValueScrubsPII(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddEmail("john@example.com")
+	card.AddPhone("+1234567890")
+	key := []byte("log-key")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger.Info("generated", "card", card)
+	logger.Info("generated", "card", card.LogWithKey(key))
+
+	output := buf.String()
+	for _, raw := range []string{"john@example.com", "+1234567890", "John", "Doe"} {
+		if strings.Contains(output, raw) {
+			t.Errorf("Log output should not contain %q: %s", raw, output)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if !strings.Contains(lines[0], "card.emails=1") {
+		t.Errorf("Expected the email count without a key: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], HashPII(key, "john@example.com")) {
+		t.Errorf("Log output should contain hashed email: %s", lines[1])
+	}
+}
@@ -0,0 +1,251 @@
+// Package logo enriches organization cards with the organization's logo.
+//
+// The logo is looked up by the organization's domain, taken from the card's
+// work URL or email address, through a pluggable Resolver and embedded as an
+// inline LOGO property. HTTPResolver covers logo services addressed by a URL
+// pattern, such as Clearbit-style logo APIs:
+//
+//	resolver := logo.Cached(logo.NewHTTPResolver("https://logo.example.com/{domain}"))
+//	for _, card := range cards {
+//		if err := logo.Enrich(ctx, card, resolver); err != nil && !errors.Is(err, logo.ErrNotFound) {
+//			log.Printf("logo: %v", err)
+//		}
+//	}
+package logo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.rumenx.com/vcard"
+)
+
+// DefaultMaxSize is the largest logo downloaded by HTTPResolver
+const DefaultMaxSize = 256 << 10
+
+// DomainPlaceholder is replaced by the domain in HTTPResolver URL templates
+const DomainPlaceholder = "{domain}"
+
+var (
+	// ErrNotFound is returned when no logo exists for the domain
+	ErrNotFound = errors.New("logo: not found")
+
+	// ErrNoDomain is returned when the card has no organization domain
+	ErrNoDomain = errors.New("logo: card has no organization domain")
+)
+
+// Logo is a resolved logo image
+type Logo struct {
+	// MediaType is the image MIME type, e.g. image/png
+	MediaType string
+
+	// Data is the image content
+	Data []byte
+}
+
+// Resolver looks up the logo of the organization owning a domain
+type Resolver interface {
+	// Resolve returns the logo for the domain, or ErrNotFound
+	Resolve(ctx context.Context, domain string) (*Logo, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface
+type ResolverFunc func(ctx context.Context, domain string) (*Logo, error)
+
+// Resolve calls f(ctx, domain)
+func (f ResolverFunc) Resolve(ctx context.Context, domain string) (*Logo, error) {
+	return f(ctx, domain)
+}
+
+// HTTPResolver downloads logos from a service addressed by a URL template
+type HTTPResolver struct {
+	// URLTemplate is the logo URL with DomainPlaceholder in place of the domain
+	URLTemplate string
+
+	// Client performs the requests; nil uses http.DefaultClient
+	Client *http.Client
+
+	// MaxSize limits the logo size in bytes; 0 uses DefaultMaxSize
+	MaxSize int64
+}
+
+// NewHTTPResolver creates a resolver for the URL template
+func NewHTTPResolver(urlTemplate string) *HTTPResolver {
+	return &HTTPResolver{URLTemplate: urlTemplate}
+}
+
+// Resolve downloads the logo for the domain. A 404 response is reported as
+// ErrNotFound.
+func (r *HTTPResolver) Resolve(ctx context.Context, domain string) (*Logo, error) {
+	if !strings.Contains(r.URLTemplate, DomainPlaceholder) {
+		return nil, fmt.Errorf("logo: URL template has no %s placeholder", DomainPlaceholder)
+	}
+	target := strings.ReplaceAll(r.URLTemplate, DomainPlaceholder, url.PathEscape(domain))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("logo: %w", err)
+	}
+	req.Header.Set("Accept", "image/*")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("logo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("logo: %s returned %s", domain, resp.Status)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("logo: %s returned non-image content %q", domain, resp.Header.Get("Content-Type"))
+	}
+
+	maxSize := r.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("logo: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("logo: %s logo exceeds %d bytes", domain, maxSize)
+	}
+
+	return &Logo{MediaType: mediaType, Data: data}, nil
+}
+
+// cachedResolver remembers the results of another resolver
+type cachedResolver struct {
+	resolver Resolver
+	mu       sync.Mutex
+	results  map[string]cachedResult
+}
+
+// cachedResult is a remembered logo or ErrNotFound
+type cachedResult struct {
+	logo *Logo
+	err  error
+}
+
+// Cached wraps a resolver so each domain is resolved once. Logos and
+// ErrNotFound are remembered; other errors are retried on the next call.
+func Cached(resolver Resolver) Resolver {
+	return &cachedResolver{resolver: resolver, results: make(map[string]cachedResult)}
+}
+
+// Resolve returns the remembered result or asks the wrapped resolver
+func (c *cachedResolver) Resolve(ctx context.Context, domain string) (*Logo, error) {
+	c.mu.Lock()
+	result, ok := c.results[domain]
+	c.mu.Unlock()
+	if ok {
+		return result.logo, result.err
+	}
+
+	logo, err := c.resolver.Resolve(ctx, domain)
+	if err == nil || errors.Is(err, ErrNotFound) {
+		c.mu.Lock()
+		c.results[domain] = cachedResult{logo: logo, err: err}
+		c.mu.Unlock()
+	}
+	return logo, err
+}
+
+// Domain returns the organization domain of the card: the host of its work
+// URL, else of its first other non-social URL, else the domain of its first
+// email address that is not a free email provider. It returns an empty
+// string when none is found.
+func Domain(card *vcard.VCard) string {
+	urls := card.GetURLs()
+	for _, u := range urls {
+		if u.Type == vcard.URLWork {
+			if host := urlHost(u.Address); host != "" {
+				return host
+			}
+		}
+	}
+	for _, u := range urls {
+		if u.Type != vcard.URLSocial {
+			if host := urlHost(u.Address); host != "" {
+				return host
+			}
+		}
+	}
+
+	for _, email := range card.GetEmails() {
+		at := strings.LastIndex(email.Address, "@")
+		if at < 0 {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimSpace(email.Address[at+1:]))
+		if domain != "" && !vcard.IsFreeEmailDomain(domain) {
+			return domain
+		}
+	}
+
+	return ""
+}
+
+// Enrich resolves the logo of the card's organization domain and embeds it
+// as an inline LOGO. Cards that already have a logo are left unchanged.
+func Enrich(ctx context.Context, card *vcard.VCard, resolver Resolver) error {
+	if card.GetLogo() != "" {
+		return nil
+	}
+
+	domain := Domain(card)
+	if domain == "" {
+		return ErrNoDomain
+	}
+
+	logo, err := resolver.Resolve(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	card.AddLogo(vcard.EncodeDataURI(logo.MediaType, logo.Data))
+	return nil
+}
+
+// Enricher adapts Enrich to the vcard.Enricher interface for use in
+// enrichment pipelines. Cards without a domain or a known logo are not
+// treated as failures.
+func Enricher(resolver Resolver) vcard.Enricher {
+	return vcard.EnricherFunc(func(ctx context.Context, card *vcard.VCard) error {
+		err := Enrich(ctx, card, resolver)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrNoDomain) {
+			return nil
+		}
+		return err
+	})
+}
+
+// urlHost returns the lower-cased host of a URL without a leading "www."
+func urlHost(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
@@ -0,0 +1,164 @@
+package logo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		card     *vcard.VCard
+		expected string
+	}{
+		{"work url", vcard.New().AddURL("https://blog.example.org", vcard.URLHome).AddURL("https://www.Acme.com/about", vcard.URLWork), "acme.com"},
+		{"other url", vcard.New().AddURL("https://twitter.com/acme", vcard.URLSocial).AddURL("acme.io"), "acme.io"},
+		{"email", vcard.New().AddEmail("jane@gmail.com").AddEmail("jane@Globex.com"), "globex.com"},
+		{"freemail only", vcard.New().AddEmail("jane@gmail.com"), ""},
+		{"none", vcard.New(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Domain(tt.card); got != tt.expected {
+				t.Errorf("Domain() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	resolver := ResolverFunc(func(ctx context.Context, domain string) (*Logo, error) {
+		if domain != "acme.com" {
+			return nil, ErrNotFound
+		}
+		return &Logo{MediaType: "image/png", Data: []byte("png")}, nil
+	})
+
+	card := vcard.New().AddName("Jane", "Doe").AddEmail("info@acme.com")
+	if err := Enrich(context.Background(), card, resolver); err != nil {
+		t.Fatalf("Enrich() returned error: %v", err)
+	}
+	if card.GetLogo() != "data:image/png;base64,cG5n" {
+		t.Errorf("Expected embedded logo, got %q", card.GetLogo())
+	}
+
+	content, _ := card.String()
+	if !strings.Contains(content, "LOGO;ENCODING=b;TYPE=PNG:cG5n") {
+		t.Errorf("Expected LOGO property in:\n%s", content)
+	}
+
+	existing := vcard.New().AddEmail("info@acme.com").AddLogo("https://acme.com/logo.svg")
+	if err := Enrich(context.Background(), existing, resolver); err != nil || existing.GetLogo() != "https://acme.com/logo.svg" {
+		t.Errorf("Expected existing logo to be kept, got %q, %v", existing.GetLogo(), err)
+	}
+
+	if err := Enrich(context.Background(), vcard.New().AddEmail("x@globex.com"), resolver); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := Enrich(context.Background(), vcard.New(), resolver); !errors.Is(err, ErrNoDomain) {
+		t.Errorf("Expected ErrNoDomain, got %v", err)
+	}
+}
+
+func TestEnricher(t *testing.T) {
+	failure := errors.New("service unavailable")
+	resolver := ResolverFunc(func(ctx context.Context, domain string) (*Logo, error) {
+		switch domain {
+		case "acme.com":
+			return &Logo{MediaType: "image/png", Data: []byte("png")}, nil
+		case "down.com":
+			return nil, failure
+		}
+		return nil, ErrNotFound
+	})
+	enricher := Enricher(resolver)
+
+	card := vcard.New().AddEmail("info@acme.com")
+	if err := enricher.Enrich(context.Background(), card); err != nil || card.GetLogo() == "" {
+		t.Errorf("Expected embedded logo, got %q, %v", card.GetLogo(), err)
+	}
+	for _, card := range []*vcard.VCard{vcard.New(), vcard.New().AddEmail("x@globex.com")} {
+		if err := enricher.Enrich(context.Background(), card); err != nil {
+			t.Errorf("Expected missing logos to be skipped, got %v", err)
+		}
+	}
+	if err := enricher.Enrich(context.Background(), vcard.New().AddEmail("x@down.com")); !errors.Is(err, failure) {
+		t.Errorf("Expected resolver error, got %v", err)
+	}
+}
+
+func TestHTTPResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme.com":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		case "/html.com":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>"))
+		case "/big.com":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(make([]byte, 100))
+		case "/down.com":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewHTTPResolver(server.URL + "/" + DomainPlaceholder)
+	resolver.MaxSize = 10
+	ctx := context.Background()
+
+	logo, err := resolver.Resolve(ctx, "acme.com")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+	if logo.MediaType != "image/png" || string(logo.Data) != "png" {
+		t.Errorf("Unexpected logo: %+v", logo)
+	}
+
+	if _, err := resolver.Resolve(ctx, "missing.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	for _, domain := range []string{"html.com", "big.com", "down.com"} {
+		if _, err := resolver.Resolve(ctx, domain); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected error for %s, got %v", domain, err)
+		}
+	}
+
+	if _, err := NewHTTPResolver(server.URL).Resolve(ctx, "acme.com"); err == nil {
+		t.Error("Expected error for template without placeholder")
+	}
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	resolver := Cached(ResolverFunc(func(ctx context.Context, domain string) (*Logo, error) {
+		calls++
+		switch domain {
+		case "acme.com":
+			return &Logo{MediaType: "image/png", Data: []byte("png")}, nil
+		case "down.com":
+			return nil, errors.New("unavailable")
+		}
+		return nil, ErrNotFound
+	}))
+
+	for i := 0; i < 2; i++ {
+		resolver.Resolve(context.Background(), "acme.com")
+		resolver.Resolve(context.Background(), "missing.com")
+		resolver.Resolve(context.Background(), "down.com")
+	}
+	if calls != 4 {
+		t.Errorf("Expected 4 resolver calls, got %d", calls)
+	}
+}
@@ -0,0 +1,45 @@
+package mapping
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// RecordFromJSON flattens a JSON object into a record. Nested objects give
+// dot-separated attribute names ("location.descriptor"), arrays give several
+// values and numbers and booleans keep their JSON text. Nulls are skipped.
+func RecordFromJSON(data []byte) (Record, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("mapping: decoding JSON record: %w", err)
+	}
+
+	record := make(Record)
+	for name, value := range object {
+		flatten(record, name, value)
+	}
+	return record, nil
+}
+
+// flatten adds a decoded JSON value to the record under the name
+func flatten(record Record, name string, value any) {
+	switch value := value.(type) {
+	case nil:
+	case map[string]any:
+		for key, nested := range value {
+			flatten(record, name+"."+key, nested)
+		}
+	case []any:
+		for _, item := range value {
+			flatten(record, name, item)
+		}
+	case string:
+		record[name] = append(record[name], value)
+	default:
+		record[name] = append(record[name], fmt.Sprint(value))
+	}
+}
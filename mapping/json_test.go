@@ -0,0 +1,43 @@
+package mapping
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRecordFromJSON(t *testing.T) {
+	record, err := RecordFromJSON([]byte(`{
+		"name": "Jane Doe",
+		"id": 1234567890123,
+		"active": true,
+		"manager": null,
+		"location": {"descriptor": "Sofia", "address": {"city": "Sofia"}},
+		"emails": ["a@example.com", "b@example.com"],
+		"phones": [{"number": "+1 555 0100"}, {"number": "+1 555 0101"}]
+	}`))
+	if err != nil {
+		t.Fatalf("RecordFromJSON() returned error: %v", err)
+	}
+
+	expected := Record{
+		"name":                  {"Jane Doe"},
+		"id":                    {"1234567890123"},
+		"active":                {"true"},
+		"location.descriptor":   {"Sofia"},
+		"location.address.city": {"Sofia"},
+		"emails":                {"a@example.com", "b@example.com"},
+		"phones.number":         {"+1 555 0100", "+1 555 0101"},
+	}
+	if len(record) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, record)
+	}
+	for name, values := range expected {
+		if !slices.Equal(record[name], values) {
+			t.Errorf("%s: expected %q, got %q", name, values, record[name])
+		}
+	}
+
+	if _, err := RecordFromJSON([]byte(`["not", "an", "object"]`)); err == nil {
+		t.Error("Expected error for a JSON array")
+	}
+}
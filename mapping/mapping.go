@@ -0,0 +1,216 @@
+// Package mapping builds contacts from records of named source attributes,
+// such as directory entries, CSV rows or HR system exports, using
+// declarative profiles:
+//
+//	profile := mapping.Profile{Fields: []mapping.Field{
+//		{Attribute: "given", Target: mapping.FirstName},
+//		{Attribute: "family", Target: mapping.LastName},
+//		{Attribute: "mail", Target: mapping.Email(vcard.EmailWork)},
+//	}}
+//	card := profile.Card(mapping.Record{"given": {"Jane"}, "family": {"Doe"}})
+//
+// Ready-made profiles live next to their source systems, e.g.
+// ldap.ActiveDirectory and hris.BambooHR. RecordFromJSON flattens JSON
+// objects into records.
+package mapping
+
+import (
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+// Record is a source record: attribute names and their values
+type Record map[string][]string
+
+// Get returns the values of the attribute. Names are matched exactly first,
+// then ignoring case, as directory attribute names are case-insensitive.
+func (r Record) Get(attribute string) []string {
+	if values, ok := r[attribute]; ok {
+		return values
+	}
+	for name, values := range r {
+		if strings.EqualFold(name, attribute) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Target sets a contact field from a source value
+type Target func(contact *vcard.Contact, value string)
+
+// Field maps a source attribute to a contact field
+type Field struct {
+	// Attribute is the source attribute name
+	Attribute string
+
+	// Target sets the contact field; it is called once per non-empty value
+	Target Target
+}
+
+// Profile maps the attributes of a source system to contact fields
+type Profile struct {
+	// Name identifies the profile, e.g. "active-directory"
+	Name string
+
+	// Fields are applied in order, so later fields win for single-valued
+	// contact fields and multi-valued fields keep the field order
+	Fields []Field
+}
+
+// Contact builds a contact from the record
+func (p Profile) Contact(record Record) vcard.Contact {
+	var contact vcard.Contact
+	for _, field := range p.Fields {
+		for _, value := range record.Get(field.Attribute) {
+			if value = strings.TrimSpace(value); value != "" {
+				field.Target(&contact, value)
+			}
+		}
+	}
+	return contact
+}
+
+// Card builds a card from the record
+func (p Profile) Card(record Record) *vcard.VCard {
+	return vcard.New().AddContact(p.Contact(record))
+}
+
+// FirstName sets the given name
+func FirstName(contact *vcard.Contact, value string) { contact.Name.First = value }
+
+// LastName sets the family name
+func LastName(contact *vcard.Contact, value string) { contact.Name.Last = value }
+
+// MiddleName sets the middle name
+func MiddleName(contact *vcard.Contact, value string) { contact.Name.Middle = value }
+
+// Prefix sets the honorific prefix
+func Prefix(contact *vcard.Contact, value string) { contact.Name.Prefix = value }
+
+// Suffix sets the honorific suffix
+func Suffix(contact *vcard.Contact, value string) { contact.Name.Suffix = value }
+
+// FullName sets the given and family names from a full name, splitting at
+// the last space ("Mary Ann Smith" gives Mary Ann and Smith)
+func FullName(contact *vcard.Contact, value string) {
+	contact.Name.First, contact.Name.Last = "", value
+	if i := strings.LastIndex(value, " "); i >= 0 {
+		contact.Name.First, contact.Name.Last = value[:i], value[i+1:]
+	}
+}
+
+// Organization sets the organization name
+func Organization(contact *vcard.Contact, value string) { contact.Organization.Name = value }
+
+// Department sets the department
+func Department(contact *vcard.Contact, value string) { contact.Organization.Department = value }
+
+// Unit adds an organizational unit below the department
+func Unit(contact *vcard.Contact, value string) {
+	contact.Organization.Units = append(contact.Organization.Units, value)
+}
+
+// Title sets the job title
+func Title(contact *vcard.Contact, value string) { contact.Organization.Title = value }
+
+// Role sets the role
+func Role(contact *vcard.Contact, value string) { contact.Organization.Role = value }
+
+// Note sets the note; several values are joined by newlines
+func Note(contact *vcard.Contact, value string) {
+	if contact.Note != "" {
+		value = contact.Note + "\n" + value
+	}
+	contact.Note = value
+}
+
+// Photo sets the photo URL or data URI
+func Photo(contact *vcard.Contact, value string) { contact.Photo = value }
+
+// Birthday sets the birthday (YYYY-MM-DD or free text)
+func Birthday(contact *vcard.Contact, value string) { contact.Birthday = &value }
+
+// UID sets the unique identifier
+func UID(contact *vcard.Contact, value string) { contact.UID = value }
+
+// Email returns a target adding an email address of the type
+func Email(emailType vcard.EmailType) Target {
+	return func(contact *vcard.Contact, value string) {
+		contact.Emails = append(contact.Emails, vcard.Email{Address: value, Type: emailType})
+	}
+}
+
+// Phone returns a target adding a phone number of the type
+func Phone(phoneType vcard.PhoneType) Target {
+	return func(contact *vcard.Contact, value string) {
+		contact.Phones = append(contact.Phones, vcard.Phone{Number: value, Type: phoneType})
+	}
+}
+
+// URL returns a target adding a URL of the type
+func URL(urlType vcard.URLType) Target {
+	return func(contact *vcard.Contact, value string) {
+		contact.URLs = append(contact.URLs, vcard.URL{Address: value, Type: urlType})
+	}
+}
+
+// Custom returns a target setting a custom X- or registered property
+func Custom(property string) Target {
+	return func(contact *vcard.Contact, value string) {
+		if contact.CustomProps == nil {
+			contact.CustomProps = make(map[string]string)
+		}
+		contact.CustomProps[property] = value
+	}
+}
+
+// Street returns a target setting the street of the address of the type
+func Street(addressType vcard.AddressType) Target {
+	return addressPart(addressType, func(address *vcard.Address, value string) { address.Street = value })
+}
+
+// Extended returns a target setting the extended address (suite, office or
+// building) of the address of the type
+func Extended(addressType vcard.AddressType) Target {
+	return addressPart(addressType, func(address *vcard.Address, value string) { address.Extended = value })
+}
+
+// City returns a target setting the city of the address of the type
+func City(addressType vcard.AddressType) Target {
+	return addressPart(addressType, func(address *vcard.Address, value string) { address.City = value })
+}
+
+// State returns a target setting the state or region of the address of the
+// type
+func State(addressType vcard.AddressType) Target {
+	return addressPart(addressType, func(address *vcard.Address, value string) { address.State = value })
+}
+
+// PostalCode returns a target setting the postal code of the address of the
+// type
+func PostalCode(addressType vcard.AddressType) Target {
+	return addressPart(addressType, func(address *vcard.Address, value string) { address.PostalCode = value })
+}
+
+// Country returns a target setting the country of the address of the type
+func Country(addressType vcard.AddressType) Target {
+	return addressPart(addressType, func(address *vcard.Address, value string) { address.Country = value })
+}
+
+// addressPart returns a target setting a part of the contact's address of
+// the type, adding the address on first use
+func addressPart(addressType vcard.AddressType, set func(address *vcard.Address, value string)) Target {
+	return func(contact *vcard.Contact, value string) {
+		for i := range contact.Addresses {
+			if contact.Addresses[i].Type == addressType {
+				set(&contact.Addresses[i], value)
+				return
+			}
+		}
+		address := vcard.Address{Type: addressType}
+		set(&address, value)
+		contact.Addresses = append(contact.Addresses, address)
+	}
+}
@@ -0,0 +1,84 @@
+package mapping
+
+import (
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestProfileContact(t *testing.T) {
+	profile := Profile{Fields: []Field{
+		{Attribute: "first", Target: FirstName},
+		{Attribute: "last", Target: LastName},
+		{Attribute: "work", Target: Email(vcard.EmailWork)},
+		{Attribute: "private", Target: Email(vcard.EmailHome)},
+		{Attribute: "cell", Target: Phone(vcard.PhoneMobile)},
+		{Attribute: "street", Target: Street(vcard.AddressWork)},
+		{Attribute: "city", Target: City(vcard.AddressWork)},
+		{Attribute: "home_city", Target: City(vcard.AddressHome)},
+		{Attribute: "comment", Target: Note},
+		{Attribute: "badge", Target: Custom("X-BADGE")},
+		{Attribute: "born", Target: Birthday},
+	}}
+
+	contact := profile.Contact(Record{
+		"FIRST":     {" Jane "},
+		"last":      {"Doe"},
+		"work":      {"jane@acme.com", "", "j.doe@acme.com"},
+		"private":   {"jane@example.com"},
+		"cell":      {"+1 555 0100"},
+		"street":    {"1 Main St"},
+		"city":      {"Springfield"},
+		"home_city": {"Shelbyville"},
+		"comment":   {"first", "second"},
+		"badge":     {"42"},
+		"born":      {"1990-05-15"},
+		"unmapped":  {"ignored"},
+	})
+
+	if contact.Name.First != "Jane" || contact.Name.Last != "Doe" {
+		t.Errorf("Unexpected name: %+v", contact.Name)
+	}
+	if len(contact.Emails) != 3 || contact.Emails[1].Address != "j.doe@acme.com" || contact.Emails[2].Type != vcard.EmailHome {
+		t.Errorf("Unexpected emails: %+v", contact.Emails)
+	}
+	if len(contact.Addresses) != 2 || contact.Addresses[0].Street != "1 Main St" || contact.Addresses[0].City != "Springfield" || contact.Addresses[1].Type != vcard.AddressHome {
+		t.Errorf("Unexpected addresses: %+v", contact.Addresses)
+	}
+	if contact.Note != "first\nsecond" || contact.CustomProps["X-BADGE"] != "42" || contact.Birthday == nil {
+		t.Errorf("Unexpected contact: %+v", contact)
+	}
+}
+
+func TestProfileCard(t *testing.T) {
+	profile := Profile{Fields: []Field{
+		{Attribute: "first", Target: FirstName},
+		{Attribute: "last", Target: LastName},
+		{Attribute: "org", Target: Organization},
+		{Attribute: "title", Target: Title},
+	}}
+
+	content, err := profile.Card(Record{"first": {"Jane"}, "last": {"Doe"}, "org": {"Acme"}, "title": {"CTO"}}).String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	for _, expected := range []string{"FN:Jane Doe", "ORG:Acme", "TITLE:CTO"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
+func TestRecordGet(t *testing.T) {
+	record := Record{"mail": {"exact"}, "MAIL": {"other"}, "Title": {"CTO"}}
+	if values := record.Get("mail"); len(values) != 1 || values[0] != "exact" {
+		t.Errorf("Expected exact match first, got %v", values)
+	}
+	if values := record.Get("title"); len(values) != 1 || values[0] != "CTO" {
+		t.Errorf("Expected case-insensitive match, got %v", values)
+	}
+	if values := record.Get("missing"); values != nil {
+		t.Errorf("Expected nil, got %v", values)
+	}
+}
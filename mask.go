@@ -0,0 +1,163 @@
+package vcard
+
+// FieldMask selects groups of contact fields. It is used to strip private
+// information before a card is shared, e.g. serving only PublicFields to
+// anonymous visitors. Name, formatted name, kind and UID are always kept.
+type FieldMask uint32
+
+const (
+	// FieldWorkEmail covers email addresses not marked as home
+	FieldWorkEmail FieldMask = 1 << iota
+
+	// FieldHomeEmail covers home email addresses
+	FieldHomeEmail
+
+	// FieldWorkPhone covers phone numbers not marked as home or mobile
+	FieldWorkPhone
+
+	// FieldHomePhone covers home phone numbers
+	FieldHomePhone
+
+	// FieldMobilePhone covers mobile phone numbers
+	FieldMobilePhone
+
+	// FieldWorkAddress covers addresses not marked as home
+	FieldWorkAddress
+
+	// FieldHomeAddress covers home addresses
+	FieldHomeAddress
+
+	// FieldOrganization covers organization, title and role
+	FieldOrganization
+
+	// FieldURLs covers all URLs
+	FieldURLs
+
+	// FieldPhoto covers the photo
+	FieldPhoto
+
+	// FieldNote covers the note
+	FieldNote
+
+	// FieldBirthday covers the birthday
+	FieldBirthday
+
+	// FieldAnniversary covers the anniversary
+	FieldAnniversary
+
+	// FieldGeo covers the geographic position
+	FieldGeo
+
+	// FieldCustom covers custom properties
+	FieldCustom
+
+	// FieldLogo covers the logo
+	FieldLogo
+
+	// FieldMembers covers the members of a group
+	FieldMembers
+)
+
+const (
+	// FieldAll selects every field
+	FieldAll FieldMask = FieldMembers<<1 - 1
+
+	// PublicFields excludes personal contact details and dates
+	PublicFields = FieldAll &^ (FieldHomeEmail | FieldHomePhone | FieldMobilePhone |
+		FieldHomeAddress | FieldBirthday | FieldAnniversary)
+)
+
+// Has reports whether all fields in other are selected
+func (m FieldMask) Has(other FieldMask) bool {
+	return m&other == other
+}
+
+// Masked returns a copy of the card containing only the selected fields
+func (v *VCard) Masked(mask FieldMask) *VCard {
+	clone := v.Clone()
+
+	clone.emails = clone.emails[:0]
+	for _, email := range v.emails {
+		field := FieldWorkEmail
+		if email.Type == EmailHome {
+			field = FieldHomeEmail
+		}
+		if mask.Has(field) {
+			clone.emails = append(clone.emails, email)
+		}
+	}
+
+	clone.phones = clone.phones[:0]
+	for _, phone := range v.phones {
+		field := FieldWorkPhone
+		switch phone.Type {
+		case PhoneHome:
+			field = FieldHomePhone
+		case PhoneMobile:
+			field = FieldMobilePhone
+		}
+		if mask.Has(field) {
+			clone.phones = append(clone.phones, phone)
+		}
+	}
+
+	clone.addresses = clone.addresses[:0]
+	for _, addr := range v.addresses {
+		field := FieldWorkAddress
+		if addr.Type == AddressHome {
+			field = FieldHomeAddress
+		}
+		if mask.Has(field) {
+			clone.addresses = append(clone.addresses, addr)
+		}
+	}
+
+	if !mask.Has(FieldOrganization) {
+		clone.organization = Organization{}
+		delete(clone.alternates, "ORG")
+		delete(clone.alternates, "TITLE")
+		delete(clone.alternates, "ROLE")
+	}
+	if !mask.Has(FieldURLs) {
+		clone.urls = clone.urls[:0]
+	}
+	if !mask.Has(FieldPhoto) {
+		clone.photo = ""
+		clone.photoCrop = nil
+	}
+	if !mask.Has(FieldLogo) {
+		clone.logo = ""
+	}
+	if !mask.Has(FieldNote) {
+		clone.note = ""
+		delete(clone.alternates, "NOTE")
+	}
+	if !mask.Has(FieldBirthday) {
+		clone.birthday = nil
+		clone.bdayText = ""
+	}
+	if !mask.Has(FieldAnniversary) {
+		clone.anniversary = nil
+		clone.annivText = ""
+	}
+	if !mask.Has(FieldGeo) {
+		clone.geo = nil
+	}
+	if !mask.Has(FieldCustom) {
+		clone.customProps = make(map[string]string)
+	}
+	if !mask.Has(FieldMembers) {
+		clone.members = nil
+	}
+
+	return clone
+}
+
+// Masked returns an address book of masked copies of the cards
+func (b *AddressBook) Masked(mask FieldMask) *AddressBook {
+	masked := &AddressBook{cards: make([]*VCard, len(b.cards))}
+	for i, card := range b.cards {
+		masked.cards[i] = card.Masked(mask)
+	}
+	return masked
+}
@@ -0,0 +1,90 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMasked(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddEmail("john@work.com", EmailWork)
+	card.AddEmail("john@home.com", EmailHome)
+	card.AddPhone("+1111", PhoneWork)
+	card.AddPhone("+2222", PhoneHome)
+	card.AddPhone("+3333", PhoneMobile)
+	card.AddAddress("1 Work St", "City", "", "", "", AddressWork)
+	card.AddAddress("2 Home St", "City", "", "", "", AddressHome)
+	card.AddOrganization("Acme Corp")
+	card.AddBirthday(time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC))
+	card.AddCustomProperty("X-TEST", "value")
+
+	public := card.Masked(PublicFields)
+
+	if len(public.GetEmails()) != 1 || public.GetEmail() != "john@work.com" {
+		t.Errorf("Expected only work email, got %v", public.GetEmails())
+	}
+	if len(public.GetPhones()) != 1 || public.GetPhone() != "+1111" {
+		t.Errorf("Expected only work phone, got %v", public.GetPhones())
+	}
+	if len(public.GetAddresses()) != 1 || public.GetAddress().Street != "1 Work St" {
+		t.Errorf("Expected only work address, got %v", public.GetAddresses())
+	}
+	if public.GetBirthday() != nil {
+		t.Error("Birthday should be stripped")
+	}
+	if public.GetOrganization().Name != "Acme Corp" || public.GetCustomProperty("X-TEST") != "value" {
+		t.Error("Public fields should be kept")
+	}
+	if public.GetFormattedName() != "John Doe" {
+		t.Error("Name should always be kept")
+	}
+
+	// Original is untouched
+	if len(card.GetPhones()) != 3 || card.GetBirthday() == nil {
+		t.Error("Masked() should not modify the original card")
+	}
+
+	full := card.Masked(FieldAll)
+	if len(full.GetPhones()) != 3 || full.GetBirthday() == nil {
+		t.Error("FieldAll should keep every field")
+	}
+
+	minimal := card.Masked(0)
+	if len(minimal.GetEmails()) != 0 || minimal.GetOrganization().Name != "" || len(minimal.GetCustomProperties()) != 0 {
+		t.Error("Empty mask should strip all optional fields")
+	}
+	if !minimal.IsValid() {
+		t.Error("Masked card should remain valid")
+	}
+}
+
+func TestMaskedLogoAndMembers(t *testing.T) {
+	group := NewWithVersion(Version40).SetKind(KindGroup).SetFormattedName("Team").
+		AddLogo("data:image/png;base64,iVBORw0KGgo=").
+		AddMember("mailto:jane@example.com")
+
+	masked := group.Masked(FieldAll &^ (FieldLogo | FieldMembers))
+	if masked.GetLogo() != "" {
+		t.Errorf("Expected the logo to be stripped, got %q", masked.GetLogo())
+	}
+	if len(masked.GetMembers()) != 0 {
+		t.Errorf("Expected the members to be stripped, got %v", masked.GetMembers())
+	}
+	content, err := masked.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+	if strings.Contains(content, "LOGO") || strings.Contains(content, "MEMBER") {
+		t.Errorf("Expected no LOGO or MEMBER lines, got:\n%s", content)
+	}
+
+	kept := group.Masked(FieldLogo | FieldMembers)
+	if kept.GetLogo() == "" || len(kept.GetMembers()) != 1 {
+		t.Error("Expected selected logo and members to be kept")
+	}
+	if len(group.GetMembers()) != 1 || group.GetLogo() == "" {
+		t.Error("Masked() should not modify the original card")
+	}
+}
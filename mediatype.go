@@ -0,0 +1,119 @@
+package vcard
+
+import (
+	"strings"
+	"sync"
+)
+
+// MediaType describes a media format used by PHOTO, LOGO and SOUND values
+type MediaType struct {
+	// MIME is the media type (e.g. "image/jpeg")
+	MIME string
+
+	// Token is the vCard 3.0 TYPE parameter value (e.g. "JPEG")
+	Token string
+
+	// Extensions are the file extensions including the dot, the first being
+	// the preferred one
+	Extensions []string
+}
+
+var (
+	mediaTypesMu sync.RWMutex
+	mediaTypes   = []MediaType{
+		{MIME: "image/jpeg", Token: "JPEG", Extensions: []string{".jpg", ".jpeg", ".jpe"}},
+		{MIME: "image/png", Token: "PNG", Extensions: []string{".png"}},
+		{MIME: "image/gif", Token: "GIF", Extensions: []string{".gif"}},
+		{MIME: "image/webp", Token: "WEBP", Extensions: []string{".webp"}},
+		{MIME: "image/bmp", Token: "BMP", Extensions: []string{".bmp"}},
+		{MIME: "image/tiff", Token: "TIFF", Extensions: []string{".tif", ".tiff"}},
+		{MIME: "image/svg+xml", Token: "SVG", Extensions: []string{".svg"}},
+		{MIME: "image/heic", Token: "HEIC", Extensions: []string{".heic"}},
+		{MIME: "audio/basic", Token: "BASIC", Extensions: []string{".au", ".snd"}},
+		{MIME: "audio/mpeg", Token: "MP3", Extensions: []string{".mp3"}},
+		{MIME: "audio/ogg", Token: "OGG", Extensions: []string{".ogg", ".oga"}},
+		{MIME: "audio/wav", Token: "WAVE", Extensions: []string{".wav"}},
+		{MIME: "audio/aac", Token: "AAC", Extensions: []string{".aac"}},
+	}
+)
+
+// The media type assumed for base64 photo data without one
+const (
+	defaultPhotoMIME  = "image/jpeg"
+	defaultPhotoToken = "JPEG"
+)
+
+// RegisterMediaType adds a media type to the registry, replacing any entry
+// with the same MIME type
+func RegisterMediaType(mediaType MediaType) {
+	mediaTypesMu.Lock()
+	defer mediaTypesMu.Unlock()
+
+	for i, existing := range mediaTypes {
+		if strings.EqualFold(existing.MIME, mediaType.MIME) {
+			mediaTypes[i] = mediaType
+			return
+		}
+	}
+	mediaTypes = append(mediaTypes, mediaType)
+}
+
+// MediaTypeByMIME looks up a media type by MIME type, ignoring parameters
+// such as "; charset=..."
+func MediaTypeByMIME(mime string) (MediaType, bool) {
+	mime, _, _ = strings.Cut(mime, ";")
+	mime = strings.TrimSpace(mime)
+	return findMediaType(func(mt MediaType) bool {
+		return strings.EqualFold(mt.MIME, mime)
+	})
+}
+
+// MediaTypeByToken looks up a media type by its vCard TYPE token (e.g. "PNG")
+func MediaTypeByToken(token string) (MediaType, bool) {
+	return findMediaType(func(mt MediaType) bool {
+		return strings.EqualFold(mt.Token, token)
+	})
+}
+
+// MediaTypeByExtension looks up a media type by file extension, with or
+// without the leading dot
+func MediaTypeByExtension(ext string) (MediaType, bool) {
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return findMediaType(func(mt MediaType) bool {
+		for _, candidate := range mt.Extensions {
+			if strings.EqualFold(candidate, ext) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// findMediaType returns the first registered media type matching the predicate
+func findMediaType(match func(MediaType) bool) (MediaType, bool) {
+	mediaTypesMu.RLock()
+	defer mediaTypesMu.RUnlock()
+
+	for _, mt := range mediaTypes {
+		if match(mt) {
+			return mt, true
+		}
+	}
+	return MediaType{}, false
+}
+
+// mediaTypeToken returns the vCard TYPE token for a MIME type, deriving it
+// from the subtype for unregistered types
+func mediaTypeToken(mime string) string {
+	if mt, ok := MediaTypeByMIME(mime); ok {
+		return mt.Token
+	}
+
+	mime, _, _ = strings.Cut(mime, ";")
+	if _, subtype, ok := strings.Cut(mime, "/"); ok && subtype != "" {
+		return strings.ToUpper(subtype)
+	}
+	return defaultPhotoToken
+}
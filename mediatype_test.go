@@ -0,0 +1,63 @@
+package vcard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMediaTypeLookup(t *testing.T) {
+	if mt, ok := MediaTypeByExtension("PNG"); !ok || mt.MIME != "image/png" {
+		t.Errorf("MediaTypeByExtension(PNG) = %v, %v", mt, ok)
+	}
+	if mt, ok := MediaTypeByExtension(".jpeg"); !ok || mt.Token != "JPEG" {
+		t.Errorf("MediaTypeByExtension(.jpeg) = %v, %v", mt, ok)
+	}
+	if mt, ok := MediaTypeByMIME("audio/ogg; codecs=vorbis"); !ok || mt.Token != "OGG" {
+		t.Errorf("MediaTypeByMIME(audio/ogg) = %v, %v", mt, ok)
+	}
+	if mt, ok := MediaTypeByToken("gif"); !ok || mt.MIME != "image/gif" {
+		t.Errorf("MediaTypeByToken(gif) = %v, %v", mt, ok)
+	}
+	if _, ok := MediaTypeByExtension(".txt"); ok {
+		t.Error("Expected .txt to be unknown")
+	}
+}
+
+func TestRegisterMediaType(t *testing.T) {
+	RegisterMediaType(MediaType{MIME: "image/avif", Token: "AVIF", Extensions: []string{".avif"}})
+
+	mt, ok := MediaTypeByExtension(".avif")
+	if !ok || mt.MIME != "image/avif" {
+		t.Fatalf("Expected registered media type, got %v, %v", mt, ok)
+	}
+
+	if got := mediaTypeToken("image/x-unknown"); got != "X-UNKNOWN" {
+		t.Errorf("Expected token derived from subtype, got %s", got)
+	}
+}
+
+func TestPhotoMediaTypes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "avatar.png")
+	if err := os.WriteFile(path, []byte("png data"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	card := New().AddName("John", "Doe")
+	if err := card.AddPhotoFromFile(path); err != nil {
+		t.Fatalf("AddPhotoFromFile() returned error: %v", err)
+	}
+	if !strings.HasPrefix(card.GetPhoto(), "data:image/png;base64,") {
+		t.Errorf("Expected PNG data URI, got %s", card.GetPhoto())
+	}
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+	if !strings.Contains(content, "PHOTO;ENCODING=b;TYPE=PNG:") {
+		t.Errorf("Expected PNG photo type, got %s", content)
+	}
+}
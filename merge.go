@@ -0,0 +1,222 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Conflict is a property both sides of a three-way merge changed in
+// different ways. Values are given in their text form.
+type Conflict struct {
+	// Property is the property name, e.g. "FN"
+	Property string `json:"property"`
+
+	// Base, Ours and Theirs are the values of the property on each card
+	Base   []string `json:"base,omitempty"`
+	Ours   []string `json:"ours,omitempty"`
+	Theirs []string `json:"theirs,omitempty"`
+}
+
+// singularProperties hold one value per card (RFC 6350 cardinality *1, and
+// FN, ORG, TITLE, ROLE, NOTE, PHOTO, LOGO and GEO in practice), so diverging
+// changes conflict instead of being combined
+var singularProperties = map[string]bool{
+	"FN":          true,
+	"N":           true,
+	"KIND":        true,
+	"BDAY":        true,
+	"ANNIVERSARY": true,
+	"GENDER":      true,
+	"UID":         true,
+	"PRODID":      true,
+	"REV":         true,
+	"ORG":         true,
+	"TITLE":       true,
+	"ROLE":        true,
+	"NOTE":        true,
+	"PHOTO":       true,
+	"LOGO":        true,
+	"GEO":         true,
+}
+
+// Merge3 merges the changes made from base to ours and from base to theirs,
+// property by property, as a version control merge of the card would. A
+// property changed on one side only takes that side's values. When both
+// sides changed a multi-valued property such as EMAIL, values added on
+// either side are kept and values removed on either side are dropped. A
+// single-valued property such as FN changed differently on both sides is a
+// conflict: the merged card keeps our value, except for REV, which takes the
+// later timestamp and is not reported. A nil base merges two cards created
+// independently.
+//
+// The merged card has our version; metadata and options are taken from
+// ours. It fails when ours or theirs is nil or a card does not validate.
+func Merge3(base, ours, theirs *VCard) (*VCard, []Conflict, error) {
+	if ours == nil || theirs == nil {
+		return nil, nil, fmt.Errorf("merge needs both our and their card")
+	}
+	version := ours.version
+	baseLines, err := mergeLines(base, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	ourLines, err := mergeLines(ours, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirLines, err := mergeLines(theirs, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Properties are written in our order, followed by those only theirs has
+	names := propertyOrder(ourLines)
+	for _, name := range propertyOrder(theirLines) {
+		if _, ok := ourLines[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	var builder strings.Builder
+	var conflicts []Conflict
+	builder.WriteString("BEGIN:VCARD\nVERSION:" + string(version) + "\n")
+	for _, name := range names {
+		lines, conflict := mergeProperty(name, baseLines[name], ourLines[name], theirLines[name])
+		if conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+		for _, line := range lines {
+			builder.WriteString(line.text + "\n")
+		}
+	}
+	builder.WriteString("END:VCARD\n")
+
+	merged, err := readCard(builder.String(), ours.options)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range ours.meta {
+		merged.SetMeta(key, value)
+	}
+	return merged, conflicts, nil
+}
+
+// mergeLine is a content line of a card being merged
+type mergeLine struct {
+	text  string
+	order int
+	value string
+}
+
+// mergeLines returns the content lines of the card, encoded in the version,
+// by property name. A nil card has no lines.
+func mergeLines(card *VCard, version Version) (map[string][]mergeLine, error) {
+	lines := make(map[string][]mergeLine)
+	if card == nil {
+		return lines, nil
+	}
+
+	if card.version != version {
+		card = card.Clone().SetVersion(version)
+	}
+	content, err := card.String()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range unfoldLines(content) {
+		property, err := ParseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		switch property.Name {
+		case "BEGIN", "END", "VERSION":
+			continue
+		}
+		lines[property.Name] = append(lines[property.Name], mergeLine{text: line, order: i, value: property.Text()})
+	}
+	return lines, nil
+}
+
+// propertyOrder returns the property names in order of first appearance
+func propertyOrder(lines map[string][]mergeLine) []string {
+	names := make([]string, 0, len(lines))
+	for name := range lines {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return lines[names[i]][0].order < lines[names[j]][0].order })
+	return names
+}
+
+// mergeProperty merges the lines of one property
+func mergeProperty(name string, base, ours, theirs []mergeLine) ([]mergeLine, *Conflict) {
+	switch {
+	case sameLines(ours, base):
+		return theirs, nil
+	case sameLines(theirs, base), sameLines(ours, theirs):
+		return ours, nil
+	case name == "REV":
+		if len(theirs) > 0 && (len(ours) == 0 || theirs[0].value > ours[0].value) {
+			return theirs, nil
+		}
+		return ours, nil
+	case singularProperties[name]:
+		return ours, &Conflict{
+			Property: name,
+			Base:     lineValues(base),
+			Ours:     lineValues(ours),
+			Theirs:   lineValues(theirs),
+		}
+	}
+
+	inBase, inOurs, inTheirs := lineSet(base), lineSet(ours), lineSet(theirs)
+	var merged []mergeLine
+	for _, line := range ours {
+		// Keep our lines unless theirs removed them
+		if !inBase[line.text] || inTheirs[line.text] {
+			merged = append(merged, line)
+		}
+	}
+	for _, line := range theirs {
+		if !inOurs[line.text] && !inBase[line.text] {
+			merged = append(merged, line)
+		}
+	}
+	return merged, nil
+}
+
+// sameLines reports whether a and b hold the same lines in any order
+func sameLines(a, b []mergeLine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	texts := func(lines []mergeLine) []string {
+		result := make([]string, len(lines))
+		for i, line := range lines {
+			result[i] = line.text
+		}
+		sort.Strings(result)
+		return result
+	}
+	return slices.Equal(texts(a), texts(b))
+}
+
+// lineSet returns the texts of the lines
+func lineSet(lines []mergeLine) map[string]bool {
+	set := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		set[line.text] = true
+	}
+	return set
+}
+
+// lineValues returns the text values of the lines
+func lineValues(lines []mergeLine) []string {
+	values := make([]string, len(lines))
+	for i, line := range lines {
+		values[i] = line.value
+	}
+	return values
+}
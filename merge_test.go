@@ -0,0 +1,128 @@
+package vcard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := New().AddName("Jane", "Doe").SetUID("1").
+		AddEmail("jane@example.com").AddPhone("+1 555 0100").AddTitle("Engineer")
+
+	ours := base.Clone().AddEmail("jane@work.example.com").AddTitle("Lead Engineer")
+	theirs := base.Clone().AddEmail("jane.doe@home.example.com").AddURL("https://jane.example.com")
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge3() returned error: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %+v", conflicts)
+	}
+
+	emails := Get[string](merged, "EMAIL")
+	expected := []string{"jane@example.com", "jane@work.example.com", "jane.doe@home.example.com"}
+	if !reflect.DeepEqual(emails, expected) {
+		t.Errorf("Expected emails %v, got %v", expected, emails)
+	}
+	if merged.GetOrganization().Title != "Lead Engineer" || len(Get[string](merged, "URL")) != 1 {
+		t.Errorf("Expected both sides' changes, got:\n%v", Diff(base, merged))
+	}
+}
+
+func TestMerge3Removals(t *testing.T) {
+	base := New().AddName("Jane", "Doe").AddEmail("old@example.com").AddEmail("keep@example.com")
+	ours := New().AddName("Jane", "Doe").AddEmail("keep@example.com")
+	theirs := base.Clone().AddEmail("new@example.com")
+
+	merged, _, err := Merge3(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge3() returned error: %v", err)
+	}
+	expected := []string{"keep@example.com", "new@example.com"}
+	if emails := Get[string](merged, "EMAIL"); !reflect.DeepEqual(emails, expected) {
+		t.Errorf("Expected emails %v, got %v", expected, emails)
+	}
+}
+
+func TestMerge3Conflict(t *testing.T) {
+	base := New().AddName("Jane", "Doe").SetUID("1")
+	ours := base.Clone().AddName("Janet", "Doe")
+	theirs := base.Clone().AddName("Jane", "Smith").SetVersion(Version30)
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge3() returned error: %v", err)
+	}
+	if merged.GetFormattedName() != "Janet Doe" {
+		t.Errorf("Expected our name to be kept, got %q", merged.GetFormattedName())
+	}
+
+	properties := []string{}
+	for _, conflict := range conflicts {
+		properties = append(properties, conflict.Property)
+	}
+	if !reflect.DeepEqual(properties, []string{"N", "FN"}) && !reflect.DeepEqual(properties, []string{"FN", "N"}) {
+		t.Fatalf("Expected FN and N conflicts, got %+v", conflicts)
+	}
+	for _, conflict := range conflicts {
+		if conflict.Property == "FN" && (conflict.Base[0] != "Jane Doe" || conflict.Ours[0] != "Janet Doe" || conflict.Theirs[0] != "Jane Smith") {
+			t.Errorf("Unexpected conflict %+v", conflict)
+		}
+	}
+}
+
+func TestMerge3WithoutBase(t *testing.T) {
+	ours := New().AddName("Jane", "Doe").AddEmail("a@example.com")
+	theirs := New().AddName("Jane", "Doe").AddEmail("b@example.com")
+
+	merged, conflicts, err := Merge3(nil, ours, theirs)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("Merge3() returned %v, %+v", err, conflicts)
+	}
+	if emails := Get[string](merged, "EMAIL"); len(emails) != 2 {
+		t.Errorf("Expected both emails, got %v", emails)
+	}
+}
+
+func TestMerge3SingularProperties(t *testing.T) {
+	base := New().AddName("Jane", "Doe").SetUID("1").
+		AddOrganization("Acme").AddNote("Met at the conference")
+	ours := base.Clone().AddOrgUnit("Sales").AddNote("Prefers email")
+	theirs := base.Clone().AddOrganization("Globex").AddNote("Prefers phone")
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge3() returned error: %v", err)
+	}
+
+	properties := map[string]Conflict{}
+	for _, conflict := range conflicts {
+		properties[conflict.Property] = conflict
+	}
+	if len(properties) != 2 {
+		t.Fatalf("Expected ORG and NOTE conflicts, got %+v", conflicts)
+	}
+	if org := properties["ORG"]; len(org.Ours) != 1 || len(org.Theirs) != 1 || org.Theirs[0] != "Globex" {
+		t.Errorf("Unexpected ORG conflict %+v", org)
+	}
+	if note := properties["NOTE"]; len(note.Ours) != 1 || note.Ours[0] != "Prefers email" || note.Theirs[0] != "Prefers phone" {
+		t.Errorf("Unexpected NOTE conflict %+v", note)
+	}
+	if notes := Get[string](merged, "NOTE"); !reflect.DeepEqual(notes, []string{"Prefers email"}) {
+		t.Errorf("Expected our note to be kept, got %v", notes)
+	}
+	if merged.GetOrganization().Name != "Acme" {
+		t.Errorf("Expected our organization to be kept, got %+v", merged.GetOrganization())
+	}
+}
+
+func TestMerge3NilCards(t *testing.T) {
+	card := New().AddName("Jane", "Doe")
+	if _, _, err := Merge3(nil, nil, card); err == nil {
+		t.Error("Expected error for a nil card of ours")
+	}
+	if _, _, err := Merge3(card, card, nil); err == nil {
+		t.Error("Expected error for a nil card of theirs")
+	}
+}
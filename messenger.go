@@ -0,0 +1,120 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TelegramMaxVCardSize is the largest vcard field accepted by the Telegram
+// Bot API sendContact method
+const TelegramMaxVCardSize = 2048
+
+// TelegramContact holds the parameters of the Telegram Bot API sendContact
+// method and marshals to its JSON field names
+type TelegramContact struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	VCard       string `json:"vcard,omitempty"`
+}
+
+// WhatsAppVCard returns the minimal vCard 3.0 accepted when sharing a contact
+// through WhatsApp: N, FN and a single TEL carrying the waid parameter that
+// links the number to a WhatsApp account, in that order
+func (v *VCard) WhatsAppVCard() (string, error) {
+	v = v.prepared()
+	phone, ok := v.primaryPhone()
+	if !ok {
+		return "", fmt.Errorf("whatsapp contact requires a phone number")
+	}
+
+	fn := v.formattedName()
+	if fn == "" {
+		return "", fmt.Errorf("whatsapp contact requires a formatted name")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	builder.WriteString("N:" + v.name.StructuredName() + "\n")
+	builder.WriteString("FN:" + escapeValue(fn) + "\n")
+	builder.WriteString(fmt.Sprintf("TEL;type=CELL;waid=%s:%s\n", phoneDigits(phone.Number), escapeValue(phone.Number)))
+	builder.WriteString("END:VCARD\n")
+	return builder.String(), nil
+}
+
+// TelegramContact returns the sendContact parameters for the card. The
+// attached vCard holds N, FN, the shared TEL and the first email, and is left
+// out when it exceeds TelegramMaxVCardSize.
+func (v *VCard) TelegramContact() (TelegramContact, error) {
+	v = v.prepared()
+	phone, ok := v.primaryPhone()
+	if !ok {
+		return TelegramContact{}, fmt.Errorf("telegram contact requires a phone number")
+	}
+
+	contact := TelegramContact{
+		PhoneNumber: phone.Number,
+		FirstName:   v.name.First,
+		LastName:    v.name.Last,
+	}
+	if contact.FirstName == "" {
+		// first_name is mandatory; fall back to the formatted name
+		contact.FirstName, contact.LastName = v.formattedName(), ""
+	}
+	if contact.FirstName == "" {
+		return TelegramContact{}, fmt.Errorf("telegram contact requires a name")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	builder.WriteString("N:" + v.name.StructuredName() + "\n")
+	builder.WriteString("FN:" + escapeValue(v.formattedName()) + "\n")
+	builder.WriteString("TEL" + typeParams(telegramPhoneType(phone.Type)).String() + ":" + escapeValue(phone.Number) + "\n")
+	if email := v.GetEmail(); email != "" {
+		builder.WriteString("EMAIL:" + escapeValue(email) + "\n")
+	}
+	builder.WriteString("END:VCARD\n")
+
+	if builder.Len() <= TelegramMaxVCardSize {
+		contact.VCard = builder.String()
+	}
+	return contact, nil
+}
+
+// primaryPhone returns the preferred phone number, else the first mobile
+// number, else the first number
+func (v *VCard) primaryPhone() (Phone, bool) {
+	if len(v.phones) == 0 {
+		return Phone{}, false
+	}
+	for _, phone := range v.phones {
+		if phone.Preferred {
+			return phone, true
+		}
+	}
+	for _, phone := range v.phones {
+		if phone.Type == PhoneMobile {
+			return phone, true
+		}
+	}
+	return v.phones[0], true
+}
+
+// telegramPhoneType returns the TYPE value for the shared phone number
+func telegramPhoneType(phoneType PhoneType) string {
+	if phoneType == "" || phoneType == PhoneMobile {
+		return "CELL"
+	}
+	return string(phoneType)
+}
+
+// phoneDigits returns the digits of a phone number, as used by WhatsApp IDs
+func phoneDigits(number string) string {
+	var builder strings.Builder
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
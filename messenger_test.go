@@ -0,0 +1,67 @@
+package vcard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWhatsAppVCard(t *testing.T) {
+	card := New().
+		AddName("John", "Doe").
+		AddEmail("john@example.com").
+		AddPhone("+1 555 0100", PhoneWork).
+		AddPhone("+1 555-0199", PhoneMobile)
+
+	content, err := card.WhatsAppVCard()
+	if err != nil {
+		t.Fatalf("WhatsAppVCard() returned error: %v", err)
+	}
+
+	expected := "BEGIN:VCARD\nVERSION:3.0\nN:Doe;John;;;\nFN:John Doe\n" +
+		"TEL;type=CELL;waid=15550199:+1 555-0199\nEND:VCARD\n"
+	if content != expected {
+		t.Errorf("WhatsAppVCard() = %q, want %q", content, expected)
+	}
+
+	if _, err := New().AddName("John", "Doe").WhatsAppVCard(); err == nil {
+		t.Error("Expected error for card without phone")
+	}
+}
+
+func TestTelegramContact(t *testing.T) {
+	card := New().
+		AddName("John", "Doe").
+		AddEmail("john@example.com").
+		AddPhoneWithPreference("+15550100", PhoneHome, true).
+		AddPhone("+15550199", PhoneMobile)
+
+	contact, err := card.TelegramContact()
+	if err != nil {
+		t.Fatalf("TelegramContact() returned error: %v", err)
+	}
+
+	if contact.PhoneNumber != "+15550100" || contact.FirstName != "John" || contact.LastName != "Doe" {
+		t.Errorf("Unexpected contact: %+v", contact)
+	}
+	if !strings.Contains(contact.VCard, "TEL;TYPE=HOME:+15550100\n") || !strings.Contains(contact.VCard, "EMAIL:john@example.com\n") {
+		t.Errorf("Unexpected vcard: %s", contact.VCard)
+	}
+
+	data, _ := json.Marshal(contact)
+	if !strings.Contains(string(data), `"phone_number":"+15550100"`) {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	// Organizations fall back to the formatted name and oversized cards drop
+	// the vcard field
+	org := New().SetKind(KindOrg).AddOrganization("Acme").AddPhone("+15550100").
+		SetFormattedName(strings.Repeat("Acme ", 500))
+	contact, err = org.TelegramContact()
+	if err != nil {
+		t.Fatalf("TelegramContact() returned error: %v", err)
+	}
+	if !strings.HasPrefix(contact.FirstName, "Acme") || contact.VCard != "" {
+		t.Errorf("Expected name fallback without vcard, got %+v", contact)
+	}
+}
@@ -0,0 +1,33 @@
+package vcard
+
+// SetMeta attaches metadata to the card, e.g. the source system or import
+// batch of a pipeline. Metadata is kept in memory only: it is never written
+// to the vCard output and is ignored by Diff, Lint and Validate. Clone copies
+// it and Reset clears it.
+func (v *VCard) SetMeta(key, value string) *VCard {
+	if v.meta == nil {
+		v.meta = make(map[string]string)
+	}
+	v.meta[key] = value
+	return v
+}
+
+// GetMeta returns the metadata value for the key, or an empty string
+func (v *VCard) GetMeta(key string) string {
+	return v.meta[key]
+}
+
+// GetMetadata returns all metadata
+func (v *VCard) GetMetadata() map[string]string {
+	meta := make(map[string]string, len(v.meta))
+	for k, value := range v.meta {
+		meta[k] = value
+	}
+	return meta
+}
+
+// RemoveMeta removes the metadata value for the key
+func (v *VCard) RemoveMeta(key string) *VCard {
+	delete(v.meta, key)
+	return v
+}
@@ -0,0 +1,48 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeta(t *testing.T) {
+	card := New().AddName("Jane", "Doe").
+		SetMeta("source", "crm").
+		SetMeta("batch", "2024-06-01-17")
+
+	if card.GetMeta("source") != "crm" || card.GetMeta("missing") != "" {
+		t.Errorf("Unexpected metadata %v", card.GetMetadata())
+	}
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+	if strings.Contains(content, "crm") || strings.Contains(content, "2024-06-01-17") {
+		t.Errorf("Expected metadata to stay out of the output:\n%s", content)
+	}
+	if changes := Diff(New().AddName("Jane", "Doe"), card); len(changes) != 0 {
+		t.Errorf("Expected no changes from metadata, got %v", changes)
+	}
+
+	clone := card.Clone()
+	clone.SetMeta("source", "ldap")
+	if card.GetMeta("source") != "crm" || clone.GetMeta("batch") != "2024-06-01-17" {
+		t.Error("Expected Clone to copy the metadata")
+	}
+
+	meta := card.GetMetadata()
+	meta["source"] = "changed"
+	if card.GetMeta("source") != "crm" {
+		t.Error("Expected GetMetadata to return a copy")
+	}
+
+	card.RemoveMeta("batch")
+	if len(card.GetMetadata()) != 1 {
+		t.Errorf("Expected one value after RemoveMeta, got %v", card.GetMetadata())
+	}
+	if card.Reset(); len(card.GetMetadata()) != 0 {
+		t.Error("Expected Reset to clear the metadata")
+	}
+	New().RemoveMeta("anything")
+}
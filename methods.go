@@ -1,53 +1,86 @@
 package vcard
 
 import (
-	"encoding/base64"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
 // AddName sets the contact's name
 func (v *VCard) AddName(first, last string) *VCard {
-	v.name.First = first
-	v.name.Last = last
+	v.name.First = v.options.name(first)
+	v.name.Last = v.options.name(last)
 	return v
 }
 
 // AddMiddleName sets the middle name
 func (v *VCard) AddMiddleName(middle string) *VCard {
-	v.name.Middle = middle
+	v.name.Middle = v.options.name(middle)
 	return v
 }
 
 // AddPrefix sets the name prefix (Mr., Dr., etc.)
 func (v *VCard) AddPrefix(prefix string) *VCard {
-	v.name.Prefix = prefix
+	v.name.Prefix = v.options.name(prefix)
 	return v
 }
 
 // AddSuffix sets the name suffix (Jr., PhD, etc.)
 func (v *VCard) AddSuffix(suffix string) *VCard {
-	v.name.Suffix = suffix
+	v.name.Suffix = v.options.name(suffix)
 	return v
 }
 
 // SetName sets the complete name structure
 func (v *VCard) SetName(name Name) *VCard {
-	v.name = name
+	v.name = name.normalized(v.options)
 	return v
 }
 
-// AddEmail adds an email address with optional type
+// SetKind sets the kind of object the card describes. Organization cards
+// (KindOrg) are validated by organization or formatted name and location
+// cards (KindLocation) by formatted name plus an address or geographic
+// position, instead of a personal name. KIND is only written for vCard 4.0.
+func (v *VCard) SetKind(kind Kind) *VCard {
+	v.kind = kind
+	return v
+}
+
+// SetShowAsCompany flags the card to be displayed by its organization name
+// rather than a person's name in Apple Contacts (X-ABShowAs:COMPANY)
+func (v *VCard) SetShowAsCompany(show bool) *VCard {
+	v.showCompany = show
+	return v
+}
+
+// SetFormattedName sets the formatted name (FN property) explicitly instead of
+// deriving it from the structured name
+func (v *VCard) SetFormattedName(fn string) *VCard {
+	v.fn = v.options.name(fn)
+	return v
+}
+
+// SetFormattedNameFallback enables deriving FN from the organization name or
+// the first email address when the card has no personal name. This allows
+// vCard 4.0 cards, where FN is mandatory, without first or last name.
+func (v *VCard) SetFormattedNameFallback(enabled bool) *VCard {
+	v.fnFallback = enabled
+	return v
+}
+
+// AddEmail adds an email address with optional type; the EmailType of the
+// card's options is used when no type is given
 func (v *VCard) AddEmail(address string, emailType ...EmailType) *VCard {
 	email := Email{
-		Address: address,
+		Address: v.options.text(address),
 	}
 
 	if len(emailType) > 0 {
 		email.Type = emailType[0]
 	} else {
-		email.Type = EmailInternet
+		email.Type = v.options.emailType()
 	}
 
 	v.emails = append(v.emails, email)
@@ -57,7 +90,7 @@ func (v *VCard) AddEmail(address string, emailType ...EmailType) *VCard {
 // AddEmailWithPreference adds an email address with type and preference
 func (v *VCard) AddEmailWithPreference(address string, emailType EmailType, preferred bool) *VCard {
 	email := Email{
-		Address:   address,
+		Address:   v.options.text(address),
 		Type:      emailType,
 		Preferred: preferred,
 	}
@@ -68,11 +101,15 @@ func (v *VCard) AddEmailWithPreference(address string, emailType EmailType, pref
 
 // AddEmails adds multiple email addresses at once
 func (v *VCard) AddEmails(emails []Email) *VCard {
-	v.emails = append(v.emails, emails...)
+	for _, email := range emails {
+		email.Address = v.options.text(email.Address)
+		v.emails = append(v.emails, email)
+	}
 	return v
 }
 
-// AddPhone adds a phone number with optional type
+// AddPhone adds a phone number with optional type; the PhoneType of the
+// card's options is used when no type is given
 func (v *VCard) AddPhone(number string, phoneType ...PhoneType) *VCard {
 	phone := Phone{
 		Number: number,
@@ -81,7 +118,7 @@ func (v *VCard) AddPhone(number string, phoneType ...PhoneType) *VCard {
 	if len(phoneType) > 0 {
 		phone.Type = phoneType[0]
 	} else {
-		phone.Type = PhoneVoice
+		phone.Type = v.options.phoneType()
 	}
 
 	v.phones = append(v.phones, phone)
@@ -109,11 +146,11 @@ func (v *VCard) AddPhones(phones []Phone) *VCard {
 // AddAddress adds an address with optional type
 func (v *VCard) AddAddress(street, city, state, postalCode, country string, addressType ...AddressType) *VCard {
 	address := Address{
-		Street:     street,
-		City:       city,
-		State:      state,
-		PostalCode: postalCode,
-		Country:    country,
+		Street:     v.options.text(street),
+		City:       v.options.text(city),
+		State:      v.options.text(state),
+		PostalCode: v.options.text(postalCode),
+		Country:    v.options.text(country),
 	}
 
 	if len(addressType) > 0 {
@@ -127,12 +164,12 @@ func (v *VCard) AddAddress(street, city, state, postalCode, country string, addr
 // AddAddressExtended adds an address with extended information
 func (v *VCard) AddAddressExtended(street, extended, city, state, postalCode, country string, addressType ...AddressType) *VCard {
 	address := Address{
-		Street:     street,
-		Extended:   extended,
-		City:       city,
-		State:      state,
-		PostalCode: postalCode,
-		Country:    country,
+		Street:     v.options.text(street),
+		Extended:   v.options.text(extended),
+		City:       v.options.text(city),
+		State:      v.options.text(state),
+		PostalCode: v.options.text(postalCode),
+		Country:    v.options.text(country),
 	}
 
 	if len(addressType) > 0 {
@@ -146,11 +183,11 @@ func (v *VCard) AddAddressExtended(street, extended, city, state, postalCode, co
 // AddAddressWithPreference adds an address with type and preference
 func (v *VCard) AddAddressWithPreference(street, city, state, postalCode, country string, addressType AddressType, preferred bool) *VCard {
 	address := Address{
-		Street:     street,
-		City:       city,
-		State:      state,
-		PostalCode: postalCode,
-		Country:    country,
+		Street:     v.options.text(street),
+		City:       v.options.text(city),
+		State:      v.options.text(state),
+		PostalCode: v.options.text(postalCode),
+		Country:    v.options.text(country),
 		Type:       addressType,
 		Preferred:  preferred,
 	}
@@ -161,37 +198,45 @@ func (v *VCard) AddAddressWithPreference(street, city, state, postalCode, countr
 
 // AddAddresses adds multiple addresses at once
 func (v *VCard) AddAddresses(addresses []Address) *VCard {
-	v.addresses = append(v.addresses, addresses...)
+	for _, address := range addresses {
+		v.addresses = append(v.addresses, address.normalized(v.options))
+	}
 	return v
 }
 
 // AddOrganization sets the organization name
 func (v *VCard) AddOrganization(name string) *VCard {
-	v.organization.Name = name
+	v.organization.Name = v.options.text(name)
 	return v
 }
 
 // AddDepartment sets the department
 func (v *VCard) AddDepartment(department string) *VCard {
-	v.organization.Department = department
+	v.organization.Department = v.options.text(department)
+	return v
+}
+
+// AddOrgUnit appends an organizational unit below the department
+func (v *VCard) AddOrgUnit(unit string) *VCard {
+	v.organization.Units = append(v.organization.Units, v.options.text(unit))
 	return v
 }
 
 // AddTitle sets the job title
 func (v *VCard) AddTitle(title string) *VCard {
-	v.organization.Title = title
+	v.organization.Title = v.options.text(title)
 	return v
 }
 
 // AddRole sets the role/position
 func (v *VCard) AddRole(role string) *VCard {
-	v.organization.Role = role
+	v.organization.Role = v.options.text(role)
 	return v
 }
 
 // SetOrganization sets the complete organization structure
 func (v *VCard) SetOrganization(org Organization) *VCard {
-	v.organization = org
+	v.organization = org.normalized(v.options)
 	return v
 }
 
@@ -227,69 +272,151 @@ func (v *VCard) AddURLs(urls []URL) *VCard {
 	return v
 }
 
+// SetGeo sets the geographic position
+func (v *VCard) SetGeo(latitude, longitude float64) *VCard {
+	v.geo = &Geo{Latitude: latitude, Longitude: longitude}
+	return v
+}
+
 // AddPhoto sets the photo (URL or base64 data)
 func (v *VCard) AddPhoto(photo string) *VCard {
 	v.photo = photo
 	return v
 }
 
-// AddPhotoFromFile loads a photo from file and encodes as base64
+// AddLogo sets the organization logo (URL, data URI or base64 data)
+func (v *VCard) AddLogo(logo string) *VCard {
+	v.logo = logo
+	return v
+}
+
+// AddMember adds a group member, referenced by URI such as the member's
+// "urn:uuid:" UID or a "mailto:" address. Members require KIND group.
+func (v *VCard) AddMember(uri string) *VCard {
+	v.members = append(v.members, uri)
+	return v
+}
+
+// AddPhotoFromFile loads a photo from file and encodes as base64. The media
+// type is taken from the file extension, defaulting to JPEG.
 func (v *VCard) AddPhotoFromFile(filename string) error {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return err
 	}
 
-	// Encode as base64 data URI
-	encoded := base64.StdEncoding.EncodeToString(data)
-	v.photo = "data:image/jpeg;base64," + encoded
+	mime := defaultPhotoMIME
+	if mediaType, ok := MediaTypeByExtension(filepath.Ext(filename)); ok {
+		mime = mediaType.MIME
+	}
+
+	v.photo = EncodeDataURI(mime, data)
 	return nil
 }
 
 // AddNote sets a note
 func (v *VCard) AddNote(note string) *VCard {
-	v.note = note
+	v.note = v.options.text(note)
 	return v
 }
 
-// AddBirthday sets the birthday
+// Floating is the location of floating date-times: a time of day without a
+// timezone, such as BDAY:19900515T103000. Parsed values without a timezone
+// are in this location, and values in it are written without one.
+var Floating = time.FixedZone("floating", 0)
+
+// IsFloating reports whether the time is a floating date-time
+func IsFloating(t time.Time) bool {
+	return t.Location() == Floating
+}
+
+// AddBirthday sets the birthday. A non-midnight time of day is preserved and
+// written as a date-time with its timezone, or without one in the Floating
+// location.
 func (v *VCard) AddBirthday(birthday time.Time) *VCard {
 	v.birthday = &birthday
+	v.bdayText = ""
 	return v
 }
 
-// AddBirthdayFromString sets the birthday from a date string (YYYY-MM-DD)
+// AddBirthdayFromString sets the birthday from a date string (YYYY-MM-DD,
+// --MM-DD when the year is unknown, or an ISO 8601 date-time)
 func (v *VCard) AddBirthdayFromString(dateStr string) error {
-	birthday, err := time.Parse("2006-01-02", dateStr)
+	birthday, err := parseDate(dateStr)
 	if err != nil {
 		return fmt.Errorf("invalid date format: %w", err)
 	}
 	v.birthday = &birthday
+	v.bdayText = ""
 	return nil
 }
 
+// SetBirthdayText sets a free-text birthday such as "circa 1800", written as
+// BDAY;VALUE=text (vCard 4.0 only). It replaces any date set before.
+func (v *VCard) SetBirthdayText(text string) *VCard {
+	v.bdayText = v.options.text(text)
+	v.birthday = nil
+	return v
+}
+
+// SetBirthdayValue sets the birthday from a date string, keeping values that
+// are not valid dates (e.g. "unknown") as free text
+func (v *VCard) SetBirthdayValue(value string) *VCard {
+	if err := v.AddBirthdayFromString(value); err != nil {
+		v.SetBirthdayText(value)
+	}
+	return v
+}
+
 // AddAnniversary sets the anniversary (vCard 4.0 only)
 func (v *VCard) AddAnniversary(anniversary time.Time) *VCard {
 	v.anniversary = &anniversary
+	v.annivText = ""
 	return v
 }
 
-// AddAnniversaryFromString sets the anniversary from a date string (YYYY-MM-DD)
+// AddAnniversaryFromString sets the anniversary from a date string (YYYY-MM-DD,
+// --MM-DD when the year is unknown, or an ISO 8601 date-time)
 func (v *VCard) AddAnniversaryFromString(dateStr string) error {
-	anniversary, err := time.Parse("2006-01-02", dateStr)
+	anniversary, err := parseDate(dateStr)
 	if err != nil {
 		return fmt.Errorf("invalid date format: %w", err)
 	}
 	v.anniversary = &anniversary
+	v.annivText = ""
 	return nil
 }
 
-// AddCustomProperty adds a custom X- property
+// SetAnniversaryText sets a free-text anniversary, written as
+// ANNIVERSARY;VALUE=text (vCard 4.0 only). It replaces any date set before.
+func (v *VCard) SetAnniversaryText(text string) *VCard {
+	v.annivText = v.options.text(text)
+	v.anniversary = nil
+	return v
+}
+
+// SetAnniversaryValue sets the anniversary from a date string, keeping values
+// that are not valid dates as free text
+func (v *VCard) SetAnniversaryValue(value string) *VCard {
+	if err := v.AddAnniversaryFromString(value); err != nil {
+		v.SetAnniversaryText(value)
+	}
+	return v
+}
+
+// SetUID sets the unique identifier (UID property) of the contact
+func (v *VCard) SetUID(uid string) *VCard {
+	v.uid = uid
+	return v
+}
+
+// AddCustomProperty adds a custom X- property or a registered property without
+// a dedicated setter (e.g. NICKNAME, EXPERTISE)
 func (v *VCard) AddCustomProperty(name, value string) *VCard {
 	if v.customProps == nil {
 		v.customProps = make(map[string]string)
 	}
-	v.customProps[name] = value
+	v.customProps[name] = v.options.text(value)
 	return v
 }
 
@@ -300,12 +427,70 @@ func (v *VCard) AddCustomProperties(props map[string]string) *VCard {
 	}
 
 	for k, val := range props {
-		v.customProps[k] = val
+		v.customProps[k] = v.options.text(val)
 	}
 
 	return v
 }
 
+// RemovePhoto clears the photo and its framing
+func (v *VCard) RemovePhoto() *VCard {
+	v.photo = ""
+	v.photoCrop = nil
+	return v
+}
+
+// RemoveLogo clears the organization logo
+func (v *VCard) RemoveLogo() *VCard {
+	v.logo = ""
+	return v
+}
+
+// RemoveNote clears the note and its alternates
+func (v *VCard) RemoveNote() *VCard {
+	v.note = ""
+	delete(v.alternates, "NOTE")
+	return v
+}
+
+// RemoveBirthday clears the birthday, whether a date or free text
+func (v *VCard) RemoveBirthday() *VCard {
+	v.birthday = nil
+	v.bdayText = ""
+	return v
+}
+
+// RemoveAnniversary clears the anniversary, whether a date or free text
+func (v *VCard) RemoveAnniversary() *VCard {
+	v.anniversary = nil
+	v.annivText = ""
+	return v
+}
+
+// RemoveGeo clears the geographic position
+func (v *VCard) RemoveGeo() *VCard {
+	v.geo = nil
+	return v
+}
+
+// RemoveUID clears the unique identifier
+func (v *VCard) RemoveUID() *VCard {
+	v.uid = ""
+	return v
+}
+
+// RemoveCustomProperty removes a custom property and its alternates.
+// Property names are compared case-insensitively, as in vCard.
+func (v *VCard) RemoveCustomProperty(name string) *VCard {
+	for key := range v.customProps {
+		if strings.EqualFold(key, name) {
+			delete(v.customProps, key)
+		}
+	}
+	delete(v.alternates, strings.ToUpper(name))
+	return v
+}
+
 // AddContact adds contact information from a Contact structure
 func (v *VCard) AddContact(contact Contact) *VCard {
 	// Set name
@@ -327,7 +512,7 @@ func (v *VCard) AddContact(contact Contact) *VCard {
 	}
 
 	// Set organization
-	if contact.Organization.Name != "" {
+	if contact.Organization.Name != "" || len(contact.Organization.OrgUnits()) > 0 {
 		v.SetOrganization(contact.Organization)
 	}
 
@@ -346,18 +531,19 @@ func (v *VCard) AddContact(contact Contact) *VCard {
 		v.AddNote(contact.Note)
 	}
 
-	// Set birthday
+	// Set birthday, preserving values that are not dates as free text
 	if contact.Birthday != nil {
-		if err := v.AddBirthdayFromString(*contact.Birthday); err == nil {
-			// Birthday set successfully
-		}
+		v.SetBirthdayValue(*contact.Birthday)
 	}
 
 	// Set anniversary
 	if contact.Anniversary != nil {
-		if err := v.AddAnniversaryFromString(*contact.Anniversary); err == nil {
-			// Anniversary set successfully
-		}
+		v.SetAnniversaryValue(*contact.Anniversary)
+	}
+
+	// Set UID
+	if contact.UID != "" {
+		v.SetUID(contact.UID)
 	}
 
 	// Add custom properties
@@ -384,10 +570,11 @@ func (v *VCard) GetPhone() string {
 	return ""
 }
 
-// GetAddress returns the first address (if any)
+// GetAddress returns a copy of the first address (if any)
 func (v *VCard) GetAddress() *Address {
 	if len(v.addresses) > 0 {
-		return &v.addresses[0]
+		address := v.addresses[0]
+		return &address
 	}
 	return nil
 }
@@ -85,6 +85,26 @@ func TestAddPhoneWithPreference(t *testing.T) {
 	}
 }
 
+func TestDefaultTypes(t *testing.T) {
+	if emails := New().AddEmail("a@example.com").GetEmails(); emails[0].Type != EmailInternet {
+		t.Errorf("Expected INTERNET by default, got %q", emails[0].Type)
+	}
+	if phones := New().AddPhone("+1234567890").GetPhones(); phones[0].Type != PhoneVoice {
+		t.Errorf("Expected VOICE by default, got %q", phones[0].Type)
+	}
+
+	card := New().SetOptions(CardOptions{EmailType: EmailWork, PhoneType: PhoneWork}).AddName("John", "Doe").AddEmail("a@example.com").AddEmail("b@example.com", EmailHome).AddPhone("+1234567890")
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	for _, expected := range []string{"EMAIL;TYPE=WORK:a@example.com", "EMAIL;TYPE=HOME:b@example.com", "TEL;TYPE=WORK:+1234567890"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
 func TestAddAddressExtended(t *testing.T) {
 	card := New()
 	card.AddName("Test", "User")
@@ -246,9 +266,107 @@ func TestPhoto(t *testing.T) {
 		t.Fatalf("Failed to generate vCard: %v", err)
 	}
 
-	if !strings.Contains(content, "PHOTO;ENCODING=b:data:image/jpeg;base64,/9j/4AAQSkZJRgABAQEAYABgAAD") {
+	if !strings.Contains(content, "PHOTO;ENCODING=b;TYPE=JPEG:/9j/4AAQSkZJRgABAQEAYABgAAD") {
 		t.Error("Photo base64 not properly formatted")
 	}
+
+	// vCard 4.0 keeps data URIs as URI values
+	card.SetVersion(Version40)
+	content, err = card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+
+	if !strings.Contains(content, "PHOTO;VALUE=uri:data:image/jpeg;base64,/9j/4AAQSkZJRgABAQEAYABgAAD") {
+		t.Error("Photo data URI not properly formatted for vCard 4.0")
+	}
+}
+
+func TestLogo(t *testing.T) {
+	card := New().AddName("Test", "User").AddLogo("data:image/png;base64,iVBORw0KGgo")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(content, "LOGO;ENCODING=b;TYPE=PNG:iVBORw0KGgo") {
+		t.Error("Logo base64 not properly formatted")
+	}
+
+	card.SetVersion(Version40)
+	if card.GetValueType("LOGO") != ValueURI {
+		t.Errorf("Expected uri value type, got %q", card.GetValueType("LOGO"))
+	}
+
+	clone := card.Clone()
+	card.Reset()
+	if card.GetLogo() != "" || clone.GetLogo() != "data:image/png;base64,iVBORw0KGgo" {
+		t.Error("Logo not reset or cloned")
+	}
+}
+
+func TestMembers(t *testing.T) {
+	group := NewWithVersion(Version40).SetKind(KindGroup).SetFormattedName("Team").
+		AddMember("urn:uuid:1").
+		AddMember("mailto:jane@example.com")
+
+	content, err := group.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(content, "MEMBER:urn:uuid:1\n") || !strings.Contains(content, "MEMBER:mailto:jane@example.com\n") {
+		t.Errorf("Members not properly formatted:\n%s", content)
+	}
+
+	group.SetVersion(Version30)
+	content, _ = group.String()
+	if !strings.Contains(content, "X-ADDRESSBOOKSERVER-MEMBER:urn:uuid:1\n") {
+		t.Errorf("Expected Apple member extension in vCard 3.0:\n%s", content)
+	}
+
+	members := group.GetMembers()
+	members[0] = "changed"
+	if group.GetMembers()[0] != "urn:uuid:1" {
+		t.Error("GetMembers should return a copy")
+	}
+
+	if err := New().AddName("John", "Doe").AddMember("urn:uuid:1").Validate(); err == nil {
+		t.Error("Expected error for members on an individual card")
+	}
+}
+
+func TestRemoveOptionalProperties(t *testing.T) {
+	card := New().AddName("John", "Doe").
+		AddPhoto("https://example.com/photo.jpg").
+		AddLogo("https://example.com/logo.png").
+		AddNote("Met at GopherCon").
+		SetBirthdayText("circa 1800").
+		AddAnniversary(time.Date(2010, 6, 1, 0, 0, 0, 0, time.UTC)).
+		SetGeo(42.69, 23.32).
+		SetUID("urn:uuid:1").
+		AddCustomProperty("X-Skype", "john.doe").
+		AddCustomProperty("NICKNAME", "Johnny").
+		AddAlternate("NOTE", "bg", "Срещнахме се на GopherCon").
+		AddAlternate("X-Skype", "bg", "джон")
+
+	card.RemovePhoto().RemoveLogo().RemoveNote().RemoveBirthday().RemoveAnniversary().
+		RemoveGeo().RemoveUID().RemoveCustomProperty("X-SKYPE")
+
+	if card.GetPhoto() != "" || card.GetLogo() != "" || card.GetNote() != "" {
+		t.Error("Expected photo, logo and note to be removed")
+	}
+	if card.GetBirthdayText() != "" || card.GetAnniversary() != nil || card.GetGeo() != nil || card.GetUID() != "" {
+		t.Error("Expected dates, geo and UID to be removed")
+	}
+	if card.GetCustomProperty("X-Skype") != "" || card.GetCustomProperty("NICKNAME") != "Johnny" {
+		t.Errorf("Expected only X-Skype to be removed, got %v", card.GetCustomProperties())
+	}
+	if len(card.GetAlternates("NOTE")) > 0 || len(card.GetAlternates("X-Skype")) > 0 {
+		t.Error("Expected the alternates of removed properties to be removed")
+	}
+	if card.GetName().First != "John" {
+		t.Error("Expected other properties to be kept")
+	}
 }
 
 func TestBirthdayFromString(t *testing.T) {
@@ -441,3 +559,66 @@ func TestGetters(t *testing.T) {
 		t.Error("GetURL() should return empty string for empty card")
 	}
 }
+
+func TestOrgUnits(t *testing.T) {
+	card := New()
+	card.AddName("Team", "Mailbox")
+	card.AddDepartment("Engineering").AddOrgUnit("Platform").AddOrgUnit("")
+
+	units := card.GetOrgUnits()
+	if len(units) != 2 || units[0] != "Engineering" || units[1] != "Platform" {
+		t.Errorf("Unexpected units: %v", units)
+	}
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(content, "ORG:;Engineering;Platform\n") {
+		t.Errorf("Expected department-only ORG, got %s", content)
+	}
+
+	card.AddOrganization("Acme; Inc")
+	content, _ = card.String()
+	if !strings.Contains(content, "ORG:Acme\\; Inc;Engineering;Platform\n") {
+		t.Errorf("Expected full ORG, got %s", content)
+	}
+
+	clone := card.Clone()
+	clone.AddOrgUnit("Other")
+	if len(card.GetOrgUnits()) != 2 {
+		t.Error("Clone() should not share organization units")
+	}
+}
+
+func TestDateValueFallback(t *testing.T) {
+	card := NewWithVersion(Version40).AddName("John", "Doe")
+
+	card.SetBirthdayValue("1990-05-15")
+	if card.GetBirthday() == nil || card.GetBirthdayText() != "" {
+		t.Error("Expected parseable birthday to be stored as a date")
+	}
+
+	card.SetBirthdayValue("unknown").SetAnniversaryValue("summer 1985")
+	if card.GetBirthday() != nil || card.GetBirthdayText() != "unknown" {
+		t.Errorf("Expected birthday text to be preserved, got %q", card.GetBirthdayText())
+	}
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+	if !strings.Contains(content, "BDAY;VALUE=text:unknown\n") || !strings.Contains(content, "ANNIVERSARY;VALUE=text:summer 1985\n") {
+		t.Errorf("Expected free-text dates, got %s", content)
+	}
+
+	// Contacts keep unparseable dates instead of dropping them
+	approximate := "circa 1800"
+	card = NewWithVersion(Version40).AddContact(Contact{
+		Name:     Name{First: "Jane", Last: "Doe"},
+		Birthday: &approximate,
+	})
+	if card.GetBirthdayText() != approximate {
+		t.Errorf("Expected contact birthday text %q, got %q", approximate, card.GetBirthdayText())
+	}
+}
@@ -0,0 +1,69 @@
+package vcard
+
+import (
+	"mime"
+	"strings"
+)
+
+// Media types and file extension of vCards
+const (
+	// MIMEType is the registered media type of vCard data (RFC 6350)
+	MIMEType = "text/vcard"
+
+	// MIMETypeLegacy is the unregistered media type sent by older clients
+	// and servers for vCard 2.1 and 3.0 data
+	MIMETypeLegacy = "text/x-vcard"
+
+	// MIMETypeDirectory is the vCard 3.0 media type of RFC 2425, used with
+	// profile=vCard
+	MIMETypeDirectory = "text/directory"
+
+	// FileExtension is the file name extension of vCard files
+	FileExtension = ".vcf"
+)
+
+// IsMIMEType reports whether a Content-Type or media type, such as
+// "text/x-vcard; charset=utf-8", denotes vCard data
+func IsMIMEType(contentType string) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case MIMEType, MIMETypeLegacy:
+		return true
+	case MIMETypeDirectory:
+		return strings.EqualFold(params["profile"], "vcard")
+	}
+	return false
+}
+
+// DetectVersionFromMIME returns the vCard version a Content-Type declares:
+// the version parameter of text/vcard (RFC 6350 section 10.1), or 3.0 for
+// text/directory;profile=vCard and for text/x-vcard without a supported
+// version. It reports false when the media type is not a vCard one or does
+// not state a version this library reads.
+func DetectVersionFromMIME(contentType string) (Version, bool) {
+	if !IsMIMEType(contentType) {
+		return "", false
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+
+	if value, ok := params["version"]; ok {
+		if version, err := ParseVersion(value); err == nil {
+			return version, true
+		}
+	}
+	if mediaType == MIMEType {
+		return "", false
+	}
+	return Version30, true
+}
+
+// MIMEParams returns the media type parameters of cards in the version, to
+// be passed to mime.FormatMediaType:
+//
+//	w.Header().Set("Content-Type", mime.FormatMediaType(vcard.MIMEType, vcard.MIMEParams(card.GetVersion())))
+func MIMEParams(version Version) map[string]string {
+	return map[string]string{"charset": "utf-8", "version": version.String()}
+}
@@ -0,0 +1,64 @@
+package vcard
+
+import (
+	"mime"
+	"testing"
+)
+
+func TestIsMIMEType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"text/vcard", true},
+		{"text/vcard; charset=utf-8", true},
+		{"TEXT/X-VCARD", true},
+		{"text/directory; profile=vCard", true},
+		{"text/directory", false},
+		{"text/calendar", false},
+		{"application/json", false},
+		{"", false},
+		{"text/vcard; =", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsMIMEType(tt.contentType); got != tt.want {
+			t.Errorf("IsMIMEType(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestDetectVersionFromMIME(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Version
+		ok          bool
+	}{
+		{"text/vcard; version=4.0", Version40, true},
+		{"text/vcard;charset=utf-8;version=3.0", Version30, true},
+		{`text/vcard; version="4.0"`, Version40, true},
+		{"text/vcard", "", false},
+		{"text/vcard; version=2.1", "", false},
+		{"text/x-vcard", Version30, true},
+		{"text/x-vcard; version=4.0", Version40, true},
+		{"text/directory; profile=vcard", Version30, true},
+		{"text/plain; version=4.0", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := DetectVersionFromMIME(tt.contentType)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DetectVersionFromMIME(%q) = %q, %v, want %q, %v", tt.contentType, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMIMEParams(t *testing.T) {
+	contentType := mime.FormatMediaType(MIMEType, MIMEParams(Version40))
+	if contentType != "text/vcard; charset=utf-8; version=4.0" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	if version, ok := DetectVersionFromMIME(contentType); !ok || version != Version40 {
+		t.Errorf("DetectVersionFromMIME(%q) = %q, %v", contentType, version, ok)
+	}
+}
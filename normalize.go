@@ -0,0 +1,51 @@
+package vcard
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeNFC returns s in Unicode normalization form C
+func normalizeNFC(s string) string {
+	if norm.NFC.IsNormalString(s) {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// normalized returns the name with its text fields normalized and the
+// NameEmoji policy applied
+func (n Name) normalized(options CardOptions) Name {
+	n.First = options.name(n.First)
+	n.Last = options.name(n.Last)
+	n.Middle = options.name(n.Middle)
+	n.Prefix = options.name(n.Prefix)
+	n.Suffix = options.name(n.Suffix)
+	return n
+}
+
+// normalized returns the address with its text fields normalized
+func (a Address) normalized(options CardOptions) Address {
+	a.Street = options.text(a.Street)
+	a.Extended = options.text(a.Extended)
+	a.City = options.text(a.City)
+	a.State = options.text(a.State)
+	a.PostalCode = options.text(a.PostalCode)
+	a.Country = options.text(a.Country)
+	return a
+}
+
+// normalized returns the organization with its text fields normalized
+func (o Organization) normalized(options CardOptions) Organization {
+	o.Name = options.text(o.Name)
+	o.Department = options.text(o.Department)
+	o.Title = options.text(o.Title)
+	o.Role = options.text(o.Role)
+	if o.Units != nil {
+		units := make([]string, len(o.Units))
+		for i, unit := range o.Units {
+			units[i] = options.text(unit)
+		}
+		o.Units = units
+	}
+	return o
+}
@@ -0,0 +1,49 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeUnicode(t *testing.T) {
+	// "José" with a combining acute accent versus the precomposed "é"
+	decomposed, composed := "José", "José"
+
+	card := New().
+		AddName(decomposed, "Müller").
+		AddOrganization("Café").
+		AddAddress("Rue de l'Église", "", "", "", "").
+		SetName(Name{First: decomposed, Last: "Müller"})
+
+	if card.GetName().First != composed || card.GetName().Last != "Müller" {
+		t.Errorf("Expected NFC name, got %+q", card.GetName())
+	}
+	if card.GetOrganization().Name != "Café" || card.GetAddress().Street != "Rue de l'Église" {
+		t.Errorf("Expected NFC organization and address")
+	}
+	if Similarity(card, New().AddName(composed, "Müller")) < 0.99 {
+		t.Error("Expected cards differing only in normalization to match")
+	}
+
+	// Struct literals bypass the setters and are normalized when serialized
+	card.name.Middle = decomposed
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if strings.Contains(content, "́") {
+		t.Errorf("Expected NFC output:\n%s", content)
+	}
+}
+
+func TestNormalizeUnicodeOptOut(t *testing.T) {
+
+	card := New().SetOptions(CardOptions{KeepUnicode: true}).AddName("José", "Doe")
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if card.GetName().First != "José" || !strings.Contains(content, "José") {
+		t.Error("Expected values to be kept unchanged when normalization is disabled")
+	}
+}
@@ -0,0 +1,63 @@
+package vcard
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// writeNoteProperty writes the note folded every 75 octets. With a positive
+// chunk size, longer notes are split into several NOTE properties of at most
+// chunk characters for clients that drop or truncate long values.
+func (v *VCard) writeNoteProperty(builder *strings.Builder, emitEmpty map[string]bool, chunk int) {
+	if v.note == "" && !emitEmpty["NOTE"] {
+		return
+	}
+
+	params := v.altIDParameter("NOTE")
+	for _, part := range chunkText(v.note, chunk) {
+		writeFolded(builder, "NOTE", params, ":", escapeValue(part))
+		builder.WriteString("\n")
+		// ALTID marks language versions of the whole note, not its chunks
+		params = ""
+	}
+	v.writeAlternates(builder, "NOTE")
+}
+
+// chunkText splits s into pieces of at most n characters. A piece ends
+// after the last whitespace in its second half, so words stay whole where
+// possible and the whitespace is kept for joinNote. Text of at most n
+// characters, or any text when n is not positive, is returned as one piece.
+func chunkText(s string, n int) []string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return []string{s}
+	}
+
+	var chunks []string
+	for utf8.RuneCountInString(s) > n {
+		end := len(truncateRunes(s, n))
+		cut := end
+		if i := strings.LastIndexFunc(s[:end], unicode.IsSpace); i >= 0 && utf8.RuneCountInString(s[:i]) >= n/2 {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			cut = i + size
+		}
+		chunks = append(chunks, s[:cut])
+		s = s[cut:]
+	}
+	if s != "" {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// joinNote appends a further NOTE value to the note. Values continuing a
+// chunk that ends in whitespace are appended as is; others start a new line.
+func joinNote(note, value string) string {
+	if note == "" {
+		return value
+	}
+	if last, _ := utf8.DecodeLastRuneInString(note); unicode.IsSpace(last) {
+		return note + value
+	}
+	return note + "\n" + value
+}
@@ -0,0 +1,153 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// longNote returns a multi-KB note full of characters that need escaping
+func longNote() string {
+	var b strings.Builder
+	for i := 0; b.Len() < 8000; i++ {
+		b.WriteString("Met at the café; discussed pricing, shipping \\ returns.\nFollow-up: ")
+		b.WriteString(strings.Repeat("ü", i%7))
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+func TestLongNoteFolding(t *testing.T) {
+	note := longNote()
+	content, err := New().AddName("Jane", "Doe").AddNote(note).String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) > 75 {
+			t.Fatalf("Line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Fatalf("Fold split a character: %q", line)
+		}
+	}
+
+	card, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if card.GetNote() != note {
+		t.Error("Expected the note to survive folding and escaping")
+	}
+}
+
+func TestLongNameFolding(t *testing.T) {
+	first, last := strings.TrimSpace(strings.Repeat("Жана ", 20)), strings.Repeat("Доу-", 20)
+	content, err := New().AddName(first, last).AddAlternate("FN", "en", "Jane").SetVersion(Version40).String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) > 75 {
+			t.Fatalf("Line of %d octets: %q", len(line), line)
+		}
+	}
+
+	card, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if card.name.First != first {
+		t.Errorf("Expected the first name to survive folding, got %q", card.name.First)
+	}
+	if card.name.Last != last {
+		t.Errorf("Expected the last name to survive folding, got %q", card.name.Last)
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	note := longNote()
+	chunks := chunkText(note, 500)
+	if len(chunks) < 16 {
+		t.Fatalf("Expected at least 16 chunks, got %d", len(chunks))
+	}
+	if strings.Join(chunks, "") != note {
+		t.Error("Expected the chunks to join to the note")
+	}
+	for _, chunk := range chunks[:len(chunks)-1] {
+		if utf8.RuneCountInString(chunk) > 500 || !strings.HasSuffix(chunk, " ") && !strings.HasSuffix(chunk, "\n") {
+			t.Errorf("Expected a chunk of at most 500 characters ending in whitespace, got %q", chunk)
+		}
+	}
+
+	if chunks := chunkText(strings.Repeat("x", 25), 10); len(chunks) != 3 || chunks[0] != strings.Repeat("x", 10) {
+		t.Errorf("Expected hard splits without whitespace, got %q", chunks)
+	}
+	if chunks := chunkText("short", 10); len(chunks) != 1 || chunks[0] != "short" {
+		t.Errorf("Unexpected chunks %q", chunks)
+	}
+	if chunks := chunkText("anything", 0); len(chunks) != 1 {
+		t.Errorf("Expected no chunking without a size, got %q", chunks)
+	}
+}
+
+func TestProfileChunkNotes(t *testing.T) {
+	note := longNote()
+	card := New().AddName("Jane", "Doe").AddNote(note)
+	profile := Profile{Name: "chunked", MaxLengths: map[string]int{"NOTE": 1000}, ChunkNotes: true}
+
+	if warnings := profile.Lint(card); len(warnings) != 0 {
+		t.Errorf("Expected no warnings for chunked notes, got %v", warnings)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Profile(profile).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	counts, err := propertyCounts(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["NOTE"] < 8 {
+		t.Errorf("Expected at least 8 NOTE properties, got %d", counts["NOTE"])
+	}
+	for _, line := range unfoldLines(buf.String()) {
+		if property, _ := ParseProperty(line); property.Name == "NOTE" && utf8.RuneCountInString(unescapeValue(property.Value)) > 1000 {
+			t.Errorf("Expected chunks of at most 1000 characters, got %d", utf8.RuneCountInString(unescapeValue(property.Value)))
+		}
+	}
+
+	read, err := Parse(buf.String())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if read.GetNote() != note {
+		t.Error("Expected the chunks to be joined back into the note")
+	}
+}
+
+func TestTruncatedNoteEscaping(t *testing.T) {
+	profile := Profile{Name: "short", MaxLengths: map[string]int{"NOTE": 12}, TruncateLong: true}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Profile(profile).Encode(New().AddName("Jane", "Doe").AddNote(`a,b;c\d,e;f\g,h`)); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `NOTE:a\,b\;c\\d\,e\;f\\`+"\n") {
+		t.Errorf("Expected the note truncated before escaping, got:\n%s", buf.String())
+	}
+}
+
+func TestJoinNote(t *testing.T) {
+	card, err := Parse("BEGIN:VCARD\nVERSION:3.0\nFN:Jane Doe\nNOTE:First note\nNOTE:Second note\nEND:VCARD\n")
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if card.GetNote() != "First note\nSecond note" {
+		t.Errorf("Expected separate notes on separate lines, got %q", card.GetNote())
+	}
+}
@@ -0,0 +1,122 @@
+package vcard
+
+import (
+	"maps"
+	"slices"
+)
+
+// CardOptions configure how a card stores, checks and writes its values.
+// The zero value gives the defaults: text in Unicode normalization form C,
+// names kept as set, INTERNET emails, VOICE phones, no phone region, unsafe
+// characters stripped on output and the DefaultURLSchemes for
+// ValidateStrict. Options are set per card, so cards configured differently
+// can be used concurrently.
+type CardOptions struct {
+	// KeepUnicode stores and writes text values byte for byte. By default
+	// they are normalized to NFC as they are stored and serialized, so that
+	// the same name typed with precomposed or combining characters compares,
+	// deduplicates and exports identically.
+	KeepUnicode bool
+
+	// NameEmoji is the policy applied to name fields (N and FN) as they are
+	// set, including names read by a Decoder configured with the options
+	NameEmoji EmojiPolicy
+
+	// EmojiText overrides the text the EmojiTransliterate policy writes for
+	// emoji and symbols, e.g. {'❤': "love"}; an empty text removes the
+	// symbol
+	EmojiText map[rune]string
+
+	// EmailType is the type given to email addresses added by AddEmail
+	// without a type, e.g. EmailWork for business address books. Empty
+	// means EmailInternet.
+	EmailType EmailType
+
+	// PhoneType is the type given to phone numbers added by AddPhone
+	// without a type. Empty means PhoneVoice.
+	PhoneType PhoneType
+
+	// PhoneRegion is the ISO 3166-1 alpha-2 region (e.g. "GB") assumed for
+	// numbers written without a country code when the card formats them
+	// (see Phone.FormatRegion)
+	PhoneRegion string
+
+	// Sanitize selects how control characters, soft hyphens and
+	// bidirectional formatting characters in values are handled. Tabs and
+	// line breaks are not affected; line breaks are escaped as usual.
+	Sanitize SanitizeMode
+
+	// URLSchemes lists the URL schemes accepted by ValidateStrict. Nil means
+	// DefaultURLSchemes.
+	URLSchemes []string
+}
+
+// DefaultURLSchemes returns the URL schemes ValidateStrict accepts unless
+// CardOptions.URLSchemes is set
+func DefaultURLSchemes() []string {
+	return []string{"https", "http", "tel", "mailto", "sip", "xmpp"}
+}
+
+// SetOptions sets the options of the card. Options applied as values are set
+// (KeepUnicode for storing, NameEmoji, EmojiText, EmailType and PhoneType) only affect
+// values set afterwards, so set them first.
+func (v *VCard) SetOptions(options CardOptions) *VCard {
+	options.URLSchemes = slices.Clone(options.URLSchemes)
+	options.EmojiText = maps.Clone(options.EmojiText)
+	v.options = options
+	return v
+}
+
+// GetOptions returns the options of the card
+func (v *VCard) GetOptions() CardOptions {
+	options := v.options
+	options.URLSchemes = slices.Clone(options.URLSchemes)
+	options.EmojiText = maps.Clone(options.EmojiText)
+	return options
+}
+
+// text normalizes a text value being stored or serialized unless
+// KeepUnicode is set
+func (o CardOptions) text(s string) string {
+	if o.KeepUnicode {
+		return s
+	}
+	return normalizeNFC(s)
+}
+
+// name normalizes a name field and applies the NameEmoji policy
+func (o CardOptions) name(s string) string {
+	s = o.text(s)
+	switch o.NameEmoji {
+	case EmojiStrip:
+		return StripEmoji(s)
+	case EmojiTransliterate:
+		return transliterateEmoji(s, o.EmojiText)
+	default:
+		return s
+	}
+}
+
+// emailType returns the type for emails added without one
+func (o CardOptions) emailType() EmailType {
+	if o.EmailType == "" {
+		return EmailInternet
+	}
+	return o.EmailType
+}
+
+// phoneType returns the type for phone numbers added without one
+func (o CardOptions) phoneType() PhoneType {
+	if o.PhoneType == "" {
+		return PhoneVoice
+	}
+	return o.PhoneType
+}
+
+// urlSchemes returns the URL schemes accepted by ValidateStrict
+func (o CardOptions) urlSchemes() []string {
+	if o.URLSchemes == nil {
+		return DefaultURLSchemes()
+	}
+	return o.URLSchemes
+}
@@ -0,0 +1,26 @@
+package vcard
+
+import "testing"
+
+func TestCardOptions(t *testing.T) {
+	var defaults CardOptions
+	if defaults.emailType() != EmailInternet || defaults.phoneType() != PhoneVoice {
+		t.Errorf("Expected INTERNET and VOICE types by default, got %q and %q", defaults.emailType(), defaults.phoneType())
+	}
+	if schemes := defaults.urlSchemes(); len(schemes) != len(DefaultURLSchemes()) || schemes[0] != "https" {
+		t.Errorf("Expected the default URL schemes, got %v", schemes)
+	}
+
+	schemes := []string{"https"}
+	card := New().SetOptions(CardOptions{Sanitize: SanitizeReject, URLSchemes: schemes})
+	schemes[0] = "ftp"
+	if options := card.GetOptions(); options.Sanitize != SanitizeReject || options.URLSchemes[0] != "https" {
+		t.Errorf("Expected the options to be copied, got %+v", options)
+	}
+	if options := card.Clone().GetOptions(); options.Sanitize != SanitizeReject {
+		t.Errorf("Expected clones to keep the options, got %+v", options)
+	}
+	if options := New().GetOptions(); options.Sanitize != SanitizeStrip || options.URLSchemes != nil {
+		t.Errorf("Expected new cards to have the default options, got %+v", options)
+	}
+}
@@ -0,0 +1,183 @@
+package vcard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// managerProperties are read, in order, for a reference to a person's
+// manager: the X-MANAGER family written by CRMs, Outlook and Evolution.
+// RELATED is not among them, as cards keep no TYPE parameter telling a
+// manager from a spouse or friend.
+var managerProperties = []string{"X-MANAGER", "X-MS-MANAGER", "X-EVOLUTION-MANAGER"}
+
+// OrgNode is a person in an organization chart built by OrgChart
+type OrgNode struct {
+	// UID is the UID of the person's card
+	UID string `json:"uid,omitempty"`
+
+	// Name is the formatted name of the person
+	Name string `json:"name"`
+
+	// Title is the person's job title
+	Title string `json:"title,omitempty"`
+
+	// Reports are the people managed by the person, in address book order
+	Reports []*OrgNode `json:"reports,omitempty"`
+
+	// Card is the person's card
+	Card *VCard `json:"-"`
+}
+
+// OrgChart is the reporting hierarchy of an address book
+type OrgChart struct {
+	// Roots are the people without a manager in the address book, in
+	// address book order
+	Roots []*OrgNode `json:"roots"`
+}
+
+// OrgChart builds the reporting hierarchy of the address book. A card's
+// manager is read from X-MANAGER, X-MS-MANAGER or X-EVOLUTION-MANAGER, and
+// may be referenced by UID (with or without the "urn:uuid:" prefix), by
+// email address (with or without "mailto:") or by formatted name. Cards
+// whose manager is not found become roots, and so does the first card of
+// every reporting cycle. Group cards are left out.
+func (b *AddressBook) OrgChart() *OrgChart {
+	var cards []*VCard
+	for _, card := range b.cards {
+		if card.kind != KindGroup {
+			cards = append(cards, card)
+		}
+	}
+
+	nodes := make([]*OrgNode, len(cards))
+	byUID := make(map[string]int)
+	byEmail := make(map[string]int)
+	byName := make(map[string]int)
+	for i, card := range cards {
+		nodes[i] = &OrgNode{UID: card.uid, Name: card.formattedName(), Title: card.organization.Title, Card: card}
+		addKey(byUID, uidKey(card.uid), i)
+		for _, email := range card.emails {
+			addKey(byEmail, strings.ToLower(strings.TrimSpace(email.Address)), i)
+		}
+		addKey(byName, strings.ToLower(strings.TrimSpace(nodes[i].Name)), i)
+	}
+
+	managers := make([]int, len(cards))
+	for i, card := range cards {
+		managers[i] = -1
+		for _, property := range managerProperties {
+			if j := resolveManager(card.customProps[property], byUID, byEmail, byName); j >= 0 && j != i {
+				managers[i] = j
+				break
+			}
+		}
+	}
+
+	// Break reporting cycles at their first card in address book order
+	for i := range managers {
+		for j, steps := managers[i], 0; j >= 0 && steps < len(managers); j, steps = managers[j], steps+1 {
+			if j == i {
+				managers[i] = -1
+				break
+			}
+		}
+	}
+
+	chart := &OrgChart{Roots: []*OrgNode{}}
+	for i, manager := range managers {
+		if manager < 0 {
+			chart.Roots = append(chart.Roots, nodes[i])
+		} else {
+			nodes[manager].Reports = append(nodes[manager].Reports, nodes[i])
+		}
+	}
+	return chart
+}
+
+// ExportJSON writes the chart as indented JSON: the roots with their
+// reports nested
+func (c *OrgChart) ExportJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// ExportDOT writes the chart as a Graphviz digraph with an edge from every
+// manager to each report, e.g. for rendering with "dot -Tsvg"
+func (c *OrgChart) ExportDOT(w io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph orgchart {\n\tnode [shape=box];\n")
+
+	ids := make(map[*OrgNode]int)
+	var write func(node *OrgNode)
+	write = func(node *OrgNode) {
+		id := len(ids)
+		ids[node] = id
+
+		label := node.Name
+		if label == "" {
+			label = node.UID
+		}
+		if node.Title != "" {
+			label += "\n" + node.Title
+		}
+		fmt.Fprintf(&builder, "\tn%d [label=%s];\n", id, dotQuote(label))
+
+		for _, report := range node.Reports {
+			write(report)
+			fmt.Fprintf(&builder, "\tn%d -> n%d;\n", id, ids[report])
+		}
+	}
+	for _, root := range c.Roots {
+		write(root)
+	}
+
+	builder.WriteString("}\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// addKey records the card index under a lookup key, keeping the first card
+func addKey(index map[string]int, key string, i int) {
+	if _, ok := index[key]; key != "" && !ok {
+		index[key] = i
+	}
+}
+
+// uidKey returns the UID compared when resolving references
+func uidKey(uid string) string {
+	uid = strings.ToLower(strings.TrimSpace(uid))
+	return strings.TrimPrefix(uid, "urn:uuid:")
+}
+
+// resolveManager returns the index of the card a manager reference points
+// to, trying UIDs, then email addresses, then formatted names, or -1
+func resolveManager(reference string, byUID, byEmail, byName map[string]int) int {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return -1
+	}
+	if i, ok := byUID[uidKey(reference)]; ok {
+		return i
+	}
+
+	lower := strings.ToLower(reference)
+	if i, ok := byEmail[strings.TrimPrefix(lower, "mailto:")]; ok {
+		return i
+	}
+	if i, ok := byName[lower]; ok {
+		return i
+	}
+	return -1
+}
+
+// dotQuote returns s as a quoted DOT string
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
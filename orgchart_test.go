@@ -0,0 +1,120 @@
+package vcard
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOrgChart(t *testing.T) {
+	ceo := New().SetUID("ceo-1").SetFormattedName("Ada Chief").AddTitle("CEO").AddEmail("ada@example.com")
+	cto := New().SetUID("cto-1").SetFormattedName("Bob Tech").AddTitle("CTO").
+		AddCustomProperty("X-MANAGER", "urn:uuid:CEO-1")
+	dev := New().SetUID("dev-1").SetFormattedName("Cy Dev").
+		AddCustomProperty("X-MANAGER", "Bob Tech")
+	cfo := New().SetUID("cfo-1").SetFormattedName("Di Money").
+		AddCustomProperty("X-MS-MANAGER", "mailto:ADA@example.com")
+	contractor := New().SetUID("ext-1").SetFormattedName("Ed Outside").
+		AddCustomProperty("X-MANAGER", "urn:uuid:unknown")
+	group := New().SetKind(KindGroup).SetFormattedName("Team")
+
+	chart := NewAddressBook(ceo, cto, dev, cfo, contractor, group).OrgChart()
+
+	if len(chart.Roots) != 2 || chart.Roots[0].Card != ceo || chart.Roots[1].Card != contractor {
+		t.Fatalf("Expected CEO and unresolved contractor as roots, got %+v", chart.Roots)
+	}
+	reports := chart.Roots[0].Reports
+	if len(reports) != 2 || reports[0].Card != cto || reports[1].Card != cfo {
+		t.Fatalf("Expected CTO and CFO reporting to CEO in book order, got %+v", reports)
+	}
+	if len(reports[0].Reports) != 1 || reports[0].Reports[0].Card != dev {
+		t.Errorf("Expected developer reporting to CTO by name, got %+v", reports[0].Reports)
+	}
+	if chart.Roots[0].Title != "CEO" || chart.Roots[0].UID != "ceo-1" {
+		t.Errorf("Unexpected root node %+v", chart.Roots[0])
+	}
+}
+
+func TestOrgChartBreaksCycles(t *testing.T) {
+	a := New().SetUID("a").SetFormattedName("A").AddCustomProperty("X-MANAGER", "b")
+	b := New().SetUID("b").SetFormattedName("B").AddCustomProperty("X-MANAGER", "a")
+	c := New().SetUID("c").SetFormattedName("C").AddCustomProperty("X-MANAGER", "b")
+	self := New().SetUID("d").SetFormattedName("D").AddCustomProperty("X-MANAGER", "d")
+
+	chart := NewAddressBook(c, a, b, self).OrgChart()
+
+	if len(chart.Roots) != 2 || chart.Roots[0].Card != a || chart.Roots[1].Card != self {
+		t.Fatalf("Expected the first card of the cycle and the self reference as roots, got %+v", chart.Roots)
+	}
+	if len(chart.Roots[0].Reports) != 1 || chart.Roots[0].Reports[0].Card != b {
+		t.Fatalf("Expected B reporting to A, got %+v", chart.Roots[0].Reports)
+	}
+	if len(chart.Roots[0].Reports[0].Reports) != 1 || chart.Roots[0].Reports[0].Reports[0].Card != c {
+		t.Errorf("Expected C reporting to B, got %+v", chart.Roots[0].Reports[0].Reports)
+	}
+}
+
+func TestOrgChartIgnoresRelated(t *testing.T) {
+	jane := New().SetUID("jane").SetFormattedName("Jane Doe")
+	john := New().SetUID("john").SetFormattedName("John Doe").AddCustomProperty("RELATED", "urn:uuid:jane")
+
+	chart := NewAddressBook(jane, john).OrgChart()
+	if len(chart.Roots) != 2 || len(chart.Roots[0].Reports) != 0 {
+		t.Errorf("Expected RELATED not to make a manager, got %+v", chart.Roots)
+	}
+}
+
+func TestOrgChartExport(t *testing.T) {
+	ceo := New().SetUID("ceo-1").SetFormattedName(`Ada "The Boss"`).AddTitle("CEO")
+	cto := New().SetUID("cto-1").SetFormattedName("Bob Tech").AddCustomProperty("X-MANAGER", "ceo-1")
+	chart := NewAddressBook(ceo, cto).OrgChart()
+
+	var buf bytes.Buffer
+	if err := chart.ExportJSON(&buf); err != nil {
+		t.Fatalf("ExportJSON() returned error: %v", err)
+	}
+	var decoded struct {
+		Roots []struct {
+			UID     string `json:"uid"`
+			Name    string `json:"name"`
+			Title   string `json:"title"`
+			Reports []struct {
+				Name string `json:"name"`
+			} `json:"reports"`
+		} `json:"roots"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(decoded.Roots) != 1 || decoded.Roots[0].Title != "CEO" ||
+		len(decoded.Roots[0].Reports) != 1 || decoded.Roots[0].Reports[0].Name != "Bob Tech" {
+		t.Errorf("Unexpected JSON chart %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := chart.ExportDOT(&buf); err != nil {
+		t.Fatalf("ExportDOT() returned error: %v", err)
+	}
+	dot := buf.String()
+	for _, want := range []string{
+		"digraph orgchart {",
+		`n0 [label="Ada \"The Boss\"\nCEO"];`,
+		`n1 [label="Bob Tech"];`,
+		"n0 -> n1;",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Expected DOT to contain %q, got:\n%s", want, dot)
+		}
+	}
+}
+
+func TestOrgChartEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewAddressBook().OrgChart().ExportJSON(&buf); err != nil {
+		t.Fatalf("ExportJSON() returned error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "{\n  \"roots\": []\n}" {
+		t.Errorf("Expected empty roots, got %q", buf.String())
+	}
+}
@@ -0,0 +1,143 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// Param is a property parameter and its values, e.g. TYPE=WORK,VOICE
+type Param struct {
+	// Name is the parameter name, written upper-cased
+	Name string
+
+	// Values are the parameter values in order
+	Values []string
+}
+
+// Params is an ordered list of property parameters. Values are written with
+// RFC 6868 encoding and quoted when needed, so they may hold any text.
+// Parameters with names that are not made of letters, digits and dashes are
+// not written.
+type Params []Param
+
+// Add appends non-empty values to the named parameter, adding the parameter
+// when it is missing. Names are compared case-insensitively.
+func (p *Params) Add(name string, values ...string) {
+	var nonEmpty []string
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return
+	}
+
+	if i := p.index(name); i >= 0 {
+		(*p)[i].Values = append((*p)[i].Values, nonEmpty...)
+		return
+	}
+	*p = append(*p, Param{Name: name, Values: nonEmpty})
+}
+
+// Set replaces the values of the named parameter, keeping its position.
+// Setting no non-empty values removes the parameter.
+func (p *Params) Set(name string, values ...string) {
+	i := p.index(name)
+	if i < 0 {
+		p.Add(name, values...)
+		return
+	}
+
+	(*p)[i].Values = nil
+	for _, value := range values {
+		if value != "" {
+			(*p)[i].Values = append((*p)[i].Values, value)
+		}
+	}
+	if len((*p)[i].Values) == 0 {
+		p.Del(name)
+	}
+}
+
+// Del removes the named parameter
+func (p *Params) Del(name string) {
+	if i := p.index(name); i >= 0 {
+		*p = append((*p)[:i], (*p)[i+1:]...)
+	}
+}
+
+// Get returns the values of the named parameter
+func (p Params) Get(name string) []string {
+	if i := p.index(name); i >= 0 {
+		return p[i].Values
+	}
+	return nil
+}
+
+// String encodes the parameters as they follow the property name, e.g.
+// ";TYPE=WORK;PREF=1"
+func (p Params) String() string {
+	var builder strings.Builder
+	for _, param := range p {
+		if len(param.Values) == 0 || !isParamName(param.Name) {
+			continue
+		}
+
+		builder.WriteString(";")
+		builder.WriteString(strings.ToUpper(param.Name))
+		builder.WriteString("=")
+		for i, value := range param.Values {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			builder.WriteString(encodeParamValue(value))
+		}
+	}
+	return builder.String()
+}
+
+// index returns the position of the named parameter, or -1
+func (p Params) index(name string) int {
+	for i, param := range p {
+		if strings.EqualFold(param.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isParamName reports whether name is a valid parameter name
+func isParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// paramEscaper applies the RFC 6868 caret encoding
+var paramEscaper = strings.NewReplacer("^", "^^", "\r\n", "^n", "\n", "^n", "\r", "^n", `"`, "^'")
+
+// encodeParamValue encodes a parameter value, quoting values that hold the
+// separators ":", ";" and ","
+func encodeParamValue(value string) string {
+	value = paramEscaper.Replace(value)
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// typeParams returns parameters holding the type values, which may be a
+// comma-separated list such as "WORK,VOICE"
+func typeParams(types ...string) Params {
+	var params Params
+	for _, t := range types {
+		params.Add("TYPE", strings.Split(t, ",")...)
+	}
+	return params
+}
@@ -0,0 +1,60 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParamsString(t *testing.T) {
+	var params Params
+	params.Add("type", "WORK", "", "VOICE")
+	params.Add("PREF", "1")
+	params.Add("TYPE", "CELL")
+	params.Add("EMPTY", "")
+	params.Add("LABEL", "1 Main St\nSuite 2")
+	params.Add("X-NOTE", `say "hi"; ok: ^_^`)
+	params.Add("BAD;NAME", "x")
+
+	expected := `;TYPE=WORK,VOICE,CELL;PREF=1;LABEL=1 Main St^nSuite 2;X-NOTE="say ^'hi^'; ok: ^^_^^"`
+	if got := params.String(); got != expected {
+		t.Errorf("Params.String() = %s, want %s", got, expected)
+	}
+}
+
+func TestParamsSetGetDel(t *testing.T) {
+	params := Params{{Name: "TYPE", Values: []string{"HOME"}}, {Name: "PREF", Values: []string{"1"}}}
+
+	params.Set("type", "WORK")
+	if values := params.Get("Type"); len(values) != 1 || values[0] != "WORK" {
+		t.Errorf("Unexpected TYPE values: %v", values)
+	}
+
+	if params.String() != ";TYPE=WORK;PREF=1" {
+		t.Errorf("Expected Set to keep the parameter position, got %s", params)
+	}
+
+	params.Del("PREF")
+	if params.Get("PREF") != nil || params.String() != ";TYPE=WORK" {
+		t.Errorf("Expected PREF to be removed, got %s", params)
+	}
+}
+
+func TestTypeParamsSplitsLists(t *testing.T) {
+	if got := typeParams("WORK,VOICE", "").String(); got != ";TYPE=WORK,VOICE" {
+		t.Errorf("Unexpected type parameters: %s", got)
+	}
+}
+
+func TestPropertyParamsOutput(t *testing.T) {
+	card := New().AddName("Jane", "Doe")
+	card.AddEmail("jane@example.com", EmailWork)
+	card.emails[0].Preferred = true
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(content, "EMAIL;TYPE=WORK;PREF=1:jane@example.com") {
+		t.Errorf("Unexpected email line in:\n%s", content)
+	}
+}
@@ -0,0 +1,191 @@
+package vcard
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FromParams builds a card from request parameters using the schema shared
+// by all framework adapters. Parameter names are case-sensitive; type values
+// are not. Unknown parameters are ignored. The email, phone and url
+// parameters may be repeated; each value takes the type given at the same
+// position (email=a@x&email=b@y&emailType=work&emailType=home).
+//
+//	firstName, lastName      name
+//	email, emailType         email; type home, mobile or work (default)
+//	phone, phoneType         phone; type home, mobile (or cell), fax or work (default)
+//	organization             organization name
+//	department, title, role  organization details
+//	url, urlType             URL; type home, social or work (default)
+//	street, extended, city,  address parts; an address is added when any
+//	state, postalCode,       part is set
+//	country
+//	addressType              address type home, postal or work (default)
+//	note                     note
+//	birthday                 birthday as YYYY-MM-DD (other text is kept as
+//	                         a free-text birthday)
+//	photoUrl                 photo URL; only http and https URLs are used
+//	prop.NAME                custom property NAME, e.g. prop.X-SKYPE=jdoe;
+//	                         only X- and registered property names are used,
+//	                         and URI-valued ones (e.g. SOCIALPROFILE) are
+//	                         dropped when their scheme is unsafe
+//
+// Adapters collect the parameters with ParamValues, so values may come from
+// path parameters, the query string or a form body.
+func FromParams(params url.Values) *VCard {
+	card := New()
+
+	if first, last := params.Get("firstName"), params.Get("lastName"); first != "" || last != "" {
+		card.AddName(first, last)
+	}
+
+	types := params["emailType"]
+	for i, email := range params["email"] {
+		if email != "" {
+			card.AddEmail(email, paramEmailType(positional(types, i)))
+		}
+	}
+
+	types = params["phoneType"]
+	for i, phone := range params["phone"] {
+		if phone != "" {
+			card.AddPhone(phone, paramPhoneType(positional(types, i)))
+		}
+	}
+
+	if org := params.Get("organization"); org != "" {
+		card.AddOrganization(org)
+	}
+	if department := params.Get("department"); department != "" {
+		card.AddDepartment(department)
+	}
+	if title := params.Get("title"); title != "" {
+		card.AddTitle(title)
+	}
+	if role := params.Get("role"); role != "" {
+		card.AddRole(role)
+	}
+
+	types = params["urlType"]
+	for i, address := range params["url"] {
+		if address != "" {
+			card.AddURL(address, paramURLType(positional(types, i)))
+		}
+	}
+
+	street, extended := params.Get("street"), params.Get("extended")
+	city, state := params.Get("city"), params.Get("state")
+	postalCode, country := params.Get("postalCode"), params.Get("country")
+	if street != "" || extended != "" || city != "" || state != "" || postalCode != "" || country != "" {
+		card.AddAddressExtended(street, extended, city, state, postalCode, country, paramAddressType(params.Get("addressType")))
+	}
+
+	if note := params.Get("note"); note != "" {
+		card.AddNote(note)
+	}
+
+	if birthday := params.Get("birthday"); birthday != "" {
+		card.SetBirthdayValue(birthday)
+	}
+
+	if photo := params.Get("photoUrl"); photo != "" {
+		if scheme := urlScheme(photo); scheme == "http" || scheme == "https" {
+			card.AddPhoto(photo)
+		}
+	}
+
+	for name, values := range params {
+		property, ok := strings.CutPrefix(name, "prop.")
+		if !ok || len(values) == 0 || values[0] == "" || !isCustomPropertyAllowed(property) {
+			continue
+		}
+		property = strings.ToUpper(property)
+		if uriProperties[property] && unsafeURLSchemes[urlScheme(values[0])] {
+			continue
+		}
+		card.AddCustomProperty(property, values[0])
+	}
+
+	return card
+}
+
+// ParamValues merges path parameters with query and form values for
+// FromParams. Each source replaces the values of the parameters it sets, so
+// explicit query and form values take precedence over path parameters when
+// passed in that order.
+func ParamValues(path map[string]string, sources ...url.Values) url.Values {
+	values := make(url.Values, len(path))
+	for name, value := range path {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	for _, source := range sources {
+		for name, list := range source {
+			if len(list) > 0 {
+				values[name] = append([]string(nil), list...)
+			}
+		}
+	}
+
+	return values
+}
+
+// positional returns the value at index i, or an empty string
+func positional(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// paramEmailType maps an emailType parameter to an email type
+func paramEmailType(value string) EmailType {
+	switch strings.ToLower(value) {
+	case "home":
+		return EmailHome
+	case "mobile":
+		return EmailMobile
+	default:
+		return EmailWork
+	}
+}
+
+// paramPhoneType maps a phoneType parameter to a phone type
+func paramPhoneType(value string) PhoneType {
+	switch strings.ToLower(value) {
+	case "home":
+		return PhoneHome
+	case "mobile", "cell":
+		return PhoneMobile
+	case "fax":
+		return PhoneFax
+	default:
+		return PhoneWork
+	}
+}
+
+// paramAddressType maps an addressType parameter to an address type
+func paramAddressType(value string) AddressType {
+	switch strings.ToLower(value) {
+	case "home":
+		return AddressHome
+	case "postal":
+		return AddressPostal
+	default:
+		return AddressWork
+	}
+}
+
+// paramURLType maps a urlType parameter to a URL type
+func paramURLType(value string) URLType {
+	switch strings.ToLower(value) {
+	case "home":
+		return URLHome
+	case "social":
+		return URLSocial
+	default:
+		return URLWork
+	}
+}
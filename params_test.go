@@ -0,0 +1,171 @@
+package vcard
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFromParams(t *testing.T) {
+	params := url.Values{
+		"firstName":    {"Jane"},
+		"lastName":     {"Smith"},
+		"email":        {"jane@example.com"},
+		"emailType":    {"HOME"},
+		"phone":        {"+1 555 123 4567"},
+		"phoneType":    {"cell"},
+		"organization": {"Acme"},
+		"department":   {"R&D"},
+		"title":        {"Engineer"},
+		"role":         {"Lead"},
+		"url":          {"https://jane.dev"},
+		"urlType":      {"home"},
+		"street":       {"1 Main St"},
+		"extended":     {"Suite 200"},
+		"city":         {"Sofia"},
+		"postalCode":   {"1000"},
+		"country":      {"Bulgaria"},
+		"addressType":  {"Postal"},
+		"note":         {"Met at GopherCon"},
+		"unknown":      {"ignored"},
+	}
+
+	card := FromParams(params)
+
+	if name := card.GetName(); name.First != "Jane" || name.Last != "Smith" {
+		t.Errorf("Unexpected name: %+v", name)
+	}
+	if emails := card.GetEmails(); len(emails) != 1 || emails[0].Type != EmailHome {
+		t.Errorf("Unexpected emails: %+v", emails)
+	}
+	if phones := card.GetPhones(); len(phones) != 1 || phones[0].Type != PhoneMobile {
+		t.Errorf("Unexpected phones: %+v", phones)
+	}
+	if org := card.GetOrganization(); org.Name != "Acme" || org.Department != "R&D" || org.Title != "Engineer" || org.Role != "Lead" {
+		t.Errorf("Unexpected organization: %+v", org)
+	}
+	if urls := card.GetURLs(); len(urls) != 1 || urls[0].Type != URLHome {
+		t.Errorf("Unexpected URLs: %+v", urls)
+	}
+	addresses := card.GetAddresses()
+	if len(addresses) != 1 {
+		t.Fatalf("Expected 1 address, got %d", len(addresses))
+	}
+	expected := Address{Street: "1 Main St", Extended: "Suite 200", City: "Sofia", PostalCode: "1000", Country: "Bulgaria", Type: AddressPostal}
+	if addresses[0] != expected {
+		t.Errorf("Unexpected address: %+v", addresses[0])
+	}
+	if card.GetNote() != "Met at GopherCon" {
+		t.Errorf("Unexpected note: %q", card.GetNote())
+	}
+}
+
+func TestFromParamsDefaults(t *testing.T) {
+	card := FromParams(url.Values{"lastName": {"Smith"}, "email": {"a@example.com"}, "phone": {"1"}, "url": {"https://x.dev"}, "city": {"Sofia"}})
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	for _, expected := range []string{"N:Smith;", "EMAIL;TYPE=WORK:", "TEL;TYPE=WORK:", "URL;TYPE=WORK:", "ADR;TYPE=WORK:;;;Sofia;;;"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
+func TestParamValues(t *testing.T) {
+	values := ParamValues(
+		map[string]string{"firstName": "Path", "lastName": "Doe", "empty": ""},
+		url.Values{"firstName": {"Query"}, "email": {"q@example.com"}},
+		url.Values{"email": {"form@example.com"}, "note": {}},
+	)
+
+	if values.Get("firstName") != "Query" || values.Get("lastName") != "Doe" {
+		t.Errorf("Expected query to override path parameters, got %v", values)
+	}
+	if values.Get("email") != "form@example.com" {
+		t.Errorf("Expected form to override query, got %v", values)
+	}
+	if _, ok := values["empty"]; ok {
+		t.Error("Expected empty path parameters to be skipped")
+	}
+	if _, ok := values["note"]; ok {
+		t.Error("Expected empty value lists to be skipped")
+	}
+}
+
+func TestFromParamsBirthdayPhotoAndProperties(t *testing.T) {
+	card := FromParams(url.Values{
+		"firstName":         {"Jane"},
+		"birthday":          {"1990-05-15"},
+		"photoUrl":          {"https://example.com/jane.jpg"},
+		"prop.X-Skype":      {"jane.doe"},
+		"prop.NICKNAME":     {"JD"},
+		"prop.X-A:b\nEMAIL": {"evil@example.com"},
+		"prop.UNKNOWN":      {"ignored"},
+		"prop.X-EMPTY":      {""},
+		"X-NOT-PREFIXED":    {"ignored"},
+	})
+
+	if birthday := card.GetBirthday(); birthday == nil || birthday.Format("2006-01-02") != "1990-05-15" {
+		t.Errorf("Unexpected birthday: %v", birthday)
+	}
+	if card.GetPhoto() != "https://example.com/jane.jpg" {
+		t.Errorf("Unexpected photo: %q", card.GetPhoto())
+	}
+
+	expected := map[string]string{"X-SKYPE": "jane.doe", "NICKNAME": "JD"}
+	props := card.GetCustomProperties()
+	if len(props) != len(expected) {
+		t.Errorf("Expected properties %v, got %v", expected, props)
+	}
+	for name, value := range expected {
+		if props[name] != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, props[name])
+		}
+	}
+
+	unsafe := FromParams(url.Values{"firstName": {"Jane"}, "prop.SOCIALPROFILE": {"javascript:alert(1)"}, "prop.IMPP": {"xmpp:jane@example.com"}})
+	if props := unsafe.GetCustomProperties(); props["SOCIALPROFILE"] != "" || props["IMPP"] != "xmpp:jane@example.com" {
+		t.Errorf("Expected only the unsafe URI property to be dropped, got %v", props)
+	}
+	if err := unsafe.Validate(); err != nil {
+		t.Errorf("Expected a valid card, got %v", err)
+	}
+
+	text := FromParams(url.Values{"firstName": {"Jane"}, "birthday": {"circa 1990"}, "photoUrl": {"javascript:alert(1)"}})
+	if text.GetBirthdayText() != "circa 1990" {
+		t.Errorf("Expected free-text birthday, got %q", text.GetBirthdayText())
+	}
+	if text.GetPhoto() != "" {
+		t.Errorf("Expected unsafe photo URL to be ignored, got %q", text.GetPhoto())
+	}
+}
+
+func TestFromParamsRepeated(t *testing.T) {
+	query, err := url.ParseQuery("firstName=Jane&email=a@x.com&email=b@y.com&email=&email=c@z.com&emailType=work&emailType=home&emailType=mobile" +
+		"&phone=555-0100&phone=555-0101&phoneType=&phoneType=cell&url=https://a.dev&url=https://b.dev&urlType=social")
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := FromParams(query)
+
+	emails := card.GetEmails()
+	expectedEmails := []Email{{Address: "a@x.com", Type: EmailWork}, {Address: "b@y.com", Type: EmailHome}, {Address: "c@z.com", Type: EmailWork}}
+	if len(emails) != len(expectedEmails) {
+		t.Fatalf("Expected %d emails, got %+v", len(expectedEmails), emails)
+	}
+	for i, expected := range expectedEmails {
+		if emails[i].Address != expected.Address || emails[i].Type != expected.Type {
+			t.Errorf("Email %d: expected %+v, got %+v", i, expected, emails[i])
+		}
+	}
+
+	if phones := card.GetPhones(); len(phones) != 2 || phones[0].Type != PhoneWork || phones[1].Type != PhoneMobile {
+		t.Errorf("Unexpected phones: %+v", phones)
+	}
+	if urls := card.GetURLs(); len(urls) != 2 || urls[0].Type != URLSocial || urls[1].Type != URLWork {
+		t.Errorf("Unexpected URLs: %+v", urls)
+	}
+}
@@ -0,0 +1,253 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a single vCard 3.0 or 4.0. Properties the library models fill
+// the matching fields and other registered and X- properties become custom
+// properties, so Parse(card.String()) gives an equal card. The card has the
+// default CardOptions; use a Decoder to read cards with other options.
+func Parse(text string) (*VCard, error) {
+	return readCard(text, CardOptions{})
+}
+
+// readCard reads a single vCard 3.0 or 4.0 into a card. Properties the
+// library models fill the matching fields; other registered and X-
+// properties become custom properties (the first occurrence wins). LABEL is
+// skipped as it is derived from ADR. A card without N takes its name from FN.
+func readCard(text string, options CardOptions) (*VCard, error) {
+	return readLines(unfoldLines(text), options)
+}
+
+// readLines reads a card from its unfolded lines (see readCard)
+func readLines(lines []string, options CardOptions) (*VCard, error) {
+	if len(lines) < 2 || !strings.EqualFold(lines[0], "BEGIN:VCARD") || !strings.EqualFold(lines[len(lines)-1], "END:VCARD") {
+		return nil, fmt.Errorf("expected a single BEGIN:VCARD ... END:VCARD block")
+	}
+
+	card := New().SetOptions(options)
+	hasName, hasVersion := false, false
+	for _, line := range lines[1 : len(lines)-1] {
+		property, err := ParseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		if property.Name == "VERSION" {
+			version, err := ParseVersion(property.Value)
+			if err != nil {
+				return nil, err
+			}
+			card.version, hasVersion = version, true
+			continue
+		}
+		if property.Name == "N" {
+			hasName = true
+		}
+		if err := card.readProperty(property); err != nil {
+			return nil, fmt.Errorf("%s: %w", property.Name, err)
+		}
+	}
+
+	if !hasVersion {
+		return nil, fmt.Errorf("missing VERSION")
+	}
+	if !hasName && card.fn != "" {
+		card.name.Last = card.fn
+		if i := strings.LastIndex(card.fn, " "); i >= 0 {
+			card.name.First, card.name.Last = card.fn[:i], card.fn[i+1:]
+		}
+	}
+
+	return card, nil
+}
+
+// readProperty sets the card field of a content line
+func (v *VCard) readProperty(property ContentLine) error {
+	value := unescapeValue(property.Value)
+	types := property.Params.Get("TYPE")
+	preferred := len(property.Params.Get("PREF")) > 0 || hasType(types, "PREF")
+
+	switch property.Name {
+	case "N":
+		components := splitComponents(property.Value)
+		v.SetName(Name{
+			Last:   component(components, 0),
+			First:  component(components, 1),
+			Middle: component(components, 2),
+			Prefix: component(components, 3),
+			Suffix: component(components, 4),
+		})
+	case "FN", "ORG", "TITLE", "ROLE", "NOTE":
+		return v.readTextProperty(property)
+	case "KIND":
+		v.SetKind(Kind(strings.ToLower(value)))
+	case "EMAIL":
+		v.AddEmailWithPreference(value, EmailType(firstType(types, EmailInternet, "WORK", "HOME", "MOBILE")), preferred)
+	case "TEL":
+		phoneType := PhoneType(firstType(types, PhoneVoice, "FAX", "CELL", "MOBILE", "WORK", "HOME"))
+		if phoneType == "CELL" {
+			phoneType = PhoneMobile
+		}
+		v.AddPhoneWithPreference(value, phoneType, preferred)
+	case "ADR":
+		components := splitComponents(property.Value)
+		v.addresses = append(v.addresses, Address{
+			Extended:   component(components, 1),
+			Street:     component(components, 2),
+			City:       component(components, 3),
+			State:      component(components, 4),
+			PostalCode: component(components, 5),
+			Country:    component(components, 6),
+			Type:       AddressType(firstType(types, "", "WORK", "HOME", "POSTAL")),
+			Preferred:  preferred,
+		}.normalized(v.options))
+	case "URL":
+		v.AddURLWithPreference(value, URLType(firstType(types, "", "WORK", "HOME", "SOCIAL")), preferred)
+	case "GEO":
+		return v.readGeo(value)
+	case "PHOTO":
+		v.photo = readMedia(property)
+		if values := property.Params.Get(CropParam); len(values) > 0 {
+			if rect, err := ParseCropRect(strings.Join(values, ",")); err == nil {
+				v.SetPhotoCrop(rect)
+			}
+		}
+	case "LOGO":
+		v.logo = readMedia(property)
+	case "BDAY", "ANNIVERSARY":
+		text := hasType(property.Params.Get("VALUE"), "TEXT")
+		switch {
+		case property.Name == "BDAY" && text:
+			v.SetBirthdayText(value)
+		case property.Name == "BDAY":
+			v.SetBirthdayValue(value)
+		case text:
+			v.SetAnniversaryText(value)
+		default:
+			v.SetAnniversaryValue(value)
+		}
+	case "UID":
+		v.SetUID(value)
+	case "MEMBER", "X-ADDRESSBOOKSERVER-MEMBER":
+		v.members = append(v.members, value)
+	case "X-ABSHOWAS":
+		v.SetShowAsCompany(strings.EqualFold(value, "COMPANY"))
+	case "LABEL":
+	default:
+		if _, ok := v.customProps[property.Name]; !ok && isCustomPropertyAllowed(property.Name) {
+			v.AddCustomProperty(property.Name, value)
+		}
+	}
+
+	return nil
+}
+
+// readTextProperty reads a text property that may carry alternate language
+// versions: the first occurrence sets the value, later ones with a LANGUAGE
+// parameter become alternates. Further NOTE values are joined to the note,
+// reassembling notes written in chunks.
+func (v *VCard) readTextProperty(property ContentLine) error {
+	value := unescapeValue(property.Value)
+	language := property.Params.Get("LANGUAGE")
+
+	var current string
+	switch property.Name {
+	case "FN":
+		current = v.fn
+	case "ORG":
+		current = v.organization.Name
+	case "TITLE":
+		current = v.organization.Title
+	case "ROLE":
+		current = v.organization.Role
+	case "NOTE":
+		current = v.note
+	}
+	if current != "" && len(language) > 0 {
+		v.AddAlternate(property.Name, language[0], value)
+		return nil
+	}
+
+	switch property.Name {
+	case "FN":
+		v.SetFormattedName(value)
+	case "ORG":
+		components := splitComponents(property.Value)
+		org := v.organization
+		org.Name, org.Department = components[0], component(components, 1)
+		org.Units = nil
+		if len(components) > 2 {
+			org.Units = components[2:]
+		}
+		v.SetOrganization(org)
+	case "TITLE":
+		v.AddTitle(value)
+	case "ROLE":
+		v.AddRole(value)
+	case "NOTE":
+		v.AddNote(joinNote(v.note, value))
+	}
+	return nil
+}
+
+// readGeo reads a geo: URI (4.0) or a latitude;longitude pair (3.0)
+func (v *VCard) readGeo(value string) error {
+	value = strings.TrimPrefix(value, "geo:")
+	if i := strings.IndexByte(value, ';'); i >= 0 && strings.Contains(value, ",") {
+		value = value[:i] // geo URI parameters, e.g. ;u=10
+	}
+	lat, lon, ok := strings.Cut(value, ",")
+	if !ok {
+		lat, lon, ok = strings.Cut(value, ";")
+	}
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil || !ok {
+		return fmt.Errorf("invalid position %q", value)
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return fmt.Errorf("invalid position %q", value)
+	}
+	v.SetGeo(latitude, longitude)
+	return nil
+}
+
+// readMedia reads a PHOTO or LOGO value: inline base64 data (3.0) becomes a
+// data URI, URIs are kept
+func readMedia(property ContentLine) string {
+	encoding := property.Params.Get("ENCODING")
+	if !hasType(encoding, "B") && !hasType(encoding, "BASE64") {
+		return property.Value
+	}
+
+	mediaType := defaultPhotoMIME
+	for _, token := range property.Params.Get("TYPE") {
+		if known, ok := MediaTypeByToken(token); ok {
+			mediaType = known.MIME
+		}
+	}
+	return "data:" + mediaType + ";base64," + strings.Join(strings.Fields(property.Value), "")
+}
+
+// hasType reports whether the parameter values contain the value, ignoring
+// case
+func hasType(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+// firstType returns the first of the known types present in the TYPE
+// values, or the fallback
+func firstType[T ~string](values []string, fallback T, known ...string) T {
+	for _, name := range known {
+		if hasType(values, name) {
+			return T(name)
+		}
+	}
+	return fallback
+}
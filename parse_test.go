@@ -0,0 +1,62 @@
+package vcard
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	card := New().AddName("Jane", "Doe").AddEmail("jane@example.com").SetUID("1").AddCustomProperty("X-TEAM", "Core")
+
+	text, err := card.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if changes := Diff(card, parsed); len(changes) != 0 {
+		t.Errorf("Expected an equal card, got %+v", changes)
+	}
+	if output, _ := parsed.String(); output != text {
+		t.Errorf("Expected the same output, got:\n%s", output)
+	}
+
+	for _, text := range []string{"", "BEGIN:VCARD\nFN:Jane\nEND:VCARD", "BEGIN:VCARD\nVERSION:9.0\nEND:VCARD"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Expected error for %q", text)
+		}
+	}
+}
+
+func TestReadCard(t *testing.T) {
+	card, err := Parse("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Jane Doe\r\nFN;LANGUAGE=bg:Джейн До\r\n" +
+		"EMAIL;TYPE=home;PREF=1:jane@example.com\r\nGEO:geo:42.69,23.32;u=10\r\nX-ABLabel:Other\r\nEND:VCARD\r\n")
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if name := card.GetName(); name.First != "Jane" || name.Last != "Doe" {
+		t.Errorf("Expected the name to be taken from FN, got %+v", name)
+	}
+	if alternates := card.GetAlternates("FN"); len(alternates) != 1 || alternates[0].Language != "bg" {
+		t.Errorf("Unexpected alternates: %+v", alternates)
+	}
+	if emails := card.GetEmails(); len(emails) != 1 || emails[0].Type != EmailHome || !emails[0].Preferred {
+		t.Errorf("Unexpected emails: %+v", emails)
+	}
+	if geo := card.GetGeo(); geo == nil || geo.Latitude != 42.69 || geo.Longitude != 23.32 {
+		t.Errorf("Unexpected position: %+v", geo)
+	}
+	if card.GetCustomProperties()["X-ABLABEL"] != "Other" {
+		t.Errorf("Expected custom property, got %v", card.GetCustomProperties())
+	}
+
+	for _, text := range []string{
+		"FN:Jane\r\n",
+		"BEGIN:VCARD\r\nFN:Jane\r\nEND:VCARD\r\n",
+		"BEGIN:VCARD\r\nVERSION:2.1\r\nFN:Jane\r\nEND:VCARD\r\n",
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nGEO:north\r\nEND:VCARD\r\n",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Expected error for %q", text)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// PhoneFormat selects how Phone.Format renders a number
+type PhoneFormat string
+
+const (
+	// PhoneFormatInternational renders "+44 20 7946 0958"
+	PhoneFormatInternational PhoneFormat = "international"
+
+	// PhoneFormatNational renders "020 7946 0958"
+	PhoneFormatNational PhoneFormat = "national"
+
+	// PhoneFormatRFC3966 renders the tel URI "tel:+44-20-7946-0958"
+	PhoneFormatRFC3966 PhoneFormat = "rfc3966"
+)
+
+// phonePlan describes the numbering plan of a country calling code
+type phonePlan struct {
+	// trunk is the national prefix dropped in international form
+	trunk string
+
+	// groups lists digit groupings of the national significant number
+	groups []phoneGroup
+}
+
+// phoneGroup groups national significant numbers of a given length that
+// start with a prefix
+type phoneGroup struct {
+	prefix string
+	length int
+	sizes  []int
+}
+
+// phoneRegions maps regions to their country calling codes
+var phoneRegions = map[string]string{
+	"AT": "43", "AU": "61", "BE": "32", "BG": "359", "BR": "55",
+	"CA": "1", "CH": "41", "CN": "86", "DE": "49", "ES": "34",
+	"FR": "33", "GB": "44", "IE": "353", "IN": "91", "IT": "39",
+	"JP": "81", "MX": "52", "NL": "31", "NZ": "64", "PL": "48",
+	"PT": "351", "RU": "7", "SE": "46", "SG": "65", "US": "1",
+	"ZA": "27",
+}
+
+// phonePlans holds the numbering plans of the supported calling codes
+var phonePlans = map[string]phonePlan{
+	"1":   {trunk: "1", groups: []phoneGroup{{"", 10, []int{3, 3, 4}}}},
+	"7":   {trunk: "8", groups: []phoneGroup{{"", 10, []int{3, 3, 2, 2}}}},
+	"27":  {trunk: "0", groups: []phoneGroup{{"", 9, []int{2, 3, 4}}}},
+	"31":  {trunk: "0", groups: []phoneGroup{{"6", 9, []int{1, 8}}, {"", 9, []int{2, 3, 4}}}},
+	"32":  {trunk: "0", groups: []phoneGroup{{"4", 9, []int{3, 2, 2, 2}}, {"", 8, []int{1, 3, 2, 2}}}},
+	"33":  {trunk: "0", groups: []phoneGroup{{"", 9, []int{1, 2, 2, 2, 2}}}},
+	"34":  {groups: []phoneGroup{{"", 9, []int{3, 3, 3}}}},
+	"39":  {groups: []phoneGroup{{"3", 10, []int{3, 3, 4}}, {"0", 10, []int{2, 4, 4}}}},
+	"41":  {trunk: "0", groups: []phoneGroup{{"", 9, []int{2, 3, 2, 2}}}},
+	"43":  {trunk: "0"},
+	"44":  {trunk: "0", groups: []phoneGroup{{"2", 10, []int{2, 4, 4}}, {"", 10, []int{4, 6}}}},
+	"46":  {trunk: "0", groups: []phoneGroup{{"7", 9, []int{2, 3, 2, 2}}, {"8", 9, []int{1, 3, 3, 2}}}},
+	"48":  {groups: []phoneGroup{{"", 9, []int{3, 3, 3}}}},
+	"49":  {trunk: "0", groups: []phoneGroup{{"1", 11, []int{3, 8}}, {"1", 10, []int{3, 7}}}},
+	"52":  {groups: []phoneGroup{{"", 10, []int{2, 4, 4}}}},
+	"55":  {trunk: "0", groups: []phoneGroup{{"", 11, []int{2, 5, 4}}, {"", 10, []int{2, 4, 4}}}},
+	"61":  {trunk: "0", groups: []phoneGroup{{"4", 9, []int{3, 3, 3}}, {"", 9, []int{1, 4, 4}}}},
+	"64":  {trunk: "0", groups: []phoneGroup{{"", 8, []int{1, 3, 4}}}},
+	"65":  {groups: []phoneGroup{{"", 8, []int{4, 4}}}},
+	"81":  {trunk: "0", groups: []phoneGroup{{"", 10, []int{2, 4, 4}}}},
+	"86":  {trunk: "0", groups: []phoneGroup{{"1", 11, []int{3, 4, 4}}}},
+	"91":  {trunk: "0", groups: []phoneGroup{{"", 10, []int{5, 5}}}},
+	"351": {groups: []phoneGroup{{"", 9, []int{3, 3, 3}}}},
+	"353": {trunk: "0", groups: []phoneGroup{{"8", 9, []int{2, 3, 4}}, {"1", 8, []int{1, 3, 4}}}},
+	"359": {trunk: "0", groups: []phoneGroup{{"8", 9, []int{2, 3, 4}}, {"9", 9, []int{2, 3, 4}}, {"2", 8, []int{1, 3, 4}}}},
+}
+
+// twoDigitCallingCodes lists the two-digit country calling codes. Calling
+// codes are prefix-free: 1 and 7 are the only one-digit codes and every code
+// not listed here is three digits long.
+var twoDigitCallingCodes = map[string]bool{
+	"20": true, "27": true, "30": true, "31": true, "32": true, "33": true,
+	"34": true, "36": true, "39": true, "40": true, "41": true, "43": true,
+	"44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "52": true, "53": true, "54": true, "55": true, "56": true,
+	"57": true, "58": true, "60": true, "61": true, "62": true, "63": true,
+	"64": true, "65": true, "66": true, "81": true, "82": true, "84": true,
+	"86": true, "90": true, "91": true, "92": true, "93": true, "94": true,
+	"95": true, "98": true,
+}
+
+// Format renders the number in the given style. Numbers with a country code
+// ("+" or "00" prefix) are split into country code and national number;
+// other numbers are national numbers of no known region and keep their
+// original form in international and RFC 3966 output, as a tel URI of a
+// local number would need a phone-context. Digit grouping follows
+// common conventions for the regions in the built-in table and falls back to
+// blocks of three and four digits. Extensions, letters and numbers too short
+// to be valid are returned unchanged.
+func (p Phone) Format(style PhoneFormat) string {
+	return p.FormatRegion(style, "")
+}
+
+// FormatRegion renders the number like Format, reading numbers without a
+// country code as national numbers of the ISO 3166-1 alpha-2 region (e.g.
+// "GB"), such as the PhoneRegion of a card's options
+func (p Phone) FormatRegion(style PhoneFormat, region string) string {
+	code, national, ok := parsePhone(p.Number, region)
+	if !ok {
+		return p.Number
+	}
+
+	groups := groupPhone(code, national)
+
+	switch style {
+	case PhoneFormatNational:
+		if code == "" {
+			return strings.Join(groups, " ")
+		}
+		plan := phonePlans[code]
+		if code == "1" && len(groups) == 3 {
+			return "(" + groups[0] + ") " + strings.Join(groups[1:], "-")
+		}
+		groups[0] = plan.trunk + groups[0]
+		return strings.Join(groups, " ")
+	case PhoneFormatRFC3966:
+		if code == "" {
+			return p.Number
+		}
+		return "tel:+" + code + "-" + strings.Join(groups, "-")
+	default:
+		if code == "" {
+			return p.Number
+		}
+		return "+" + code + " " + strings.Join(groups, " ")
+	}
+}
+
+// parsePhone splits a number into its country calling code and national
+// significant number. The code is empty when it cannot be determined.
+func parsePhone(number, region string) (code, national string, ok bool) {
+	number = strings.TrimSpace(number)
+	international := strings.HasPrefix(number, "+")
+
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+		default:
+			return "", "", false
+		}
+	}
+
+	national = digits.String()
+	if !international && strings.HasPrefix(national, "00") {
+		international, national = true, national[2:]
+	}
+	if len(national) < 5 || len(national) > 17 {
+		return "", "", false
+	}
+
+	if international {
+		code = callingCode(national)
+		national = national[len(code):]
+		if phonePlans[code].trunk == "0" {
+			// "+44 (0)20 ..." style numbers repeat the trunk prefix
+			national = strings.TrimPrefix(national, "0")
+		}
+		return code, national, national != ""
+	}
+
+	code, known := phoneRegions[strings.ToUpper(region)]
+	if !known {
+		return "", national, true
+	}
+	return code, strings.TrimPrefix(national, phonePlans[code].trunk), true
+}
+
+// callingCode returns the country calling code at the start of digits
+func callingCode(digits string) string {
+	switch {
+	case digits[0] == '1' || digits[0] == '7':
+		return digits[:1]
+	case twoDigitCallingCodes[digits[:2]]:
+		return digits[:2]
+	default:
+		return digits[:3]
+	}
+}
+
+// groupPhone splits a national significant number into display groups
+func groupPhone(code, national string) []string {
+	sizes := defaultPhoneGroups(len(national))
+	for _, group := range phonePlans[code].groups {
+		if group.length == len(national) && strings.HasPrefix(national, group.prefix) {
+			sizes = group.sizes
+			break
+		}
+	}
+
+	groups := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		groups = append(groups, national[:size])
+		national = national[size:]
+	}
+	return groups
+}
+
+// defaultPhoneGroups splits n digits into blocks of three, ending with
+// blocks of four when n is not a multiple of three
+func defaultPhoneGroups(n int) []int {
+	if n <= 5 {
+		return []int{n}
+	}
+
+	var tail []int
+	switch n % 3 {
+	case 1:
+		tail, n = []int{4}, n-4
+	case 2:
+		tail, n = []int{4, 4}, n-8
+	}
+
+	sizes := make([]int, 0, n/3+len(tail))
+	for ; n > 0; n -= 3 {
+		sizes = append(sizes, 3)
+	}
+	return append(sizes, tail...)
+}
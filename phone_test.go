@@ -0,0 +1,67 @@
+package vcard
+
+import "testing"
+
+func TestPhoneFormat(t *testing.T) {
+	tests := []struct {
+		number        string
+		international string
+		national      string
+		rfc3966       string
+	}{
+		{"+44 20 7946 0958", "+44 20 7946 0958", "020 7946 0958", "tel:+44-20-7946-0958"},
+		{"+44 (0)7700 900123", "+44 7700 900123", "07700 900123", "tel:+44-7700-900123"},
+		{"+1 (555) 123-4567", "+1 555 123 4567", "(555) 123-4567", "tel:+1-555-123-4567"},
+		{"00359 88 1234567", "+359 88 123 4567", "088 123 4567", "tel:+359-88-123-4567"},
+		{"+39 06 1234 5678", "+39 06 1234 5678", "06 1234 5678", "tel:+39-06-1234-5678"},
+		{"+380441234567", "+380 441 234 567", "441 234 567", "tel:+380-441-234-567"},
+		{"555-0100 ext. 12", "555-0100 ext. 12", "555-0100 ext. 12", "555-0100 ext. 12"},
+		{"112", "112", "112", "112"},
+	}
+
+	for _, tt := range tests {
+		phone := Phone{Number: tt.number}
+		if got := phone.Format(PhoneFormatInternational); got != tt.international {
+			t.Errorf("Format(%q, international) = %q, want %q", tt.number, got, tt.international)
+		}
+		if got := phone.Format(PhoneFormatNational); got != tt.national {
+			t.Errorf("Format(%q, national) = %q, want %q", tt.number, got, tt.national)
+		}
+		if got := phone.Format(PhoneFormatRFC3966); got != tt.rfc3966 {
+			t.Errorf("Format(%q, rfc3966) = %q, want %q", tt.number, got, tt.rfc3966)
+		}
+	}
+}
+
+func TestPhoneFormatDefaultRegion(t *testing.T) {
+	phone := Phone{Number: "020 7946 0958"}
+	if got := phone.Format(PhoneFormatInternational); got != "020 7946 0958" {
+		t.Errorf("Expected number without region to be unchanged, got %q", got)
+	}
+	if got := phone.Format(PhoneFormatRFC3966); got != "020 7946 0958" {
+		t.Errorf("Expected no tel URI without a region, got %q", got)
+	}
+
+	if got := phone.FormatRegion(PhoneFormatInternational, "gb"); got != "+44 20 7946 0958" {
+		t.Errorf("Expected region to be inferred, got %q", got)
+	}
+	if got := phone.FormatRegion(PhoneFormatNational, "gb"); got != "020 7946 0958" {
+		t.Errorf("Expected national format, got %q", got)
+	}
+
+	card := New().SetOptions(CardOptions{PhoneRegion: "GB"}).AddName("Jane", "Doe").AddPhone(phone.Number)
+	if got := card.Preview().Phone; got != "+44 20 7946 0958" {
+		t.Errorf("Expected the card's region in the preview, got %q", got)
+	}
+
+	if got := (Phone{Number: "555.123.4567"}).FormatRegion(PhoneFormatRFC3966, "US"); got != "tel:+1-555-123-4567" {
+		t.Errorf("Expected US tel URI, got %q", got)
+	}
+	us := Phone{Number: "1 (555) 123-4567"}
+	if got := us.FormatRegion(PhoneFormatInternational, "US"); got != "+1 555 123 4567" {
+		t.Errorf("Expected the leading 1 to be read as the trunk prefix, got %q", got)
+	}
+	if got := us.FormatRegion(PhoneFormatNational, "US"); got != "(555) 123-4567" {
+		t.Errorf("Expected US national format, got %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package vcard
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for photo dimensions
+	_ "image/jpeg" // register JPEG for photo dimensions
+	_ "image/png"  // register PNG for photo dimensions
+	"strconv"
+	"strings"
+)
+
+// CropParam is the PHOTO parameter Apple Contacts stores the photo framing in
+const CropParam = "X-ABCROP-RECTANGLE"
+
+// cropPrefix starts every X-ABCROP-RECTANGLE value
+const cropPrefix = "ABClipRect_1"
+
+// CropRect is the framing of a contact photo, written by Apple Contacts as
+// "ABClipRect_1&x&y&width&height&checksum". Coordinates are in image pixels
+// and may be negative when the photo was zoomed out. Apple measures Y from
+// the bottom edge of the image.
+type CropRect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+
+	// Checksum is the base64 MD5 digest of the image the rectangle belongs
+	// to; Apple devices ignore rectangles that do not match the photo
+	Checksum string
+}
+
+// String encodes the rectangle as an X-ABCROP-RECTANGLE value
+func (r CropRect) String() string {
+	return strings.Join([]string{
+		cropPrefix,
+		strconv.Itoa(r.X),
+		strconv.Itoa(r.Y),
+		strconv.Itoa(r.Width),
+		strconv.Itoa(r.Height),
+		r.Checksum,
+	}, "&")
+}
+
+// ParseCropRect parses an X-ABCROP-RECTANGLE value. The checksum is
+// optional.
+func ParseCropRect(value string) (CropRect, error) {
+	parts := strings.Split(value, "&")
+	if len(parts) < 5 || len(parts) > 6 || parts[0] != cropPrefix {
+		return CropRect{}, fmt.Errorf("invalid crop rectangle: %q", value)
+	}
+
+	var numbers [4]int
+	for i, part := range parts[1:5] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return CropRect{}, fmt.Errorf("invalid crop rectangle: %q", value)
+		}
+		numbers[i] = n
+	}
+	if numbers[2] <= 0 || numbers[3] <= 0 {
+		return CropRect{}, fmt.Errorf("invalid crop rectangle size: %q", value)
+	}
+
+	rect := CropRect{X: numbers[0], Y: numbers[1], Width: numbers[2], Height: numbers[3]}
+	if len(parts) == 6 {
+		rect.Checksum = parts[5]
+	}
+	return rect, nil
+}
+
+// SetPhotoCrop sets the framing of the photo, written as the
+// X-ABCROP-RECTANGLE parameter of PHOTO
+func (v *VCard) SetPhotoCrop(rect CropRect) *VCard {
+	v.photoCrop = &rect
+	return v
+}
+
+// GetPhotoCrop returns a copy of the photo framing if set
+func (v *VCard) GetPhotoCrop() *CropRect {
+	if v.photoCrop == nil {
+		return nil
+	}
+	rect := *v.photoCrop
+	return &rect
+}
+
+// RemovePhotoCrop clears the photo framing
+func (v *VCard) RemovePhotoCrop() *VCard {
+	v.photoCrop = nil
+	return v
+}
+
+// GeneratePhotoCrop frames the inline photo with the largest centered square,
+// the framing Apple Contacts uses for new photos. The photo must be a JPEG,
+// PNG or GIF data URI or base64 data; remote photos are not fetched.
+func (v *VCard) GeneratePhotoCrop() error {
+	data, err := v.photoData()
+	if err != nil {
+		return err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("reading photo dimensions: %w", err)
+	}
+
+	side := min(config.Width, config.Height)
+	sum := md5.Sum(data)
+	v.photoCrop = &CropRect{
+		X:        (config.Width - side) / 2,
+		Y:        (config.Height - side) / 2,
+		Width:    side,
+		Height:   side,
+		Checksum: base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	return nil
+}
+
+// photoData returns the bytes of the inline photo
+func (v *VCard) photoData() ([]byte, error) {
+	switch {
+	case v.photo == "":
+		return nil, fmt.Errorf("card has no photo")
+	case strings.HasPrefix(v.photo, "data:"):
+		_, data, err := DecodeDataURI(v.photo)
+		return data, err
+	case urlScheme(v.photo) != "":
+		return nil, fmt.Errorf("photo is a remote URL, not inline data")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(v.photo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid photo data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,117 @@
+package vcard
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestCropRectString(t *testing.T) {
+	rect := CropRect{X: -38, Y: 0, Width: 476, Height: 476, Checksum: "nrC4HnzsVFuUsG+pNEG3Aw=="}
+	value := rect.String()
+	if value != "ABClipRect_1&-38&0&476&476&nrC4HnzsVFuUsG+pNEG3Aw==" {
+		t.Errorf("Unexpected value %q", value)
+	}
+
+	parsed, err := ParseCropRect(value)
+	if err != nil || parsed != rect {
+		t.Errorf("ParseCropRect(%q) = %+v, %v", value, parsed, err)
+	}
+
+	if parsed, err := ParseCropRect("ABClipRect_1&0&10&200&200"); err != nil || parsed.Checksum != "" || parsed.Y != 10 {
+		t.Errorf("Expected a rectangle without checksum, got %+v, %v", parsed, err)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"ABClipRect_2&0&0&1&1&x",
+		"ABClipRect_1&0&0&1",
+		"ABClipRect_1&a&0&1&1&x",
+		"ABClipRect_1&0&0&0&1&x",
+		"ABClipRect_1&0&0&1&1&x&y",
+	} {
+		if _, err := ParseCropRect(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestPhotoCropWritten(t *testing.T) {
+	rect := CropRect{X: 10, Y: 20, Width: 100, Height: 100, Checksum: "abc="}
+	card := New().AddName("Jane", "Doe").AddPhoto("/9j/4AAQ").SetPhotoCrop(rect)
+
+	content, _ := card.String()
+	content = strings.ReplaceAll(content, "\r\n ", "")
+	if !strings.Contains(content, "PHOTO;ENCODING=b;TYPE=JPEG;X-ABCROP-RECTANGLE=ABClipRect_1&10&20&100&100&abc=:") {
+		t.Errorf("Expected crop parameter in:\n%s", content)
+	}
+
+	card.SetVersion(Version40).AddPhoto("https://example.com/jane.jpg")
+	content, _ = card.String()
+	content = strings.ReplaceAll(content, "\r\n ", "")
+	if !strings.Contains(content, "PHOTO;VALUE=uri;X-ABCROP-RECTANGLE=ABClipRect_1&10&20&100&100&abc=:") {
+		t.Errorf("Expected crop parameter in:\n%s", content)
+	}
+
+	got := card.GetPhotoCrop()
+	got.X = 99
+	if card.GetPhotoCrop().X != 10 || card.Clone().GetPhotoCrop().X != 10 {
+		t.Error("Expected the crop rectangle to be copied")
+	}
+
+	if card.RemovePhotoCrop().GetPhotoCrop() != nil {
+		t.Error("Expected RemovePhotoCrop to clear the rectangle")
+	}
+	if card.SetPhotoCrop(rect).RemovePhoto().GetPhotoCrop() != nil {
+		t.Error("Expected RemovePhoto to clear the rectangle")
+	}
+}
+
+func TestGeneratePhotoCrop(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 300, 200))); err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum(buf.Bytes())
+
+	card := New().AddName("Jane", "Doe").AddPhoto(EncodeDataURI("image/png", buf.Bytes()))
+	if err := card.GeneratePhotoCrop(); err != nil {
+		t.Fatalf("GeneratePhotoCrop() returned error: %v", err)
+	}
+	expected := CropRect{X: 50, Y: 0, Width: 200, Height: 200, Checksum: base64.StdEncoding.EncodeToString(sum[:])}
+	if got := card.GetPhotoCrop(); got == nil || *got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	card.AddPhoto(base64.StdEncoding.EncodeToString(buf.Bytes())).RemovePhotoCrop()
+	if err := card.GeneratePhotoCrop(); err != nil || card.GetPhotoCrop() == nil {
+		t.Errorf("Expected base64 photo to be framed, got %v", err)
+	}
+
+	for _, photo := range []string{"", "https://example.com/jane.jpg", "not base64!", EncodeDataURI("image/png", []byte("no image"))} {
+		if err := New().AddPhoto(photo).GeneratePhotoCrop(); err == nil {
+			t.Errorf("Expected error for photo %q", photo)
+		}
+	}
+}
+
+func TestPhotoCropRead(t *testing.T) {
+	data, err := fs.ReadFile(Corpus(), "apple-contacts.vcf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := Parse(string(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	expected := CropRect{Width: 1, Height: 1, Checksum: "d1qib3UQvoCYfcTWPpwR6A=="}
+	if got := card.GetPhotoCrop(); got == nil || *got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
@@ -0,0 +1,467 @@
+// Package photoproxy serves contact photos from the application's own
+// origin, so contact pages do not hotlink third-party avatars.
+//
+// A Proxy downloads a card's remote PHOTO URL with a timeout and size limit,
+// optionally scales it down, and caches the result. Inline photos (data URIs
+// and base64 data) are served the same way. Downloads from private and
+// loopback addresses are refused unless AllowPrivateNetworks is set, so the
+// proxy cannot be pointed at internal services:
+//
+//	proxy := photoproxy.New()
+//	http.Handle("/contacts/{id}/photo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		proxy.Serve(w, r, lookup(r.PathValue("id")))
+//	}))
+//
+// The framework adapters wrap Serve as their Photo handlers.
+package photoproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // register GIF for decoding
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/retry"
+)
+
+const (
+	// DefaultTimeout limits each photo download
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultMaxSize is the largest photo downloaded, in bytes
+	DefaultMaxSize = 2 << 20
+
+	// DefaultMaxDimension is the largest width or height requested through
+	// SizeParam
+	DefaultMaxDimension = 1024
+
+	// DefaultCacheTTL is how long photos are cached and may be cached by
+	// clients
+	DefaultCacheTTL = time.Hour
+
+	// DefaultCacheEntries is the number of photos kept in the cache
+	DefaultCacheEntries = 256
+
+	// SizeParam is the query parameter holding the requested size in pixels
+	SizeParam = "size"
+)
+
+// maxPixels rejects images that would decompress to huge bitmaps
+const maxPixels = 40_000_000
+
+var (
+	// ErrNoPhoto is returned when the card has no photo
+	ErrNoPhoto = errors.New("photoproxy: card has no photo")
+
+	// ErrTooLarge is returned for photos over the size or pixel limit
+	ErrTooLarge = errors.New("photoproxy: photo too large")
+
+	// ErrNotImage is returned when the photo is not an image
+	ErrNotImage = errors.New("photoproxy: photo is not an image")
+
+	// ErrForbiddenAddress is returned for photo URLs resolving to private,
+	// loopback or link-local addresses
+	ErrForbiddenAddress = errors.New("photoproxy: photo URL resolves to a private address")
+
+	// ErrUpstream is returned when the photo host answers with an error status
+	ErrUpstream = errors.New("photoproxy: photo host returned an error")
+)
+
+// Photo is a served photo
+type Photo struct {
+	// MediaType is the image MIME type, e.g. image/jpeg
+	MediaType string
+
+	// Data is the image content
+	Data []byte
+}
+
+// Proxy downloads, scales and caches contact photos. The zero value is not
+// usable; create proxies with New.
+type Proxy struct {
+	// Client performs the downloads. The default client, created on first
+	// use, enforces Timeout and refuses private addresses; a custom client is
+	// used as is.
+	Client *http.Client
+
+	// Timeout limits each download
+	Timeout time.Duration
+
+	// MaxSize limits the photo size in bytes
+	MaxSize int64
+
+	// MaxDimension caps the size requested through SizeParam
+	MaxDimension int
+
+	// CacheTTL is how long photos are cached, also sent as Cache-Control
+	// max-age
+	CacheTTL time.Duration
+
+	// CacheEntries is the number of photos kept in the cache; 0 disables
+	// caching
+	CacheEntries int
+
+	// Retry downloads photos again after network errors and retryable
+	// statuses (see retry.Status); nil makes a single attempt. Timeout
+	// applies to each attempt.
+	Retry *retry.Policy
+
+	// AllowPrivateNetworks permits photo URLs resolving to private, loopback
+	// and link-local addresses
+	AllowPrivateNetworks bool
+
+	mu            sync.Mutex
+	cache         map[string]cacheEntry
+	defaultClient *http.Client
+	clientOnce    sync.Once
+}
+
+// cacheEntry is a cached photo and its expiry
+type cacheEntry struct {
+	photo   *Photo
+	expires time.Time
+}
+
+// New creates a proxy with the default limits
+func New() *Proxy {
+	return &Proxy{
+		Timeout:      DefaultTimeout,
+		MaxSize:      DefaultMaxSize,
+		MaxDimension: DefaultMaxDimension,
+		CacheTTL:     DefaultCacheTTL,
+		CacheEntries: DefaultCacheEntries,
+		cache:        make(map[string]cacheEntry),
+	}
+}
+
+// Serve writes the card's photo, scaled to the SizeParam query parameter
+// when given. Errors are answered with 404 (no photo), 504 (timeout) or 502.
+func (p *Proxy) Serve(w http.ResponseWriter, r *http.Request, card *vcard.VCard) {
+	photo, err := p.Photo(r.Context(), card, p.RequestedSize(r.URL.Query().Get(SizeParam)))
+	if err != nil {
+		http.Error(w, http.StatusText(StatusCode(err)), StatusCode(err))
+		return
+	}
+
+	for name, value := range p.Headers(photo) {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(photo.Data)
+}
+
+// Headers returns the response headers for the photo
+func (p *Proxy) Headers(photo *Photo) map[string]string {
+	return map[string]string{
+		"Content-Type":           photo.MediaType,
+		"Content-Length":         strconv.Itoa(len(photo.Data)),
+		"Cache-Control":          "public, max-age=" + strconv.Itoa(int(p.CacheTTL.Seconds())),
+		"X-Content-Type-Options": "nosniff",
+	}
+}
+
+// RequestedSize parses a SizeParam value, capped at MaxDimension. Invalid
+// and missing values give 0, the original size.
+func (p *Proxy) RequestedSize(value string) int {
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		return 0
+	}
+	return min(size, p.MaxDimension)
+}
+
+// StatusCode returns the HTTP status answering an error from Photo
+func StatusCode(err error) int {
+	var timeout net.Error
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrNoPhoto):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.As(err, &timeout) && timeout.Timeout():
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// Photo returns the card's photo, scaled down to fit size x size pixels when
+// size is positive
+func (p *Proxy) Photo(ctx context.Context, card *vcard.VCard, size int) (*Photo, error) {
+	if card == nil || card.GetPhoto() == "" {
+		return nil, ErrNoPhoto
+	}
+	source := card.GetPhoto()
+	key := strconv.Itoa(size) + " " + source
+
+	if photo := p.cached(key); photo != nil {
+		return photo, nil
+	}
+
+	photo, err := p.load(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 {
+		if photo, err = scale(photo, size); err != nil {
+			return nil, err
+		}
+	}
+
+	p.store(key, photo)
+	return photo, nil
+}
+
+// rasterTypes are the served media types. Types are sniffed from the data,
+// never taken from the photo host, and SVG is excluded as it can carry
+// scripts running on the serving origin.
+var rasterTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/bmp":  true,
+}
+
+// load reads an inline photo or downloads a remote one
+func (p *Proxy) load(ctx context.Context, source string) (*Photo, error) {
+	var photo *Photo
+	switch {
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		var err error
+		if photo, err = p.download(ctx, source); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(source, "data:"):
+		_, data, err := vcard.DecodeDataURI(source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+		}
+		photo = &Photo{MediaType: http.DetectContentType(data), Data: data}
+	default:
+		data, err := base64.StdEncoding.DecodeString(source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+		}
+		photo = &Photo{MediaType: http.DetectContentType(data), Data: data}
+	}
+
+	if !rasterTypes[photo.MediaType] {
+		return nil, fmt.Errorf("%w: %s", ErrNotImage, photo.MediaType)
+	}
+	if int64(len(photo.Data)) > p.MaxSize {
+		return nil, ErrTooLarge
+	}
+	return photo, nil
+}
+
+// download fetches a remote photo within the time and size limits, retrying
+// transient failures under the Retry policy
+func (p *Proxy) download(ctx context.Context, source string) (*Photo, error) {
+	var photo *Photo
+	err := p.Retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		photo, err = p.fetch(ctx, source)
+		return err
+	})
+	return photo, err
+}
+
+// fetch makes a single download attempt. Errors a retry can't fix are marked
+// permanent.
+func (p *Proxy) fetch(ctx context.Context, source string) (*Photo, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, retry.Permanent(fmt.Errorf("photoproxy: %w", err))
+	}
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		if errors.Is(err, ErrForbiddenAddress) {
+			return nil, retry.Permanent(ErrForbiddenAddress)
+		}
+		return nil, fmt.Errorf("photoproxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("%w: %s", ErrUpstream, resp.Status)
+		if !retry.Status(resp.StatusCode) {
+			return nil, retry.Permanent(err)
+		}
+		return nil, err
+	}
+	if resp.ContentLength > p.MaxSize {
+		return nil, retry.Permanent(ErrTooLarge)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, p.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("photoproxy: %w", err)
+	}
+	if int64(len(data)) > p.MaxSize {
+		return nil, retry.Permanent(ErrTooLarge)
+	}
+
+	return &Photo{MediaType: http.DetectContentType(data), Data: data}, nil
+}
+
+// client returns the configured client or one refusing private addresses
+func (p *Proxy) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+
+	p.clientOnce.Do(func() {
+		dialer := &net.Dialer{Timeout: p.Timeout}
+		if !p.AllowPrivateNetworks {
+			dialer.Control = refusePrivate
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = dialer.DialContext
+		p.defaultClient = &http.Client{Transport: transport, Timeout: p.Timeout}
+	})
+	return p.defaultClient
+}
+
+// refusePrivate rejects connections to private addresses. It runs after
+// name resolution, so DNS answers pointing at internal hosts are caught too.
+func refusePrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// cached returns an unexpired cached photo
+func (p *Proxy) cached(key string) *Photo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.cache[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(p.cache, key)
+		return nil
+	}
+	return entry.photo
+}
+
+// store caches the photo, evicting the entry closest to expiry when full
+func (p *Proxy) store(key string, photo *Photo) {
+	if p.CacheEntries <= 0 || p.CacheTTL <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cache == nil {
+		p.cache = make(map[string]cacheEntry)
+	}
+	if len(p.cache) >= p.CacheEntries {
+		var oldest string
+		for k, entry := range p.cache {
+			if oldest == "" || entry.expires.Before(p.cache[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(p.cache, oldest)
+	}
+	p.cache[key] = cacheEntry{photo: photo, expires: time.Now().Add(p.CacheTTL)}
+}
+
+// scale shrinks the photo to fit size x size pixels, averaging the source
+// pixels covered by each target pixel. Smaller photos and formats that
+// cannot be decoded (e.g. WebP) are returned unchanged. PNG and GIF photos
+// become PNG, others JPEG.
+func scale(photo *Photo, size int) (*Photo, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(photo.Data))
+	if err != nil {
+		return photo, nil
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	if config.Width <= size && config.Height <= size {
+		return photo, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(photo.Data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	width, height := size, size
+	if config.Width > config.Height {
+		height = max(1, config.Height*size/config.Width)
+	} else {
+		width = max(1, config.Width*size/config.Height)
+	}
+
+	rgba := image.NewRGBA(src.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*config.Height/height, max((y+1)*config.Height/height, y*config.Height/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*config.Width/width, max((x+1)*config.Width/width, x*config.Width/width+1)
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := rgba.PixOffset(rgba.Rect.Min.X+sx, rgba.Rect.Min.Y+sy)
+					r += int(rgba.Pix[i])
+					g += int(rgba.Pix[i+1])
+					b += int(rgba.Pix[i+2])
+					a += int(rgba.Pix[i+3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		err = png.Encode(&buf, dst)
+		photo = &Photo{MediaType: "image/png"}
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		photo = &Photo{MediaType: "image/jpeg"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("photoproxy: %w", err)
+	}
+	photo.Data = buf.Bytes()
+	return photo, nil
+}
@@ -0,0 +1,256 @@
+package photoproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/retry"
+)
+
+// testPNG returns a PNG image of the given size
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testProxy returns a proxy allowed to reach the local test servers
+func testProxy() *Proxy {
+	proxy := New()
+	proxy.AllowPrivateNetworks = true
+	return proxy
+}
+
+func TestPhotoRemoteAndCache(t *testing.T) {
+	data := testPNG(t, 300, 200)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	proxy := testProxy()
+	card := vcard.New().AddName("Jane", "Doe").AddPhoto(server.URL + "/jane.png")
+
+	photo, err := proxy.Photo(context.Background(), card, 0)
+	if err != nil {
+		t.Fatalf("Photo() returned error: %v", err)
+	}
+	if photo.MediaType != "image/png" || !bytes.Equal(photo.Data, data) {
+		t.Errorf("Unexpected photo %s of %d bytes", photo.MediaType, len(photo.Data))
+	}
+
+	if _, err := proxy.Photo(context.Background(), card, 0); err != nil {
+		t.Fatalf("Photo() returned error: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected one download, got %d", requests.Load())
+	}
+
+	scaled, err := proxy.Photo(context.Background(), card, 100)
+	if err != nil {
+		t.Fatalf("Photo() returned error: %v", err)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(scaled.Data))
+	if err != nil {
+		t.Fatalf("Scaled photo is not a PNG: %v", err)
+	}
+	if config.Width != 100 || config.Height != 66 {
+		t.Errorf("Expected 100x66, got %dx%d", config.Width, config.Height)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected a second download for the new size, got %d", requests.Load())
+	}
+}
+
+func TestPhotoLimits(t *testing.T) {
+	data := testPNG(t, 64, 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			w.Write(data)
+		case "/page":
+			w.Write([]byte("<html><body>not a photo</body></html>"))
+		case "/svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		setup  func(*Proxy)
+		err    error
+		status int
+	}{
+		{"too large", "/photo", func(p *Proxy) { p.MaxSize = 100 }, ErrTooLarge, http.StatusBadGateway},
+		{"not an image", "/page", nil, ErrNotImage, http.StatusBadGateway},
+		{"svg", "/svg", nil, ErrNotImage, http.StatusBadGateway},
+		{"upstream error", "/missing", nil, ErrUpstream, http.StatusBadGateway},
+		{"timeout", "/slow", func(p *Proxy) { p.Timeout = 20 * time.Millisecond }, nil, http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := testProxy()
+			if tt.setup != nil {
+				tt.setup(proxy)
+			}
+
+			_, err := proxy.Photo(context.Background(), vcard.New().AddPhoto(server.URL+tt.path), 0)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+			if StatusCode(err) != tt.status {
+				t.Errorf("Expected status %d, got %d for %v", tt.status, StatusCode(err), err)
+			}
+		})
+	}
+}
+
+func TestPhotoRetry(t *testing.T) {
+	data := testPNG(t, 16, 16)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case n == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	proxy := testProxy()
+	proxy.Retry = &retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+
+	if _, err := proxy.Photo(context.Background(), vcard.New().AddPhoto(server.URL+"/photo.png"), 0); err != nil {
+		t.Fatalf("Photo() returned error: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected one retry, got %d requests", requests.Load())
+	}
+
+	requests.Store(0)
+	if _, err := proxy.Photo(context.Background(), vcard.New().AddPhoto(server.URL+"/missing"), 0); !errors.Is(err, ErrUpstream) {
+		t.Errorf("Expected ErrUpstream, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no retry of a 404, got %d requests", requests.Load())
+	}
+}
+
+func TestPhotoRefusesPrivateAddresses(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	_, err := New().Photo(context.Background(), vcard.New().AddPhoto(server.URL+"/photo.png"), 0)
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Expected ErrForbiddenAddress, got %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Error("Expected no request to reach the private address")
+	}
+}
+
+func TestPhotoInline(t *testing.T) {
+	data := testPNG(t, 8, 8)
+	proxy := New()
+
+	for _, source := range []string{
+		vcard.EncodeDataURI("image/png", data),
+		base64.StdEncoding.EncodeToString(data),
+	} {
+		photo, err := proxy.Photo(context.Background(), vcard.New().AddPhoto(source), 0)
+		if err != nil {
+			t.Fatalf("Photo() returned error: %v", err)
+		}
+		if photo.MediaType != "image/png" || !bytes.Equal(photo.Data, data) {
+			t.Errorf("Unexpected photo %s of %d bytes", photo.MediaType, len(photo.Data))
+		}
+	}
+
+	if _, err := proxy.Photo(context.Background(), vcard.New().AddPhoto("data:image/svg+xml;base64,"+base64.StdEncoding.EncodeToString([]byte("<svg/>"))), 0); !errors.Is(err, ErrNotImage) {
+		t.Errorf("Expected ErrNotImage for SVG, got %v", err)
+	}
+	if _, err := proxy.Photo(context.Background(), vcard.New().AddName("Jane", "Doe"), 0); !errors.Is(err, ErrNoPhoto) || StatusCode(err) != http.StatusNotFound {
+		t.Errorf("Expected ErrNoPhoto, got %v", err)
+	}
+	if _, err := proxy.Photo(context.Background(), nil, 0); !errors.Is(err, ErrNoPhoto) {
+		t.Errorf("Expected ErrNoPhoto for nil card, got %v", err)
+	}
+}
+
+func TestRequestedSize(t *testing.T) {
+	proxy := New()
+	tests := map[string]int{"": 0, "abc": 0, "-5": 0, "0": 0, "64": 64, "5000": DefaultMaxDimension}
+	for value, expected := range tests {
+		if got := proxy.RequestedSize(value); got != expected {
+			t.Errorf("RequestedSize(%q) = %d, expected %d", value, got, expected)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	proxy := New()
+	card := vcard.New().AddPhoto(vcard.EncodeDataURI("image/png", testPNG(t, 40, 20)))
+
+	w := httptest.NewRecorder()
+	proxy.Serve(w, httptest.NewRequest(http.MethodGet, "/photo?size=10", nil), card)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "image/png" || w.Header().Get("Cache-Control") != "public, max-age=3600" ||
+		w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("Unexpected headers: %v", w.Header())
+	}
+	config, err := png.DecodeConfig(w.Body)
+	if err != nil || config.Width != 10 || config.Height != 5 {
+		t.Errorf("Expected a 10x5 PNG, got %+v (%v)", config, err)
+	}
+
+	w = httptest.NewRecorder()
+	proxy.Serve(w, httptest.NewRequest(http.MethodGet, "/photo", nil), vcard.New())
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
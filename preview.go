@@ -0,0 +1,144 @@
+package vcard
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Preview is a UI-oriented summary of a card with preference and fallback
+// logic already applied. Text fields hold raw card values and must be
+// escaped by the renderer; URL and Avatar never hold script or non-image
+// data URIs, so they can be used as link and image targets.
+type Preview struct {
+	// DisplayName is the formatted name, falling back to organization or email
+	DisplayName string `json:"displayName"`
+
+	// Initials are up to two letters derived from the display name
+	Initials string `json:"initials"`
+
+	// Email is the preferred (or first) email address
+	Email string `json:"email,omitempty"`
+
+	// Phone is the preferred (or first) phone number in international format,
+	// reading numbers without a country code in the card's PhoneRegion
+	Phone string `json:"phone,omitempty"`
+
+	// URL is the preferred (or first) URL
+	URL string `json:"url,omitempty"`
+
+	// Avatar is the photo as a URL or data URI, or a generated monogram when
+	// the card has no photo
+	Avatar string `json:"avatar,omitempty"`
+
+	// Subtitle combines job title and organization ("Engineer, Acme Corp")
+	Subtitle string `json:"subtitle,omitempty"`
+
+	// Address is the preferred (or first) address on a single line
+	Address string `json:"address,omitempty"`
+}
+
+// Preview returns a summary of the card tailored for rendering in UIs
+func (v *VCard) Preview() Preview {
+	preview := Preview{
+		DisplayName: v.displayName(),
+		Avatar:      v.avatar(),
+	}
+	preview.Initials = initials(preview.DisplayName)
+
+	for i, email := range v.emails {
+		if i == 0 || email.Preferred {
+			preview.Email = email.Address
+		}
+		if email.Preferred {
+			break
+		}
+	}
+
+	for i, phone := range v.phones {
+		if i == 0 || phone.Preferred {
+			preview.Phone = phone.FormatRegion(PhoneFormatInternational, v.options.PhoneRegion)
+		}
+		if phone.Preferred {
+			break
+		}
+	}
+
+	for _, url := range v.urls {
+		if unsafeURLSchemes[urlScheme(url.Address)] {
+			continue
+		}
+		if preview.URL == "" || url.Preferred {
+			preview.URL = url.Address
+		}
+		if url.Preferred {
+			break
+		}
+	}
+
+	for i, addr := range v.addresses {
+		if i == 0 || addr.Preferred {
+			preview.Address = strings.ReplaceAll(addr.FormattedAddress(), "\n", ", ")
+		}
+		if addr.Preferred {
+			break
+		}
+	}
+
+	var subtitle []string
+	if v.organization.Title != "" {
+		subtitle = append(subtitle, v.organization.Title)
+	}
+	if v.organization.Name != "" && v.organization.Name != preview.DisplayName {
+		subtitle = append(subtitle, v.organization.Name)
+	}
+	preview.Subtitle = strings.Join(subtitle, ", ")
+
+	return preview
+}
+
+// displayName returns the formatted name with organization and email fallbacks
+func (v *VCard) displayName() string {
+	if name := v.formattedName(); name != "" {
+		return name
+	}
+	if v.organization.Name != "" {
+		return v.organization.Name
+	}
+	return v.GetEmail()
+}
+
+// avatar returns the photo as something a browser can load directly, falling
+// back to a monogram when there is no photo or it is not a web or image URL
+func (v *VCard) avatar() string {
+	scheme := urlScheme(v.photo)
+	switch {
+	case scheme == "http", scheme == "https":
+		return v.photo
+	case scheme == "data" && strings.HasPrefix(strings.ToLower(v.photo), "data:image/"):
+		return v.photo
+	case v.photo != "" && scheme == "":
+		return "data:" + defaultPhotoMIME + ";base64," + v.photo
+	case v.displayName() == "":
+		return ""
+	default:
+		return v.MonogramDataURI(DefaultMonogramSize)
+	}
+}
+
+// initials returns the upper-cased first letters of the first and last words
+func initials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	first := []rune(words[0])[0]
+	if len(words) == 1 {
+		return strings.ToUpper(string(first))
+	}
+
+	last := []rune(words[len(words)-1])[0]
+	return strings.ToUpper(string([]rune{first, last}))
+}
@@ -0,0 +1,81 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreview(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddEmail("john@example.com")
+	card.AddEmailWithPreference("j.doe@work.com", EmailWork, true)
+	card.AddPhone("+15551234567")
+	card.AddAddress("123 Main St", "Anytown", "CA", "12345", "USA")
+	card.AddOrganization("Acme Corp").AddTitle("Engineer")
+	card.AddPhoto("https://example.com/john.jpg")
+
+	preview := card.Preview()
+
+	if preview.DisplayName != "John Doe" {
+		t.Errorf("Expected display name 'John Doe', got %s", preview.DisplayName)
+	}
+	if preview.Initials != "JD" {
+		t.Errorf("Expected initials 'JD', got %s", preview.Initials)
+	}
+	if preview.Email != "j.doe@work.com" {
+		t.Errorf("Expected preferred email, got %s", preview.Email)
+	}
+	if preview.Phone != "+1 555 123 4567" {
+		t.Errorf("Expected first phone, got %s", preview.Phone)
+	}
+	if preview.Subtitle != "Engineer, Acme Corp" {
+		t.Errorf("Expected subtitle 'Engineer, Acme Corp', got %s", preview.Subtitle)
+	}
+	if preview.Address != "123 Main St, Anytown, CA, 12345, USA" {
+		t.Errorf("Unexpected address label: %s", preview.Address)
+	}
+	if preview.Avatar != "https://example.com/john.jpg" {
+		t.Errorf("Expected photo URL as avatar, got %s", preview.Avatar)
+	}
+}
+
+func TestPreviewFallbacks(t *testing.T) {
+	card := New()
+	card.AddOrganization("Acme Corp")
+	card.AddPhoto("aGVsbG8=")
+
+	preview := card.Preview()
+
+	if preview.DisplayName != "Acme Corp" {
+		t.Errorf("Expected organization as display name, got %s", preview.DisplayName)
+	}
+	if preview.Subtitle != "" {
+		t.Errorf("Organization should not repeat in subtitle, got %s", preview.Subtitle)
+	}
+	if preview.Initials != "AC" {
+		t.Errorf("Expected initials 'AC', got %s", preview.Initials)
+	}
+	if preview.Avatar != "data:image/jpeg;base64,aGVsbG8=" {
+		t.Errorf("Expected data URI avatar, got %s", preview.Avatar)
+	}
+
+	if New().Preview().Initials != "" {
+		t.Error("Empty card should have no initials")
+	}
+}
+
+func TestPreviewUnsafeValues(t *testing.T) {
+	card := New().AddName("John", "Doe").
+		AddPhoto("javascript:alert(1)").
+		AddURL("https://example.com")
+	card.urls = append([]URL{{Address: " javascript:alert(2)", Preferred: true}}, card.urls...)
+
+	preview := card.Preview()
+	if preview.URL != "https://example.com" {
+		t.Errorf("Expected unsafe URL to be skipped, got %s", preview.URL)
+	}
+	if !strings.HasPrefix(preview.Avatar, "data:image/svg+xml;base64,") {
+		t.Errorf("Expected monogram for unsafe photo, got %s", preview.Avatar)
+	}
+}
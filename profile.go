@@ -0,0 +1,363 @@
+package vcard
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Profile adapts the encoder output to the quirks of a specific client or
+// server. Use one of the predefined profiles with Encoder.Profile.
+type Profile struct {
+	// Name identifies the profile
+	Name string
+
+	// Version forces the vCard version; empty keeps the card's version
+	Version Version
+
+	// CRLF terminates lines with CRLF as required by RFC 2426 and RFC 6350
+	// instead of LF
+	CRLF bool
+
+	// RequireUID derives a stable UID from the card's FN, N, EMAIL and TEL
+	// values for cards that have none; CardDAV servers reject cards without
+	// a UID
+	RequireUID bool
+
+	// InlinePhotosOnly drops photos and logos given as remote URLs, which the
+	// client does not fetch
+	InlinePhotosOnly bool
+
+	// AppleGroups writes group cards in vCard 3.0 as
+	// X-ADDRESSBOOKSERVER-KIND:group, since KIND only exists in 4.0
+	AppleGroups bool
+
+	// MaxLengths limits the length in characters of FN, NOTE, ORG, TITLE,
+	// ROLE and custom property values (e.g. {"NOTE": 2000}) for clients that
+	// drop longer values on import
+	MaxLengths map[string]int
+
+	// TruncateLong truncates values longer than MaxLengths; otherwise
+	// encoding fails. Profile.Lint reports the affected values either way.
+	TruncateLong bool
+
+	// ChunkNotes writes notes longer than MaxLengths["NOTE"] as several NOTE
+	// properties, split after whitespace where possible, instead of
+	// truncating or rejecting them
+	ChunkNotes bool
+
+	// Properties, when set, lists the only properties written, in this
+	// order, for clients that render a strictly ordered minimal card best.
+	// BEGIN, VERSION and END are always written; other properties are
+	// dropped with a warning.
+	Properties []string
+
+	// AppleTypes writes the TEL and EMAIL types as Apple clients do in
+	// vCard 3.0: CELL for mobile numbers and TYPE=pref for the preferred
+	// value
+	AppleTypes bool
+
+	// MaxPhotoSize drops inline photos whose data exceeds this many bytes,
+	// which the client would fail to import; zero means no limit
+	MaxPhotoSize int
+}
+
+var (
+	// ProfileThunderbird targets the Thunderbird CardBook add-on, which
+	// downloads remote photos and reads both vCard versions
+	ProfileThunderbird = Profile{
+		Name:        "thunderbird",
+		CRLF:        true,
+		RequireUID:  true,
+		AppleGroups: true,
+	}
+
+	// ProfileSOGo targets SOGo, which stores vCard 3.0 and only displays
+	// inline photos
+	ProfileSOGo = Profile{
+		Name:             "sogo",
+		Version:          Version30,
+		CRLF:             true,
+		RequireUID:       true,
+		InlinePhotosOnly: true,
+		AppleGroups:      true,
+	}
+
+	// ProfileNextcloud targets Nextcloud Contacts, which stores vCard 3.0 and
+	// does not load remote photo URLs
+	ProfileNextcloud = Profile{
+		Name:             "nextcloud",
+		Version:          Version30,
+		CRLF:             true,
+		RequireUID:       true,
+		InlinePhotosOnly: true,
+		AppleGroups:      true,
+	}
+
+	// ProfileAppleShare targets the iOS share sheet and AirDrop contact
+	// import, which render a minimal vCard 3.0 card best: name, phone
+	// numbers, email addresses and an inline photo of at most 256 KiB, in
+	// that order. Anything else is dropped, as it is either hidden on the
+	// contact poster or makes the import show a generic card.
+	ProfileAppleShare = Profile{
+		Name:             "apple-share",
+		Version:          Version30,
+		CRLF:             true,
+		InlinePhotosOnly: true,
+		AppleTypes:       true,
+		Properties:       []string{"N", "FN", "ORG", "TITLE", "TEL", "EMAIL", "PHOTO"},
+		MaxPhotoSize:     256 << 10,
+	}
+)
+
+// apply returns a copy of the card adjusted to the profile and warnings for
+// the values it drops
+func (p Profile) apply(card *VCard) (*VCard, []Warning) {
+	card = card.Clone()
+
+	if p.Version != "" {
+		card.SetVersion(p.Version)
+	}
+
+	var warnings []Warning
+	if p.InlinePhotosOnly && card.photoValueType() == ValueURI && !strings.HasPrefix(card.photo, "data:") {
+		card.photo = ""
+		card.photoCrop = nil
+		warnings = append(warnings, Warning{Property: "PHOTO", Message: "remote photo URL is not loaded by the client and is dropped"})
+	}
+	if p.InlinePhotosOnly && card.mediaValueType(card.logo) == ValueURI && !strings.HasPrefix(card.logo, "data:") {
+		card.logo = ""
+		warnings = append(warnings, Warning{Property: "LOGO", Message: "remote logo URL is not loaded by the client and is dropped"})
+	}
+
+	if p.MaxPhotoSize > 0 && strings.HasPrefix(card.photo, "data:") {
+		if _, data, err := DecodeDataURI(card.photo); err != nil || len(data) > p.MaxPhotoSize {
+			card.photo = ""
+			card.photoCrop = nil
+			warnings = append(warnings, Warning{Property: "PHOTO", Message: fmt.Sprintf("inline photo exceeds %d bytes and is dropped", p.MaxPhotoSize)})
+		}
+	}
+
+	if p.AppleGroups && card.kind == KindGroup && card.version != Version40 {
+		card.AddCustomProperty("X-ADDRESSBOOKSERVER-KIND", "group")
+	}
+
+	if p.RequireUID && card.uid == "" {
+		card.uid = identityUID(card)
+	}
+
+	return card, warnings
+}
+
+// Lint returns the card's warnings followed by the values exceeding the
+// profile's MaxLengths
+func (p Profile) Lint(card *VCard) []Warning {
+	return append(card.Lint(), p.limitValues(card.Clone(), false)...)
+}
+
+// limit enforces MaxLengths on a card already adjusted by apply and returns
+// warnings for the truncated values
+func (p Profile) limit(card *VCard) ([]Warning, error) {
+	warnings := p.limitValues(card, p.TruncateLong)
+	if len(warnings) > 0 && !p.TruncateLong {
+		return nil, fmt.Errorf("profile %s: %s", p.Name, warnings[0])
+	}
+	return warnings, nil
+}
+
+// limitedValue is a card value subject to MaxLengths
+type limitedValue struct {
+	property string
+	value    *string
+}
+
+// limitValues returns a warning for every value longer than its MaxLengths
+// entry, truncating the value in the card when truncate is set
+func (p Profile) limitValues(card *VCard, truncate bool) []Warning {
+	if len(p.MaxLengths) == 0 {
+		return nil
+	}
+
+	fn := card.formattedName()
+	fields := []limitedValue{
+		{"FN", &fn},
+		{"NOTE", &card.note},
+		{"ORG", &card.organization.Name},
+		{"TITLE", &card.organization.Title},
+		{"ROLE", &card.organization.Role},
+	}
+
+	names := make([]string, 0, len(card.customProps))
+	for name := range card.customProps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	custom := make([]string, len(names))
+	for i, name := range names {
+		custom[i] = card.customProps[name]
+		fields = append(fields, limitedValue{strings.ToUpper(name), &custom[i]})
+	}
+
+	var warnings []Warning
+	for _, field := range fields {
+		if p.ChunkNotes && field.property == "NOTE" {
+			continue
+		}
+		limit := p.maxLength(field.property)
+		length := utf8.RuneCountInString(*field.value)
+		if limit <= 0 || length <= limit {
+			continue
+		}
+
+		action := "is rejected"
+		if truncate {
+			action = "is truncated"
+			*field.value = truncateRunes(*field.value, limit)
+		}
+		warnings = append(warnings, Warning{
+			Property: field.property,
+			Message:  fmt.Sprintf("%d characters exceed the limit of %d and %s", length, limit, action),
+		})
+	}
+
+	if truncate {
+		if fn != card.formattedName() {
+			card.fn = fn
+		}
+		for i, name := range names {
+			card.customProps[name] = custom[i]
+		}
+	}
+
+	return warnings
+}
+
+// maxLength returns the MaxLengths entry of the property, ignoring case
+func (p Profile) maxLength(property string) int {
+	for name, limit := range p.MaxLengths {
+		if strings.EqualFold(name, property) {
+			return limit
+		}
+	}
+	return 0
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	i := 0
+	for offset := range s {
+		if i == n {
+			return s[:offset]
+		}
+		i++
+	}
+	return s
+}
+
+// order keeps the Properties of the encoded card in the listed order,
+// applies AppleTypes and returns warnings for the properties it drops
+func (p Profile) order(content string) (string, []Warning) {
+	if len(p.Properties) == 0 && !p.AppleTypes {
+		return content, nil
+	}
+
+	byName := make(map[string][]string)
+	var lines, dropped []string
+	for _, line := range unfoldLines(content) {
+		property, err := ParseProperty(line)
+		if err != nil {
+			continue
+		}
+		if p.AppleTypes && (property.Name == "TEL" || property.Name == "EMAIL") {
+			line = appleTypes(property)
+		}
+
+		switch name := property.Name; {
+		case name == "BEGIN", name == "VERSION", len(p.Properties) == 0:
+			lines = append(lines, line)
+		case name == "END":
+		case slices.ContainsFunc(p.Properties, func(listed string) bool { return strings.EqualFold(listed, name) }):
+			byName[name] = append(byName[name], line)
+		case !slices.Contains(dropped, name):
+			dropped = append(dropped, name)
+		}
+	}
+
+	for _, name := range p.Properties {
+		lines = append(lines, byName[strings.ToUpper(name)]...)
+	}
+	if len(p.Properties) > 0 {
+		lines = append(lines, "END:VCARD")
+	}
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(foldLine(line) + "\n")
+	}
+
+	warnings := make([]Warning, len(dropped))
+	for i, name := range dropped {
+		warnings[i] = Warning{Property: name, Message: fmt.Sprintf("is not part of the %s profile and is dropped", p.Name)}
+	}
+	return builder.String(), warnings
+}
+
+// appleTypes rewrites the parameters of a TEL or EMAIL line as Apple
+// clients write them in vCard 3.0: mobile numbers as CELL and the preferred
+// value as TYPE=pref instead of PREF=1
+func appleTypes(property ContentLine) string {
+	params := property.Params
+	types := params.Get("TYPE")
+	for i, value := range types {
+		if strings.EqualFold(value, "MOBILE") {
+			types[i] = "CELL"
+		}
+	}
+	if len(params.Get("PREF")) > 0 {
+		params.Del("PREF")
+		types = append(types, "pref")
+	}
+	if len(types) > 0 {
+		params.Set("TYPE", types...)
+	}
+
+	line := property.Name + params.String() + ":" + property.Value
+	if property.Group != "" {
+		line = property.Group + "." + line
+	}
+	return line
+}
+
+// finish applies the profile's line ending to the encoded card
+func (p Profile) finish(content string) string {
+	if !p.CRLF {
+		return content
+	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\n", "\r\n")
+}
+
+// identityUID returns a UID derived from the identity fields of the card:
+// FN, N, EMAIL and TEL. Other properties don't affect it, so the UID stays
+// the same when the card gains a note or a photo.
+func identityUID(card *VCard) string {
+	fields := []string{"FN:" + card.formattedName(), "N:" + card.name.StructuredName()}
+	for _, email := range card.emails {
+		fields = append(fields, "EMAIL:"+strings.ToLower(email.Address))
+	}
+	for _, phone := range card.phones {
+		fields = append(fields, "TEL:"+phone.Number)
+	}
+	return derivedUID(strings.Join(fields, "\n"))
+}
+
+// derivedUID returns a name-based (version 5 style) UUID URN for the content
+func derivedUID(content string) string {
+	sum := sha1.Sum([]byte(content))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,245 @@
+package vcard
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateFixtures = flag.Bool("update", false, "rewrite the profile fixtures in testdata")
+
+// profileCases are the cards checked against every profile fixture
+var profileCases = map[string]func() *VCard{
+	"person": func() *VCard {
+		return New().
+			AddName("John", "Doe").
+			AddEmail("john@example.com").
+			AddPhone("+1234567890", PhoneMobile).
+			AddPhoto("https://example.com/john.jpg").
+			AddCustomProperty("CATEGORIES", "Friends")
+	},
+	"inline-photo": func() *VCard {
+		return New().
+			AddName("Jane", "Doe").
+			SetUID("urn:uuid:2b4a1c2e-0d5f-4f47-9d7e-3c1a4e2f6b8a").
+			AddPhoto("data:image/png;base64,iVBORw0KGgo=")
+	},
+	"group": func() *VCard {
+		return NewWithVersion(Version40).
+			SetKind(KindGroup).
+			SetFormattedName("Engineering Team").
+			SetUID("urn:uuid:9f0e4a6b-5d2c-4e1f-8a3b-7c6d5e4f3a2b")
+	},
+}
+
+func TestProfileFixtures(t *testing.T) {
+	profiles := []Profile{ProfileThunderbird, ProfileSOGo, ProfileNextcloud, ProfileAppleShare}
+
+	for _, profile := range profiles {
+		for name, build := range profileCases {
+			var buf bytes.Buffer
+			if err := NewEncoder(&buf).Profile(profile).Encode(build()); err != nil {
+				t.Fatalf("%s/%s: Encode() returned error: %v", profile.Name, name, err)
+			}
+
+			fixture := filepath.Join("testdata", "profiles", profile.Name+"-"+name+".vcf")
+			if *updateFixtures {
+				if err := os.WriteFile(fixture, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("Failed to write fixture: %v", err)
+				}
+				continue
+			}
+
+			expected, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+			if buf.String() != string(expected) {
+				t.Errorf("%s/%s: output differs from %s:\n%s", profile.Name, name, fixture, buf.String())
+			}
+		}
+	}
+}
+
+func TestProfileQuirks(t *testing.T) {
+	person := profileCases["person"]()
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Profile(ProfileNextcloud).Encode(person); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	content := buf.String()
+
+	if strings.Contains(content, "PHOTO") {
+		t.Error("Expected remote photo to be dropped for Nextcloud")
+	}
+	if !strings.Contains(content, "\r\nUID:urn:uuid:") {
+		t.Error("Expected a derived UID")
+	}
+	if strings.Count(content, "\n") != strings.Count(content, "\r\n") {
+		t.Error("Expected CRLF line endings only")
+	}
+	if person.GetUID() != "" || person.GetPhoto() == "" {
+		t.Error("Expected the profile not to modify the original card")
+	}
+
+	// The derived UID is stable across encodings
+	var again bytes.Buffer
+	NewEncoder(&again).Profile(ProfileNextcloud).Encode(profileCases["person"]())
+	if again.String() != content {
+		t.Error("Expected derived UID to be deterministic")
+	}
+
+	// It depends on the identity fields only
+	noted := profileCases["person"]().AddNote("Met at the conference").AddCustomProperty("X-ALPHA", "a").AddCustomProperty("X-BETA", "b")
+	if uid := deriveUID(t, noted); uid != deriveUID(t, profileCases["person"]()) {
+		t.Errorf("Expected the UID not to depend on NOTE or custom properties, got %s", uid)
+	}
+	if deriveUID(t, profileCases["person"]().AddEmail("john@work.example.com")) == deriveUID(t, profileCases["person"]()) {
+		t.Error("Expected the UID to depend on EMAIL")
+	}
+
+	group := profileCases["group"]()
+	buf.Reset()
+	if err := NewEncoder(&buf).Profile(ProfileSOGo).Encode(group); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "X-ADDRESSBOOKSERVER-KIND:group\r\n") || strings.Contains(buf.String(), "KIND:group\r\nFN") {
+		t.Errorf("Expected Apple-style group for vCard 3.0, got %s", buf.String())
+	}
+}
+
+// deriveUID returns the UID the Nextcloud profile derives for the card
+func deriveUID(t *testing.T, card *VCard) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Profile(ProfileNextcloud).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	card, err := Parse(buf.String())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	return card.GetUID()
+}
+
+func TestProfileMaxLengths(t *testing.T) {
+	card := New().
+		AddName("Jane", "Doe").
+		AddNote("Ünïcödé note that is far too long").
+		AddTitle("CTO").
+		AddCustomProperty("x-bio", "A rather long biography")
+	profile := Profile{Name: "strict", MaxLengths: map[string]int{"note": 7, "TITLE": 10, "X-BIO": 8}}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).Profile(profile).Encode(card)
+	if err == nil || !strings.Contains(err.Error(), "NOTE") {
+		t.Fatalf("Expected the long note to be rejected, got %v", err)
+	}
+
+	warnings := profile.Lint(card)
+	if len(warnings) != 2 || warnings[0].Property != "NOTE" || warnings[1].Property != "X-BIO" {
+		t.Errorf("Expected NOTE and X-BIO warnings, got %v", warnings)
+	}
+
+	profile.TruncateLong = true
+	profile.MaxLengths["FN"] = 3
+	if err := NewEncoder(&buf).Profile(profile).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	for _, expected := range []string{"NOTE:Ünïcödé\n", "TITLE:CTO\n", "X-BIO:A rather\n", "FN:Jan\n", "N:Doe;Jane;;;\n"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, buf.String())
+		}
+	}
+	if card.GetNote() != "Ünïcödé note that is far too long" {
+		t.Error("Expected the original card to be left unchanged")
+	}
+}
+
+func TestProfileAppleShare(t *testing.T) {
+	data, err := corpus.ReadFile("corpus/apple-contacts.vcf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := Parse(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	card.AddURL("https://example.com", URLWork)
+
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf).Profile(ProfileAppleShare)
+	if err := encoder.Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+
+	fixture := filepath.Join("testdata", "profiles", "apple-share-ios-export.vcf")
+	if *updateFixtures {
+		if err := os.WriteFile(fixture, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("Failed to write fixture: %v", err)
+		}
+	} else if expected, err := os.ReadFile(fixture); err != nil || buf.String() != string(expected) {
+		t.Errorf("Output differs from %s:\n%s", fixture, buf.String())
+	}
+
+	var order []string
+	for _, line := range unfoldLines(buf.String()) {
+		property, _ := ParseProperty(line)
+		if len(order) == 0 || order[len(order)-1] != property.Name {
+			order = append(order, property.Name)
+		}
+	}
+	if got := strings.Join(order, " "); got != "BEGIN VERSION N FN ORG TITLE TEL EMAIL PHOTO END" {
+		t.Errorf("Expected the minimal property order, got %s", got)
+	}
+
+	dropped := map[string]bool{}
+	for _, warning := range encoder.Warnings() {
+		dropped[warning.Property] = true
+	}
+	for _, name := range []string{"ADR", "URL", "NOTE", "BDAY", "UID"} {
+		if !dropped[name] {
+			t.Errorf("Expected a warning for the dropped %s", name)
+		}
+	}
+}
+
+func TestProfileMaxPhotoSize(t *testing.T) {
+	large := New().AddName("Jane", "Doe").
+		AddPhoto(EncodeDataURI("image/jpeg", bytes.Repeat([]byte{0xff}, ProfileAppleShare.MaxPhotoSize+1)))
+
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf).Profile(ProfileAppleShare)
+	if err := encoder.Encode(large); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "PHOTO") {
+		t.Error("Expected the oversized photo to be dropped")
+	}
+	if warnings := encoder.Warnings(); len(warnings) == 0 || warnings[0].Property != "PHOTO" {
+		t.Errorf("Expected a PHOTO warning, got %v", warnings)
+	}
+}
+
+func TestProfileAppleTypes(t *testing.T) {
+	card := New().
+		AddName("Jane", "Doe").
+		AddPhoneWithPreference("+1555", PhoneMobile, true).
+		AddNote("Kept")
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Profile(Profile{Name: "apple", AppleTypes: true}).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	content := buf.String()
+	if !strings.Contains(content, "TEL;TYPE=CELL,pref:+1555\n") {
+		t.Errorf("Expected Apple phone types, got %s", content)
+	}
+	if !strings.Contains(content, "NOTE:Kept\nEND:VCARD") {
+		t.Errorf("Expected the other properties to keep their order, got %s", content)
+	}
+}
@@ -0,0 +1,63 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// registeredProperties lists IANA-registered vCard properties that are not
+// managed by dedicated setters and may therefore be supplied as custom
+// properties (RFC 2426, RFC 6350, RFC 6474, RFC 6715, RFC 8605, RFC 9554)
+var registeredProperties = map[string]bool{
+	"AGENT":         true,
+	"BIRTHPLACE":    true,
+	"CALADRURI":     true,
+	"CALURI":        true,
+	"CATEGORIES":    true,
+	"CLASS":         true,
+	"CLIENTPIDMAP":  true,
+	"CONTACT-URI":   true,
+	"CREATED":       true,
+	"DEATHDATE":     true,
+	"DEATHPLACE":    true,
+	"EXPERTISE":     true,
+	"FBURL":         true,
+	"GENDER":        true,
+	"GRAMGENDER":    true,
+	"HOBBY":         true,
+	"IMPP":          true,
+	"INTEREST":      true,
+	"JSPROP":        true,
+	"KEY":           true,
+	"LANG":          true,
+	"LANGUAGE":      true,
+	"MAILER":        true,
+	"NAME":          true,
+	"NICKNAME":      true,
+	"ORG-DIRECTORY": true,
+	"PRODID":        true,
+	"PROFILE":       true,
+	"PRONOUNS":      true,
+	"RELATED":       true,
+	"REV":           true,
+	"SOCIALPROFILE": true,
+	"SORT-STRING":   true,
+	"SOUND":         true,
+	"SOURCE":        true,
+	"TZ":            true,
+	"XML":           true,
+}
+
+// isCustomPropertyAllowed reports whether a custom property name is written
+// to the output: extensions (X-) and registered properties without a
+// dedicated setter. Names may only hold letters, digits and dashes, so a
+// name cannot smuggle parameters or extra lines into the output.
+func isCustomPropertyAllowed(name string) bool {
+	for _, r := range name {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+
+	name = strings.ToUpper(name)
+	return (strings.HasPrefix(name, "X-") && len(name) > 2) || registeredProperties[name]
+}
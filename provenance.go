@@ -0,0 +1,68 @@
+package vcard
+
+import "time"
+
+// Provenance properties written by Encoder.Provenance
+const (
+	PropertySource     = "X-GOVCARD-SOURCE"
+	PropertyImportedAt = "X-GOVCARD-IMPORTED-AT"
+)
+
+// Metadata keys holding the provenance of a card (see SetMeta)
+const (
+	MetaSource     = "source"
+	MetaImportedAt = "imported-at"
+)
+
+// provenanceTimestamp is the vCard timestamp format of PropertyImportedAt
+const provenanceTimestamp = "20060102T150405Z"
+
+// SetProvenance records the source system of the card and when it was
+// imported in its metadata. It is only written when the encoder enables
+// Encoder.Provenance. An empty source or zero time is left unset.
+func (v *VCard) SetProvenance(source string, importedAt time.Time) *VCard {
+	if source != "" {
+		v.SetMeta(MetaSource, source)
+	}
+	if !importedAt.IsZero() {
+		v.SetMeta(MetaImportedAt, importedAt.UTC().Format(time.RFC3339))
+	}
+	return v
+}
+
+// GetProvenance returns the source system and import time recorded by
+// SetProvenance, falling back to the provenance properties of a card read
+// from a file written with Encoder.Provenance
+func (v *VCard) GetProvenance() (string, time.Time) {
+	source, stamp := v.GetMeta(MetaSource), v.GetMeta(MetaImportedAt)
+	var importedAt time.Time
+	if stamp != "" {
+		importedAt, _ = time.Parse(time.RFC3339, stamp)
+	}
+
+	if values := Get[string](v, PropertySource); source == "" && len(values) > 0 {
+		source = values[0]
+	}
+	if values := Get[string](v, PropertyImportedAt); importedAt.IsZero() && len(values) > 0 {
+		importedAt, _ = time.Parse(provenanceTimestamp, values[0])
+	}
+	return source, importedAt
+}
+
+// withProvenance returns the card with its provenance metadata added as
+// provenance properties, or the card itself when it has none
+func (v *VCard) withProvenance() *VCard {
+	source, importedAt := v.GetMeta(MetaSource), v.GetMeta(MetaImportedAt)
+	if source == "" && importedAt == "" {
+		return v
+	}
+
+	card := v.Clone()
+	if source != "" {
+		card.AddCustomProperty(PropertySource, source)
+	}
+	if stamp, err := time.Parse(time.RFC3339, importedAt); err == nil {
+		card.AddCustomProperty(PropertyImportedAt, stamp.UTC().Format(provenanceTimestamp))
+	}
+	return card
+}
@@ -0,0 +1,50 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProvenance(t *testing.T) {
+	imported := time.Date(2024, 6, 1, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	card := New().AddName("Jane", "Doe").SetProvenance("salesforce", imported)
+
+	if source, at := card.GetProvenance(); source != "salesforce" || !at.Equal(imported) {
+		t.Errorf("Unexpected provenance %q, %v", source, at)
+	}
+
+	var plain bytes.Buffer
+	if err := NewEncoder(&plain).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	if strings.Contains(plain.String(), PropertySource) {
+		t.Errorf("Expected no provenance without the option:\n%s", plain.String())
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Provenance(true).Encode(card); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	for _, expected := range []string{"X-GOVCARD-SOURCE:salesforce\n", "X-GOVCARD-IMPORTED-AT:20240601T123000Z\n"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, buf.String())
+		}
+	}
+	if len(card.GetCustomProperties()) != 0 {
+		t.Error("Expected the card to be left unchanged")
+	}
+
+	read, err := Parse(buf.String())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if source, at := read.GetProvenance(); source != "salesforce" || !at.Equal(imported) {
+		t.Errorf("Expected provenance read from the properties, got %q, %v", source, at)
+	}
+
+	if source, at := New().GetProvenance(); source != "" || !at.IsZero() {
+		t.Errorf("Expected no provenance, got %q, %v", source, at)
+	}
+}
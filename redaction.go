@@ -0,0 +1,110 @@
+package vcard
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redaction is a named visibility profile selecting the fields exported to a
+// target, such as a public web page or a full backup. Apply it with
+// Encoder.Redaction, AddressBook.Redacted or the adapters' Redaction option.
+type Redaction struct {
+	// Name identifies the redaction, e.g. in configuration files
+	Name string
+
+	// Fields are the field groups exported
+	Fields FieldMask
+
+	// Exclude lists further custom or registered properties removed by
+	// name, such as internal identifiers (e.g. "X-EMPLOYEE-ID")
+	Exclude []string
+}
+
+var (
+	// RedactionPublicWeb exports what a public profile page may show: no
+	// personal contact details or dates, notes, position or custom
+	// properties
+	RedactionPublicWeb = Redaction{
+		Name:   "public-web",
+		Fields: PublicFields &^ (FieldNote | FieldGeo | FieldCustom),
+	}
+
+	// RedactionInternalDirectory exports what colleagues may see: work and
+	// mobile contact details, without home details, dates or position
+	RedactionInternalDirectory = Redaction{
+		Name: "internal-directory",
+		Fields: FieldAll &^ (FieldHomeEmail | FieldHomePhone | FieldHomeAddress |
+			FieldBirthday | FieldAnniversary | FieldGeo),
+	}
+
+	// RedactionFullBackup exports every field
+	RedactionFullBackup = Redaction{
+		Name:   "full-backup",
+		Fields: FieldAll,
+	}
+)
+
+var (
+	redactionsMu sync.RWMutex
+	redactions   = map[string]Redaction{
+		RedactionPublicWeb.Name:         RedactionPublicWeb,
+		RedactionInternalDirectory.Name: RedactionInternalDirectory,
+		RedactionFullBackup.Name:        RedactionFullBackup,
+	}
+)
+
+// RegisterRedaction adds or replaces a redaction in the registry under its
+// name
+func RegisterRedaction(redaction Redaction) {
+	redactionsMu.Lock()
+	defer redactionsMu.Unlock()
+	redactions[redaction.Name] = redaction
+}
+
+// LookupRedaction returns the registered redaction with the name. The
+// built-in redactions are "public-web", "internal-directory" and
+// "full-backup".
+func LookupRedaction(name string) (Redaction, bool) {
+	redactionsMu.RLock()
+	defer redactionsMu.RUnlock()
+	redaction, ok := redactions[name]
+	return redaction, ok
+}
+
+// RedactionNames returns the names of all registered redactions in sorted
+// order
+func RedactionNames() []string {
+	redactionsMu.RLock()
+	defer redactionsMu.RUnlock()
+
+	names := make([]string, 0, len(redactions))
+	for name := range redactions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Redacted returns a copy of the card with only the fields the redaction
+// exports
+func (v *VCard) Redacted(redaction Redaction) *VCard {
+	clone := v.Masked(redaction.Fields)
+	for _, name := range redaction.Exclude {
+		for key := range clone.customProps {
+			if strings.EqualFold(key, name) {
+				delete(clone.customProps, key)
+			}
+		}
+	}
+	return clone
+}
+
+// Redacted returns an address book of redacted copies of the cards
+func (b *AddressBook) Redacted(redaction Redaction) *AddressBook {
+	redacted := &AddressBook{cards: make([]*VCard, len(b.cards))}
+	for i, card := range b.cards {
+		redacted.cards[i] = card.Redacted(redaction)
+	}
+	return redacted
+}
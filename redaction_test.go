@@ -0,0 +1,92 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func redactionCard() *VCard {
+	card := New().AddName("Jane", "Doe").SetUID("1").
+		AddEmail("jane@work.example.com", EmailWork).
+		AddEmail("jane@home.example.com", EmailHome).
+		AddPhone("+1 555 0100", PhoneWork).
+		AddPhone("+1 555 0101", PhoneMobile).
+		AddNote("Prefers email").
+		AddBirthday(time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)).
+		AddCustomProperty("X-EMPLOYEE-ID", "E42").
+		AddCustomProperty("X-TEAM", "Platform")
+	card.SetProvenance("crm", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	return card
+}
+
+func TestRedactions(t *testing.T) {
+	card := redactionCard()
+
+	tests := []struct {
+		redaction Redaction
+		present   []string
+		absent    []string
+	}{
+		{RedactionPublicWeb, []string{"jane@work.example.com", "+1 555 0100"}, []string{"home.example.com", "0101", "NOTE", "BDAY", "X-TEAM"}},
+		{RedactionInternalDirectory, []string{"0101", "NOTE", "X-TEAM", "X-EMPLOYEE-ID"}, []string{"home.example.com", "BDAY"}},
+		{RedactionFullBackup, []string{"home.example.com", "BDAY", "X-EMPLOYEE-ID"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.redaction.Name, func(t *testing.T) {
+			content, err := card.Redacted(tt.redaction).String()
+			if err != nil {
+				t.Fatalf("String() returned error: %v", err)
+			}
+			for _, text := range tt.present {
+				if !strings.Contains(content, text) {
+					t.Errorf("Expected %q in:\n%s", text, content)
+				}
+			}
+			for _, text := range tt.absent {
+				if strings.Contains(content, text) {
+					t.Errorf("Expected no %q in:\n%s", text, content)
+				}
+			}
+		})
+	}
+
+	if len(card.GetEmails()) != 2 {
+		t.Error("Redacted modified the original card")
+	}
+}
+
+func TestRedactionExclude(t *testing.T) {
+	internal := RedactionInternalDirectory
+	internal.Name = "internal-no-ids"
+	internal.Exclude = []string{"x-employee-id"}
+	RegisterRedaction(internal)
+	defer func() {
+		redactionsMu.Lock()
+		delete(redactions, internal.Name)
+		redactionsMu.Unlock()
+	}()
+
+	redaction, ok := LookupRedaction("internal-no-ids")
+	if !ok {
+		t.Fatalf("Expected the registered redaction, have %v", RedactionNames())
+	}
+
+	var builder strings.Builder
+	book := NewAddressBook(redactionCard(), redactionCard())
+	if err := NewEncoder(&builder).Provenance(true).Redaction(redaction).EncodeAddressBook(book); err != nil {
+		t.Fatalf("Encode() returned error: %v", err)
+	}
+	if strings.Contains(builder.String(), "X-EMPLOYEE-ID") || !strings.Contains(builder.String(), "X-TEAM:Platform") {
+		t.Errorf("Unexpected output:\n%s", builder.String())
+	}
+
+	public, _ := book.Redacted(RedactionPublicWeb).String()
+	if strings.Contains(public, "X-GOVCARD-SOURCE") || strings.Count(public, "BEGIN:VCARD") != 2 {
+		t.Errorf("Unexpected public book:\n%s", public)
+	}
+	if _, ok := LookupRedaction("unknown"); ok {
+		t.Error("Expected no redaction named unknown")
+	}
+}
@@ -0,0 +1,149 @@
+// Package retry runs network operations again after transient failures,
+// waiting an exponentially growing, jittered delay between attempts.
+//
+// The photo proxy and webhook notifier take an optional *Policy; a nil policy
+// makes a single attempt:
+//
+//	policy := retry.Default
+//	notifier.Retry = &policy
+//
+//	err := policy.Do(ctx, func(ctx context.Context) error {
+//		resp, err := client.Do(req.WithContext(ctx))
+//		if err != nil {
+//			return err
+//		}
+//		defer resp.Body.Close()
+//		if !retry.Status(resp.StatusCode) {
+//			return retry.Permanent(fmt.Errorf("unexpected status %d", resp.StatusCode))
+//		}
+//		...
+//	})
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// Policy describes how often and how long to wait before retrying
+type Policy struct {
+	// MaxAttempts is the number of attempts including the first; values
+	// below 1 make a single attempt
+	MaxAttempts int
+
+	// InitialDelay is the wait before the second attempt
+	InitialDelay time.Duration
+
+	// MaxDelay caps the wait between attempts; 0 leaves it uncapped
+	MaxDelay time.Duration
+
+	// Multiplier grows the delay after each attempt; values below 1 use 2
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction (0 to 1) in either
+	// direction, so clients failing together do not retry together
+	Jitter float64
+}
+
+// Default retries twice, after about 200ms and 400ms
+var Default = Policy{
+	MaxAttempts:  3,
+	InitialDelay: 200 * time.Millisecond,
+	MaxDelay:     5 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Do returns it without further attempts. Do unwraps
+// it again, so callers see the original error.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// Status reports whether a response with the HTTP status code is worth
+// retrying: request timeouts, rate limits and server errors other than 501
+func Status(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented:
+		return false
+	}
+	return code >= 500
+}
+
+// Delay returns the wait after the given failed attempt, counted from 1,
+// before jitter
+func (p Policy) Delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// Do calls fn until it succeeds, returns a Permanent error, the attempts are
+// used up or the context is done. It returns the last error of fn, or the
+// context error when the context ends while waiting. A nil policy calls fn
+// once.
+func (p *Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := 1
+	if p != nil && p.MaxAttempts > 1 {
+		attempts = p.MaxAttempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(p.jittered(p.Delay(attempt)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// jittered spreads the delay randomly by the Jitter fraction
+func (p *Policy) jittered(delay time.Duration) time.Duration {
+	jitter := min(max(p.Jitter, 0), 1)
+	if jitter == 0 || delay <= 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + jitter*(2*rand.Float64()-1)))
+}
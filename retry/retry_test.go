@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+func TestDo(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+
+	calls := 0
+	err := policy.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = policy.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || calls != 3 {
+		t.Errorf("Expected the last error after 3 calls, got %v after %d", err, calls)
+	}
+
+	calls = 0
+	err = policy.Do(context.Background(), func(context.Context) error {
+		calls++
+		return Permanent(errTransient)
+	})
+	if err != errTransient || calls != 1 {
+		t.Errorf("Expected the unwrapped permanent error after one call, got %v after %d", err, calls)
+	}
+
+	var none *Policy
+	calls = 0
+	none.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errTransient
+	})
+	if calls != 1 {
+		t.Errorf("Expected a nil policy to call once, got %d", calls)
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	policy := Policy{MaxAttempts: 5, InitialDelay: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := policy.Do(ctx, func(context.Context) error { return errTransient })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Do kept waiting after the context ended")
+	}
+}
+
+func TestDelay(t *testing.T) {
+	policy := Policy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, want := range expected {
+		if got := policy.Delay(i + 1); got != want*time.Millisecond {
+			t.Errorf("Delay(%d) = %v, expected %v", i+1, got, want*time.Millisecond)
+		}
+	}
+
+	jittered := Policy{Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := jittered.jittered(time.Second); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("Jittered delay %v outside ±50%%", got)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := map[int]bool{
+		http.StatusOK:                  false,
+		http.StatusNotFound:            false,
+		http.StatusRequestTimeout:      true,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusNotImplemented:      false,
+		http.StatusServiceUnavailable:  true,
+	}
+	for code, expected := range tests {
+		if Status(code) != expected {
+			t.Errorf("Status(%d) = %v, expected %v", code, !expected, expected)
+		}
+	}
+}
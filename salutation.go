@@ -0,0 +1,76 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// salutationRule describes how a language addresses a contact
+type salutationRule struct {
+	// greeting opens the salutation ("Dear")
+	greeting string
+
+	// honorific is appended to the family name instead of a greeting ("様")
+	honorific string
+
+	// fallback is used when the card has no usable name
+	fallback string
+}
+
+// salutationRules maps lowercase language codes to their rules
+var salutationRules = map[string]salutationRule{
+	"en": {greeting: "Dear", fallback: "Dear Sir or Madam"},
+	"de": {greeting: "Guten Tag", fallback: "Sehr geehrte Damen und Herren"},
+	"fr": {greeting: "Bonjour", fallback: "Madame, Monsieur"},
+	"es": {greeting: "Estimado/a", fallback: "A quien corresponda"},
+	"it": {greeting: "Gentile", fallback: "Gentili Signore e Signori"},
+	"bg": {greeting: "Уважаеми/а", fallback: "Уважаеми дами и господа"},
+	"ja": {honorific: "様", fallback: "ご担当者様"},
+}
+
+// Salutation returns a letter greeting such as "Dear Dr. Doe" for the given
+// locale (e.g. "en", "de-AT", "fr_FR"). Unknown locales fall back to English.
+//
+// When a prefix is set it is combined with the last name; otherwise the first
+// and last names are used.
+func (v *VCard) Salutation(locale string) string {
+	rule, ok := salutationRules[salutationLanguage(locale)]
+	if !ok {
+		rule = salutationRules["en"]
+	}
+
+	if rule.honorific != "" {
+		if v.name.Last != "" {
+			return v.name.Last + rule.honorific
+		}
+		if v.name.First != "" {
+			return v.name.First + rule.honorific
+		}
+		return rule.fallback
+	}
+
+	var parts []string
+	if v.name.Prefix != "" && v.name.Last != "" {
+		parts = []string{v.name.Prefix, v.name.Last}
+	} else {
+		for _, part := range []string{v.name.First, v.name.Last} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		return rule.fallback
+	}
+
+	return rule.greeting + " " + strings.Join(parts, " ")
+}
+
+// salutationLanguage extracts the lowercase language code from a locale
+func salutationLanguage(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,35 @@
+package vcard
+
+import "testing"
+
+func TestSalutation(t *testing.T) {
+	doctor := New().AddName("John", "Doe").AddPrefix("Dr.")
+	plain := New().AddName("Jane", "Smith")
+	firstOnly := New().AddName("Cher", "")
+	empty := New()
+
+	tests := []struct {
+		card     *VCard
+		locale   string
+		expected string
+	}{
+		{doctor, "en", "Dear Dr. Doe"},
+		{doctor, "en-US", "Dear Dr. Doe"},
+		{plain, "en", "Dear Jane Smith"},
+		{firstOnly, "en", "Dear Cher"},
+		{empty, "en", "Dear Sir or Madam"},
+		{doctor, "de_AT", "Guten Tag Dr. Doe"},
+		{plain, "fr-FR", "Bonjour Jane Smith"},
+		{empty, "de", "Sehr geehrte Damen und Herren"},
+		{plain, "ja", "Smith様"},
+		{empty, "ja", "ご担当者様"},
+		{plain, "xx", "Dear Jane Smith"},
+		{plain, "", "Dear Jane Smith"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.card.Salutation(tt.locale); got != tt.expected {
+			t.Errorf("Salutation(%q) = %q, want %q", tt.locale, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package vcard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SanitizeMode selects how control characters, soft hyphens and
+// bidirectional formatting characters in values are handled (see
+// CardOptions.Sanitize). Such characters let a value display differently
+// from what it holds, e.g. a name reversed with U+202E RIGHT-TO-LEFT
+// OVERRIDE.
+type SanitizeMode int
+
+const (
+	// SanitizeStrip removes the characters when the card is serialized
+	SanitizeStrip SanitizeMode = iota
+
+	// SanitizeReject makes Validate fail for values holding the characters
+	SanitizeReject
+
+	// SanitizeOff writes values unchanged
+	SanitizeOff
+)
+
+// SanitizeText removes control characters other than tabs and line breaks,
+// soft hyphens and bidirectional embedding, override and isolate characters
+func SanitizeText(s string) string {
+	if strings.IndexFunc(s, isUnsafeRune) < 0 {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isUnsafeRune(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isUnsafeRune reports whether r is removed by SanitizeText
+func isUnsafeRune(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return false
+	case r < 0x20 || r >= 0x7f && r <= 0x9f:
+		// C0 and C1 control characters
+		return true
+	case r == 0xad:
+		// Soft hyphen
+		return true
+	case r >= 0x202a && r <= 0x202e:
+		// Bidirectional embeddings and overrides
+		return true
+	case r >= 0x2066 && r <= 0x2069:
+		// Bidirectional isolates
+		return true
+	default:
+		return false
+	}
+}
+
+// prepared returns the card to serialize: a copy with the Sanitize mode
+// and Unicode normalization applied to its text values, or the card itself
+// when both are off
+func (v *VCard) prepared() *VCard {
+	if v.options.Sanitize == SanitizeOff && v.options.KeepUnicode {
+		return v
+	}
+
+	card := v.Clone()
+	card.textFields(func(_ string, value *string) {
+		if v.options.Sanitize != SanitizeOff {
+			*value = SanitizeText(*value)
+		}
+		*value = v.options.text(*value)
+	})
+	return card
+}
+
+// validateText rejects values with unsafe characters in SanitizeReject mode
+func (v *VCard) validateText() error {
+	if v.options.Sanitize != SanitizeReject {
+		return nil
+	}
+
+	for _, field := range v.textValues() {
+		if strings.IndexFunc(field.value, isUnsafeRune) >= 0 {
+			return fmt.Errorf("%s contains control or bidirectional formatting characters", field.property)
+		}
+	}
+	return nil
+}
+
+// sanitizeWarnings reports the properties whose values are altered in
+// SanitizeStrip mode
+func (v *VCard) sanitizeWarnings() []Warning {
+	if v.options.Sanitize != SanitizeStrip {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var properties []string
+	for _, field := range v.textValues() {
+		if !seen[field.property] && strings.IndexFunc(field.value, isUnsafeRune) >= 0 {
+			seen[field.property] = true
+			properties = append(properties, field.property)
+		}
+	}
+	sort.Strings(properties)
+
+	warnings := make([]Warning, 0, len(properties))
+	for _, property := range properties {
+		warnings = append(warnings, Warning{
+			Property: property,
+			Message:  "control or bidirectional formatting characters are removed",
+		})
+	}
+	return warnings
+}
+
+// textValue is a text value of the card with its property name
+type textValue struct {
+	property string
+	value    string
+}
+
+// textValues returns the text values of the card
+func (v *VCard) textValues() []textValue {
+	var values []textValue
+	v.textFields(func(property string, value *string) {
+		values = append(values, textValue{property, *value})
+	})
+	return values
+}
+
+// textFields calls fn with the property name and the location of every text
+// value of the card, so fn can replace the value
+func (v *VCard) textFields(fn func(property string, value *string)) {
+	fn("FN", &v.fn)
+	for _, part := range []*string{&v.name.Last, &v.name.First, &v.name.Middle, &v.name.Prefix, &v.name.Suffix} {
+		fn("N", part)
+	}
+	fn("ORG", &v.organization.Name)
+	fn("ORG", &v.organization.Department)
+	fn("TITLE", &v.organization.Title)
+	fn("ROLE", &v.organization.Role)
+	fn("NOTE", &v.note)
+	fn("UID", &v.uid)
+	fn("BDAY", &v.bdayText)
+	fn("ANNIVERSARY", &v.annivText)
+
+	for i := range v.organization.Units {
+		fn("ORG", &v.organization.Units[i])
+	}
+	for i := range v.emails {
+		fn("EMAIL", &v.emails[i].Address)
+	}
+	for i := range v.phones {
+		fn("TEL", &v.phones[i].Number)
+	}
+	for i := range v.addresses {
+		addr := &v.addresses[i]
+		for _, part := range []*string{&addr.Street, &addr.Extended, &addr.City, &addr.State, &addr.PostalCode, &addr.Country} {
+			fn("ADR", part)
+		}
+	}
+	for i := range v.urls {
+		fn("URL", &v.urls[i].Address)
+	}
+	for i := range v.members {
+		fn("MEMBER", &v.members[i])
+	}
+	for property, alternates := range v.alternates {
+		for i := range alternates {
+			fn(property, &alternates[i].Value)
+		}
+	}
+	for name, value := range v.customProps {
+		replaced := value
+		fn(strings.ToUpper(name), &replaced)
+		if replaced != value {
+			v.customProps[name] = replaced
+		}
+	}
+}
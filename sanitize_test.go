@@ -0,0 +1,56 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := map[string]string{
+		"Jane Doe":                "Jane Doe",
+		"Jane\u202eeoD":           "JaneeoD",
+		"soft\u00adhyphen":        "softhyphen",
+		"bell\x07 and \x1b[31m":   "bell and [31m",
+		"c1\u0085control":         "c1control",
+		"isolate\u2066x\u2069":    "isolatex",
+		"tab\tand\nnewline\r\n":   "tab\tand\nnewline\r\n",
+		"emoji 👩\u200d💻 and عربي": "emoji 👩\u200d💻 and عربي",
+	}
+	for input, expected := range tests {
+		if got := SanitizeText(input); got != expected {
+			t.Errorf("SanitizeText(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestSanitizeModes(t *testing.T) {
+	card := New().AddName("Jane", "Doe\u202e").AddNote("Hi\u00adthere")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if strings.ContainsRune(content, '\u202e') || !strings.Contains(content, "NOTE:Hithere") {
+		t.Errorf("Expected unsafe characters to be stripped:\n%s", content)
+	}
+	if warnings := card.Lint(); len(warnings) != 2 || warnings[0].Property != "N" || warnings[1].Property != "NOTE" {
+		t.Errorf("Expected N and NOTE warnings, got %v", warnings)
+	}
+
+	card.SetOptions(CardOptions{Sanitize: SanitizeReject})
+	if err := card.Validate(); err == nil || !strings.Contains(err.Error(), "bidirectional") {
+		t.Errorf("Expected reject mode to fail validation, got %v", err)
+	}
+
+	card.SetOptions(CardOptions{Sanitize: SanitizeOff})
+	content, err = card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.ContainsRune(content, '\u202e') {
+		t.Error("Expected values to be unchanged with sanitization off")
+	}
+	if len(card.Lint()) != 0 {
+		t.Errorf("Expected no warnings with sanitization off, got %v", card.Lint())
+	}
+}
@@ -0,0 +1,149 @@
+package vcard
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+)
+
+// corpus holds reference cards from the RFC examples and from the exports of
+// major address book vendors
+//
+//go:embed corpus/*.vcf
+var corpus embed.FS
+
+// CheckResult is the outcome of the self-check of one reference card
+type CheckResult struct {
+	// Name is the file name of the reference card, e.g. "rfc6350.vcf"
+	Name string
+
+	// Err describes the failure; nil when the card passed
+	Err error
+}
+
+// Corpus returns the reference cards used by SelfCheck, one per .vcf file
+func Corpus() fs.FS {
+	sub, _ := fs.Sub(corpus, "corpus")
+	return sub
+}
+
+// SelfCheck reads every reference card of the embedded corpus, regenerates
+// it and reads the output back. A card passes when the properties the
+// library models survive the round trip and a second round trip produces
+// the same output. Run it after upgrading to verify the wire format:
+//
+//	for _, result := range vcard.SelfCheck() {
+//		if result.Err != nil {
+//			log.Printf("%s: %v", result.Name, result.Err)
+//		}
+//	}
+func SelfCheck() []CheckResult {
+	entries, err := fs.ReadDir(corpus, "corpus")
+	if err != nil {
+		return []CheckResult{{Name: "corpus", Err: err}}
+	}
+
+	var results []CheckResult
+	for _, entry := range entries {
+		data, err := fs.ReadFile(corpus, path.Join("corpus", entry.Name()))
+		if err == nil {
+			err = checkRoundTrip(string(data))
+		}
+		results = append(results, CheckResult{Name: entry.Name(), Err: err})
+	}
+	return results
+}
+
+// checkRoundTrip verifies the generate and read round trip of a card
+func checkRoundTrip(text string) error {
+	card, err := readCard(text, CardOptions{})
+	if err != nil {
+		return fmt.Errorf("reading reference card: %w", err)
+	}
+	output, err := card.String()
+	if err != nil {
+		return fmt.Errorf("generating card: %w", err)
+	}
+
+	original, err := propertyCounts(text)
+	if err != nil {
+		return err
+	}
+	generated, err := propertyCounts(output)
+	if err != nil {
+		return fmt.Errorf("reading generated card: %w", err)
+	}
+	for name, count := range original {
+		if !card.checksProperty(name) {
+			continue
+		}
+		if generated[name] == 0 || repeatableProperties[name] && generated[name] != count {
+			return fmt.Errorf("%s: %d in reference card, %d generated", name, count, generated[name])
+		}
+	}
+
+	again, err := readCard(output, CardOptions{})
+	if err != nil {
+		return fmt.Errorf("reading generated card: %w", err)
+	}
+	second, err := again.String()
+	if err != nil {
+		return fmt.Errorf("regenerating card: %w", err)
+	}
+	if !slices.Equal(sortedLines(output), sortedLines(second)) {
+		return fmt.Errorf("output changed on the second round trip:\n%s\n---\n%s", output, second)
+	}
+
+	return nil
+}
+
+// repeatableProperties are the modeled properties a card may hold several
+// times; every occurrence must survive the round trip
+var repeatableProperties = map[string]bool{
+	"ADR":    true,
+	"EMAIL":  true,
+	"MEMBER": true,
+	"TEL":    true,
+	"URL":    true,
+}
+
+// modeledProperties are read into dedicated fields by readCard
+var modeledProperties = map[string]bool{
+	"ADR": true, "ANNIVERSARY": true, "BDAY": true, "EMAIL": true, "FN": true,
+	"GEO": true, "KIND": true, "LOGO": true, "MEMBER": true, "N": true,
+	"NOTE": true, "ORG": true, "PHOTO": true, "ROLE": true, "TEL": true,
+	"TITLE": true, "UID": true, "URL": true,
+}
+
+// checksProperty reports whether a property of the reference card must
+// appear in the generated card: modeled and custom properties defined by the
+// card's version
+func (v *VCard) checksProperty(name string) bool {
+	if !Supports(v.version, name) {
+		return false
+	}
+	return modeledProperties[name] || name != "LABEL" && isCustomPropertyAllowed(name)
+}
+
+// propertyCounts counts the properties of vCard text by name
+func propertyCounts(text string) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, line := range unfoldLines(text) {
+		property, err := ParseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		counts[property.Name]++
+	}
+	return counts, nil
+}
+
+// sortedLines returns the unfolded lines of vCard text in sorted order, as
+// custom properties are written in no particular order
+func sortedLines(text string) []string {
+	lines := unfoldLines(text)
+	slices.Sort(lines)
+	return lines
+}
@@ -0,0 +1,28 @@
+package vcard
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestSelfCheck(t *testing.T) {
+	results := SelfCheck()
+	files, err := fs.Glob(Corpus(), "*.vcf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(files) || len(results) == 0 {
+		t.Fatalf("Expected a result per corpus card, got %d for %d files", len(results), len(files))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Name, result.Err)
+		}
+	}
+}
+
+func TestCheckRoundTripReportsFailures(t *testing.T) {
+	if err := checkRoundTrip("BEGIN:VCARD\r\nVERSION:3.0\r\nEMAIL:jane@example.com\r\nEND:VCARD\r\n"); err == nil {
+		t.Error("Expected an invalid card to fail the check")
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// BasicAuth returns middleware requiring the username and password through
+// HTTP basic authentication. Credentials are compared in constant time; serve
+// over TLS so they are not sent in the clear.
+func BasicAuth(username, password string) func(http.Handler) http.Handler {
+	wantUser := sha256.Sum256([]byte(username))
+	wantPassword := sha256.Sum256([]byte(password))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			gotUser := sha256.Sum256([]byte(user))
+			gotPassword := sha256.Sum256([]byte(pass))
+			if !ok || subtle.ConstantTimeCompare(gotUser[:], wantUser[:])&subtle.ConstantTimeCompare(gotPassword[:], wantPassword[:]) != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="vcard", charset="UTF-8"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
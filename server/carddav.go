@@ -0,0 +1,257 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/store"
+)
+
+// Paths served by CardDAV. Requests for /.well-known/carddav are redirected
+// to the principal with 308, keeping the method.
+const (
+	PrincipalPath   = "/principals/user/"
+	HomeSetPath     = "/addressbooks/user/"
+	AddressBookPath = "/addressbooks/user/contacts/"
+)
+
+// CardDAV returns a CardDAV (RFC 6352) handler serving the store as a single
+// address book at AddressBookPath, with each card at its path-escaped UID
+// plus ".vcf". It answers the discovery PROPFINDs of common clients,
+// addressbook-multiget and addressbook-query REPORTs (queries return every
+// card; filters are ignored) and conditional GET, PUT and DELETE of cards.
+// Listing the address book needs a store implementing store.Lister, as all
+// stores in the store package do. Mount the handler at the root of the host
+// and wrap it with BasicAuth, as clients expect to authenticate:
+//
+//	http.Handle("/", server.BasicAuth("me", password)(server.CardDAV(contacts)))
+func CardDAV(s store.ContactStore) http.Handler {
+	h := &cardStore{store: s}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("DAV", "1, 3, addressbook")
+		switch {
+		case r.URL.Path == "/.well-known/carddav":
+			http.Redirect(w, r, PrincipalPath, http.StatusPermanentRedirect)
+		case r.Method == http.MethodOptions:
+			w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		case r.Method == "PROPFIND":
+			h.propfind(w, r)
+		case r.Method == "REPORT" && r.URL.Path == AddressBookPath:
+			h.report(w, r)
+		case strings.HasPrefix(r.URL.Path, AddressBookPath) && len(r.URL.Path) > len(AddressBookPath):
+			uid, ok := resourceUID(r.URL.EscapedPath())
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			h.serveCard(w, r, uid, true)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// ResourceName returns the name of the card resource with the UID
+func ResourceName(uid string) string {
+	return url.PathEscape(uid) + vcard.FileExtension
+}
+
+// resourceUID returns the UID of the card resource at the escaped path or
+// URL
+func resourceUID(href string) (string, bool) {
+	if parsed, err := url.Parse(href); err == nil {
+		href = parsed.EscapedPath()
+	}
+	name, ok := strings.CutPrefix(href, AddressBookPath)
+	if !ok || strings.Contains(name, "/") {
+		return "", false
+	}
+	name, ok = strings.CutSuffix(name, vcard.FileExtension)
+	if !ok {
+		return "", false
+	}
+	uid, err := url.PathUnescape(name)
+	return uid, err == nil && uid != ""
+}
+
+// propfind describes the principal, home set, address book or a card. Every
+// known property is returned whatever the request asks for.
+func (h *cardStore) propfind(w http.ResponseWriter, r *http.Request) {
+	depth := r.Header.Get("Depth")
+	var responses []string
+	switch r.URL.Path {
+	case "/", PrincipalPath:
+		responses = append(responses, davResponse(r.URL.Path,
+			"<d:resourcetype><d:principal/></d:resourcetype>"+
+				"<d:current-user-principal><d:href>"+PrincipalPath+"</d:href></d:current-user-principal>"+
+				"<card:addressbook-home-set><d:href>"+HomeSetPath+"</d:href></card:addressbook-home-set>"))
+	case HomeSetPath:
+		responses = append(responses, davResponse(HomeSetPath, "<d:resourcetype><d:collection/></d:resourcetype>"))
+		if depth != "0" {
+			cards, err := h.cards(r)
+			if err != nil {
+				storeError(w, err)
+				return
+			}
+			responses = append(responses, addressBookResponse(cards))
+		}
+	case AddressBookPath:
+		cards, err := h.cards(r)
+		if err != nil {
+			storeError(w, err)
+			return
+		}
+		responses = append(responses, addressBookResponse(cards))
+		if depth != "0" {
+			for _, card := range cards {
+				responses = append(responses, cardResponse(card, false))
+			}
+		}
+	default:
+		uid, ok := resourceUID(r.URL.EscapedPath())
+		content, err := h.store.Get(uid)
+		if !ok || err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		responses = append(responses, cardResponse(storedCard{uid, content}, false))
+	}
+
+	writeMultistatus(w, responses)
+}
+
+// report answers addressbook-multiget with the requested cards and
+// addressbook-query with all cards
+func (h *cardStore) report(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		XMLName xml.Name
+		Hrefs   []string `xml:"DAV: href"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(r.Body, int64(DefaultUploadLimits.MaxCardSize))).Decode(&request); err != nil {
+		http.Error(w, "Invalid REPORT body", http.StatusBadRequest)
+		return
+	}
+
+	var responses []string
+	switch request.XMLName.Local {
+	case "addressbook-multiget":
+		for _, href := range request.Hrefs {
+			uid, ok := resourceUID(href)
+			content, err := h.store.Get(uid)
+			if !ok || err != nil {
+				responses = append(responses, "<d:response><d:href>"+escapeXML(href)+"</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>")
+				continue
+			}
+			responses = append(responses, cardResponse(storedCard{uid, content}, true))
+		}
+	case "addressbook-query":
+		cards, err := h.cards(r)
+		if err != nil {
+			storeError(w, err)
+			return
+		}
+		for _, card := range cards {
+			responses = append(responses, cardResponse(card, true))
+		}
+	default:
+		writeDAVError(w, http.StatusForbidden, "d:supported-report")
+		return
+	}
+
+	writeMultistatus(w, responses)
+}
+
+// storedCard is the content of a card and its UID
+type storedCard struct {
+	uid     string
+	content string
+}
+
+// cards returns all stored cards in UID order
+func (h *cardStore) cards(r *http.Request) ([]storedCard, error) {
+	uids, err := listUIDs(r.Context(), h.store)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]storedCard, 0, len(uids))
+	for _, uid := range uids {
+		content, err := h.store.Get(uid)
+		if err != nil {
+			// Deleted since listing
+			continue
+		}
+		cards = append(cards, storedCard{uid, content})
+	}
+	return cards, nil
+}
+
+// addressBookResponse describes the address book collection. Its CTag
+// changes whenever a card does.
+func addressBookResponse(cards []storedCard) string {
+	hash := sha256.New()
+	for _, card := range cards {
+		io.WriteString(hash, card.uid+"\x00"+etag(card.content)+"\x00")
+	}
+
+	return davResponse(AddressBookPath,
+		"<d:resourcetype><d:collection/><card:addressbook/></d:resourcetype>"+
+			"<d:displayname>Contacts</d:displayname>"+
+			"<cs:getctag>"+hex.EncodeToString(hash.Sum(nil)[:16])+"</cs:getctag>"+
+			"<card:supported-address-data>"+
+			`<card:address-data-type content-type="text/vcard" version="3.0"/>`+
+			`<card:address-data-type content-type="text/vcard" version="4.0"/>`+
+			"</card:supported-address-data>")
+}
+
+// cardResponse describes a card, with its content for REPORTs
+func cardResponse(card storedCard, data bool) string {
+	props := "<d:resourcetype/>" +
+		"<d:getetag>" + escapeXML(etag(card.content)) + "</d:getetag>" +
+		"<d:getcontenttype>text/vcard; charset=utf-8</d:getcontenttype>" +
+		"<d:getcontentlength>" + strconv.Itoa(len(card.content)) + "</d:getcontentlength>"
+	if data {
+		props += "<card:address-data>" + escapeXML(card.content) + "</card:address-data>"
+	}
+	return davResponse(AddressBookPath+ResourceName(card.uid), props)
+}
+
+// davResponse is a multistatus response with the properties found
+func davResponse(href, props string) string {
+	return "<d:response><d:href>" + escapeXML(href) + "</d:href><d:propstat><d:prop>" + props +
+		"</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>"
+}
+
+// writeMultistatus writes a 207 Multi-Status document
+func writeMultistatus(w http.ResponseWriter, responses []string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, xml.Header+`<d:multistatus xmlns:d="DAV:" xmlns:card="urn:ietf:params:xml:ns:carddav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, r := range responses {
+		io.WriteString(w, r)
+	}
+	io.WriteString(w, "</d:multistatus>")
+}
+
+// writeDAVError writes a WebDAV or CardDAV precondition error, e.g.
+// "card:valid-address-data"
+func writeDAVError(w http.ResponseWriter, status int, condition string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `%s<d:error xmlns:d="DAV:" xmlns:card="urn:ietf:params:xml:ns:carddav"><%s/></d:error>`, xml.Header, condition)
+}
+
+// escapeXML escapes text for XML content
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/store"
+)
+
+// errNoListing is returned for stores that can't list their cards
+var errNoListing = errors.New("server: store cannot list its cards")
+
+// cardStore serves the cards of a store over HTTP
+type cardStore struct {
+	store store.ContactStore
+
+	// mu serializes writes, so conditional requests check and write
+	// atomically
+	mu sync.Mutex
+}
+
+// Contacts returns a REST handler over the store, to be mounted with
+// http.StripPrefix:
+//
+//	GET    /        {"uids": [...]} for stores that list their cards
+//	GET    /{uid}   the card as text/vcard with its ETag
+//	PUT    /{uid}   store a text/vcard body; 201 when new, 204 when replaced
+//	DELETE /{uid}   remove the card
+//
+// UIDs are path-escaped. All card requests honour If-Match and If-None-Match
+// against the ETag, with 304 Not Modified for a GET whose If-None-Match
+// matches, and a PUT body without a UID takes it from the path.
+func Contacts(s store.ContactStore) http.Handler {
+	h := &cardStore{store: s}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		escaped := strings.TrimPrefix(r.URL.EscapedPath(), "/")
+		if escaped == "" {
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			uids, err := listUIDs(r.Context(), s)
+			if err != nil {
+				storeError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string][]string{"uids": uids})
+			return
+		}
+
+		uid, err := url.PathUnescape(escaped)
+		if err != nil || strings.Contains(escaped, "/") {
+			http.Error(w, "Invalid UID", http.StatusBadRequest)
+			return
+		}
+		h.serveCard(w, r, uid, false)
+	})
+}
+
+// serveCard handles GET, PUT and DELETE of the card with the UID. CardDAV
+// requests report invalid cards as a CardDAV precondition error.
+func (h *cardStore) serveCard(w http.ResponseWriter, r *http.Request, uid string, dav bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+	}
+
+	content, err := h.store.Get(uid)
+	exists := err == nil
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		storeError(w, err)
+		return
+	}
+
+	tag := ""
+	if exists {
+		tag = etag(content)
+	}
+	if status := preconditions(r, tag, exists); status != 0 {
+		if status == http.StatusNotModified {
+			w.Header().Set("ETag", tag)
+		}
+		w.WriteHeader(status)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", vcard.MIMEType+"; charset=utf-8")
+		w.Header().Set("ETag", tag)
+		io.WriteString(w, content)
+	case http.MethodPut:
+		card, status := readCard(w, r, uid)
+		switch {
+		case card == nil && dav && status == http.StatusUnsupportedMediaType:
+			writeDAVError(w, http.StatusForbidden, "card:valid-address-data")
+			return
+		case card == nil:
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		if err := h.store.Put(card); err != nil {
+			storeError(w, err)
+			return
+		}
+		if stored, err := h.store.Get(uid); err == nil {
+			w.Header().Set("ETag", etag(stored))
+		}
+		if exists {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+	case http.MethodDelete:
+		if err := h.store.Delete(uid); err != nil {
+			storeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// readCard reads the request body as a card with the UID. It returns nil
+// and the response status for bodies that are too large, are not a vCard or
+// hold a different UID.
+func readCard(w http.ResponseWriter, r *http.Request, uid string) (*vcard.VCard, int) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(DefaultUploadLimits.MaxCardSize)))
+	if err != nil {
+		return nil, http.StatusRequestEntityTooLarge
+	}
+
+	card, err := vcard.Parse(string(data))
+	if err != nil {
+		return nil, http.StatusUnsupportedMediaType
+	}
+	switch card.GetUID() {
+	case "":
+		card.SetUID(uid)
+	case uid:
+	default:
+		return nil, http.StatusConflict
+	}
+	return card, 0
+}
+
+// listUIDs returns the UIDs of a store implementing store.Lister
+func listUIDs(ctx context.Context, s store.ContactStore) ([]string, error) {
+	if lister, ok := s.(store.Lister); ok {
+		return lister.UIDs(ctx)
+	}
+	return nil, errNoListing
+}
+
+// etag returns the entity tag of the stored content
+func etag(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// preconditions evaluates If-Match and If-None-Match against the resource
+// as in RFC 9110 section 13.2.2. It returns 0 when the request may proceed,
+// 304 for a GET or HEAD whose If-None-Match matches and 412 otherwise.
+func preconditions(r *http.Request, tag string, exists bool) int {
+	if match := r.Header.Values("If-Match"); len(match) > 0 {
+		if !exists || !matchETag(match, tag, false) {
+			return http.StatusPreconditionFailed
+		}
+	}
+	if match := r.Header.Values("If-None-Match"); len(match) > 0 && exists {
+		if matchETag(match, tag, true) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	}
+	return 0
+}
+
+// matchETag reports whether the comma-separated entity tags of the header
+// values contain "*" or the tag. Weak comparison ignores the W/ prefix,
+// strong comparison never matches a weak tag.
+func matchETag(values []string, tag string, weak bool) bool {
+	for _, value := range values {
+		for _, candidate := range strings.Split(value, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" {
+				return true
+			}
+			if rest, ok := strings.CutPrefix(candidate, "W/"); ok {
+				if !weak {
+					continue
+				}
+				candidate = rest
+			}
+			if candidate == strings.TrimPrefix(tag, "W/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// storeError maps a store error to a response without leaking its details
+func storeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		http.Error(w, "Not Found", http.StatusNotFound)
+	case errors.Is(err, errNoListing):
+		http.Error(w, "Not Implemented", http.StatusNotImplemented)
+	case errors.Is(err, store.ErrConflict):
+		http.Error(w, "Precondition Failed", http.StatusPreconditionFailed)
+	default:
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/store"
+)
+
+// serve sends a request to the handler and returns the recorded response
+func serve(handler http.Handler, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestContacts(t *testing.T) {
+	contacts := store.NewMemoryStore()
+	handler := Contacts(contacts)
+
+	// A card without UID takes the one of the path
+	content, _ := vcard.New().AddName("Jane", "Doe").String()
+	rr := serve(handler, http.MethodPut, "/urn:uuid:1%2F2", content, map[string]string{"If-None-Match": "*"})
+	if rr.Code != http.StatusCreated || rr.Header().Get("ETag") == "" {
+		t.Fatalf("Expected 201 with an ETag, got %d %v", rr.Code, rr.Header())
+	}
+	tag := rr.Header().Get("ETag")
+
+	rr = serve(handler, http.MethodGet, "/urn:uuid:1%2F2", "", nil)
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != tag || !strings.Contains(rr.Body.String(), "UID:urn:uuid:1/2") {
+		t.Errorf("Unexpected GET: %d %v\n%s", rr.Code, rr.Header(), rr.Body)
+	}
+
+	rr = serve(handler, http.MethodGet, "/", "", nil)
+	var list struct {
+		UIDs []string `json:"uids"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil || !reflect.DeepEqual(list.UIDs, []string{"urn:uuid:1/2"}) {
+		t.Errorf("Unexpected listing %+v (%v)", list, err)
+	}
+
+	other, _ := vcard.New().AddName("John", "Doe").SetUID("other").String()
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{"stale etag", http.MethodPut, content, map[string]string{"If-Match": `"stale"`}, http.StatusPreconditionFailed},
+		{"existing", http.MethodPut, content, map[string]string{"If-None-Match": "*"}, http.StatusPreconditionFailed},
+		{"not a card", http.MethodPut, "hello", nil, http.StatusUnsupportedMediaType},
+		{"other uid", http.MethodPut, other, nil, http.StatusConflict},
+		{"unchanged", http.MethodGet, "", map[string]string{"If-None-Match": tag}, http.StatusNotModified},
+		{"unchanged head", http.MethodHead, "", map[string]string{"If-None-Match": `"stale", W/` + tag}, http.StatusNotModified},
+		{"unchanged any", http.MethodGet, "", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"changed", http.MethodGet, "", map[string]string{"If-None-Match": `"stale"`}, http.StatusOK},
+		{"weak if-match", http.MethodPut, content, map[string]string{"If-Match": "W/" + tag}, http.StatusPreconditionFailed},
+		{"same etag", http.MethodPut, content, map[string]string{"If-None-Match": `"stale", ` + tag}, http.StatusPreconditionFailed},
+		{"replace", http.MethodPut, content, map[string]string{"If-Match": `"stale", ` + tag}, http.StatusNoContent},
+		{"post", http.MethodPost, content, nil, http.StatusMethodNotAllowed},
+		{"delete", http.MethodDelete, "", nil, http.StatusNoContent},
+		{"deleted", http.MethodGet, "", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rr := serve(handler, tt.method, "/urn:uuid:1%2F2", tt.body, tt.headers); rr.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, rr.Code)
+		}
+	}
+}
+
+func TestCardDAVListingNeedsUIDs(t *testing.T) {
+	handler := CardDAV(struct{ store.ContactStore }{store.NewMemoryStore()})
+
+	if rr := serve(handler, "PROPFIND", AddressBookPath, "", map[string]string{"Depth": "1"}); rr.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501 for a store without UIDs, got %d", rr.Code)
+	}
+	if rr := serve(handler, "PROPFIND", PrincipalPath, "", nil); rr.Code != http.StatusMultiStatus {
+		t.Errorf("Expected discovery to work, got %d", rr.Code)
+	}
+}
+
+func TestResourceUID(t *testing.T) {
+	tests := map[string]string{
+		AddressBookPath + "jane.vcf":                                       "jane",
+		"https://dav.example.com" + AddressBookPath + "urn:uuid:1%2F2.vcf": "urn:uuid:1/2",
+		AddressBookPath + "jane.txt":                                       "",
+		AddressBookPath + "a/b.vcf":                                        "",
+		"/elsewhere/jane.vcf":                                              "",
+	}
+	for href, expected := range tests {
+		if uid, _ := resourceUID(href); uid != expected {
+			t.Errorf("resourceUID(%q) = %q, expected %q", href, uid, expected)
+		}
+	}
+	if ResourceName("urn:uuid:1/2") != "urn:uuid:1%2F2.vcf" {
+		t.Errorf("Unexpected resource name %q", ResourceName("urn:uuid:1/2"))
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	handler := BasicAuth("user", "secret")(okHandler)
+
+	tests := []struct {
+		user, password string
+		status         int
+	}{
+		{"user", "secret", http.StatusOK},
+		{"user", "wrong", http.StatusUnauthorized},
+		{"other", "secret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(tt.user, tt.password)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s/%s: expected %d, got %d", tt.user, tt.password, tt.status, rr.Code)
+		}
+	}
+
+	rr := serve(handler, http.MethodGet, "/", "", nil)
+	if rr.Code != http.StatusUnauthorized || rr.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Expected a challenge without credentials, got %d", rr.Code)
+	}
+}
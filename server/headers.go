@@ -0,0 +1,151 @@
+// Package server provides net/http building blocks for serving vCards.
+//
+// The middleware works with any router built on net/http (including chi):
+//
+//	cors := server.CORS(server.CORSOptions{
+//		AllowedOrigins: []string{"https://app.example.com"},
+//	})
+//	http.Handle("/vcard", cors(server.SecurityHeaders(nil)(handler)))
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures cross-origin access
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to read responses. Entries may
+	// use a leading wildcard subdomain ("https://*.example.com"); "*" allows
+	// any origin. An empty list disables cross-origin access.
+	AllowedOrigins []string
+
+	// AllowedMethods lists the methods allowed in cross-origin requests
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers allowed in cross-origin requests
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers readable by the browser
+	ExposedHeaders []string
+
+	// AllowCredentials permits cookies and authorization headers. It is
+	// ignored for the "*" origin.
+	AllowCredentials bool
+
+	// MaxAge sets how long browsers may cache preflight results
+	MaxAge time.Duration
+}
+
+// DefaultCORSOptions allows the methods and headers used by the adapters but
+// no origins; set AllowedOrigins to enable cross-origin access
+var DefaultCORSOptions = CORSOptions{
+	AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+	AllowedHeaders: []string{"Content-Type", "Authorization"},
+	ExposedHeaders: []string{"Content-Disposition"},
+	MaxAge:         10 * time.Minute,
+}
+
+// DefaultSecurityHeaders are sent by SecurityHeaders when no headers are given
+var DefaultSecurityHeaders = map[string]string{
+	"X-Content-Type-Options":  "nosniff",
+	"X-Frame-Options":         "DENY",
+	"Referrer-Policy":         "no-referrer",
+	"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
+	"Cache-Control":           "no-store",
+}
+
+// CORS returns middleware applying the cross-origin policy. Preflight requests
+// from allowed origins are answered with 204; other requests are passed on,
+// carrying CORS headers only when their origin is allowed.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	// Apply defaults for missing fields
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = DefaultCORSOptions.AllowedMethods
+	}
+	if len(opts.AllowedHeaders) == 0 {
+		opts.AllowedHeaders = DefaultCORSOptions.AllowedHeaders
+	}
+
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+
+			allowed, wildcard := opts.matchOrigin(origin)
+			if origin == "" || !allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if wildcard {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if opts.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			// Preflight request
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				if opts.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposed != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposed)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchOrigin reports whether the origin is allowed and whether it matched
+// the "*" entry
+func (o CORSOptions) matchOrigin(origin string) (allowed, wildcard bool) {
+	for _, pattern := range o.AllowedOrigins {
+		switch {
+		case pattern == "*":
+			return true, true
+		case strings.EqualFold(pattern, origin):
+			return true, false
+		case strings.Contains(pattern, "://*."):
+			scheme, domain, _ := strings.Cut(pattern, "://*")
+			if strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(strings.ToLower(origin), strings.ToLower(domain)) &&
+				len(origin) > len(scheme)+len("://")+len(domain) {
+				return true, false
+			}
+		}
+	}
+
+	return false, false
+}
+
+// SecurityHeaders returns middleware setting the given response headers on
+// every request. Nil headers use DefaultSecurityHeaders.
+func SecurityHeaders(headers map[string]string) func(http.Handler) http.Handler {
+	if headers == nil {
+		headers = DefaultSecurityHeaders
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for key, value := range headers {
+				w.Header().Set(key, value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+	})(okHandler)
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://evil.com", false},
+		{"https://sub.example.org", true},
+		{"https://example.org", false},
+		{"http://sub.example.org", false},
+		{"https://sub.example.org.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Origin", tt.origin)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		got := rr.Header().Get("Access-Control-Allow-Origin")
+		if tt.allowed && got != tt.origin {
+			t.Errorf("Origin %s: expected to be allowed, got %q", tt.origin, got)
+		}
+		if !tt.allowed && got != "" {
+			t.Errorf("Origin %s: expected to be rejected, got %q", tt.origin, got)
+		}
+		if tt.allowed && rr.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("Origin %s: expected credentials to be allowed", tt.origin)
+		}
+		if rr.Code != http.StatusOK {
+			t.Errorf("Origin %s: expected status 200, got %d", tt.origin, rr.Code)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler := CORS(CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		MaxAge:         time.Hour,
+	})(okHandler)
+
+	req := httptest.NewRequest("OPTIONS", "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Max-Age"); got != "3600" {
+		t.Errorf("Expected max age 3600, got %q", got)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST, OPTIONS" {
+		t.Errorf("Expected default methods, got %q", got)
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	})(okHandler)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected *, got %q", got)
+	}
+	if rr.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("Expected credentials not to be allowed for the * origin")
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SecurityHeaders(nil)(okHandler).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	for key, value := range DefaultSecurityHeaders {
+		if got := rr.Header().Get(key); got != value {
+			t.Errorf("Expected %s: %s, got %q", key, value, got)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	SecurityHeaders(map[string]string{"X-Frame-Options": "SAMEORIGIN"})(okHandler).
+		ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if got := rr.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+		t.Errorf("Expected custom header, got %q", got)
+	}
+	if rr.Header().Get("X-Content-Type-Options") != "" {
+		t.Error("Expected only the custom headers to be set")
+	}
+}
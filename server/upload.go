@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/bundle"
+)
+
+// DefaultUploadLimits suits "import your contacts" endpoints: a typical
+// phone export with photos fits, while oversized requests are cut off early
+var DefaultUploadLimits = UploadLimits{
+	MaxBodySize: 10 << 20,
+	MaxCards:    5000,
+	MaxCardSize: 1 << 20,
+}
+
+var (
+	// ErrBodyTooLarge is returned when the request body exceeds MaxBodySize
+	ErrBodyTooLarge = errors.New("server: request body too large")
+
+	// ErrTooManyCards is returned when the upload holds more than MaxCards
+	// cards. It is bundle.ErrTooManyCards, so either can be matched.
+	ErrTooManyCards = bundle.ErrTooManyCards
+
+	// ErrCardTooLarge is returned when a card exceeds MaxCardSize. It is
+	// bundle.ErrCardTooLarge, so either can be matched.
+	ErrCardTooLarge = bundle.ErrCardTooLarge
+
+	// ErrNoCards is returned when the upload contains no vCard
+	ErrNoCards = errors.New("server: upload contains no vCards")
+)
+
+// UploadLimits bounds the .vcf data accepted from a request. Zero values mean
+// no limit.
+type UploadLimits struct {
+	// MaxBodySize is the maximum request body size in bytes
+	MaxBodySize int64
+
+	// MaxCards is the maximum number of cards across all uploaded files
+	MaxCards int
+
+	// MaxCardSize is the maximum size of a single card in bytes
+	MaxCardSize int
+}
+
+// uploadKey is the request context key of the uploaded cards
+type uploadKey struct{}
+
+// ReadUpload reads the vCards uploaded with the request, either as the raw
+// body or as the files of a multipart/form-data request, enforcing the
+// limits while streaming. Limit violations return ErrBodyTooLarge,
+// ErrTooManyCards or ErrCardTooLarge.
+func ReadUpload(w http.ResponseWriter, r *http.Request, limits UploadLimits) ([]bundle.Card, error) {
+	if limits.MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limits.MaxBodySize)
+	}
+	cardLimits := bundle.Limits{MaxCards: limits.MaxCards, MaxCardSize: limits.MaxCardSize}
+
+	var cards []bundle.Card
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			return nil, fmt.Errorf("server: invalid multipart request: %w", err)
+		}
+
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, uploadError(err)
+			}
+
+			// Form values are skipped; only files are read
+			if part.FileName() == "" {
+				part.Close()
+				continue
+			}
+
+			found, err := bundle.ReadStream(part.FileName(), part, cardLimits)
+			part.Close()
+			if err != nil {
+				return nil, uploadError(err)
+			}
+
+			cards = append(cards, found...)
+			if limits.MaxCards > 0 && len(cards) > limits.MaxCards {
+				return nil, fmt.Errorf("%w: upload holds more than %d cards", ErrTooManyCards, limits.MaxCards)
+			}
+		}
+	} else {
+		found, err := bundle.ReadStream("body", r.Body, cardLimits)
+		if err != nil {
+			return nil, uploadError(err)
+		}
+		cards = found
+	}
+
+	if len(cards) == 0 {
+		return nil, ErrNoCards
+	}
+	return cards, nil
+}
+
+// DecodeContacts reads a JSON array of contacts, as posted to the Bulk
+// handlers of the framework adapters, enforcing the limits while streaming:
+// bodies over MaxBodySize fail with ErrBodyTooLarge and arrays of more than
+// MaxCards items with ErrTooManyCards. MaxCardSize is not applied. Wrap
+// request bodies with http.MaxBytesReader as well, so the server stops
+// reading the connection.
+func DecodeContacts(r io.Reader, limits UploadLimits) ([]vcard.Contact, error) {
+	if limits.MaxBodySize > 0 {
+		r = http.MaxBytesReader(nil, io.NopCloser(r), limits.MaxBodySize)
+	}
+
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		if err == nil {
+			err = errors.New("expected an array of contacts")
+		}
+		return nil, uploadError(err)
+	}
+
+	contacts := []vcard.Contact{}
+	for decoder.More() {
+		if limits.MaxCards > 0 && len(contacts) == limits.MaxCards {
+			return nil, fmt.Errorf("%w: request holds more than %d contacts", ErrTooManyCards, limits.MaxCards)
+		}
+		var contact vcard.Contact
+		if err := decoder.Decode(&contact); err != nil {
+			return nil, uploadError(err)
+		}
+		contacts = append(contacts, contact)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, uploadError(err)
+	}
+	return contacts, nil
+}
+
+// Upload returns middleware that reads the uploaded vCards with ReadUpload
+// and passes them to next, which retrieves them with UploadedCards. Limit
+// violations are answered with 413 Request Entity Too Large and other
+// invalid uploads with 400 Bad Request.
+func Upload(limits UploadLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cards, err := ReadUpload(w, r, limits)
+			if err != nil {
+				http.Error(w, err.Error(), UploadStatus(err))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), uploadKey{}, cards)))
+		})
+	}
+}
+
+// UploadedCards returns the cards read by the Upload middleware
+func UploadedCards(r *http.Request) []bundle.Card {
+	cards, _ := r.Context().Value(uploadKey{}).([]bundle.Card)
+	return cards
+}
+
+// UploadStatus returns the HTTP status for an error returned by ReadUpload
+// or DecodeContacts
+func UploadStatus(err error) int {
+	if errors.Is(err, ErrBodyTooLarge) || errors.Is(err, ErrTooManyCards) || errors.Is(err, ErrCardTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// uploadError reports a body cut off by http.MaxBytesReader as ErrBodyTooLarge
+func uploadError(err error) error {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, maxBytes.Limit)
+	}
+	if errors.Is(err, ErrTooManyCards) || errors.Is(err, ErrCardTooLarge) {
+		return err
+	}
+	return fmt.Errorf("server: invalid upload: %w", err)
+}
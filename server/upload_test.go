@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func vcards(n int) string {
+	var builder strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&builder, "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Contact %d\r\nEND:VCARD\r\n", i)
+	}
+	return builder.String()
+}
+
+func multipartBody(t *testing.T, files map[string]string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("comment", "my contacts")
+	for name, content := range files {
+		part, err := writer.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	writer.Close()
+	return &body, writer.FormDataContentType()
+}
+
+func TestReadUpload(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(vcards(3)))
+	req.Header.Set("Content-Type", "text/vcard")
+
+	cards, err := ReadUpload(httptest.NewRecorder(), req, DefaultUploadLimits)
+	if err != nil {
+		t.Fatalf("ReadUpload() returned error: %v", err)
+	}
+	if len(cards) != 3 || cards[0].Source != "body" || !strings.Contains(cards[2].Content, "FN:Contact 2") {
+		t.Errorf("Unexpected cards: %v", cards)
+	}
+}
+
+func TestReadUploadMultipart(t *testing.T) {
+	body, contentType := multipartBody(t, map[string]string{"a.vcf": vcards(2), "b.vcf": vcards(1)})
+	req := httptest.NewRequest(http.MethodPost, "/import", body)
+	req.Header.Set("Content-Type", contentType)
+
+	cards, err := ReadUpload(httptest.NewRecorder(), req, DefaultUploadLimits)
+	if err != nil {
+		t.Fatalf("ReadUpload() returned error: %v", err)
+	}
+	if len(cards) != 3 {
+		t.Errorf("Expected 3 cards from both files, got %d", len(cards))
+	}
+
+	body, contentType = multipartBody(t, map[string]string{"a.vcf": vcards(2), "b.vcf": vcards(2)})
+	req = httptest.NewRequest(http.MethodPost, "/import", body)
+	req.Header.Set("Content-Type", contentType)
+
+	if _, err := ReadUpload(httptest.NewRecorder(), req, UploadLimits{MaxCards: 3}); !errors.Is(err, ErrTooManyCards) {
+		t.Errorf("Expected ErrTooManyCards across files, got %v", err)
+	}
+}
+
+func TestReadUploadLimits(t *testing.T) {
+	huge := "BEGIN:VCARD\nVERSION:3.0\nFN:Big\nNOTE:" + strings.Repeat("x", 2000) + "\nEND:VCARD\n"
+	tests := []struct {
+		name     string
+		body     string
+		limits   UploadLimits
+		expected error
+	}{
+		{"body", vcards(100), UploadLimits{MaxBodySize: 512}, ErrBodyTooLarge},
+		{"cards", vcards(5), UploadLimits{MaxCards: 4}, ErrTooManyCards},
+		{"card size", vcards(1) + huge, UploadLimits{MaxCardSize: 1024}, ErrCardTooLarge},
+		{"line size", huge, UploadLimits{MaxCardSize: 100}, ErrCardTooLarge},
+		{"empty", "hello", UploadLimits{}, ErrNoCards},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(tt.body))
+			if _, err := ReadUpload(httptest.NewRecorder(), req, tt.limits); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	body, contentType := multipartBody(t, map[string]string{"a.vcf": vcards(100)})
+	req := httptest.NewRequest(http.MethodPost, "/import", body)
+	req.Header.Set("Content-Type", contentType)
+	if _, err := ReadUpload(httptest.NewRecorder(), req, UploadLimits{MaxBodySize: 1024}); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge for multipart upload, got %v", err)
+	}
+}
+
+func TestDecodeContacts(t *testing.T) {
+	contacts, err := DecodeContacts(strings.NewReader(`[{"Name": {"First": "John"}}, {"Name": {"First": "Jane"}}]`), DefaultUploadLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contacts) != 2 || contacts[1].Name.First != "Jane" {
+		t.Errorf("Expected two contacts, got %+v", contacts)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		limits UploadLimits
+		status int
+		err    error
+	}{
+		{"body", `[{"Note": "` + strings.Repeat("x", 2000) + `"}]`, UploadLimits{MaxBodySize: 1024}, http.StatusRequestEntityTooLarge, ErrBodyTooLarge},
+		{"items", `[{},{},{}]`, UploadLimits{MaxCards: 2}, http.StatusRequestEntityTooLarge, ErrTooManyCards},
+		{"object", `{"Name": {"First": "John"}}`, UploadLimits{}, http.StatusBadRequest, nil},
+		{"truncated", `[{"Name": {"First": "John"}}`, UploadLimits{}, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeContacts(strings.NewReader(tt.body), tt.limits)
+			if err == nil || tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+			if status := UploadStatus(err); status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+}
+
+func TestUploadMiddleware(t *testing.T) {
+	var received int
+	handler := Upload(UploadLimits{MaxCards: 2})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = len(UploadedCards(r))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{vcards(2), http.StatusNoContent},
+		{vcards(3), http.StatusRequestEntityTooLarge},
+		{"not a vcard", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+		}
+	}
+	if received != 2 {
+		t.Errorf("Expected handler to receive 2 cards, got %d", received)
+	}
+}
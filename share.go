@@ -0,0 +1,33 @@
+package vcard
+
+// dataURIMediaType is the media type of vCard data URIs
+const dataURIMediaType = MIMEType + ";charset=utf-8"
+
+// QREncoder renders content as a QR code PNG, e.g. wrapping
+// github.com/skip2/go-qrcode
+type QREncoder func(content string) ([]byte, error)
+
+// DataURI returns the card as a base64 data:text/vcard URI, so that pages can
+// offer the card for download without another request
+func (v *VCard) DataURI() (string, error) {
+	content, err := v.String()
+	if err != nil {
+		return "", err
+	}
+	return EncodeDataURI(dataURIMediaType, []byte(content)), nil
+}
+
+// QRCode renders the card content as a QR code with the encoder and returns
+// the PNG as a base64 data URI, ready to use as an image source
+func (v *VCard) QRCode(encode QREncoder) (string, error) {
+	content, err := v.String()
+	if err != nil {
+		return "", err
+	}
+
+	png, err := encode(content)
+	if err != nil {
+		return "", err
+	}
+	return EncodeDataURI("image/png", png), nil
+}
@@ -0,0 +1,56 @@
+package vcard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDataURI(t *testing.T) {
+	card := New().AddName("Jane", "Doe")
+
+	uri, err := card.DataURI()
+	if err != nil {
+		t.Fatalf("DataURI failed: %v", err)
+	}
+	if !strings.HasPrefix(uri, "data:text/vcard;charset=utf-8;base64,") {
+		t.Errorf("Unexpected data URI prefix: %q", uri)
+	}
+
+	_, data, err := DecodeDataURI(uri)
+	if err != nil {
+		t.Fatalf("DecodeDataURI failed: %v", err)
+	}
+	content, _ := card.String()
+	if string(data) != content {
+		t.Errorf("Expected data URI to carry the card, got %q", data)
+	}
+
+	if _, err := New().DataURI(); err == nil {
+		t.Error("Expected an error for an invalid card")
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	card := New().AddName("Jane", "Doe")
+
+	var encoded string
+	uri, err := card.QRCode(func(content string) ([]byte, error) {
+		encoded = content
+		return []byte("png"), nil
+	})
+	if err != nil {
+		t.Fatalf("QRCode failed: %v", err)
+	}
+	if uri != "data:image/png;base64,cG5n" {
+		t.Errorf("Unexpected QR code data URI: %q", uri)
+	}
+	if !strings.Contains(encoded, "FN:Jane Doe") {
+		t.Errorf("Expected the card content to be encoded, got %q", encoded)
+	}
+
+	failure := errors.New("too large")
+	if _, err := card.QRCode(func(string) ([]byte, error) { return nil, failure }); !errors.Is(err, failure) {
+		t.Errorf("Expected encoder error, got %v", err)
+	}
+}
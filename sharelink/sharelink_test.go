@@ -0,0 +1,108 @@
+package sharelink
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var (
+	testKey  = []byte("0123456789abcdef0123456789abcdef")
+	otherKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func newSigner(t *testing.T, key []byte) *Signer {
+	t.Helper()
+	signer, err := New(key)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	return signer
+}
+
+func TestNewRejectsShortKeys(t *testing.T) {
+	for _, key := range [][]byte{nil, {}, []byte("secret"), testKey[:MinKeySize-1]} {
+		if _, err := New(key); !errors.Is(err, ErrShortKey) {
+			t.Errorf("New(%q) error = %v, want ErrShortKey", key, err)
+		}
+	}
+
+	// The signer keeps its own copy of the key
+	key := append([]byte(nil), testKey...)
+	signer := newSigner(t, key)
+	token := signer.Token("abc", time.Hour)
+	key[0] ^= 0xff
+	if _, err := signer.Verify(token); err != nil {
+		t.Errorf("Expected the token to verify after the caller's key changed, got %v", err)
+	}
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	signer := newSigner(t, testKey)
+
+	token := signer.Token("urn:uuid:1234", time.Hour)
+	uid, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() returned error: %v", err)
+	}
+
+	if uid != "urn:uuid:1234" {
+		t.Errorf("Expected UID urn:uuid:1234, got %s", uid)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	signer := newSigner(t, testKey)
+	token := signer.Token("abc", time.Hour)
+
+	tests := []string{
+		"",
+		"abc",
+		token + "x",
+		newSigner(t, otherKey).Token("abc", time.Hour),
+	}
+
+	for _, tt := range tests {
+		if _, err := signer.Verify(tt); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Verify(%q) error = %v, want ErrInvalidToken", tt, err)
+		}
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	signer := newSigner(t, testKey)
+	token := signer.Token("abc", time.Minute)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+	if _, err := signer.Verify(token); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+}
+
+func TestURL(t *testing.T) {
+	signer := newSigner(t, testKey)
+
+	link, err := signer.URL("https://example.com/share?lang=en", "abc", time.Hour)
+	if err != nil {
+		t.Fatalf("URL() returned error: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Failed to parse link: %v", err)
+	}
+
+	if u.Query().Get("lang") != "en" {
+		t.Error("Expected existing query parameters to be preserved")
+	}
+
+	if uid, err := signer.Verify(u.Query().Get(QueryParam)); err != nil || uid != "abc" {
+		t.Errorf("Expected link token to verify to abc, got %q (%v)", uid, err)
+	}
+
+	if _, err := signer.URL("://bad", "abc", time.Hour); err == nil {
+		t.Error("Expected error for invalid base URL")
+	}
+}
@@ -0,0 +1,185 @@
+// Package signature generates email signatures from vCards.
+//
+// The HTML uses tables and inline styles only, which is what mail clients
+// render consistently. A link to a hosted .vcf file and a QR code image can
+// be added so recipients can save the contact in one step:
+//
+//	html, err := signature.HTML(card, signature.Options{
+//		VCardURL:  "https://example.com/team/john.vcf",
+//		QRCodeURL: "https://example.com/team/john-qr.png",
+//	})
+package signature
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+// DefaultLinkText is the text of the .vcf download link
+const DefaultLinkText = "Save contact"
+
+// DefaultAccentColor colors the name and links
+const DefaultAccentColor = "#1a73e8"
+
+// Options configures the generated signature
+type Options struct {
+	// VCardURL links to a hosted copy of the card; empty omits the link
+	VCardURL string
+
+	// QRCodeURL is a hosted QR code image; empty omits the image. Many mail
+	// clients block data URIs, so the image should be hosted.
+	QRCodeURL string
+
+	// LinkText is the text of the .vcf link
+	LinkText string
+
+	// AccentColor is a hex color (#rgb or #rrggbb) for the name and links
+	AccentColor string
+
+	// ShowPhoto includes the card photo when it is a hosted http(s) image
+	ShowPhoto bool
+}
+
+// hexColor matches the accepted accent colors
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// signatureTemplate is the HTML signature layout
+var signatureTemplate = template.Must(template.New("signature").Parse(
+	`<table cellpadding="0" cellspacing="0" border="0" style="font-family:Arial,Helvetica,sans-serif;font-size:13px;line-height:1.4;color:#333333;">
+<tr>
+{{- if .Photo}}
+<td style="padding-right:12px;vertical-align:top;"><img src="{{.Photo}}" alt="" width="64" height="64" style="border-radius:50%;display:block;"></td>
+{{- end}}
+<td style="vertical-align:top;">
+<div style="font-size:15px;font-weight:bold;color:{{.Color}};">{{.Name}}</div>
+{{- with .Subtitle}}
+<div>{{.}}</div>
+{{- end}}
+{{- with .Phone}}
+<div>{{if .Link}}<a href="{{.Link}}" style="color:#333333;text-decoration:none;">{{.Text}}</a>{{else}}{{.Text}}{{end}}</div>
+{{- end}}
+{{- with .Email}}
+<div><a href="{{.Link}}" style="color:{{$.Color}};text-decoration:none;">{{.Text}}</a></div>
+{{- end}}
+{{- with .URL}}
+<div><a href="{{.Link}}" style="color:{{$.Color}};text-decoration:none;">{{.Text}}</a></div>
+{{- end}}
+{{- with .Address}}
+<div style="color:#777777;">{{.}}</div>
+{{- end}}
+{{- if .VCardURL}}
+<div style="padding-top:6px;"><a href="{{.VCardURL}}" style="color:{{.Color}};">{{.LinkText}}</a></div>
+{{- end}}
+</td>
+{{- if .QRCodeURL}}
+<td style="padding-left:12px;vertical-align:top;"><img src="{{.QRCodeURL}}" alt="{{.LinkText}}" width="80" height="80" style="display:block;"></td>
+{{- end}}
+</tr>
+</table>
+`))
+
+// link is an anchor with its target and text
+type link struct {
+	Link template.URL
+	Text string
+}
+
+// HTML returns the signature as an HTML fragment. Card values are escaped, so
+// the result is safe to embed even when the card holds untrusted data.
+func HTML(card *vcard.VCard, opts Options) (template.HTML, error) {
+	preview := card.Preview()
+	if preview.DisplayName == "" {
+		return "", fmt.Errorf("signature requires a name, organization or email")
+	}
+
+	if opts.LinkText == "" {
+		opts.LinkText = DefaultLinkText
+	}
+	if opts.AccentColor == "" {
+		opts.AccentColor = DefaultAccentColor
+	}
+	if !hexColor.MatchString(opts.AccentColor) {
+		return "", fmt.Errorf("invalid accent color: %q", opts.AccentColor)
+	}
+
+	data := struct {
+		Name      string
+		Subtitle  string
+		Phone     *link
+		Email     *link
+		URL       *link
+		Address   string
+		Photo     string
+		VCardURL  string
+		QRCodeURL string
+		LinkText  string
+		Color     template.CSS
+	}{
+		Name:      preview.DisplayName,
+		Subtitle:  preview.Subtitle,
+		Address:   preview.Address,
+		VCardURL:  opts.VCardURL,
+		QRCodeURL: opts.QRCodeURL,
+		LinkText:  opts.LinkText,
+		Color:     template.CSS(opts.AccentColor),
+	}
+
+	if preview.Phone != "" {
+		data.Phone = &link{Text: preview.Phone}
+		// only numbers that parse are linked; others could carry any scheme
+		if tel := (vcard.Phone{Number: preview.Phone}).Format(vcard.PhoneFormatRFC3966); strings.HasPrefix(tel, "tel:") {
+			data.Phone.Link = template.URL(tel)
+		}
+	}
+	if preview.Email != "" {
+		data.Email = &link{Link: template.URL("mailto:" + preview.Email), Text: preview.Email}
+	}
+	if preview.URL != "" {
+		data.URL = &link{Link: template.URL(preview.URL), Text: displayURL(preview.URL)}
+	}
+	if photo := card.GetPhoto(); opts.ShowPhoto && (strings.HasPrefix(photo, "https://") || strings.HasPrefix(photo, "http://")) {
+		data.Photo = photo
+	}
+
+	var buf bytes.Buffer
+	if err := signatureTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render signature: %w", err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// Text returns the plain-text signature, including the "-- " delimiter
+// recognised by mail clients
+func Text(card *vcard.VCard, opts Options) string {
+	preview := card.Preview()
+
+	lines := []string{"-- ", preview.DisplayName}
+	for _, value := range []string{preview.Subtitle, preview.Phone, preview.Email, preview.URL, preview.Address} {
+		if value != "" {
+			lines = append(lines, value)
+		}
+	}
+	if opts.VCardURL != "" {
+		text := opts.LinkText
+		if text == "" {
+			text = DefaultLinkText
+		}
+		lines = append(lines, text+": "+opts.VCardURL)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// displayURL shortens a URL for display by removing the scheme and a
+// trailing slash
+func displayURL(url string) string {
+	for _, scheme := range []string{"https://", "http://"} {
+		url = strings.TrimPrefix(url, scheme)
+	}
+	return strings.TrimSuffix(url, "/")
+}
@@ -0,0 +1,95 @@
+package signature
+
+import (
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func newCard() *vcard.VCard {
+	return vcard.New().
+		AddName("John", "Doe").
+		AddEmail("john@example.com").
+		AddPhone("+1 (555) 123-4567").
+		AddOrganization("Acme <Corp>").
+		AddTitle("Engineer").
+		AddURL("https://example.com/").
+		AddPhoto("https://example.com/john.jpg")
+}
+
+func TestHTML(t *testing.T) {
+	html, err := HTML(newCard(), Options{
+		VCardURL:  "https://example.com/john.vcf",
+		QRCodeURL: "https://example.com/john.png",
+		ShowPhoto: true,
+	})
+	if err != nil {
+		t.Fatalf("HTML() returned error: %v", err)
+	}
+
+	for _, expected := range []string{
+		">John Doe<",
+		"Acme &lt;Corp&gt;",
+		`href="tel:&#43;1-555-123-4567"`,
+		`href="mailto:john@example.com"`,
+		">example.com<",
+		`href="https://example.com/john.vcf"`,
+		">" + DefaultLinkText + "<",
+		`src="https://example.com/john.png"`,
+		`src="https://example.com/john.jpg"`,
+		"color:#1a73e8",
+	} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("Expected %q in signature:\n%s", expected, html)
+		}
+	}
+}
+
+func TestHTMLOptional(t *testing.T) {
+	html, err := HTML(vcard.New().AddName("Jane", "Smith").AddPhoto("data:image/png;base64,AAAA"), Options{ShowPhoto: true})
+	if err != nil {
+		t.Fatalf("HTML() returned error: %v", err)
+	}
+	for _, unexpected := range []string{"<img", "href="} {
+		if strings.Contains(string(html), unexpected) {
+			t.Errorf("Did not expect %q in signature:\n%s", unexpected, html)
+		}
+	}
+}
+
+func TestHTMLEscaping(t *testing.T) {
+	card := vcard.New().
+		AddName("<script>alert(1)</script>", "Doe").
+		AddPhone("javascript:alert(1)").
+		AddURL("javascript:alert(2)").
+		AddEmail(`x"onmouseover="alert(3)@example.com`)
+
+	html, err := HTML(card, Options{})
+	if err != nil {
+		t.Fatalf("HTML() returned error: %v", err)
+	}
+	for _, unexpected := range []string{"<script>", `href="javascript:`, `"onmouseover`} {
+		if strings.Contains(string(html), unexpected) {
+			t.Errorf("Did not expect %q in signature:\n%s", unexpected, html)
+		}
+	}
+}
+
+func TestHTMLErrors(t *testing.T) {
+	if _, err := HTML(vcard.New(), Options{}); err == nil {
+		t.Error("Expected error for card without a name")
+	}
+	if _, err := HTML(newCard(), Options{AccentColor: "red;background:url(x)"}); err == nil {
+		t.Error("Expected error for invalid accent color")
+	}
+}
+
+func TestText(t *testing.T) {
+	text := Text(newCard(), Options{VCardURL: "https://example.com/john.vcf", LinkText: "vCard"})
+
+	expected := "-- \nJohn Doe\nEngineer, Acme <Corp>\n+1 555 123 4567\njohn@example.com\nhttps://example.com/\nvCard: https://example.com/john.vcf\n"
+	if text != expected {
+		t.Errorf("Text() = %q, want %q", text, expected)
+	}
+}
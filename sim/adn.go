@@ -0,0 +1,174 @@
+// Package sim exports vCards as SIM card phonebook entries.
+//
+// Entries follow the abbreviated dialling number format of GSM 11.11
+// (EF_ADN): the name is limited to a few characters of the GSM 7-bit default
+// alphabet and the number to 20 BCD digits.
+//
+//	entries, errs := sim.Export(cards, sim.DefaultOptions)
+//	for _, entry := range entries {
+//		record := entry.Record(sim.DefaultOptions.NameLength)
+//		// write record to the SIM with your provisioning tool
+//	}
+package sim
+
+import (
+	"fmt"
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+// MaxNumberDigits is the number of digits an ADN record can hold without an
+// extension record
+const MaxNumberDigits = 20
+
+// Options configures the export
+type Options struct {
+	// NameLength is the size of the alpha identifier in bytes; it depends on
+	// the SIM and is commonly 14
+	NameLength int
+
+	// AllPhones exports every phone number of a card, suffixing the name
+	// with the phone type ("/M", "/W", "/H"); otherwise only the first
+	// number is exported
+	AllPhones bool
+}
+
+// DefaultOptions exports the first number of each card with 14-byte names
+var DefaultOptions = Options{
+	NameLength: 14,
+}
+
+// Entry is a SIM phonebook entry
+type Entry struct {
+	// Name is the transliterated, truncated name
+	Name string
+
+	// Number holds digits, "*" and "#", with a leading "+" for
+	// international numbers
+	Number string
+}
+
+// Export converts cards to SIM entries. Cards without a usable phone number
+// are skipped and reported in the returned errors.
+func Export(cards []*vcard.VCard, opts Options) ([]Entry, []error) {
+	if opts.NameLength <= 0 {
+		opts.NameLength = DefaultOptions.NameLength
+	}
+
+	var entries []Entry
+	var errs []error
+	for i, card := range cards {
+		phones := card.GetPhones()
+		if len(phones) == 0 {
+			errs = append(errs, fmt.Errorf("card %d: no phone number", i))
+			continue
+		}
+		if !opts.AllPhones {
+			phones = phones[:1]
+		}
+
+		for _, phone := range phones {
+			number, err := normalizeNumber(phone.Number)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("card %d: %w", i, err))
+				continue
+			}
+
+			name := card.GetFormattedName()
+			if opts.AllPhones && len(card.GetPhones()) > 1 {
+				name = truncate(name, opts.NameLength-2) + typeSuffix(phone.Type)
+			}
+
+			entries = append(entries, Entry{
+				Name:   truncate(name, opts.NameLength),
+				Number: number,
+			})
+		}
+	}
+
+	return entries, errs
+}
+
+// Record returns the EF_ADN record for the entry: the GSM-encoded name
+// padded with 0xFF to nameLength bytes, followed by the BCD number length,
+// type of number, 10 BCD bytes and the unused capability and extension
+// identifiers
+func (e Entry) Record(nameLength int) []byte {
+	record := make([]byte, nameLength+14)
+	for i := range record {
+		record[i] = 0xFF
+	}
+	copy(record[:nameLength], encodeGSM(e.Name))
+
+	digits := strings.TrimPrefix(e.Number, "+")
+	if digits == "" {
+		return record
+	}
+
+	number := record[nameLength:]
+	number[0] = byte(1 + (len(digits)+1)/2)
+	number[1] = 0x81 // unknown type of number, ISDN numbering plan
+	if strings.HasPrefix(e.Number, "+") {
+		number[1] = 0x91 // international
+	}
+	for i := 0; i < len(digits); i++ {
+		nibble := bcdDigit(digits[i])
+		if i%2 == 0 {
+			number[2+i/2] = 0xF0 | nibble
+		} else {
+			number[2+i/2] = number[2+i/2]&0x0F | nibble<<4
+		}
+	}
+
+	return record
+}
+
+// normalizeNumber strips formatting from a phone number
+func normalizeNumber(raw string) (string, error) {
+	var builder strings.Builder
+	digits := 0
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9', r == '*', r == '#':
+			builder.WriteRune(r)
+			digits++
+		case r == '+' && i == 0:
+			builder.WriteRune(r)
+		}
+	}
+
+	switch {
+	case digits == 0:
+		return "", fmt.Errorf("phone number %q has no digits", raw)
+	case digits > MaxNumberDigits:
+		return "", fmt.Errorf("phone number %q exceeds %d digits", raw, MaxNumberDigits)
+	}
+	return builder.String(), nil
+}
+
+// typeSuffix returns the name suffix marking the phone type
+func typeSuffix(phoneType vcard.PhoneType) string {
+	switch phoneType {
+	case vcard.PhoneMobile:
+		return "/M"
+	case vcard.PhoneHome:
+		return "/H"
+	case vcard.PhoneFax:
+		return "/F"
+	default:
+		return "/W"
+	}
+}
+
+// bcdDigit returns the BCD nibble for a dialling digit
+func bcdDigit(c byte) byte {
+	switch c {
+	case '*':
+		return 0x0A
+	case '#':
+		return 0x0B
+	default:
+		return c - '0'
+	}
+}
@@ -0,0 +1,80 @@
+package sim
+
+import (
+	"bytes"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestExport(t *testing.T) {
+	cards := []*vcard.VCard{
+		vcard.New().AddName("Zoë", "Brontë-Łukasiewicz").AddPhone("+44 (20) 7946-0958", vcard.PhoneMobile),
+		vcard.New().AddName("No", "Phone"),
+		vcard.New().AddName("Too", "Long").AddPhone("123456789012345678901"),
+	}
+
+	entries, errs := Export(cards, DefaultOptions)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %v", entries)
+	}
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v", errs)
+	}
+
+	if entries[0].Name != "Zoe Bronte-Luk" {
+		t.Errorf("Expected transliterated, truncated name, got %q", entries[0].Name)
+	}
+	if entries[0].Number != "+442079460958" {
+		t.Errorf("Expected normalized number, got %q", entries[0].Number)
+	}
+}
+
+func TestExportAllPhones(t *testing.T) {
+	card := vcard.New().AddName("Jonathan", "Doe").
+		AddPhone("555-0100", vcard.PhoneMobile).
+		AddPhone("555-0199", vcard.PhoneWork)
+
+	entries, _ := Export([]*vcard.VCard{card}, Options{NameLength: 12, AllPhones: true})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %v", entries)
+	}
+	if entries[0].Name != "Jonathan D/M" || entries[1].Name != "Jonathan D/W" {
+		t.Errorf("Unexpected names: %q, %q", entries[0].Name, entries[1].Name)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		expected string
+	}{
+		{"Anna", 14, "Anna"},
+		{"Ana [Work]", 8, "Ana [Wo"},
+		{"Ana [Work]", 5, "Ana"},
+		{"李小龙", 14, "???"},
+		{"Müller €", 8, "Müller"},
+		{"Müller €", 9, "Müller €"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.name, tt.size); got != tt.expected {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.name, tt.size, got, tt.expected)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	record := Entry{Name: "Ab€", Number: "+1234*"}.Record(6)
+
+	expected := []byte{
+		0x41, 0x62, 0x1B, 0x65, 0xFF, 0xFF, // alpha identifier
+		0x04, 0x91, // length, international
+		0x21, 0x43, 0xFA, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // BCD digits
+		0xFF, 0xFF, // capability and extension identifiers
+	}
+	if !bytes.Equal(record, expected) {
+		t.Errorf("Record() = % X, want % X", record, expected)
+	}
+}
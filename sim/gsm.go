@@ -0,0 +1,94 @@
+package sim
+
+import "strings"
+
+// gsmEscape introduces a character of the GSM extension table
+const gsmEscape = 0x1B
+
+// gsmAlphabet is the GSM 03.38 default alphabet indexed by code
+const gsmAlphabet = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsmExtension maps characters of the extension table to their codes
+var gsmExtension = map[rune]byte{
+	'^': 0x14, '{': 0x28, '}': 0x29, '\\': 0x2F,
+	'[': 0x3C, '~': 0x3D, ']': 0x3E, '|': 0x40, '€': 0x65,
+}
+
+// gsmCodes maps characters of the default alphabet to their codes
+var gsmCodes = func() map[rune]byte {
+	codes := make(map[rune]byte)
+	for i, r := range []rune(gsmAlphabet) {
+		if r != gsmEscape {
+			codes[r] = byte(i)
+		}
+	}
+	return codes
+}()
+
+// transliterations replace common characters missing from the GSM alphabet
+var transliterations = strings.NewReplacer(
+	"á", "a", "â", "a", "ã", "a", "ā", "a", "ą", "a", "À", "A", "Á", "A", "Â", "A", "Ã", "A",
+	"ç", "c", "č", "c", "ć", "c", "Č", "C", "Ć", "C",
+	"ď", "d", "Ď", "D", "đ", "d", "Đ", "D",
+	"ê", "e", "ë", "e", "ě", "e", "ę", "e", "ē", "e", "È", "E", "Ê", "E", "Ë", "E", "Ě", "E",
+	"í", "i", "î", "i", "ï", "i", "ī", "i", "Ì", "I", "Í", "I", "Î", "I", "Ï", "I",
+	"ł", "l", "Ł", "L", "ľ", "l", "Ľ", "L",
+	"ń", "n", "ň", "n", "Ń", "N", "Ň", "N",
+	"ó", "o", "ô", "o", "õ", "o", "ő", "o", "Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O", "Ő", "O",
+	"ř", "r", "Ř", "R",
+	"ś", "s", "š", "s", "Ś", "S", "Š", "S",
+	"ť", "t", "Ť", "T",
+	"ú", "u", "û", "u", "ů", "u", "ű", "u", "Ù", "U", "Ú", "U", "Û", "U", "Ů", "U", "Ű", "U",
+	"ý", "y", "ÿ", "y", "Ý", "Y",
+	"ž", "z", "ź", "z", "ż", "z", "Ž", "Z", "Ź", "Z", "Ż", "Z",
+	"œ", "oe", "Œ", "OE",
+)
+
+// gsmLength returns the encoded size of a character, or 0 when it cannot be
+// represented
+func gsmLength(r rune) int {
+	if _, ok := gsmCodes[r]; ok {
+		return 1
+	}
+	if _, ok := gsmExtension[r]; ok {
+		return 2
+	}
+	return 0
+}
+
+// truncate transliterates the name to the GSM alphabet, replacing other
+// characters with "?", and shortens it to fit in the given number of bytes
+// without splitting extension characters
+func truncate(name string, size int) string {
+	var builder strings.Builder
+	used := 0
+	for _, r := range transliterations.Replace(strings.TrimSpace(name)) {
+		n := gsmLength(r)
+		if n == 0 {
+			r, n = '?', 1
+		}
+		if used+n > size {
+			break
+		}
+		builder.WriteRune(r)
+		used += n
+	}
+	return strings.TrimRight(builder.String(), " ")
+}
+
+// encodeGSM encodes a name produced by truncate in the unpacked GSM 7-bit
+// default alphabet used by SIM alpha identifiers
+func encodeGSM(name string) []byte {
+	encoded := make([]byte, 0, len(name))
+	for _, r := range name {
+		if code, ok := gsmCodes[r]; ok {
+			encoded = append(encoded, code)
+		} else if code, ok := gsmExtension[r]; ok {
+			encoded = append(encoded, gsmEscape, code)
+		} else {
+			encoded = append(encoded, gsmCodes['?'])
+		}
+	}
+	return encoded
+}
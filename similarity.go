@@ -0,0 +1,186 @@
+package vcard
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SimilarityWeights sets how much each field contributes to Similarity.
+// Fields missing on either card are left out and the remaining weights are
+// scaled to add up to one.
+type SimilarityWeights struct {
+	Name         float64
+	Email        float64
+	Phone        float64
+	Organization float64
+}
+
+// DefaultSimilarityWeights favours email and phone, which rarely collide
+// between different people, over names
+var DefaultSimilarityWeights = SimilarityWeights{
+	Name:         0.4,
+	Email:        0.3,
+	Phone:        0.2,
+	Organization: 0.1,
+}
+
+// Similarity scores how likely two cards describe the same person, from 0
+// (nothing in common) to 1 (identical), using DefaultSimilarityWeights
+func Similarity(a, b *VCard) float64 {
+	return SimilarityWithWeights(a, b, DefaultSimilarityWeights)
+}
+
+// SimilarityWithWeights scores two cards like Similarity with custom weights.
+// Names are compared fuzzily regardless of word order and case, emails by
+// their best matching pair, phones by their trailing nine digits so that
+// country code prefixes are ignored, and organizations fuzzily.
+func SimilarityWithWeights(a, b *VCard, weights SimilarityWeights) float64 {
+	fields := []struct {
+		weight float64
+		score  func() (float64, bool)
+	}{
+		{weights.Name, func() (float64, bool) { return nameSimilarity(personName(a), personName(b)) }},
+		{weights.Email, func() (float64, bool) {
+			return bestPairSimilarity(emailAddresses(a), emailAddresses(b), StringSimilarity)
+		}},
+		{weights.Phone, func() (float64, bool) { return bestPairSimilarity(phoneKeys(a), phoneKeys(b), exactSimilarity) }},
+		{weights.Organization, func() (float64, bool) { return nameSimilarity(a.organization.Name, b.organization.Name) }},
+	}
+
+	var score, total float64
+	for _, field := range fields {
+		if field.weight <= 0 {
+			continue
+		}
+		if similarity, ok := field.score(); ok {
+			score += field.weight * similarity
+			total += field.weight
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return score / total
+}
+
+// Levenshtein returns the edit distance between two strings: the number of
+// single-character insertions, deletions and substitutions turning a into b
+func Levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	if len(source) < len(target) {
+		source, target = target, source
+	}
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i, sr := range source {
+		current[0] = i + 1
+		for j, tr := range target {
+			cost := 1
+			if sr == tr {
+				cost = 0
+			}
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+// StringSimilarity returns the Levenshtein similarity of two strings, from 0
+// (completely different) to 1 (equal)
+func StringSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// nameSimilarity compares two names ignoring case, punctuation and word
+// order. It reports false when either name is empty.
+func nameSimilarity(a, b string) (float64, bool) {
+	wordsA, wordsB := nameWords(a), nameWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0, false
+	}
+
+	similarity := StringSimilarity(strings.Join(wordsA, " "), strings.Join(wordsB, " "))
+
+	sort.Strings(wordsA)
+	sort.Strings(wordsB)
+	return max(similarity, StringSimilarity(strings.Join(wordsA, " "), strings.Join(wordsB, " "))), true
+}
+
+// personName returns the card's formatted name without the organization and
+// email fallbacks
+func personName(card *VCard) string {
+	if card.fn != "" {
+		return card.fn
+	}
+	return card.name.FormattedName()
+}
+
+// nameWords returns the lower-cased words of a name
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// bestPairSimilarity returns the highest similarity between any value of a
+// and any value of b. It reports false when either list is empty.
+func bestPairSimilarity(a, b []string, similarity func(a, b string) float64) (float64, bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return 0, false
+	}
+
+	best := 0.0
+	for _, x := range a {
+		for _, y := range b {
+			best = max(best, similarity(x, y))
+		}
+	}
+	return best, true
+}
+
+// exactSimilarity returns 1 for equal strings and 0 otherwise
+func exactSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	return 0
+}
+
+// emailAddresses returns the card's lower-cased email addresses
+func emailAddresses(card *VCard) []string {
+	addresses := make([]string, 0, len(card.emails))
+	for _, email := range card.emails {
+		if address := strings.ToLower(strings.TrimSpace(email.Address)); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// phoneKeys returns the trailing nine digits of the card's phone numbers
+func phoneKeys(card *VCard) []string {
+	keys := make([]string, 0, len(card.phones))
+	for _, phone := range card.phones {
+		digits := phoneDigits(phone.Number)
+		if len(digits) > 9 {
+			digits = digits[len(digits)-9:]
+		}
+		if digits != "" {
+			keys = append(keys, digits)
+		}
+	}
+	return keys
+}
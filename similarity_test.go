@@ -0,0 +1,66 @@
+package vcard
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"Zoë", "Zoe", 1},
+		{"same", "same", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+		if got := Levenshtein(tt.b, tt.a); got != tt.expected {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.expected)
+		}
+	}
+}
+
+func TestStringSimilarity(t *testing.T) {
+	if got := StringSimilarity("", ""); got != 1 {
+		t.Errorf("Expected empty strings to be equal, got %v", got)
+	}
+	if got := StringSimilarity("kitten", "sitting"); math.Abs(got-(1-3.0/7)) > 1e-9 {
+		t.Errorf("Unexpected similarity %v", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	john := New().AddName("John", "Doe").AddEmail("john@example.com").AddPhone("+1 555 123 4567").AddOrganization("Acme")
+
+	same := New().SetFormattedName("doe, john").AddName("John", "Doe").AddEmail("JOHN@example.com").AddPhone("(555) 123-4567")
+	if got := Similarity(john, same); got != 1 {
+		t.Errorf("Expected identical person to score 1, got %v", got)
+	}
+
+	typo := New().AddName("Jon", "Doe").AddEmail("jon@example.com")
+	if got := Similarity(john, typo); got < 0.8 || got >= 1 {
+		t.Errorf("Expected close match for typo, got %v", got)
+	}
+
+	other := New().AddName("Jane", "Smith").AddEmail("jane@globex.com").AddPhone("+1 555 987 6543").AddOrganization("Globex")
+	if got := Similarity(john, other); got > 0.5 {
+		t.Errorf("Expected low score for different person, got %v", got)
+	}
+
+	if got := Similarity(New(), New()); got != 0 {
+		t.Errorf("Expected 0 for empty cards, got %v", got)
+	}
+
+	emailOnly := SimilarityWeights{Email: 1}
+	if got := SimilarityWithWeights(john, other, emailOnly); got >= 0.5 {
+		t.Errorf("Expected email-only score to ignore names, got %v", got)
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+// DirStore is a ContactStore keeping one file per card, named escaped UID +
+// ".vcf", in a directory. The files can be read, backed up and synced with
+// ordinary tools. Writes replace files atomically.
+type DirStore struct {
+	dir string
+}
+
+// OpenDir returns a store writing to the directory, creating it when missing
+func OpenDir(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("store: failed to create directory: %w", err)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+// Put stores the card, replacing any earlier version with the same UID
+func (s *DirStore) Put(card *vcard.VCard) error {
+	uid := card.GetUID()
+	if uid == "" {
+		return ErrMissingUID
+	}
+
+	content, err := card.String()
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(s.dir, ".put-*")
+	if err != nil {
+		return fmt.Errorf("store: failed to write card: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.WriteString(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("store: failed to write card: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("store: failed to write card: %w", err)
+	}
+	if err := os.Rename(temp.Name(), s.path(uid)); err != nil {
+		return fmt.Errorf("store: failed to write card: %w", err)
+	}
+	return nil
+}
+
+// Get returns the stored vCard content for the UID
+func (s *DirStore) Get(uid string) (string, error) {
+	data, err := os.ReadFile(s.path(uid))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("store: failed to read card: %w", err)
+	}
+	return string(data), nil
+}
+
+// Delete removes the card with the UID
+func (s *DirStore) Delete(uid string) error {
+	err := os.Remove(s.path(uid))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("store: failed to delete card: %w", err)
+	}
+	return nil
+}
+
+// UIDs returns the UIDs of all stored cards in sorted order
+func (s *DirStore) UIDs(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("store: failed to list cards: %w", err)
+	}
+
+	uids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), vcard.FileExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if uid, err := url.PathUnescape(name); err == nil {
+			uids = append(uids, uid)
+		}
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+// Close is a no-op; files are closed after every operation
+func (s *DirStore) Close() error {
+	return nil
+}
+
+// path returns the file of the UID. UIDs are escaped so values containing
+// slashes or dots map to a single file inside the directory.
+func (s *DirStore) path(uid string) string {
+	name := url.PathEscape(uid)
+	if name == "." || name == ".." {
+		name = strings.ReplaceAll(name, ".", "%2E")
+	}
+	return filepath.Join(s.dir, name+vcard.FileExtension)
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testListingStore exercises a store implementing Lister
+func testListingStore(t *testing.T, s interface {
+	ContactStore
+	Lister
+}) {
+	t.Helper()
+
+	if uids, err := s.UIDs(context.Background()); err != nil || uids == nil || len(uids) != 0 {
+		t.Errorf("Expected an empty, non-nil list, got %#v (%v)", uids, err)
+	}
+
+	if err := s.Put(newCard("urn:uuid:1/2", "John")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := s.Put(newCard("..", "Jane")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := s.Put(newCard("urn:uuid:1/2", "Jack")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := s.Put(newCard("", "No")); !errors.Is(err, ErrMissingUID) {
+		t.Errorf("Expected ErrMissingUID, got %v", err)
+	}
+
+	content, err := s.Get("urn:uuid:1/2")
+	if err != nil || !strings.Contains(content, "FN:Jack Doe") {
+		t.Errorf("Expected the latest version, got %q (%v)", content, err)
+	}
+
+	uids, err := s.UIDs(context.Background())
+	if err != nil || !reflect.DeepEqual(uids, []string{"..", "urn:uuid:1/2"}) {
+		t.Errorf("Unexpected UIDs %v (%v)", uids, err)
+	}
+
+	if err := s.Delete(".."); err != nil {
+		t.Errorf("Delete() returned error: %v", err)
+	}
+	if _, err := s.Get(".."); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after Delete, got %v", err)
+	}
+	if err := s.Delete(".."); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a second Delete, got %v", err)
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "contacts")
+	s, err := OpenDir(dir)
+	if err != nil {
+		t.Fatalf("OpenDir() returned error: %v", err)
+	}
+	testListingStore(t, s)
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "urn:uuid:1%2F2.vcf" {
+		t.Errorf("Expected one escaped file, got %v", entries)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testListingStore(t, NewMemoryStore())
+}
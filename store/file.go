@@ -0,0 +1,516 @@
+// Package store provides durable storage for vCards.
+//
+// FileStore keeps cards in an append-only .vcf file with a sidecar index
+// mapping each UID to the offset of its latest version, which is enough for
+// small applications that do not want to run a database:
+//
+//	contacts, err := store.Open("contacts.vcf")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer contacts.Close()
+//
+//	err = contacts.Put(card)
+//	content, err := contacts.Get(card.GetUID())
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.rumenx.com/vcard"
+)
+
+// IndexSuffix is appended to the data file path to name the index file
+const IndexSuffix = ".idx"
+
+// compactSuffix names the files written by Compact before they replace the
+// data file and the index
+const compactSuffix = ".compact"
+
+var (
+	// ErrNotFound is returned for UIDs that are not in the store
+	ErrNotFound = errors.New("store: card not found")
+
+	// ErrMissingUID is returned when storing a card without a UID
+	ErrMissingUID = errors.New("store: card has no UID")
+)
+
+// indexEntry is one line of the index file. Later entries for the same UID
+// replace earlier ones; deleted entries remove the UID.
+type indexEntry struct {
+	UID     string `json:"uid"`
+	Offset  int64  `json:"offset,omitempty"`
+	Length  int64  `json:"length,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// FileStore is a persistent card store backed by an append-only .vcf file
+// and an index file. It is safe for concurrent use within one process.
+type FileStore struct {
+	mu    sync.RWMutex
+	path  string
+	data  *os.File
+	index *os.File
+	cards map[string]indexEntry
+	size  int64
+
+	// synced is set once the directory entries of the files are durable
+	synced bool
+}
+
+// Open opens or creates the store at path. The index is read from
+// path+IndexSuffix, or rebuilt from the data file when it is missing. A
+// compaction interrupted by a crash is finished or discarded first.
+func Open(path string) (*FileStore, error) {
+	s := &FileStore{path: path}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Put stores the card, replacing any earlier version with the same UID. The
+// card and its index entry are synced to disk, along with the directory
+// holding the files on the first Put, before Put returns.
+func (s *FileStore) Put(card *vcard.VCard) error {
+	uid := card.GetUID()
+	if uid == "" {
+		return ErrMissingUID
+	}
+
+	content, err := card.String()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.data.WriteAt([]byte(content), s.size); err != nil {
+		return fmt.Errorf("store: failed to write card: %w", err)
+	}
+	if err := s.data.Sync(); err != nil {
+		return fmt.Errorf("store: failed to sync data file: %w", err)
+	}
+
+	entry := indexEntry{UID: uid, Offset: s.size, Length: int64(len(content))}
+	if err := s.appendIndex(entry); err != nil {
+		return err
+	}
+	if err := s.syncDirOnce(); err != nil {
+		return err
+	}
+
+	s.size += entry.Length
+	s.cards[uid] = entry
+	return nil
+}
+
+// Get returns the stored vCard content for the UID
+func (s *FileStore) Get(uid string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.cards[uid]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	buf := make([]byte, entry.Length)
+	if _, err := s.data.ReadAt(buf, entry.Offset); err != nil {
+		return "", fmt.Errorf("store: failed to read card: %w", err)
+	}
+	return string(buf), nil
+}
+
+// Delete removes the card with the UID. The data stays in the file until the
+// next Compact. Like Put, Delete syncs the index before it returns.
+func (s *FileStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.cards[uid]; !ok {
+		return ErrNotFound
+	}
+
+	if err := s.appendIndex(indexEntry{UID: uid, Deleted: true}); err != nil {
+		return err
+	}
+	if err := s.syncDirOnce(); err != nil {
+		return err
+	}
+
+	delete(s.cards, uid)
+	return nil
+}
+
+// UIDs returns the UIDs of all stored cards in sorted order
+func (s *FileStore) UIDs(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	uids := make([]string, 0, len(s.cards))
+	for uid := range s.cards {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+// Len returns the number of stored cards
+func (s *FileStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.cards)
+}
+
+// Compact rewrites the data and index files keeping only the latest version
+// of every stored card. The compacted files are written under temporary
+// names and committed by a single rename of the index; a crash before that
+// rename leaves the old files in place, and Open finishes a committed
+// compaction that was interrupted.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.writeCompacted(); err != nil {
+		os.Remove(s.path + compactSuffix)
+		os.Remove(s.path + IndexSuffix + ".tmp")
+		return err
+	}
+
+	// Commit: from here on the compacted files replace the old ones, even
+	// if the store crashes before the switch below completes
+	if err := os.Rename(s.path+IndexSuffix+".tmp", s.path+IndexSuffix+compactSuffix); err != nil {
+		os.Remove(s.path + compactSuffix)
+		os.Remove(s.path + IndexSuffix + ".tmp")
+		return fmt.Errorf("store: failed to commit compacted index: %w", err)
+	}
+
+	// Swap in the compacted files and reopen them, reloading whatever is on
+	// disk if the switch fails half way
+	closeErr := errors.Join(s.data.Close(), s.index.Close())
+	if err := s.open(); err != nil {
+		return errors.Join(closeErr, err)
+	}
+	return nil
+}
+
+// writeCompacted writes the latest version of every card to the compacted
+// data file and its index to a temporary file, and syncs both
+func (s *FileStore) writeCompacted() error {
+	data, err := os.Create(s.path + compactSuffix)
+	if err != nil {
+		return fmt.Errorf("store: failed to create compacted file: %w", err)
+	}
+	defer data.Close()
+
+	index, err := os.Create(s.path + IndexSuffix + ".tmp")
+	if err != nil {
+		return fmt.Errorf("store: failed to create compacted index: %w", err)
+	}
+	defer index.Close()
+
+	uids := make([]string, 0, len(s.cards))
+	for uid := range s.cards {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	encoder := json.NewEncoder(index)
+	var offset int64
+	for _, uid := range uids {
+		entry := s.cards[uid]
+		buf := make([]byte, entry.Length)
+		if _, err := s.data.ReadAt(buf, entry.Offset); err != nil {
+			return fmt.Errorf("store: failed to read card: %w", err)
+		}
+		if _, err := data.Write(buf); err != nil {
+			return fmt.Errorf("store: failed to write compacted file: %w", err)
+		}
+
+		entry.Offset = offset
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("store: failed to write compacted index: %w", err)
+		}
+		offset += entry.Length
+	}
+
+	if err := data.Sync(); err != nil {
+		return fmt.Errorf("store: failed to sync compacted file: %w", err)
+	}
+	if err := index.Sync(); err != nil {
+		return fmt.Errorf("store: failed to sync compacted index: %w", err)
+	}
+	// The compacted data file must be durable before the commit rename is
+	return syncDir(s.path)
+}
+
+// finishCompaction completes a committed compaction whose files were not
+// yet switched in, and removes the files of one that was not committed
+func finishCompaction(path string) error {
+	if _, err := os.Stat(path + IndexSuffix + compactSuffix); os.IsNotExist(err) {
+		for _, name := range []string{path + compactSuffix, path + IndexSuffix + ".tmp"} {
+			if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("store: failed to remove compacted file: %w", err)
+			}
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("store: failed to stat compacted index: %w", err)
+	}
+
+	// The data file is already in place if the switch stopped between the
+	// two renames
+	if err := os.Rename(path+compactSuffix, path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("store: failed to replace data file: %w", err)
+	}
+	if err := os.Rename(path+IndexSuffix+compactSuffix, path+IndexSuffix); err != nil {
+		return fmt.Errorf("store: failed to replace index file: %w", err)
+	}
+	return syncDir(path)
+}
+
+// Close closes the underlying files
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dataErr := s.data.Close()
+	indexErr := s.index.Close()
+	return errors.Join(dataErr, indexErr)
+}
+
+// open finishes an interrupted compaction, opens the files and reads the
+// index, rebuilding it from the data file when it is missing
+func (s *FileStore) open() error {
+	if err := finishCompaction(s.path); err != nil {
+		return err
+	}
+
+	data, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("store: failed to open data file: %w", err)
+	}
+
+	info, err := data.Stat()
+	if err != nil {
+		data.Close()
+		return fmt.Errorf("store: failed to stat data file: %w", err)
+	}
+	s.data = data
+	s.size = info.Size()
+	s.cards = make(map[string]indexEntry)
+
+	_, statErr := os.Stat(s.path + IndexSuffix)
+	if err := s.openIndex(); err != nil {
+		data.Close()
+		return err
+	}
+
+	if os.IsNotExist(statErr) {
+		err = s.rebuildIndex()
+	} else {
+		err = s.loadIndex()
+	}
+	if err != nil {
+		s.data.Close()
+		s.index.Close()
+		return err
+	}
+	return nil
+}
+
+// openIndex opens the index file for appending
+func (s *FileStore) openIndex() error {
+	index, err := os.OpenFile(s.path+IndexSuffix, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("store: failed to open index file: %w", err)
+	}
+	s.index = index
+	return nil
+}
+
+// appendIndex writes entries to the index file and syncs it. When the write
+// or the sync fails the index is truncated back to its previous size, so
+// that no torn or unsynced line is left for later entries to follow.
+func (s *FileStore) appendIndex(entries ...indexEntry) error {
+	var lines []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("store: failed to encode index entry: %w", err)
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	info, err := s.index.Stat()
+	if err != nil {
+		return fmt.Errorf("store: failed to stat index: %w", err)
+	}
+
+	if _, err := s.index.Write(lines); err != nil {
+		return errors.Join(fmt.Errorf("store: failed to write index: %w", err), s.truncateIndex(info.Size()))
+	}
+	if err := s.index.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("store: failed to sync index: %w", err), s.truncateIndex(info.Size()))
+	}
+	return nil
+}
+
+// truncateIndex cuts the index file back to size
+func (s *FileStore) truncateIndex(size int64) error {
+	if err := s.index.Truncate(size); err != nil {
+		return fmt.Errorf("store: failed to truncate index: %w", err)
+	}
+	return nil
+}
+
+// syncDirOnce syncs the directory holding the files the first time the store
+// is written to
+func (s *FileStore) syncDirOnce() error {
+	if s.synced {
+		return nil
+	}
+	if err := syncDir(s.path); err != nil {
+		return err
+	}
+	s.synced = true
+	return nil
+}
+
+// loadIndex replays the index file. Entries pointing past the end of the data
+// file, left by an interrupted write, are ignored, and so is a last line cut
+// short by a crash: it is truncated so that later entries start on a line of
+// their own.
+func (s *FileStore) loadIndex() error {
+	if _, err := s.index.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("store: failed to read index: %w", err)
+	}
+
+	reader := bufio.NewReader(s.index)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("store: failed to read index: %w", err)
+		}
+		if len(line) == 0 {
+			return nil
+		}
+
+		var entry indexEntry
+		if jsonErr := json.Unmarshal(line, &entry); jsonErr != nil {
+			if err != io.EOF {
+				return fmt.Errorf("store: corrupt index entry: %w", jsonErr)
+			}
+			if err := s.index.Truncate(offset); err != nil {
+				return fmt.Errorf("store: failed to truncate index: %w", err)
+			}
+			return nil
+		}
+
+		switch {
+		case entry.Deleted:
+			delete(s.cards, entry.UID)
+		case entry.Offset+entry.Length <= s.size:
+			s.cards[entry.UID] = entry
+		}
+
+		if err == io.EOF {
+			// A complete entry missing its line break
+			if _, err := s.index.Write([]byte{'\n'}); err != nil {
+				return fmt.Errorf("store: failed to write index: %w", err)
+			}
+			return nil
+		}
+		offset += int64(len(line))
+	}
+}
+
+// rebuildIndex scans the data file for cards and their UID properties and
+// writes a fresh index. Content lines are unfolded and grouped UID lines
+// (e.g. "item1.UID") are recognized. Deletions recorded only in a lost index
+// cannot be recovered.
+func (s *FileStore) rebuildIndex() error {
+	reader := bufio.NewReader(io.NewSectionReader(s.data, 0, s.size))
+
+	var entries []indexEntry
+	var offset, start int64
+	var uid, logical string
+	for {
+		line, err := reader.ReadString('\n')
+		trimmed := strings.TrimRight(line, "\r\n")
+
+		if strings.HasPrefix(trimmed, " ") || strings.HasPrefix(trimmed, "\t") {
+			logical += trimmed[1:]
+		} else {
+			// The previous content line is complete
+			if property, parseErr := vcard.ParseProperty(logical); parseErr == nil && property.Name == "UID" {
+				uid = property.Text()
+			}
+			logical = trimmed
+
+			switch {
+			case strings.EqualFold(trimmed, "BEGIN:VCARD"):
+				start, uid = offset, ""
+			case strings.EqualFold(trimmed, "END:VCARD") && uid != "":
+				entries = append(entries, indexEntry{UID: uid, Offset: start, Length: offset + int64(len(line)) - start})
+			}
+		}
+
+		offset += int64(len(line))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("store: failed to scan data file: %w", err)
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := s.appendIndex(entries...); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		s.cards[entry.UID] = entry
+	}
+	return nil
+}
+
+// syncDir syncs the directory holding path, so that files created or renamed
+// in it survive a crash. Windows cannot sync directories and persists the
+// entries with the files.
+func syncDir(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("store: failed to open directory: %w", err)
+	}
+	defer dir.Close()
+
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("store: failed to sync directory: %w", err)
+	}
+	return nil
+}
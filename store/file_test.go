@@ -0,0 +1,298 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func newCard(uid, first string) *vcard.VCard {
+	return vcard.New().AddName(first, "Doe").SetUID(uid)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.vcf")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+
+	if err := s.Put(newCard("1", "John")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := s.Put(newCard("2", "Jane")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := s.Put(newCard("1", "Johnny")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := s.Put(vcard.New().AddName("No", "UID")); !errors.Is(err, ErrMissingUID) {
+		t.Errorf("Expected ErrMissingUID, got %v", err)
+	}
+
+	content, err := s.Get("1")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	if !strings.Contains(content, "FN:Johnny Doe") {
+		t.Errorf("Expected latest version, got %s", content)
+	}
+
+	if err := s.Delete("2"); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if _, err := s.Get("2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := s.Delete("2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for second delete, got %v", err)
+	}
+	s.Close()
+
+	// Reopening replays the index
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer s.Close()
+
+	if uids, _ := s.UIDs(context.Background()); !reflect.DeepEqual(uids, []string{"1"}) {
+		t.Errorf("Expected UIDs [1], got %v", uids)
+	}
+	if content, _ := s.Get("1"); !strings.Contains(content, "FN:Johnny Doe") {
+		t.Errorf("Expected latest version after reopen, got %s", content)
+	}
+}
+
+func TestFileStoreCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.vcf")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer s.Close()
+
+	for _, name := range []string{"John", "Johnny", "Jonathan"} {
+		s.Put(newCard("1", name))
+	}
+	s.Put(newCard("2", "Jane"))
+	s.Delete("2")
+
+	before, _ := os.Stat(path)
+	if err := s.Compact(); err != nil {
+		t.Fatalf("Compact() returned error: %v", err)
+	}
+	after, _ := os.Stat(path)
+
+	if after.Size() >= before.Size() {
+		t.Errorf("Expected compaction to shrink the file (%d >= %d)", after.Size(), before.Size())
+	}
+	if content, _ := s.Get("1"); !strings.Contains(content, "FN:Jonathan Doe") {
+		t.Errorf("Expected latest version after compaction, got %s", content)
+	}
+
+	// The store stays writable after compaction
+	if err := s.Put(newCard("3", "Jim")); err != nil {
+		t.Fatalf("Put() after Compact() returned error: %v", err)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Expected 2 cards, got %d", s.Len())
+	}
+}
+
+func TestFileStoreInterruptedCompact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "contacts.vcf")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	for _, name := range []string{"John", "Johnny"} {
+		s.Put(newCard("1", name))
+	}
+	s.Put(newCard("2", "Jane"))
+	s.Close()
+	oldData, _ := os.ReadFile(path)
+	oldIndex, _ := os.ReadFile(path + IndexSuffix)
+
+	s, _ = Open(path)
+	if err := s.Compact(); err != nil {
+		t.Fatalf("Compact() returned error: %v", err)
+	}
+	s.Close()
+	newData, _ := os.ReadFile(path)
+	newIndex, _ := os.ReadFile(path + IndexSuffix)
+
+	write := func(name string, content []byte) {
+		t.Helper()
+		if err := os.WriteFile(name, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(name string) {
+		t.Helper()
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("%s: Open() returned error: %v", name, err)
+		}
+		defer s.Close()
+		for uid, want := range map[string]string{"1": "FN:Johnny Doe", "2": "FN:Jane Doe"} {
+			if content, _ := s.Get(uid); !strings.HasPrefix(content, "BEGIN:VCARD") || !strings.Contains(content, want) {
+				t.Errorf("%s: expected %q for UID %s, got %q", name, want, uid, content)
+			}
+		}
+		for _, suffix := range []string{compactSuffix, IndexSuffix + compactSuffix, IndexSuffix + ".tmp"} {
+			if _, err := os.Stat(path + suffix); !os.IsNotExist(err) {
+				t.Errorf("%s: expected %s to be cleaned up, got %v", name, suffix, err)
+			}
+		}
+	}
+
+	// Committed, crashed before the switch
+	write(path, oldData)
+	write(path+IndexSuffix, oldIndex)
+	write(path+compactSuffix, newData)
+	write(path+IndexSuffix+compactSuffix, newIndex)
+	check("before switch")
+	if data, _ := os.ReadFile(path); string(data) != string(newData) {
+		t.Errorf("Expected the compacted data file to be switched in")
+	}
+
+	// Committed, crashed between the data and index renames
+	write(path, newData)
+	write(path+IndexSuffix, oldIndex)
+	write(path+IndexSuffix+compactSuffix, newIndex)
+	check("between renames")
+
+	// Not committed: the compacted files are discarded
+	write(path, oldData)
+	write(path+IndexSuffix, oldIndex)
+	write(path+compactSuffix, newData)
+	write(path+IndexSuffix+".tmp", newIndex)
+	check("uncommitted")
+	if data, _ := os.ReadFile(path); string(data) != string(oldData) {
+		t.Errorf("Expected the old data file to be kept")
+	}
+}
+
+func TestFileStoreRebuildsIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.vcf")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	s.Put(newCard("a;b", "John"))
+	s.Put(newCard("c", "Jane"))
+	s.Close()
+
+	if err := os.Remove(path + IndexSuffix); err != nil {
+		t.Fatalf("Failed to remove index: %v", err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer s.Close()
+
+	if uids, _ := s.UIDs(context.Background()); !reflect.DeepEqual(uids, []string{"a;b", "c"}) {
+		t.Errorf("Expected rebuilt UIDs, got %v", uids)
+	}
+	if content, _ := s.Get("c"); !strings.HasPrefix(content, "BEGIN:VCARD") || !strings.HasSuffix(content, "END:VCARD\n") {
+		t.Errorf("Expected complete card, got %q", content)
+	}
+}
+
+func TestFileStoreTruncatedIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.vcf")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	s.Put(newCard("1", "John"))
+	s.Put(newCard("2", "Jane"))
+	s.Close()
+
+	// Cut the last entry short, as a crash while appending would
+	info, err := os.Stat(path + IndexSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path+IndexSuffix, info.Size()-5); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error for a truncated index: %v", err)
+	}
+	if uids, _ := s.UIDs(context.Background()); !reflect.DeepEqual(uids, []string{"1"}) {
+		t.Errorf("Expected the truncated entry to be dropped, got %v", uids)
+	}
+	if err := s.Put(newCard("3", "Jim")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error after appending to a repaired index: %v", err)
+	}
+	if uids, _ := s.UIDs(context.Background()); !reflect.DeepEqual(uids, []string{"1", "3"}) {
+		t.Errorf("Expected the entries written after the repair, got %v", uids)
+	}
+	s.Close()
+
+	// An entry missing only its line break is kept and the line completed
+	info, _ = os.Stat(path + IndexSuffix)
+	os.Truncate(path+IndexSuffix, info.Size()-1)
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer s.Close()
+	if uids, _ := s.UIDs(context.Background()); !reflect.DeepEqual(uids, []string{"1", "3"}) {
+		t.Errorf("Expected the entry without line break to be kept, got %v", uids)
+	}
+	data, err := os.ReadFile(path + IndexSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "}\n") {
+		t.Errorf("Expected the index to end with a complete line, got %q", data)
+	}
+}
+
+func TestFileStoreRebuildsFoldedIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.vcf")
+	uid := "urn:uuid:" + strings.Repeat("0123456789", 8)
+	content := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:John Doe\r\nUID:" + uid[:60] + "\r\n " + uid[60:] + "\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Jane Doe\r\nitem1.UID:jane\\,doe\r\nEND:VCARD\r\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer s.Close()
+
+	if uids, _ := s.UIDs(context.Background()); !reflect.DeepEqual(uids, []string{"jane,doe", uid}) {
+		t.Errorf("Expected unfolded and grouped UIDs, got %v", uids)
+	}
+	if card, _ := s.Get(uid); !strings.Contains(card, "FN:John Doe") {
+		t.Errorf("Expected the folded card, got %q", card)
+	}
+}
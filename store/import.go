@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+
+	"go.rumenx.com/vcard"
+)
+
+// Actions reported by Import
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	// DryRun computes the report without writing to the store
+	DryRun bool
+}
+
+// CardChange describes what an import does to one card
+type CardChange struct {
+	// UID identifies the card
+	UID string `json:"uid"`
+
+	// Action is ActionCreate, ActionUpdate or ActionUnchanged
+	Action string `json:"action"`
+
+	// Changes are the property changes from the stored card. A created
+	// card lists all its values as added.
+	Changes []vcard.Change `json:"changes,omitempty"`
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+
+	// Duplicates counts cards replacing an earlier card of the same import
+	// with the same UID
+	Duplicates int `json:"duplicates"`
+
+	// Cards lists the outcome per card in import order
+	Cards []CardChange `json:"cards"`
+}
+
+// Import stores the cards, comparing each with the stored version of its UID.
+// Unchanged cards are not written. With DryRun set, Import only reads from
+// the store and returns the report of what an import would do, which makes
+// it safe to preview large migrations. Import stops at the first failed
+// write or read and returns the report so far; cards without a UID fail with
+// ErrMissingUID.
+func Import(s ContactStore, cards []*vcard.VCard, opts ImportOptions) (ImportReport, error) {
+	report := ImportReport{Cards: []CardChange{}}
+	// planned holds the cards of this import by UID, standing in for the
+	// store contents in a dry run
+	planned := make(map[string]*vcard.VCard, len(cards))
+
+	for _, card := range cards {
+		uid := card.GetUID()
+		if uid == "" {
+			return report, ErrMissingUID
+		}
+
+		old, seen := planned[uid]
+		if seen {
+			report.Duplicates++
+		} else {
+			stored, err := s.Get(uid)
+			switch {
+			case errors.Is(err, ErrNotFound):
+			case err != nil:
+				return report, err
+			default:
+				// Content this package can't read is replaced as a whole
+				old, _ = vcard.Parse(stored)
+				if old == nil {
+					old = vcard.New()
+				}
+			}
+		}
+
+		change := CardChange{UID: uid, Changes: vcard.Diff(old, card)}
+		switch {
+		case old == nil:
+			change.Action = ActionCreate
+			report.Created++
+		case len(change.Changes) == 0:
+			change.Action = ActionUnchanged
+			report.Unchanged++
+		default:
+			change.Action = ActionUpdate
+			report.Updated++
+		}
+
+		if !opts.DryRun && change.Action != ActionUnchanged {
+			if err := s.Put(card); err != nil {
+				return report, err
+			}
+		}
+		planned[uid] = card
+		report.Cards = append(report.Cards, change)
+	}
+
+	return report, nil
+}
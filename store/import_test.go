@@ -0,0 +1,81 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+// sameCard reports whether the stored content holds the card
+func sameCard(content string, card *vcard.VCard) bool {
+	stored, err := vcard.Parse(content)
+	return err == nil && len(vcard.Diff(stored, card)) == 0
+}
+
+func TestImport(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "contacts.vcf"))
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Put(newCard("1", "John")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(newCard("2", "Jane")); err != nil {
+		t.Fatal(err)
+	}
+
+	cards := []*vcard.VCard{
+		newCard("1", "John"),
+		newCard("2", "Janet"),
+		newCard("3", "Jack"),
+		newCard("3", "Jackie"),
+	}
+
+	report, err := Import(s, cards, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Import() returned error: %v", err)
+	}
+	if report.Created != 1 || report.Updated != 2 || report.Unchanged != 1 || report.Duplicates != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	actions := []string{ActionUnchanged, ActionUpdate, ActionCreate, ActionUpdate}
+	for i, change := range report.Cards {
+		if change.Action != actions[i] {
+			t.Errorf("Card %d: expected %s, got %s", i, actions[i], change.Action)
+		}
+	}
+	if changes := report.Cards[1].Changes; len(changes) == 0 || changes[0].Property != "FN" {
+		t.Errorf("Expected the FN change of card 2, got %+v", changes)
+	}
+
+	if s.Len() != 2 {
+		t.Errorf("Dry run wrote to the store: %d cards", s.Len())
+	}
+	if content, _ := s.Get("2"); !sameCard(content, newCard("2", "Jane")) {
+		t.Errorf("Dry run changed card 2:\n%s", content)
+	}
+
+	applied, err := Import(s, cards, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import() returned error: %v", err)
+	}
+	if applied.Created != report.Created || applied.Updated != report.Updated || applied.Unchanged != report.Unchanged {
+		t.Errorf("Import differs from its dry run: %+v", applied)
+	}
+	if content, _ := s.Get("3"); !sameCard(content, newCard("3", "Jackie")) {
+		t.Errorf("Expected the last duplicate to win, got:\n%s", content)
+	}
+
+	again, _ := Import(s, cards[:3], ImportOptions{DryRun: true})
+	if again.Unchanged != 2 || again.Updated != 1 {
+		t.Errorf("Expected cards 1 and 2 unchanged after the import, got %+v", again)
+	}
+
+	if _, err := Import(s, []*vcard.VCard{vcard.New().AddName("No", "UID")}, ImportOptions{DryRun: true}); !errors.Is(err, ErrMissingUID) {
+		t.Errorf("Expected ErrMissingUID, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.rumenx.com/vcard"
+)
+
+// MemoryStore is a ContactStore holding cards in memory, for tests and
+// caches. It is safe for concurrent use.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	cards map[string]string
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cards: make(map[string]string)}
+}
+
+// Put stores the card, replacing any earlier version with the same UID
+func (s *MemoryStore) Put(card *vcard.VCard) error {
+	uid := card.GetUID()
+	if uid == "" {
+		return ErrMissingUID
+	}
+
+	content, err := card.String()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cards[uid] = content
+	return nil
+}
+
+// Get returns the stored vCard content for the UID
+func (s *MemoryStore) Get(uid string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	content, ok := s.cards[uid]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return content, nil
+}
+
+// Delete removes the card with the UID
+func (s *MemoryStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.cards[uid]; !ok {
+		return ErrNotFound
+	}
+	delete(s.cards, uid)
+	return nil
+}
+
+// UIDs returns the UIDs of all stored cards in sorted order
+func (s *MemoryStore) UIDs(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	uids := make([]string, 0, len(s.cards))
+	for uid := range s.cards {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+// Close is a no-op
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"go.rumenx.com/vcard"
+)
+
+// ErrConflict is returned when a conditional write finds the object changed
+var ErrConflict = errors.New("store: object was modified concurrently")
+
+// ObjectClient is the subset of an S3-compatible API used by ObjectStore.
+// Wrap the SDK of your choice (AWS, MinIO, R2, GCS interop) to implement it.
+type ObjectClient interface {
+	// GetObject returns the object data and ETag, or ErrNotFound
+	GetObject(ctx context.Context, key string) (data []byte, etag string, err error)
+
+	// PutObject writes the object and returns its new ETag. A non-empty
+	// ifMatch makes the write conditional (If-Match, or If-None-Match: * when
+	// ifMatch is "*"); failed conditions return ErrConflict.
+	PutObject(ctx context.Context, key string, data []byte, ifMatch string) (etag string, err error)
+
+	// DeleteObject removes the object, or returns ErrNotFound
+	DeleteObject(ctx context.Context, key string) error
+
+	// ListObjects returns the keys starting with prefix
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ObjectStore is a ContactStore keeping one object per card, named
+// prefix + escaped UID + ".vcf", in an S3-compatible bucket
+type ObjectStore struct {
+	client ObjectClient
+	prefix string
+}
+
+// NewObjectStore returns a store writing objects under the key prefix
+// (e.g. "contacts/")
+func NewObjectStore(client ObjectClient, prefix string) *ObjectStore {
+	return &ObjectStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Put stores the card unconditionally
+func (s *ObjectStore) Put(card *vcard.VCard) error {
+	_, err := s.PutIfMatch(context.Background(), card, "")
+	return err
+}
+
+// PutIfMatch stores the card only if the stored object still has the given
+// ETag, as returned by GetWithETag. Use "*" to create the card only if it does
+// not exist yet and an empty ETag to write unconditionally. It returns the
+// new ETag, or ErrConflict when the object was changed by someone else.
+func (s *ObjectStore) PutIfMatch(ctx context.Context, card *vcard.VCard, etag string) (string, error) {
+	uid := card.GetUID()
+	if uid == "" {
+		return "", ErrMissingUID
+	}
+
+	content, err := card.String()
+	if err != nil {
+		return "", err
+	}
+
+	return s.client.PutObject(ctx, s.key(uid), []byte(content), etag)
+}
+
+// Get returns the stored vCard content for the UID
+func (s *ObjectStore) Get(uid string) (string, error) {
+	content, _, err := s.GetWithETag(context.Background(), uid)
+	return content, err
+}
+
+// GetWithETag returns the stored vCard content for the UID with its ETag
+func (s *ObjectStore) GetWithETag(ctx context.Context, uid string) (string, string, error) {
+	data, etag, err := s.client.GetObject(ctx, s.key(uid))
+	if err != nil {
+		return "", "", err
+	}
+	return string(data), etag, nil
+}
+
+// Delete removes the card with the UID
+func (s *ObjectStore) Delete(uid string) error {
+	return s.client.DeleteObject(context.Background(), s.key(uid))
+}
+
+// UIDs returns the UIDs of all stored cards
+func (s *ObjectStore) UIDs(ctx context.Context) ([]string, error) {
+	keys, err := s.client.ListObjects(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(key, s.prefix), vcard.FileExtension)
+		if !ok {
+			continue
+		}
+		if uid, err := url.PathUnescape(name); err == nil {
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
+
+// Close is a no-op; the client is owned by the caller
+func (s *ObjectStore) Close() error {
+	return nil
+}
+
+// key returns the object key for the UID. UIDs are escaped so values such as
+// "urn:uuid:..." or ones containing slashes map to a single object.
+func (s *ObjectStore) key(uid string) string {
+	return s.prefix + url.PathEscape(uid) + vcard.FileExtension
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryClient is an in-memory ObjectClient with S3 conditional write rules
+type memoryClient struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	etags   map[string]string
+	version int
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{
+		objects: make(map[string][]byte),
+		etags:   make(map[string]string),
+	}
+}
+
+func (c *memoryClient) GetObject(ctx context.Context, key string) ([]byte, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.objects[key]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return data, c.etags[key], nil
+}
+
+func (c *memoryClient) PutObject(ctx context.Context, key string, data []byte, ifMatch string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, exists := c.etags[key]
+	switch {
+	case ifMatch == "*" && exists:
+		return "", ErrConflict
+	case ifMatch != "" && ifMatch != "*" && ifMatch != current:
+		return "", ErrConflict
+	}
+
+	c.version++
+	etag := strconv.Itoa(c.version)
+	c.objects[key] = data
+	c.etags[key] = etag
+	return etag, nil
+}
+
+func (c *memoryClient) DeleteObject(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.objects[key]; !ok {
+		return ErrNotFound
+	}
+	delete(c.objects, key)
+	delete(c.etags, key)
+	return nil
+}
+
+func (c *memoryClient) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []string
+	for key := range c.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func TestObjectStore(t *testing.T) {
+	client := newMemoryClient()
+	s := NewObjectStore(client, "contacts/")
+	ctx := context.Background()
+
+	if err := s.Put(newCard("urn:uuid:1/2", "John")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if _, ok := client.objects["contacts/urn:uuid:1%2F2.vcf"]; !ok {
+		t.Errorf("Expected escaped object key, got %v", client.objects)
+	}
+
+	content, etag, err := s.GetWithETag(ctx, "urn:uuid:1/2")
+	if err != nil {
+		t.Fatalf("GetWithETag() returned error: %v", err)
+	}
+	if !strings.Contains(content, "FN:John Doe") {
+		t.Errorf("Unexpected content: %s", content)
+	}
+
+	// A write based on the current ETag succeeds, a stale one conflicts
+	if _, err := s.PutIfMatch(ctx, newCard("urn:uuid:1/2", "Johnny"), etag); err != nil {
+		t.Fatalf("PutIfMatch() returned error: %v", err)
+	}
+	if _, err := s.PutIfMatch(ctx, newCard("urn:uuid:1/2", "Jack"), etag); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict for stale ETag, got %v", err)
+	}
+	if _, err := s.PutIfMatch(ctx, newCard("urn:uuid:1/2", "Jack"), "*"); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict when creating an existing card, got %v", err)
+	}
+
+	s.Put(newCard("2", "Jane"))
+	uids, err := s.UIDs(ctx)
+	if err != nil {
+		t.Fatalf("UIDs() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(uids, []string{"2", "urn:uuid:1/2"}) {
+		t.Errorf("Unexpected UIDs: %v", uids)
+	}
+
+	if err := s.Delete("2"); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if _, err := s.Get("2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.rumenx.com/vcard"
+)
+
+// DefaultTable is the table used by NewSQLStore
+const DefaultTable = "vcards"
+
+// SQLStore is a ContactStore backed by a SQL database. It stores the raw card
+// with indexed name, email and phone columns for lookups. The statements use
+// SQLite syntax; open the database with a SQLite driver of your choice:
+//
+//	import _ "modernc.org/sqlite" // or github.com/mattn/go-sqlite3
+//
+//	db, err := sql.Open("sqlite", "contacts.db")
+//	contacts, err := store.NewSQLStore(db)
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore creates the vcards table and its indexes when missing and
+// returns a store using it
+func NewSQLStore(db *sql.DB) (*SQLStore, error) {
+	return NewSQLStoreWithTable(db, DefaultTable)
+}
+
+// NewSQLStoreWithTable is like NewSQLStore but uses the given table name
+func NewSQLStoreWithTable(db *sql.DB, table string) (*SQLStore, error) {
+	if !validIdentifier(table) {
+		return nil, fmt.Errorf("store: invalid table name: %q", table)
+	}
+
+	s := &SQLStore{db: db, table: table}
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS ` + table + ` (
+			uid TEXT PRIMARY KEY,
+			content TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			email TEXT NOT NULL DEFAULT '',
+			phone TEXT NOT NULL DEFAULT '',
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + table + `_name ON ` + table + ` (name)`,
+		`CREATE INDEX IF NOT EXISTS ` + table + `_email ON ` + table + ` (email)`,
+		`CREATE INDEX IF NOT EXISTS ` + table + `_phone ON ` + table + ` (phone)`,
+	}
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, fmt.Errorf("store: failed to create schema: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Put stores the card, replacing any earlier version with the same UID
+func (s *SQLStore) Put(card *vcard.VCard) error {
+	return s.PutContext(context.Background(), card)
+}
+
+// PutContext is like Put but honours the context
+func (s *SQLStore) PutContext(ctx context.Context, card *vcard.VCard) error {
+	uid := card.GetUID()
+	if uid == "" {
+		return ErrMissingUID
+	}
+
+	content, err := card.String()
+	if err != nil {
+		return err
+	}
+
+	columns := cardColumns(card)
+	_, err = s.db.ExecContext(ctx, `INSERT INTO `+s.table+` (uid, content, name, email, phone, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (uid) DO UPDATE SET content = excluded.content, name = excluded.name,
+			email = excluded.email, phone = excluded.phone, updated_at = excluded.updated_at`,
+		uid, content, columns.name, columns.email, columns.phone, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("store: failed to write card: %w", err)
+	}
+	return nil
+}
+
+// Get returns the stored vCard content for the UID
+func (s *SQLStore) Get(uid string) (string, error) {
+	var content string
+	err := s.db.QueryRow(`SELECT content FROM `+s.table+` WHERE uid = ?`, uid).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("store: failed to read card: %w", err)
+	}
+	return content, nil
+}
+
+// Delete removes the card with the UID
+func (s *SQLStore) Delete(uid string) error {
+	result, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE uid = ?`, uid)
+	if err != nil {
+		return fmt.Errorf("store: failed to delete card: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FindByEmail returns the UIDs of cards whose primary email matches,
+// ignoring case
+func (s *SQLStore) FindByEmail(email string) ([]string, error) {
+	return s.find(context.Background(), `email = ?`, strings.ToLower(email))
+}
+
+// FindByPhone returns the UIDs of cards whose primary phone number matches
+// after removing formatting characters
+func (s *SQLStore) FindByPhone(phone string) ([]string, error) {
+	return s.find(context.Background(), `phone = ?`, normalizePhone(phone))
+}
+
+// FindByName returns the UIDs of cards whose formatted name contains the text
+func (s *SQLStore) FindByName(text string) ([]string, error) {
+	return s.find(context.Background(), `name LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(text)+"%")
+}
+
+// UIDs returns the UIDs of all stored cards in sorted order
+func (s *SQLStore) UIDs(ctx context.Context) ([]string, error) {
+	return s.find(ctx, `1 = 1`)
+}
+
+// Close closes the database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// find returns the sorted UIDs of rows matching the condition
+func (s *SQLStore) find(ctx context.Context, condition string, args ...any) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT uid FROM `+s.table+` WHERE `+condition+` ORDER BY uid`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: failed to query cards: %w", err)
+	}
+	defer rows.Close()
+
+	// An empty list rather than nil, so it encodes as [] in JSON
+	uids := make([]string, 0)
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("store: failed to query cards: %w", err)
+		}
+		uids = append(uids, uid)
+	}
+	return uids, rows.Err()
+}
+
+// likeEscaper escapes LIKE wildcards in search text
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// indexedColumns holds the searchable values of a card
+type indexedColumns struct {
+	name  string
+	email string
+	phone string
+}
+
+// cardColumns extracts the normalized searchable values of a card
+func cardColumns(card *vcard.VCard) indexedColumns {
+	return indexedColumns{
+		name:  card.GetFormattedName(),
+		email: strings.ToLower(card.GetEmail()),
+		phone: normalizePhone(card.GetPhone()),
+	}
+}
+
+// normalizePhone keeps the digits and a leading plus sign of a phone number
+func normalizePhone(phone string) string {
+	var builder strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// validIdentifier reports whether the table name is safe to use unquoted
+func validIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestCardColumns(t *testing.T) {
+	card := vcard.New().
+		AddName("John", "Doe").
+		AddEmail("John.Doe@Example.com").
+		AddPhone("+1 (555) 123-4567")
+
+	columns := cardColumns(card)
+	if columns.name != "John Doe" {
+		t.Errorf("Expected name John Doe, got %q", columns.name)
+	}
+	if columns.email != "john.doe@example.com" {
+		t.Errorf("Expected lower-cased email, got %q", columns.email)
+	}
+	if columns.phone != "+15551234567" {
+		t.Errorf("Expected normalized phone, got %q", columns.phone)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := map[string]string{
+		"+44 20 7946 0958": "+442079460958",
+		"555.123.4567":     "5551234567",
+		" +1-800-FLOWERS":  "+1800",
+		"12+34":            "1234",
+		"":                 "",
+	}
+
+	for input, expected := range tests {
+		if got := normalizePhone(input); got != expected {
+			t.Errorf("normalizePhone(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestSQLStoreTableName(t *testing.T) {
+	for _, name := range []string{"", "1cards", "cards; DROP TABLE users", "my-cards"} {
+		if _, err := NewSQLStoreWithTable(nil, name); err == nil {
+			t.Errorf("Expected error for table name %q", name)
+		}
+	}
+
+	for _, name := range []string{"vcards", "crm_contacts2", "_cards"} {
+		if !validIdentifier(name) {
+			t.Errorf("Expected table name %q to be valid", name)
+		}
+	}
+}
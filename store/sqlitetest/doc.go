@@ -0,0 +1,6 @@
+// Package sqlitetest runs the statements of store.SQLStore against a real
+// SQLite database. It is a separate module so the store package does not
+// depend on a SQLite driver, and its tests are behind the sqlite build tag:
+//
+//	cd store/sqlitetest && go mod tidy && go test -tags sqlite ./...
+package sqlitetest
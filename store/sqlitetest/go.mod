@@ -0,0 +1,10 @@
+module go.rumenx.com/vcard/store/sqlitetest
+
+go 1.23.6
+
+require (
+	go.rumenx.com/vcard v0.0.0
+	modernc.org/sqlite v1.39.0
+)
+
+replace go.rumenx.com/vcard => ../../
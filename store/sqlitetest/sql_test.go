@@ -0,0 +1,138 @@
+//go:build sqlite
+
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/store"
+
+	_ "modernc.org/sqlite"
+)
+
+// openDB opens a SQLite database in a temporary directory
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "contacts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLStore(t *testing.T) {
+	db := openDB(t)
+	s, err := store.NewSQLStore(db)
+	if err != nil {
+		t.Fatalf("NewSQLStore() returned error: %v", err)
+	}
+
+	jane := vcard.New().AddName("Jane", "Doe").SetUID("jane").AddEmail("Jane@Example.com").AddPhone("+1 (555) 010-0001")
+	john := vcard.New().AddName("John", "100% Roe_").SetUID("john").AddEmail("john@example.com")
+	for _, card := range []*vcard.VCard{jane, john} {
+		if err := s.Put(card); err != nil {
+			t.Fatalf("Put() returned error: %v", err)
+		}
+	}
+	if err := s.Put(vcard.New().AddName("No", "UID")); !errors.Is(err, store.ErrMissingUID) {
+		t.Errorf("Expected ErrMissingUID, got %v", err)
+	}
+
+	// Putting the same UID again updates the row
+	if err := s.Put(jane.Clone().AddNote("Updated").AddEmail("jane@work.example.com")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	content, err := s.Get("jane")
+	if err != nil || !strings.Contains(content, "NOTE:Updated") {
+		t.Errorf("Expected the updated card, got %q (%v)", content, err)
+	}
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ` + store.DefaultTable).Scan(&rows); err != nil || rows != 2 {
+		t.Errorf("Expected 2 rows after the upsert, got %d (%v)", rows, err)
+	}
+
+	finds := []struct {
+		name     string
+		find     func(string) ([]string, error)
+		query    string
+		expected []string
+	}{
+		{"FindByEmail", s.FindByEmail, "JANE@example.COM", []string{"jane"}},
+		{"FindByPhone", s.FindByPhone, "+15550100001", []string{"jane"}},
+		{"FindByName", s.FindByName, "Doe", []string{"jane"}},
+		{"FindByName", s.FindByName, "100%", []string{"john"}},
+		{"FindByName", s.FindByName, "Roe_", []string{"john"}},
+		{"FindByName", s.FindByName, "_", []string{"john"}},
+		{"FindByName", s.FindByName, "J", []string{"jane", "john"}},
+	}
+	for _, find := range finds {
+		uids, err := find.find(find.query)
+		if err != nil || !reflect.DeepEqual(uids, find.expected) {
+			t.Errorf("%s(%q) = %v (%v), want %v", find.name, find.query, uids, err, find.expected)
+		}
+	}
+
+	uids, err := s.UIDs(context.Background())
+	if err != nil || !reflect.DeepEqual(uids, []string{"jane", "john"}) {
+		t.Errorf("Unexpected UIDs %v (%v)", uids, err)
+	}
+
+	if err := s.Delete("john"); err != nil {
+		t.Errorf("Delete() returned error: %v", err)
+	}
+	if err := s.Delete("john"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a second delete, got %v", err)
+	}
+	if _, err := s.Get("john"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSQLStoreEmpty(t *testing.T) {
+	s, err := store.NewSQLStore(openDB(t))
+	if err != nil {
+		t.Fatalf("NewSQLStore() returned error: %v", err)
+	}
+
+	uids, err := s.UIDs(context.Background())
+	if err != nil || uids == nil || len(uids) != 0 {
+		t.Errorf("Expected an empty, non-nil list, got %#v (%v)", uids, err)
+	}
+}
+
+func TestSQLStoreReopen(t *testing.T) {
+	db := openDB(t)
+	s, err := store.NewSQLStoreWithTable(db, "crm_contacts")
+	if err != nil {
+		t.Fatalf("NewSQLStoreWithTable() returned error: %v", err)
+	}
+	if err := s.Put(vcard.New().AddName("Jane", "Doe").SetUID("jane")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+
+	// Creating the schema again keeps the stored cards
+	s, err = store.NewSQLStoreWithTable(db, "crm_contacts")
+	if err != nil {
+		t.Fatalf("NewSQLStoreWithTable() returned error: %v", err)
+	}
+	if _, err := s.Get("jane"); err != nil {
+		t.Errorf("Expected the card to survive reopening, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.PutContext(ctx, vcard.New().AddName("John", "Roe").SetUID("john")); err == nil {
+		t.Error("Expected an error for a canceled context")
+	}
+	if _, err := s.UIDs(ctx); err == nil {
+		t.Error("Expected an error listing with a canceled context")
+	}
+}
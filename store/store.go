@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+
+	"go.rumenx.com/vcard"
+)
+
+// ContactStore is implemented by the card stores in this package
+type ContactStore interface {
+	// Put stores the card, replacing any earlier version with the same UID
+	Put(card *vcard.VCard) error
+
+	// Get returns the stored vCard content for the UID, or ErrNotFound
+	Get(uid string) (string, error)
+
+	// Delete removes the card with the UID, or returns ErrNotFound
+	Delete(uid string) error
+
+	// Close releases the resources held by the store
+	Close() error
+}
+
+// Lister is implemented by stores that can list their cards, which the
+// server package needs to serve collections
+type Lister interface {
+	// UIDs returns the UIDs of all stored cards
+	UIDs(ctx context.Context) ([]string, error)
+}
+
+var (
+	_ ContactStore = (*FileStore)(nil)
+	_ ContactStore = (*SQLStore)(nil)
+	_ ContactStore = (*ObjectStore)(nil)
+	_ ContactStore = (*DirStore)(nil)
+	_ ContactStore = (*MemoryStore)(nil)
+
+	_ Lister = (*FileStore)(nil)
+	_ Lister = (*SQLStore)(nil)
+	_ Lister = (*ObjectStore)(nil)
+	_ Lister = (*DirStore)(nil)
+	_ Lister = (*MemoryStore)(nil)
+)
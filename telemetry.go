@@ -0,0 +1,103 @@
+package vcard
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Span names of traced operations
+const (
+	SpanParse    = "vcard.parse"
+	SpanGenerate = "vcard.generate"
+	SpanBulk     = "vcard.bulk"
+)
+
+// Attribute keys recorded on spans
+const (
+	AttrCards   = "vcard.cards"
+	AttrBytes   = "vcard.bytes"
+	AttrVersion = "vcard.version"
+)
+
+// Tracer starts spans around parse, generate and bulk operations, so export
+// latency can be traced in production. The vcardotel module implements it
+// over an OpenTelemetry TracerProvider; attach it to a context with
+// ContextWithTracer, or set the adapters' Tracer option.
+type Tracer interface {
+	// Start starts the named span as a child of the span in ctx
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation
+type Span interface {
+	// SetAttributes records attributes such as the card count and size
+	SetAttributes(attrs ...slog.Attr)
+
+	// End ends the span, marking it failed when err is not nil
+	End(err error)
+}
+
+// tracerKey is the context key of the Tracer
+type tracerKey struct{}
+
+// ContextWithTracer returns a copy of ctx in which the ParseContext and
+// StringContext operations are traced with the tracer. A nil tracer
+// returns ctx unchanged.
+func ContextWithTracer(ctx context.Context, tracer Tracer) context.Context {
+	if tracer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// startSpan starts the named span with the tracer in ctx, or a span doing
+// nothing when there is none
+func startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if tracer, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		return tracer.Start(ctx, name)
+	}
+	return ctx, noopSpan{}
+}
+
+// noopSpan is the span of untraced operations
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) End(error)                  {}
+
+// ParseContext is Parse traced as a SpanParse span with the size of the text
+func ParseContext(ctx context.Context, text string) (*VCard, error) {
+	_, span := startSpan(ctx, SpanParse)
+	span.SetAttributes(slog.Int(AttrBytes, len(text)))
+
+	card, err := Parse(text)
+	if err == nil {
+		span.SetAttributes(slog.Int(AttrCards, 1), slog.String(AttrVersion, card.version.String()))
+	}
+	span.End(err)
+	return card, err
+}
+
+// StringContext is String traced as a SpanGenerate span with the size of the
+// content
+func (v *VCard) StringContext(ctx context.Context) (string, error) {
+	_, span := startSpan(ctx, SpanGenerate)
+	span.SetAttributes(slog.Int(AttrCards, 1), slog.String(AttrVersion, v.version.String()))
+
+	content, err := v.String()
+	span.SetAttributes(slog.Int(AttrBytes, len(content)))
+	span.End(err)
+	return content, err
+}
+
+// StringContext is String traced as a SpanBulk span with the card count and
+// the size of the document
+func (b *AddressBook) StringContext(ctx context.Context) (string, error) {
+	_, span := startSpan(ctx, SpanBulk)
+	span.SetAttributes(slog.Int(AttrCards, len(b.cards)))
+
+	content, err := b.String()
+	span.SetAttributes(slog.Int(AttrBytes, len(content)))
+	span.End(err)
+	return content, err
+}
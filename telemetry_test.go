@@ -0,0 +1,113 @@
+package vcard
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// recordingTracer records the spans it starts
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name  string
+	attrs map[string]slog.Value
+	err   error
+	ended bool
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordedSpan{name: name, attrs: make(map[string]slog.Value)}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) End(err error) {
+	s.err = err
+	s.ended = true
+}
+
+func TestTracedOperations(t *testing.T) {
+	tracer := &recordingTracer{}
+	ctx := ContextWithTracer(context.Background(), tracer)
+
+	card := New().AddName("Jane", "Doe").SetVersion(Version40)
+	content, err := card.StringContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseContext(ctx, content); err != nil {
+		t.Fatal(err)
+	}
+	book := NewAddressBook(card, New().AddName("John", "Doe"))
+	bulk, err := book.StringContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name  string
+		cards int64
+		bytes int
+	}{
+		{SpanGenerate, 1, len(content)},
+		{SpanParse, 1, len(content)},
+		{SpanBulk, 2, len(bulk)},
+	}
+	if len(tracer.spans) != len(want) {
+		t.Fatalf("Expected %d spans, got %d", len(want), len(tracer.spans))
+	}
+	for i, w := range want {
+		span := tracer.spans[i]
+		if span.name != w.name || !span.ended || span.err != nil {
+			t.Errorf("span %d = %+v, want ended %s", i, span, w.name)
+		}
+		if got := span.attrs[AttrCards].Int64(); got != w.cards {
+			t.Errorf("%s cards = %d, want %d", w.name, got, w.cards)
+		}
+		if got := span.attrs[AttrBytes].Int64(); got != int64(w.bytes) {
+			t.Errorf("%s bytes = %d, want %d", w.name, got, w.bytes)
+		}
+	}
+	if got := tracer.spans[0].attrs[AttrVersion].String(); got != "4.0" {
+		t.Errorf("generate version = %q, want 4.0", got)
+	}
+}
+
+func TestTracedFailure(t *testing.T) {
+	tracer := &recordingTracer{}
+	ctx := ContextWithTracer(context.Background(), tracer)
+
+	if _, err := ParseContext(ctx, "not a vcard"); err == nil {
+		t.Fatal("Expected a parse error")
+	}
+	if _, err := New().StringContext(ctx); err == nil {
+		t.Fatal("Expected a validation error")
+	}
+	for _, span := range tracer.spans {
+		if span.err == nil {
+			t.Errorf("Expected %s to record the error", span.name)
+		}
+	}
+}
+
+func TestUntracedContext(t *testing.T) {
+	content, err := New().AddName("Jane", "Doe").StringContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseContext(context.Background(), content); err != nil {
+		t.Fatal(err)
+	}
+	if ctx := ContextWithTracer(context.Background(), nil); ctx.Value(tracerKey{}) != nil {
+		t.Error("Expected a nil tracer to leave the context untraced")
+	}
+}
@@ -0,0 +1,248 @@
+package vcard
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// EmployeeInfo contains the inputs for EmployeeCard
+type EmployeeInfo struct {
+	First        string
+	Last         string
+	Title        string
+	Department   string
+	Organization string
+	Email        string
+	Phone        string
+	Mobile       string
+	EmployeeID   string
+}
+
+// EmployeeCard builds a work contact card with preferred work email and phone
+func EmployeeCard(info EmployeeInfo) *VCard {
+	card := New()
+	card.AddName(info.First, info.Last)
+	card.SetOrganization(Organization{
+		Name:       info.Organization,
+		Department: info.Department,
+		Title:      info.Title,
+	})
+
+	if info.Email != "" {
+		card.AddEmailWithPreference(info.Email, EmailWork, true)
+	}
+	if info.Phone != "" {
+		card.AddPhoneWithPreference(info.Phone, PhoneWork, true)
+	}
+	if info.Mobile != "" {
+		card.AddPhone(info.Mobile, PhoneMobile)
+	}
+	if info.EmployeeID != "" {
+		card.AddCustomProperty("X-EMPLOYEE-ID", info.EmployeeID)
+	}
+
+	return card
+}
+
+// SupportDeskInfo contains the inputs for SupportDeskCard
+type SupportDeskInfo struct {
+	Organization string
+	Email        string
+	Phone        string
+	URL          string
+	Hours        string
+}
+
+// SupportDeskCard builds a vCard 4.0 organization card for a help desk
+func SupportDeskCard(info SupportDeskInfo) *VCard {
+	card := NewWithVersion(Version40)
+	card.SetKind(KindOrg)
+	card.AddOrganization(info.Organization)
+	card.SetFormattedName(info.Organization + " Support")
+
+	if info.Email != "" {
+		card.AddEmailWithPreference(info.Email, EmailWork, true)
+	}
+	if info.Phone != "" {
+		card.AddPhoneWithPreference(info.Phone, PhoneWork, true)
+	}
+	if info.URL != "" {
+		card.AddURL(info.URL, URLWork)
+	}
+	if info.Hours != "" {
+		card.AddNote("Support hours: " + info.Hours)
+	}
+
+	return card
+}
+
+// VenueInfo contains the inputs for VenueCard
+type VenueInfo struct {
+	Name    string
+	Address Address
+	Geo     *Geo
+	Phone   string
+	URL     string
+}
+
+// VenueCard builds a vCard 4.0 location card for a venue or meeting room
+func VenueCard(info VenueInfo) *VCard {
+	card := NewWithVersion(Version40)
+	card.SetKind(KindLocation)
+	card.SetFormattedName(info.Name)
+
+	if info.Address != (Address{}) {
+		card.AddAddresses([]Address{info.Address})
+	}
+	if info.Geo != nil {
+		card.SetGeo(info.Geo.Latitude, info.Geo.Longitude)
+	}
+	if info.Phone != "" {
+		card.AddPhone(info.Phone, PhoneWork)
+	}
+	if info.URL != "" {
+		card.AddURL(info.URL, URLWork)
+	}
+
+	return card
+}
+
+// EventBoothInfo contains the inputs for EventBoothCard
+type EventBoothInfo struct {
+	Organization string
+	Event        string
+	Booth        string
+	Email        string
+	URL          string
+}
+
+// EventBoothCard builds a vCard 4.0 organization card for an exhibitor booth
+func EventBoothCard(info EventBoothInfo) *VCard {
+	card := NewWithVersion(Version40)
+	card.SetKind(KindOrg)
+	card.AddOrganization(info.Organization)
+
+	if info.Email != "" {
+		card.AddEmailWithPreference(info.Email, EmailWork, true)
+	}
+	if info.URL != "" {
+		card.AddURL(info.URL, URLWork)
+	}
+	if info.Booth != "" && info.Event != "" {
+		card.AddNote(fmt.Sprintf("Visit us at booth %s, %s", info.Booth, info.Event))
+	}
+
+	return card
+}
+
+// TemplateFunc builds a card from string parameters, e.g. query values
+type TemplateFunc func(params map[string]string) (*VCard, error)
+
+var (
+	templatesMu sync.RWMutex
+	templates   = map[string]TemplateFunc{
+		"employee": func(p map[string]string) (*VCard, error) {
+			return EmployeeCard(EmployeeInfo{
+				First:        p["first"],
+				Last:         p["last"],
+				Title:        p["title"],
+				Department:   p["department"],
+				Organization: p["organization"],
+				Email:        p["email"],
+				Phone:        p["phone"],
+				Mobile:       p["mobile"],
+				EmployeeID:   p["employeeId"],
+			}), nil
+		},
+		"support-desk": func(p map[string]string) (*VCard, error) {
+			return SupportDeskCard(SupportDeskInfo{
+				Organization: p["organization"],
+				Email:        p["email"],
+				Phone:        p["phone"],
+				URL:          p["url"],
+				Hours:        p["hours"],
+			}), nil
+		},
+		"venue": func(p map[string]string) (*VCard, error) {
+			info := VenueInfo{
+				Name: p["name"],
+				Address: Address{
+					Street:     p["street"],
+					City:       p["city"],
+					State:      p["state"],
+					PostalCode: p["postalCode"],
+					Country:    p["country"],
+				},
+				Phone: p["phone"],
+				URL:   p["url"],
+			}
+			if p["latitude"] != "" || p["longitude"] != "" {
+				lat, err := strconv.ParseFloat(p["latitude"], 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid latitude: %w", err)
+				}
+				lon, err := strconv.ParseFloat(p["longitude"], 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid longitude: %w", err)
+				}
+				info.Geo = &Geo{Latitude: lat, Longitude: lon}
+			}
+			return VenueCard(info), nil
+		},
+		"event-booth": func(p map[string]string) (*VCard, error) {
+			return EventBoothCard(EventBoothInfo{
+				Organization: p["organization"],
+				Event:        p["event"],
+				Booth:        p["booth"],
+				Email:        p["email"],
+				URL:          p["url"],
+			}), nil
+		},
+	}
+)
+
+// RegisterTemplate adds or replaces a named template in the registry
+func RegisterTemplate(name string, fn TemplateFunc) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[name] = fn
+}
+
+// NewFromTemplate builds a card using a registered template. The built-in
+// templates are "employee", "support-desk", "venue" and "event-booth"; their
+// parameters mirror the fields of the corresponding Info structs in
+// lowerCamelCase. The returned card is validated.
+func NewFromTemplate(name string, params map[string]string) (*VCard, error) {
+	templatesMu.RLock()
+	fn, ok := templates[name]
+	templatesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown vcard template: %q", name)
+	}
+
+	card, err := fn(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := card.Validate(); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// TemplateNames returns the names of all registered templates in sorted order
+func TemplateNames() []string {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,90 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmployeeCard(t *testing.T) {
+	card := EmployeeCard(EmployeeInfo{
+		First:        "John",
+		Last:         "Doe",
+		Title:        "Engineer",
+		Department:   "R&D",
+		Organization: "Acme Corp",
+		Email:        "john@acme.com",
+		Mobile:       "+1234567890",
+		EmployeeID:   "E42",
+	})
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Employee card should be valid: %v", err)
+	}
+
+	for _, expected := range []string{"ORG:Acme Corp;R&D", "TITLE:Engineer", "EMAIL;TYPE=WORK;PREF=1:john@acme.com", "TEL;TYPE=MOBILE:+1234567890", "X-EMPLOYEE-ID:E42"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in %s", expected, content)
+		}
+	}
+}
+
+func TestOrganizationTemplates(t *testing.T) {
+	desk := SupportDeskCard(SupportDeskInfo{Organization: "Acme", Email: "help@acme.com", Hours: "9-5"})
+	if desk.GetFormattedName() != "Acme Support" || desk.GetKind() != KindOrg || !desk.IsValid() {
+		t.Errorf("Unexpected support desk card: %s %s", desk.GetFormattedName(), desk.GetKind())
+	}
+
+	booth := EventBoothCard(EventBoothInfo{Organization: "Acme", Event: "GopherCon", Booth: "12"})
+	if booth.GetNote() != "Visit us at booth 12, GopherCon" || !booth.IsValid() {
+		t.Errorf("Unexpected booth card note: %s", booth.GetNote())
+	}
+
+	venue := VenueCard(VenueInfo{Name: "Room 4B", Geo: &Geo{Latitude: 1, Longitude: 2}})
+	if venue.GetKind() != KindLocation || len(venue.GetAddresses()) != 0 || !venue.IsValid() {
+		t.Error("Unexpected venue card")
+	}
+}
+
+func TestTemplateRegistry(t *testing.T) {
+	names := TemplateNames()
+	if strings.Join(names, ",") != "employee,event-booth,support-desk,venue" {
+		t.Errorf("Unexpected built-in templates: %v", names)
+	}
+
+	card, err := NewFromTemplate("venue", map[string]string{
+		"name":      "HQ",
+		"latitude":  "42.69",
+		"longitude": "23.32",
+	})
+	if err != nil {
+		t.Fatalf("NewFromTemplate() returned error: %v", err)
+	}
+	if card.GetGeo() == nil || card.GetGeo().Latitude != 42.69 {
+		t.Error("Expected venue geo from parameters")
+	}
+
+	if _, err := NewFromTemplate("venue", map[string]string{"name": "HQ", "latitude": "x"}); err == nil {
+		t.Error("Expected error for invalid latitude")
+	}
+	if _, err := NewFromTemplate("employee", map[string]string{}); err == nil {
+		t.Error("Expected validation error for empty employee")
+	}
+	if _, err := NewFromTemplate("missing", nil); err == nil {
+		t.Error("Expected error for unknown template")
+	}
+
+	RegisterTemplate("test-custom", func(p map[string]string) (*VCard, error) {
+		return New().AddName(p["first"], "Custom"), nil
+	})
+	defer func() {
+		templatesMu.Lock()
+		delete(templates, "test-custom")
+		templatesMu.Unlock()
+	}()
+
+	card, err = NewFromTemplate("test-custom", map[string]string{"first": "Jane"})
+	if err != nil || card.GetFormattedName() != "Jane Custom" {
+		t.Errorf("Custom template failed: %v", err)
+	}
+}
@@ -0,0 +1,533 @@
+// Package transform modifies contacts with small scripts, so migration and
+// bulk import tools can be configured without recompiling:
+//
+//	program, err := transform.Compile(`
+//		# Normalize the organization and tag the source
+//		org = upper(trim(org))
+//		fn = default(fn, first + " " + last)
+//		x-source = "legacy-crm"
+//	`)
+//	program.Apply(card)
+//
+// A script is a list of assignments, one per line or separated by
+// semicolons. The right-hand side concatenates string literals, fields and
+// function calls with +. Fields are read and assigned in order, so later
+// lines see the results of earlier ones.
+//
+// Fields are fn, first, last, middle, prefix, suffix, org, department,
+// title, role, note and uid, plus custom properties named with an x- prefix
+// (e.g. x-source). email, phone and url read the first value of the card
+// and cannot be assigned.
+//
+// Functions are upper(s), lower(s), title(s), trim(s), replace(s, old, new),
+// default(s, fallback...) returning the first non-empty argument, and
+// domain(s) returning the part of an email address after the @.
+//
+// A Program is a vcard.Enricher, so it runs in the Bulk handlers of the
+// framework adapters and in AddressBook.Enrich.
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"go.rumenx.com/vcard"
+)
+
+// ErrSyntax is returned for scripts that cannot be compiled
+var ErrSyntax = errors.New("transform: syntax error")
+
+// Program is a compiled script
+type Program struct {
+	assignments []assignment
+}
+
+// assignment sets a field to the value of an expression
+type assignment struct {
+	field string
+	value expression
+}
+
+// expression evaluates to a string for a card
+type expression interface {
+	eval(card *vcard.VCard) string
+}
+
+// literal is a string literal
+type literal string
+
+func (l literal) eval(*vcard.VCard) string { return string(l) }
+
+// fieldRef reads a field of the card
+type fieldRef string
+
+func (f fieldRef) eval(card *vcard.VCard) string { return getField(card, string(f)) }
+
+// concat joins the values of its expressions
+type concat []expression
+
+func (c concat) eval(card *vcard.VCard) string {
+	var b strings.Builder
+	for _, e := range c {
+		b.WriteString(e.eval(card))
+	}
+	return b.String()
+}
+
+// call applies a function to the values of its arguments
+type call struct {
+	fn   func(args []string) string
+	args []expression
+}
+
+func (c call) eval(card *vcard.VCard) string {
+	args := make([]string, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(card)
+	}
+	return c.fn(args)
+}
+
+// function is a script function and its number of arguments; a negative
+// arity accepts at least -arity arguments
+type function struct {
+	arity int
+	fn    func(args []string) string
+}
+
+// functions are the functions available to scripts
+var functions = map[string]function{
+	"upper": {1, func(args []string) string { return strings.ToUpper(args[0]) }},
+	"lower": {1, func(args []string) string { return strings.ToLower(args[0]) }},
+	// A Caser holds state, so each call gets its own and programs can run
+	// concurrently
+	"title": {1, func(args []string) string { return cases.Title(language.Und).String(args[0]) }},
+	"trim":  {1, func(args []string) string { return strings.TrimSpace(args[0]) }},
+	"replace": {3, func(args []string) string {
+		return strings.ReplaceAll(args[0], args[1], args[2])
+	}},
+	"default": {-1, func(args []string) string {
+		for _, arg := range args {
+			if strings.TrimSpace(arg) != "" {
+				return arg
+			}
+		}
+		return ""
+	}},
+	"domain": {1, func(args []string) string {
+		if _, domain, ok := strings.Cut(args[0], "@"); ok {
+			return strings.ToLower(domain)
+		}
+		return ""
+	}},
+}
+
+// fields are the assignable fields; readOnly fields can only be read
+var (
+	fields   = []string{"fn", "first", "last", "middle", "prefix", "suffix", "org", "department", "title", "role", "note", "uid"}
+	readOnly = []string{"email", "phone", "url"}
+)
+
+// Compile parses a script
+func Compile(source string) (*Program, error) {
+	p := &parser{lexer: lexer{source: source, line: 1}}
+	p.next()
+
+	program := &Program{}
+	for p.token.kind != tokenEOF {
+		if p.token.kind == tokenEnd {
+			p.next()
+			continue
+		}
+		a, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		program.assignments = append(program.assignments, a)
+	}
+	return program, nil
+}
+
+// MustCompile is like Compile but panics on errors, for scripts known at
+// compile time
+func MustCompile(source string) *Program {
+	program, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+	return program
+}
+
+// Apply runs the script on the card
+func (p *Program) Apply(card *vcard.VCard) {
+	if card == nil {
+		return
+	}
+	for _, a := range p.assignments {
+		setField(card, a.field, a.value.eval(card))
+	}
+}
+
+// Enrich runs the script on the card, implementing vcard.Enricher
+func (p *Program) Enrich(ctx context.Context, card *vcard.VCard) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.Apply(card)
+	return nil
+}
+
+// isCustomField reports whether a field names a custom property
+func isCustomField(field string) bool {
+	return len(field) > 2 && strings.EqualFold(field[:2], "x-")
+}
+
+// properties maps the read-only fields to their properties
+var properties = map[string]string{"email": "EMAIL", "phone": "TEL", "url": "URL"}
+
+// getField returns the value of a field
+func getField(card *vcard.VCard, field string) string {
+	name, org := card.GetName(), card.GetOrganization()
+	switch field {
+	case "fn":
+		return card.GetFormattedName()
+	case "first":
+		return name.First
+	case "last":
+		return name.Last
+	case "middle":
+		return name.Middle
+	case "prefix":
+		return name.Prefix
+	case "suffix":
+		return name.Suffix
+	case "org":
+		return org.Name
+	case "department":
+		return org.Department
+	case "title":
+		return org.Title
+	case "role":
+		return org.Role
+	case "note":
+		return card.GetNote()
+	case "uid":
+		return card.GetUID()
+	}
+
+	if property, ok := properties[field]; ok {
+		field = property
+	}
+	value, _ := vcard.GetFirst[string](card, field)
+	return value
+}
+
+// setField assigns a field
+func setField(card *vcard.VCard, field, value string) {
+	name, org := card.GetName(), card.GetOrganization()
+	switch field {
+	case "fn":
+		card.SetFormattedName(value)
+	case "first":
+		name.First = value
+		card.SetName(name)
+	case "last":
+		name.Last = value
+		card.SetName(name)
+	case "middle":
+		name.Middle = value
+		card.SetName(name)
+	case "prefix":
+		name.Prefix = value
+		card.SetName(name)
+	case "suffix":
+		name.Suffix = value
+		card.SetName(name)
+	case "org":
+		org.Name = value
+		card.SetOrganization(org)
+	case "department":
+		org.Department = value
+		card.SetOrganization(org)
+	case "title":
+		card.AddTitle(value)
+	case "role":
+		card.AddRole(value)
+	case "note":
+		card.AddNote(value)
+	case "uid":
+		card.SetUID(value)
+	default:
+		card.RemoveCustomProperty(field)
+		if value != "" {
+			card.AddCustomProperty(strings.ToUpper(field), value)
+		}
+	}
+}
+
+// parser compiles the tokens of a script
+type parser struct {
+	lexer lexer
+	token token
+}
+
+// next advances to the next token
+func (p *parser) next() {
+	p.token = p.lexer.next()
+}
+
+// errorf returns a syntax error at the current line. At an invalid token
+// the lexer's description of the problem is reported instead.
+func (p *parser) errorf(format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	if p.token.kind == tokenError {
+		message = p.token.text
+	}
+	return fmt.Errorf("%w: line %d: %s", ErrSyntax, p.token.line, message)
+}
+
+// assignment parses field = expression
+func (p *parser) assignment() (assignment, error) {
+	if p.token.kind != tokenIdent {
+		return assignment{}, p.errorf("expected a field name, got %s", p.token)
+	}
+	field := strings.ToLower(p.token.text)
+	switch {
+	case contains(readOnly, field):
+		return assignment{}, p.errorf("field %q cannot be assigned", field)
+	case !contains(fields, field) && !isCustomField(field):
+		return assignment{}, p.errorf("unknown field %q", field)
+	}
+
+	p.next()
+	if p.token.kind != tokenAssign {
+		return assignment{}, p.errorf("expected = after %q, got %s", field, p.token)
+	}
+	p.next()
+
+	value, err := p.expression()
+	if err != nil {
+		return assignment{}, err
+	}
+	if p.token.kind != tokenEnd && p.token.kind != tokenEOF {
+		return assignment{}, p.errorf("unexpected %s", p.token)
+	}
+	return assignment{field: field, value: value}, nil
+}
+
+// expression parses terms joined by +
+func (p *parser) expression() (expression, error) {
+	var terms concat
+	for {
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if p.token.kind != tokenPlus {
+			break
+		}
+		p.next()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+// term parses a literal, a field, a call or a parenthesized expression
+func (p *parser) term() (expression, error) {
+	switch token := p.token; token.kind {
+	case tokenString:
+		p.next()
+		return literal(token.text), nil
+	case tokenLParen:
+		p.next()
+		e, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.token.kind != tokenRParen {
+			return nil, p.errorf("expected ), got %s", p.token)
+		}
+		p.next()
+		return e, nil
+	case tokenIdent:
+		p.next()
+		name := strings.ToLower(token.text)
+		if p.token.kind == tokenLParen {
+			return p.call(name)
+		}
+		if !contains(fields, name) && !contains(readOnly, name) && !isCustomField(name) {
+			return nil, p.errorf("unknown field %q", name)
+		}
+		return fieldRef(name), nil
+	default:
+		return nil, p.errorf("expected a value, got %s", token)
+	}
+}
+
+// call parses the arguments of a function call
+func (p *parser) call(name string) (expression, error) {
+	f, ok := functions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %q", name)
+	}
+	p.next()
+
+	var args []expression
+	for p.token.kind != tokenRParen {
+		if len(args) > 0 {
+			if p.token.kind != tokenComma {
+				return nil, p.errorf("expected , or ) in call of %s, got %s", name, p.token)
+			}
+			p.next()
+		}
+		arg, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+
+	if f.arity >= 0 && len(args) != f.arity || f.arity < 0 && len(args) < -f.arity {
+		return nil, p.errorf("wrong number of arguments in call of %s", name)
+	}
+	return call{fn: f.fn, args: args}, nil
+}
+
+// contains reports whether the list holds the name
+func contains(list []string, name string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenKind classifies tokens
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenError
+	tokenEnd
+	tokenIdent
+	tokenString
+	tokenAssign
+	tokenPlus
+	tokenComma
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexical token of a script
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// String describes the token for error messages
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of script"
+	case tokenEnd:
+		return "end of line"
+	case tokenString:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return t.text
+	}
+}
+
+// lexer splits a script into tokens
+type lexer struct {
+	source string
+	pos    int
+	line   int
+}
+
+// next returns the next token
+func (l *lexer) next() token {
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '\n' || c == ';':
+			t := token{kind: tokenEnd, text: string(c), line: l.line}
+			if c == '\n' {
+				l.line++
+			}
+			l.pos++
+			return t
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+		default:
+			return l.lexToken()
+		}
+	}
+	return token{kind: tokenEOF, line: l.line}
+}
+
+// lexToken reads a token that is not whitespace or a line end
+func (l *lexer) lexToken() token {
+	start, c := l.pos, l.source[l.pos]
+	single := map[byte]tokenKind{'=': tokenAssign, '+': tokenPlus, ',': tokenComma, '(': tokenLParen, ')': tokenRParen}
+	if kind, ok := single[c]; ok {
+		l.pos++
+		return token{kind: kind, text: string(c), line: l.line}
+	}
+
+	if c == '"' {
+		var b strings.Builder
+		for l.pos++; l.pos < len(l.source); l.pos++ {
+			switch c := l.source[l.pos]; {
+			case c == '"':
+				l.pos++
+				return token{kind: tokenString, text: b.String(), line: l.line}
+			case c == '\n':
+				return token{kind: tokenError, text: "unterminated string", line: l.line}
+			case c == '\\' && l.pos+1 < len(l.source):
+				l.pos++
+				switch l.source[l.pos] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(l.source[l.pos])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return token{kind: tokenError, text: "unterminated string", line: l.line}
+	}
+
+	for l.pos < len(l.source) && isIdentByte(l.source[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		l.pos++
+		return token{kind: tokenError, text: fmt.Sprintf("unexpected character %q", c), line: l.line}
+	}
+	return token{kind: tokenIdent, text: l.source[start:l.pos], line: l.line}
+}
+
+// isIdentByte reports whether c may appear in field and function names
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
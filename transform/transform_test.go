@@ -0,0 +1,135 @@
+package transform
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestApply(t *testing.T) {
+	program, err := Compile(`
+		# Clean up a legacy CRM export
+		org = upper(trim(org))
+		org = default(org, domain(email))
+		first = title(first); last = title(last)
+		fn = first + " " + last
+		title = replace(title, "Mgr", "Manager")
+		x-source = "legacy-crm"
+		x-legacy-id = ""
+		note = "Imported from " + x-source + "\n" + note
+	`)
+	if err != nil {
+		t.Fatalf("Compile() returned error: %v", err)
+	}
+
+	card := vcard.New().
+		AddName("jane", "DOE").
+		AddEmail("jane@Example.com").
+		AddTitle("Sales Mgr").
+		AddNote("VIP").
+		AddCustomProperty("X-LEGACY-ID", "42")
+	program.Apply(card)
+
+	if card.GetFormattedName() != "Jane Doe" || card.GetName().Last != "Doe" {
+		t.Errorf("Unexpected name %+v, %q", card.GetName(), card.GetFormattedName())
+	}
+	org := card.GetOrganization()
+	if org.Name != "example.com" || org.Title != "Sales Manager" {
+		t.Errorf("Unexpected organization %+v", org)
+	}
+	if card.GetCustomProperty("X-SOURCE") != "legacy-crm" || card.GetCustomProperty("X-LEGACY-ID") != "" {
+		t.Errorf("Unexpected custom properties %v", card.GetCustomProperties())
+	}
+	if card.GetNote() != "Imported from legacy-crm\nVIP" {
+		t.Errorf("Unexpected note %q", card.GetNote())
+	}
+
+	uppercase := MustCompile(`org = upper(org)`)
+	card = vcard.New().AddName("Jane", "Doe").AddOrganization("Acme")
+	uppercase.Apply(card)
+	if card.GetOrganization().Name != "ACME" {
+		t.Errorf("Expected ACME, got %q", card.GetOrganization().Name)
+	}
+	uppercase.Apply(nil)
+}
+
+func TestEnricher(t *testing.T) {
+	book := vcard.NewAddressBook(
+		vcard.New().AddName("Jane", "Doe").AddOrganization("acme"),
+		vcard.New().AddName("John", "Roe"),
+	)
+
+	var enricher vcard.Enricher = MustCompile(`org = upper(default(org, "unknown"))`)
+	if errs := book.Enrich(context.Background(), enricher); len(errs) != 0 {
+		t.Fatalf("Enrich() returned errors: %v", errs)
+	}
+	for i, expected := range []string{"ACME", "UNKNOWN"} {
+		if org := book.Cards()[i].GetOrganization().Name; org != expected {
+			t.Errorf("Card %d: expected %q, got %q", i, expected, org)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := enricher.Enrich(ctx, vcard.New()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestConcurrentPrograms(t *testing.T) {
+	programs := []*Program{MustCompile(`fn = title(fn)`), MustCompile(`org = title(org)`)}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				card := vcard.New().SetFormattedName("jane doe").AddOrganization("acme corp")
+				programs[i%2].Apply(card)
+				if card.GetFormattedName() != "Jane Doe" && card.GetOrganization().Name != "Acme Corp" {
+					t.Errorf("Unexpected card %q, %q", card.GetFormattedName(), card.GetOrganization().Name)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := map[string]string{
+		`nickname = "x"`:          `unknown field "nickname"`,
+		`email = lower(email)`:    `field "email" cannot be assigned`,
+		`org upper(org)`:          `expected = after "org"`,
+		`org = shout(org)`:        `unknown function "shout"`,
+		`org = upper(org, title)`: "wrong number of arguments in call of upper",
+		`org = default()`:         "wrong number of arguments in call of default",
+		`org = "open`:             "unterminated string",
+		"fn = first\norg = org !": "line 2: unexpected character '!'",
+		`org = (org`:              "expected ), got end of script",
+		`org = org org`:           "unexpected org",
+		`org = upper(org title)`:  "expected , or ) in call of upper",
+		`org =`:                   "expected a value, got end of script",
+		`= org`:                   "expected a field name, got =",
+		`org = nickname`:          `unknown field "nickname"`,
+	}
+
+	for source, expected := range tests {
+		_, err := Compile(source)
+		if !errors.Is(err, ErrSyntax) || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Compile(%q) = %v, expected %q", source, err, expected)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustCompile to panic")
+		}
+	}()
+	MustCompile(`bad`)
+}
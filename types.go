@@ -8,7 +8,7 @@ import (
 type EmailType string
 
 const (
-	// EmailInternet represents an internet email address (default)
+	// EmailInternet represents an internet email address
 	EmailInternet EmailType = "INTERNET"
 
 	// EmailWork represents a work email address
@@ -25,7 +25,7 @@ const (
 type PhoneType string
 
 const (
-	// PhoneVoice represents a voice phone number (default)
+	// PhoneVoice represents a voice phone number
 	PhoneVoice PhoneType = "VOICE"
 
 	// PhoneWork represents a work phone number
@@ -69,6 +69,46 @@ const (
 	URLSocial URLType = "SOCIAL"
 )
 
+// Kind represents the kind of object a vCard describes (KIND property)
+type Kind string
+
+const (
+	// KindIndividual represents a single person (default)
+	KindIndividual Kind = "individual"
+
+	// KindGroup represents a group of people or resources
+	KindGroup Kind = "group"
+
+	// KindOrg represents an organization rather than a person
+	KindOrg Kind = "org"
+
+	// KindLocation represents a named geographical place
+	KindLocation Kind = "location"
+)
+
+// ValueType represents the VALUE parameter of a property
+type ValueType string
+
+const (
+	// ValueText marks a free-text value
+	ValueText ValueType = "text"
+
+	// ValueURI marks a URI value (e.g. a photo URL)
+	ValueURI ValueType = "uri"
+
+	// ValueDate marks a date value
+	ValueDate ValueType = "date"
+
+	// ValueDateTime marks a date with a time of day
+	ValueDateTime ValueType = "date-time"
+
+	// ValueDateAndOrTime marks a vCard 4.0 date, time or date-time value
+	ValueDateAndOrTime ValueType = "date-and-or-time"
+
+	// ValueBinary marks inline base64 data (vCard 3.0 ENCODING=b)
+	ValueBinary ValueType = "binary"
+)
+
 // Name represents the structured name information
 type Name struct {
 	// Last name (family name)
@@ -228,9 +268,12 @@ type Organization struct {
 	// Organization name
 	Name string
 
-	// Department
+	// Department (first organizational unit)
 	Department string
 
+	// Units are additional organizational units below the department
+	Units []string
+
 	// Job title
 	Title string
 
@@ -238,6 +281,21 @@ type Organization struct {
 	Role string
 }
 
+// OrgUnits returns the department followed by the additional units,
+// skipping empty entries
+func (o Organization) OrgUnits() []string {
+	var units []string
+	if o.Department != "" {
+		units = append(units, o.Department)
+	}
+	for _, unit := range o.Units {
+		if unit != "" {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
 // URL represents a website or URL with optional type
 type URL struct {
 	// The URL
@@ -250,6 +308,20 @@ type URL struct {
 	Preferred bool
 }
 
+// Geo represents a geographic position (GEO property)
+type Geo struct {
+	// Latitude in decimal degrees
+	Latitude float64
+
+	// Longitude in decimal degrees
+	Longitude float64
+}
+
+// valid reports whether the coordinates are within range
+func (g Geo) valid() bool {
+	return g.Latitude >= -90 && g.Latitude <= 90 && g.Longitude >= -180 && g.Longitude <= 180
+}
+
 // Contact represents a complete contact structure for batch operations
 type Contact struct {
 	Name         Name
@@ -260,7 +332,8 @@ type Contact struct {
 	URLs         []URL
 	Photo        string
 	Note         string
-	Birthday     *string // Date string in YYYY-MM-DD format
-	Anniversary  *string // Date string in YYYY-MM-DD format
+	Birthday     *string // Date string in YYYY-MM-DD format, or free text
+	Anniversary  *string // Date string in YYYY-MM-DD format, or free text
+	UID          string
 	CustomProps  map[string]string
 }
@@ -0,0 +1,115 @@
+package vcard
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// unsafeURLSchemes are rejected by Validate: web UIs rendering the card as a
+// link would execute or inline them
+var unsafeURLSchemes = map[string]bool{
+	"javascript": true,
+	"vbscript":   true,
+	"data":       true,
+}
+
+// uriProperties are the custom properties whose values are URIs, checked
+// for unsafe schemes like the card's URLs
+var uriProperties = map[string]bool{
+	"CALADRURI":       true,
+	"CALURI":          true,
+	"CONTACT-URI":     true,
+	"FBURL":           true,
+	"IMPP":            true,
+	"KEY":             true,
+	"ORG-DIRECTORY":   true,
+	"RELATED":         true,
+	"SOCIALPROFILE":   true,
+	"SOUND":           true,
+	"SOURCE":          true,
+	"X-SOCIALPROFILE": true,
+}
+
+// ValidateStrict runs Validate and additionally requires every URL to parse
+// and to use one of the card's URLSchemes (see CardOptions), with a host for
+// http and https, and rejects data the card's version does not support (see
+// Supports)
+func (v *VCard) ValidateStrict() error {
+	if err := v.Validate(); err != nil {
+		return err
+	}
+
+	if warnings := v.versionWarnings(); len(warnings) > 0 {
+		return fmt.Errorf("unsupported in vCard %s: %s", v.version, warnings[0])
+	}
+
+	for _, u := range v.urls {
+		if err := validateStrictURL(u.Address, v.options.urlSchemes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateURLs rejects URLs and URI-valued custom properties (e.g.
+// SOCIALPROFILE or IMPP set through AddCustomProperty) with unsafe schemes
+func (v *VCard) validateURLs() error {
+	for _, u := range v.urls {
+		if scheme := urlScheme(u.Address); unsafeURLSchemes[scheme] {
+			return fmt.Errorf("url scheme %q is not allowed", scheme)
+		}
+	}
+	for name, value := range v.CustomProperties() {
+		if !uriProperties[strings.ToUpper(name)] {
+			continue
+		}
+		if scheme := urlScheme(value); unsafeURLSchemes[scheme] {
+			return fmt.Errorf("%s scheme %q is not allowed", strings.ToUpper(name), scheme)
+		}
+	}
+	return nil
+}
+
+// validateStrictURL checks a URL against the strict syntax and scheme rules
+func validateStrictURL(address string, schemes []string) error {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", address, err)
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	if !slices.Contains(schemes, scheme) {
+		return fmt.Errorf("url %q must use one of the schemes %s", address, strings.Join(schemes, ", "))
+	}
+	if (scheme == "http" || scheme == "https") && parsed.Host == "" {
+		return fmt.Errorf("url %q has no host", address)
+	}
+	if parsed.Opaque == "" && parsed.Host == "" && parsed.Path == "" {
+		return fmt.Errorf("url %q is empty after the scheme", address)
+	}
+	return nil
+}
+
+// urlScheme returns the lower-cased scheme of a URL as a browser would read
+// it: whitespace and control characters, which browsers strip, are ignored.
+// It returns an empty string for URLs without a scheme.
+func urlScheme(address string) string {
+	var builder strings.Builder
+	for _, r := range address {
+		if r == ':' {
+			return strings.ToLower(builder.String())
+		}
+		switch {
+		case r <= ' ' || r == 0x7f:
+			continue
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z',
+			builder.Len() > 0 && (r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.'):
+			builder.WriteRune(r)
+		default:
+			return ""
+		}
+	}
+	return ""
+}
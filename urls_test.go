@@ -0,0 +1,94 @@
+package vcard
+
+import "testing"
+
+func TestValidateRejectsUnsafeURLs(t *testing.T) {
+	for _, address := range []string{
+		"javascript:alert(1)",
+		"JavaScript:alert(1)",
+		" java\tscript:alert(1)",
+		"vbscript:msgbox(1)",
+		"data:text/html;base64,PHNjcmlwdD4=",
+	} {
+		card := New().AddName("John", "Doe").AddURL(address)
+		if err := card.Validate(); err == nil {
+			t.Errorf("Expected %q to be rejected", address)
+		}
+	}
+
+	for _, address := range []string{"https://example.com", "example.com", "ftp://files.example.com", "/relative:path"} {
+		card := New().AddName("John", "Doe").AddURL(address)
+		if err := card.Validate(); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", address, err)
+		}
+	}
+}
+
+func TestValidateRejectsUnsafeURIProperties(t *testing.T) {
+	for _, name := range []string{"SOCIALPROFILE", "impp", "X-SOCIALPROFILE", "SOURCE"} {
+		card := New().AddName("John", "Doe").AddCustomProperty(name, " javascript:alert(1)")
+		if err := card.Validate(); err == nil {
+			t.Errorf("Expected an unsafe %s to be rejected", name)
+		}
+	}
+
+	card := New().AddName("John", "Doe").
+		AddCustomProperty("SOCIALPROFILE", "https://social.example/@john").
+		AddCustomProperty("IMPP", "xmpp:john@example.com").
+		AddCustomProperty("NICKNAME", "javascript:is not a URI here")
+	if err := card.Validate(); err != nil {
+		t.Errorf("Expected safe URI properties to be accepted, got %v", err)
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	valid := []string{
+		"https://example.com/about",
+		"http://example.com",
+		"tel:+1-555-123-4567",
+		"mailto:john@example.com",
+		"sip:john@example.com",
+		"xmpp:john@example.com",
+	}
+	for _, address := range valid {
+		card := New().AddName("John", "Doe").AddURL(address)
+		if err := card.ValidateStrict(); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", address, err)
+		}
+	}
+
+	invalid := []string{"example.com", "ftp://files.example.com", "https://", "tel:", "https://exa mple.com", "javascript:alert(1)"}
+	for _, address := range invalid {
+		card := New().AddName("John", "Doe").AddURL(address)
+		if err := card.ValidateStrict(); err == nil {
+			t.Errorf("Expected %q to be rejected", address)
+		}
+	}
+
+	if err := New().ValidateStrict(); err == nil {
+		t.Error("Expected ValidateStrict to run the regular validation")
+	}
+
+	ftp := New().SetOptions(CardOptions{URLSchemes: []string{"https", "ftp"}}).AddName("John", "Doe").AddURL("ftp://files.example.com")
+	if err := ftp.ValidateStrict(); err != nil {
+		t.Errorf("Expected the card's URL schemes to be used, got %v", err)
+	}
+	if err := ftp.Clone().AddURL("mailto:john@example.com").ValidateStrict(); err == nil {
+		t.Error("Expected schemes outside the card's list to be rejected")
+	}
+}
+
+func TestURLScheme(t *testing.T) {
+	tests := map[string]string{
+		"HTTPS://example.com": "https",
+		"\x01javascript:x":    "javascript",
+		"example.com":         "",
+		"1http:x":             "",
+		"svn+ssh://host":      "svn+ssh",
+	}
+	for address, expected := range tests {
+		if got := urlScheme(address); got != expected {
+			t.Errorf("urlScheme(%q) = %q, want %q", address, got, expected)
+		}
+	}
+}
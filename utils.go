@@ -2,7 +2,10 @@ package vcard
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // escapeValue escapes special characters in vCard property values
@@ -16,75 +19,197 @@ func escapeValue(value string) string {
 	return value
 }
 
-// unescapeValue unescapes special characters in vCard property values
+// unescapeValue unescapes special characters in vCard property values in a
+// single pass, so an escaped backslash is never read as the start of another
+// escape. Unknown escapes such as "\:" (written by some exporters) yield the
+// escaped character.
 func unescapeValue(value string) string {
-	value = strings.ReplaceAll(value, "\\\\", "\\")
-	value = strings.ReplaceAll(value, "\\,", ",")
-	value = strings.ReplaceAll(value, "\\;", ";")
-	value = strings.ReplaceAll(value, "\\n", "\n")
-	value = strings.ReplaceAll(value, "\\r", "\r")
-	value = strings.ReplaceAll(value, "\\t", "\t")
-	return value
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 't':
+			builder.WriteByte('\t')
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String()
+}
+
+// dateLayouts lists the accepted date and date-time input formats with the
+// location of values without a timezone: date-times are floating
+var dateLayouts = []struct {
+	layout   string
+	location *time.Location
+}{
+	{"2006-01-02", time.UTC},
+	{"20060102", time.UTC},
+	{time.RFC3339, time.UTC},
+	{"2006-01-02T15:04:05", Floating},
+	{"20060102T150405Z0700", time.UTC},
+	{"20060102T150405", Floating},
+	{"20060102T1504Z0700", time.UTC},
 }
 
-// foldLine folds long lines according to vCard specification (75 characters)
+// parseDate parses a date (YYYY-MM-DD or YYYYMMDD), an ISO 8601 date-time with
+// optional timezone, or a year-less --MM-DD / --MMDD date. Year-less dates are
+// stored with year 0 and date-times without a timezone in Floating.
+func parseDate(dateStr string) (time.Time, error) {
+	if strings.HasPrefix(dateStr, "--") {
+		if len(dateStr) == len("--0102") {
+			return time.Parse("2006--0102", "0000"+dateStr)
+		}
+		return time.Parse("2006--01-02", "0000"+dateStr)
+	}
+
+	var err error
+	for _, layout := range dateLayouts {
+		var date time.Time
+		if date, err = time.ParseInLocation(layout.layout, dateStr, layout.location); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// hasTimeOfDay reports whether the time carries a meaningful time component.
+// Floating values always do, even at midnight.
+func hasTimeOfDay(date time.Time) bool {
+	return IsFloating(date) || date.Hour() != 0 || date.Minute() != 0 || date.Second() != 0 || date.Nanosecond() != 0
+}
+
+// formatDate formats a date for output. vCard 4.0 uses the ISO 8601 basic
+// format required by RFC 6350 (19900515, --0515, 19900515T103000Z) while 3.0
+// keeps the extended format (1990-05-15, --05-15, 1990-05-15T10:30:00Z).
+// Values with a time of day are written as date-time including the timezone,
+// which floating values leave out.
+func formatDate(date time.Time, version Version) string {
+	if version == Version40 {
+		switch {
+		case date.Year() == 0:
+			return date.Format("--0102")
+		case !hasTimeOfDay(date):
+			return date.Format("20060102")
+		case IsFloating(date):
+			return date.Format("20060102T150405")
+		default:
+			return date.Format("20060102T150405Z0700")
+		}
+	}
+
+	switch {
+	case date.Year() == 0:
+		return date.Format("--01-02")
+	case !hasTimeOfDay(date):
+		return date.Format("2006-01-02")
+	case IsFloating(date):
+		return date.Format("2006-01-02T15:04:05")
+	default:
+		return date.Format("2006-01-02T15:04:05Z07:00")
+	}
+}
+
+// foldLine folds long lines according to the vCard specification: no
+// physical line is longer than 75 octets, counting the space that starts a
+// continuation line. Folds never split a multi-byte character or an escape
+// sequence such as `\,`, which some parsers misread when it spans lines.
 func foldLine(line string) string {
 	if len(line) <= 75 {
 		return line
 	}
 
 	var result strings.Builder
-	for i, r := range line {
-		if i > 0 && i%75 == 0 {
-			result.WriteString("\r\n ")
-		}
-		result.WriteRune(r)
-	}
-
+	writeFolded(&result, line)
 	return result.String()
 }
 
-// formatTypeParameter formats type parameters for vCard properties
-func formatTypeParameter(types ...string) string {
-	if len(types) == 0 {
-		return ""
+// writeFolded writes the concatenated parts to the builder as a single line
+// folded like foldLine, without joining the parts first. Escape sequences
+// must not span parts.
+func writeFolded(builder *strings.Builder, parts ...string) {
+	size := 0
+	for _, part := range parts {
+		size += len(part)
 	}
-
-	var validTypes []string
-	for _, t := range types {
-		if t != "" {
-			validTypes = append(validTypes, t)
+	builder.Grow(size + size/74*3)
+
+	column, width := 0, 75
+	for _, part := range parts {
+		for len(part) > 0 {
+			n := foldPoint(part, width-column)
+			if n == 0 && column > 0 {
+				// The next character or escape sequence does not fit
+				builder.WriteString("\r\n ")
+				column, width = 0, 74
+				continue
+			}
+			if n == 0 {
+				n = foldPoint(part, len(part))
+			}
+
+			builder.WriteString(part[:n])
+			column += n
+			part = part[n:]
 		}
 	}
+}
 
-	if len(validTypes) == 0 {
-		return ""
+// foldPoint returns the length of the longest prefix of s of at most room
+// octets that ends between characters and outside escape sequences. Invalid
+// UTF-8 is treated as single-octet characters.
+func foldPoint(s string, room int) int {
+	n := 0
+	for n < len(s) {
+		_, size := utf8.DecodeRuneInString(s[n:])
+		if s[n] == '\\' && n+size < len(s) {
+			_, escaped := utf8.DecodeRuneInString(s[n+size:])
+			size += escaped
+		}
+		if n+size > room {
+			break
+		}
+		n += size
 	}
+	return n
+}
 
-	return ";TYPE=" + strings.Join(validTypes, ",")
+// propertyParams returns the type and preference parameters of a typed
+// property
+func propertyParams(preferred bool, types ...string) Params {
+	params := typeParams(types...)
+	if preferred {
+		params.Add("PREF", "1")
+	}
+	return params
 }
 
 // writeNameProperties writes name-related properties to the builder
-func (v *VCard) writeNameProperties(builder *strings.Builder) error {
-	// Write structured name (N property) - required
-	builder.WriteString(fmt.Sprintf("N:%s\n", v.name.StructuredName()))
-
-	// Write formatted name (FN property) - required
-	formattedName := v.name.FormattedName()
-	if formattedName == "" {
-		// If no formatted name, use "Last, First" or just "First" or "Last"
-		if v.name.Last != "" && v.name.First != "" {
-			formattedName = v.name.Last + ", " + v.name.First
-		} else if v.name.First != "" {
-			formattedName = v.name.First
-		} else if v.name.Last != "" {
-			formattedName = v.name.Last
-		}
+func (v *VCard) writeNameProperties(builder *strings.Builder, emitEmpty map[string]bool) error {
+	// Write structured name (N property) - required in 3.0, optional in 4.0
+	structuredName := v.name.StructuredName()
+	if structuredName != ";;;;" || v.version != Version40 || emitEmpty["N"] {
+		builder.WriteString(foldLine("N:"+structuredName) + "\n")
 	}
 
-	if formattedName != "" {
-		builder.WriteString(fmt.Sprintf("FN:%s\n", escapeValue(formattedName)))
-	}
+	// Write formatted name (FN property) - required, written even when empty
+	formattedName := v.formattedName()
+	builder.WriteString(foldLine("FN"+v.altIDParameter("FN")+":"+escapeValue(formattedName)) + "\n")
+	v.writeAlternates(builder, "FN")
 
 	return nil
 }
@@ -92,18 +217,13 @@ func (v *VCard) writeNameProperties(builder *strings.Builder) error {
 // writeEmailProperties writes email properties to the builder
 func (v *VCard) writeEmailProperties(builder *strings.Builder) {
 	for _, email := range v.emails {
-		var typeParam string
-		if email.Type != "" {
-			typeParam = formatTypeParameter(string(email.Type))
-		} else {
-			typeParam = formatTypeParameter("INTERNET")
+		emailType := string(email.Type)
+		if emailType == "" {
+			emailType = "INTERNET"
 		}
+		params := propertyParams(email.Preferred, emailType)
 
-		if email.Preferred {
-			typeParam += ";PREF=1"
-		}
-
-		line := fmt.Sprintf("EMAIL%s:%s", typeParam, escapeValue(email.Address))
+		line := fmt.Sprintf("EMAIL%s:%s", params, escapeValue(email.Address))
 		builder.WriteString(foldLine(line) + "\n")
 	}
 }
@@ -111,18 +231,13 @@ func (v *VCard) writeEmailProperties(builder *strings.Builder) {
 // writePhoneProperties writes phone properties to the builder
 func (v *VCard) writePhoneProperties(builder *strings.Builder) {
 	for _, phone := range v.phones {
-		var typeParam string
-		if phone.Type != "" {
-			typeParam = formatTypeParameter(string(phone.Type))
-		} else {
-			typeParam = formatTypeParameter("VOICE")
+		phoneType := string(phone.Type)
+		if phoneType == "" {
+			phoneType = "VOICE"
 		}
+		params := propertyParams(phone.Preferred, phoneType)
 
-		if phone.Preferred {
-			typeParam += ";PREF=1"
-		}
-
-		line := fmt.Sprintf("TEL%s:%s", typeParam, escapeValue(phone.Number))
+		line := fmt.Sprintf("TEL%s:%s", params, escapeValue(phone.Number))
 		builder.WriteString(foldLine(line) + "\n")
 	}
 }
@@ -130,119 +245,204 @@ func (v *VCard) writePhoneProperties(builder *strings.Builder) {
 // writeAddressProperties writes address properties to the builder
 func (v *VCard) writeAddressProperties(builder *strings.Builder) {
 	for _, addr := range v.addresses {
-		var typeParam string
-		if addr.Type != "" {
-			typeParam = formatTypeParameter(string(addr.Type))
-		}
+		params := propertyParams(addr.Preferred, string(addr.Type))
 
-		if addr.Preferred {
-			typeParam += ";PREF=1"
-		}
-
-		line := fmt.Sprintf("ADR%s:%s", typeParam, addr.StructuredAddress())
+		line := fmt.Sprintf("ADR%s:%s", params, addr.StructuredAddress())
 		builder.WriteString(foldLine(line) + "\n")
 
 		// Also write formatted address label if we have address data
 		if addr.Street != "" || addr.City != "" || addr.State != "" || addr.PostalCode != "" || addr.Country != "" {
-			labelLine := fmt.Sprintf("LABEL%s:%s", typeParam, escapeValue(addr.FormattedAddress()))
+			labelLine := fmt.Sprintf("LABEL%s:%s", params, escapeValue(addr.FormattedAddress()))
 			builder.WriteString(foldLine(labelLine) + "\n")
 		}
 	}
 }
 
 // writeOrganizationProperties writes organization properties to the builder
-func (v *VCard) writeOrganizationProperties(builder *strings.Builder) {
-	if v.organization.Name != "" {
-		var orgParts []string
-		orgParts = append(orgParts, escapeValue(v.organization.Name))
-		if v.organization.Department != "" {
-			orgParts = append(orgParts, escapeValue(v.organization.Department))
+func (v *VCard) writeOrganizationProperties(builder *strings.Builder, emitEmpty map[string]bool) {
+	// Units may be written without an organization name (e.g. "ORG:;Support")
+	units := v.organization.OrgUnits()
+	if v.organization.Name != "" || len(units) > 0 || emitEmpty["ORG"] {
+		orgParts := []string{escapeValue(v.organization.Name)}
+		for _, unit := range units {
+			orgParts = append(orgParts, escapeValue(unit))
 		}
 
-		line := fmt.Sprintf("ORG:%s", strings.Join(orgParts, ";"))
+		line := fmt.Sprintf("ORG%s:%s", v.altIDParameter("ORG"), strings.Join(orgParts, ";"))
 		builder.WriteString(foldLine(line) + "\n")
+		v.writeAlternates(builder, "ORG")
 	}
 
-	if v.organization.Title != "" {
-		line := fmt.Sprintf("TITLE:%s", escapeValue(v.organization.Title))
+	if v.organization.Title != "" || emitEmpty["TITLE"] {
+		line := fmt.Sprintf("TITLE%s:%s", v.altIDParameter("TITLE"), escapeValue(v.organization.Title))
 		builder.WriteString(foldLine(line) + "\n")
+		v.writeAlternates(builder, "TITLE")
 	}
 
-	if v.organization.Role != "" {
-		line := fmt.Sprintf("ROLE:%s", escapeValue(v.organization.Role))
+	if v.organization.Role != "" || emitEmpty["ROLE"] {
+		line := fmt.Sprintf("ROLE%s:%s", v.altIDParameter("ROLE"), escapeValue(v.organization.Role))
 		builder.WriteString(foldLine(line) + "\n")
+		v.writeAlternates(builder, "ROLE")
 	}
 }
 
 // writeURLProperties writes URL properties to the builder
 func (v *VCard) writeURLProperties(builder *strings.Builder) {
 	for _, url := range v.urls {
-		var typeParam string
-		if url.Type != "" {
-			typeParam = formatTypeParameter(string(url.Type))
-		}
+		params := propertyParams(url.Preferred, string(url.Type))
 
-		if url.Preferred {
-			typeParam += ";PREF=1"
-		}
-
-		line := fmt.Sprintf("URL%s:%s", typeParam, escapeValue(url.Address))
+		line := fmt.Sprintf("URL%s:%s", params, escapeValue(url.Address))
 		builder.WriteString(foldLine(line) + "\n")
 	}
 }
 
-// writePhotoProperty writes photo property to the builder
-func (v *VCard) writePhotoProperty(builder *strings.Builder) {
-	if v.photo == "" {
+// writeGeoProperty writes the geographic position to the builder, using a
+// geo: URI for vCard 4.0 and the structured value for 3.0
+func (v *VCard) writeGeoProperty(builder *strings.Builder) {
+	if v.geo == nil {
 		return
 	}
 
-	// Check if it's a URL or base64 data
-	if strings.HasPrefix(v.photo, "http://") || strings.HasPrefix(v.photo, "https://") {
-		// External URL
-		line := fmt.Sprintf("PHOTO;VALUE=uri:%s", v.photo)
-		builder.WriteString(foldLine(line) + "\n")
-	} else if strings.HasPrefix(v.photo, "data:") {
-		// Data URI (base64 encoded)
-		line := fmt.Sprintf("PHOTO;ENCODING=b:%s", v.photo)
-		builder.WriteString(foldLine(line) + "\n")
+	lat := strconv.FormatFloat(v.geo.Latitude, 'f', -1, 64)
+	lon := strconv.FormatFloat(v.geo.Longitude, 'f', -1, 64)
+	if v.version == Version40 {
+		builder.WriteString(fmt.Sprintf("GEO:geo:%s,%s\n", lat, lon))
 	} else {
-		// Assume it's base64 data without data URI prefix
-		line := fmt.Sprintf("PHOTO;ENCODING=b;TYPE=JPEG:%s", v.photo)
-		builder.WriteString(foldLine(line) + "\n")
+		builder.WriteString(fmt.Sprintf("GEO:%s;%s\n", lat, lon))
 	}
 }
 
-// writeBirthdayProperty writes birthday property to the builder
-func (v *VCard) writeBirthdayProperty(builder *strings.Builder) {
-	if v.birthday == nil {
+// writeMemberProperties writes group members to the builder. vCard 3.0 has
+// no MEMBER property, so the Apple Contacts extension is used instead.
+func (v *VCard) writeMemberProperties(builder *strings.Builder) {
+	name := "MEMBER"
+	if !Supports(v.version, name) {
+		name = "X-ADDRESSBOOKSERVER-MEMBER"
+	}
+	for _, member := range v.members {
+		builder.WriteString(foldLine(name+":"+member) + "\n")
+	}
+}
+
+// photoValueType returns the VALUE type of the photo
+func (v *VCard) photoValueType() ValueType {
+	return v.mediaValueType(v.photo)
+}
+
+// mediaValueType returns the VALUE type of an image property (PHOTO or LOGO)
+func (v *VCard) mediaValueType(value string) ValueType {
+	switch {
+	case value == "":
+		return ""
+	case strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://"):
+		return ValueURI
+	case strings.HasPrefix(value, "data:") && v.version == Version40:
+		return ValueURI
+	default:
+		return ValueBinary
+	}
+}
+
+// writePhotoProperty writes photo property to the builder
+func (v *VCard) writePhotoProperty(builder *strings.Builder) {
+	var params Params
+	if v.photoCrop != nil {
+		params.Add(CropParam, v.photoCrop.String())
+	}
+	v.writeMediaProperty(builder, "PHOTO", v.photo, params)
+}
+
+// writeLogoProperty writes logo property to the builder
+func (v *VCard) writeLogoProperty(builder *strings.Builder) {
+	v.writeMediaProperty(builder, "LOGO", v.logo, nil)
+}
+
+// writeMediaProperty writes an image property to the builder. vCard 4.0
+// writes data URIs as URI values while 3.0 writes them as inline base64 data.
+// Images can be megabytes, so the value is folded straight into the builder
+// rather than through intermediate copies. The extra parameters follow the
+// value and encoding parameters.
+func (v *VCard) writeMediaProperty(builder *strings.Builder, name, value string, extra Params) {
+	switch v.mediaValueType(value) {
+	case "":
 		return
+	case ValueURI:
+		writeFolded(builder, name, valueParam(ValueURI)+extra.String(), ":", value)
+	default:
+		data, imageType := value, defaultPhotoToken
+		if mediaType, payload, _, ok := splitDataURI(value); ok {
+			data = payload
+			imageType = mediaTypeToken(mediaType)
+		}
+		var params Params
+		params.Add("ENCODING", "b")
+		params.Add("TYPE", imageType)
+		params = append(params, extra...)
+		writeFolded(builder, name, params.String(), ":", data)
 	}
 
-	// Format date according to vCard specification
-	dateStr := v.birthday.Format("2006-01-02")
-	line := fmt.Sprintf("BDAY:%s", dateStr)
-	builder.WriteString(line + "\n")
+	builder.WriteString("\n")
 }
 
-// writeAnniversaryProperty writes anniversary property to the builder
-func (v *VCard) writeAnniversaryProperty(builder *strings.Builder) {
-	if v.anniversary == nil {
+// dateValueType returns the VALUE type of a date property. Free text is
+// vCard 4.0 only.
+func (v *VCard) dateValueType(date *time.Time, text string) ValueType {
+	switch {
+	case text != "" && v.version == Version40:
+		return ValueText
+	case date == nil:
+		return ""
+	case v.version == Version40:
+		return ValueDateAndOrTime
+	case hasTimeOfDay(*date) && date.Year() != 0:
+		return ValueDateTime
+	default:
+		return ValueDate
+	}
+}
+
+// writeDateProperty writes a date property with the VALUE parameter required
+// for free text (4.0) and date-time values (3.0)
+func (v *VCard) writeDateProperty(builder *strings.Builder, name string, date *time.Time, text string) {
+	var line string
+	switch valueType := v.dateValueType(date, text); valueType {
+	case "":
 		return
+	case ValueText:
+		line = name + valueParam(valueType) + ":" + escapeValue(text)
+	case ValueDateTime:
+		line = name + valueParam(valueType) + ":" + formatDate(*date, v.version)
+	default:
+		line = name + ":" + formatDate(*date, v.version)
 	}
 
+	builder.WriteString(foldLine(line) + "\n")
+}
+
+// valueParam returns the VALUE parameter for the value type
+func valueParam(valueType ValueType) string {
+	params := Params{{Name: "VALUE", Values: []string{string(valueType)}}}
+	return params.String()
+}
+
+// writeBirthdayProperty writes birthday property to the builder
+func (v *VCard) writeBirthdayProperty(builder *strings.Builder) {
+	v.writeDateProperty(builder, "BDAY", v.birthday, v.bdayText)
+}
+
+// writeAnniversaryProperty writes anniversary property to the builder
+func (v *VCard) writeAnniversaryProperty(builder *strings.Builder) {
 	// Anniversary is vCard 4.0 only
-	if v.version == Version40 {
-		dateStr := v.anniversary.Format("2006-01-02")
-		line := fmt.Sprintf("ANNIVERSARY:%s", dateStr)
-		builder.WriteString(line + "\n")
+	if Supports(v.version, "ANNIVERSARY") {
+		v.writeDateProperty(builder, "ANNIVERSARY", v.anniversary, v.annivText)
 	}
 }
 
-// writeCustomProperties writes custom X- properties to the builder
+// writeCustomProperties writes custom X- and registered properties to the
+// builder, ordered by name. Unknown names are skipped and reported by Lint.
 func (v *VCard) writeCustomProperties(builder *strings.Builder) {
-	for name, value := range v.customProps {
-		if strings.HasPrefix(strings.ToUpper(name), "X-") && value != "" {
+	for name, value := range v.CustomProperties() {
+		if isCustomPropertyAllowed(name) && value != "" {
 			line := fmt.Sprintf("%s:%s", strings.ToUpper(name), escapeValue(value))
 			builder.WriteString(foldLine(line) + "\n")
 		}
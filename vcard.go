@@ -25,8 +25,10 @@
 package vcard
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 )
@@ -47,20 +49,54 @@ func (v Version) String() string {
 	return string(v)
 }
 
-// VCard represents a vCard contact entry with all supported properties
+// Supported reports whether the version is one of SupportedVersions
+func (v Version) Supported() bool {
+	return slices.Contains(SupportedVersions(), v)
+}
+
+// ErrUnsupportedVersion is returned for versions other than
+// SupportedVersions
+var ErrUnsupportedVersion = errors.New("unsupported vcard version")
+
+// ParseVersion converts a version string such as "3.0" or "4.0" into a
+// Version. Other versions give ErrUnsupportedVersion.
+func ParseVersion(s string) (Version, error) {
+	if version := Version(strings.TrimSpace(s)); version.Supported() {
+		return version, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedVersion, s)
+}
+
+// VCard represents a vCard contact entry with all supported properties.
+// Getters return copies: changing a returned slice, map or pointer never
+// changes the card, which is only modified through its methods.
 type VCard struct {
 	version      Version
+	kind         Kind
+	showCompany  bool
 	name         Name
+	fn           string
+	fnFallback   bool
 	emails       []Email
 	phones       []Phone
 	addresses    []Address
 	organization Organization
 	urls         []URL
+	geo          *Geo
 	photo        string
+	photoCrop    *CropRect
+	logo         string
 	note         string
 	birthday     *time.Time
 	anniversary  *time.Time
+	bdayText     string
+	annivText    string
+	uid          string
+	members      []string
+	alternates   map[string][]Alternate
 	customProps  map[string]string
+	meta         map[string]string
+	options      CardOptions
 }
 
 // New creates a new vCard instance with default settings (version 3.0)
@@ -75,14 +111,17 @@ func New() *VCard {
 	}
 }
 
-// NewWithVersion creates a new vCard instance with the specified version
+// NewWithVersion creates a new vCard instance with the specified version.
+// Unsupported versions make Validate fail with ErrUnsupportedVersion.
 func NewWithVersion(version Version) *VCard {
 	card := New()
 	card.version = version
 	return card
 }
 
-// SetVersion sets the vCard version
+// SetVersion sets the vCard version. Unsupported versions are kept so the
+// mistake is not hidden, but make Validate, and so String and SaveToFile,
+// fail with ErrUnsupportedVersion; check version strings with ParseVersion.
 func (v *VCard) SetVersion(version Version) *VCard {
 	v.version = version
 	return v
@@ -95,9 +134,17 @@ func (v *VCard) GetVersion() Version {
 
 // String generates the vCard content as a string
 func (v *VCard) String() (string, error) {
+	return v.encode(defaultEmitEmpty(), 0)
+}
+
+// encode generates the vCard content, writing empty values for the properties
+// enabled in emitEmpty and splitting notes longer than a positive noteChunk
+// characters into several NOTE properties
+func (v *VCard) encode(emitEmpty map[string]bool, noteChunk int) (string, error) {
 	if err := v.Validate(); err != nil {
 		return "", fmt.Errorf("vcard validation failed: %w", err)
 	}
+	v = v.prepared()
 
 	var builder strings.Builder
 
@@ -105,8 +152,13 @@ func (v *VCard) String() (string, error) {
 	builder.WriteString("BEGIN:VCARD\n")
 	builder.WriteString(fmt.Sprintf("VERSION:%s\n", v.version))
 
+	// KIND is defined by vCard 4.0 only
+	if v.kind != "" && Supports(v.version, "KIND") {
+		builder.WriteString(fmt.Sprintf("KIND:%s\n", v.kind))
+	}
+
 	// Add name information
-	if err := v.writeNameProperties(&builder); err != nil {
+	if err := v.writeNameProperties(&builder, emitEmpty); err != nil {
 		return "", err
 	}
 
@@ -114,24 +166,28 @@ func (v *VCard) String() (string, error) {
 	v.writeEmailProperties(&builder)
 	v.writePhoneProperties(&builder)
 	v.writeAddressProperties(&builder)
-	v.writeOrganizationProperties(&builder)
+	v.writeOrganizationProperties(&builder, emitEmpty)
 	v.writeURLProperties(&builder)
+	v.writeGeoProperty(&builder)
 
 	// Add optional properties
-	if v.photo != "" {
-		v.writePhotoProperty(&builder)
-	}
+	v.writePhotoProperty(&builder)
+	v.writeLogoProperty(&builder)
 
-	if v.note != "" {
-		builder.WriteString(fmt.Sprintf("NOTE:%s\n", escapeValue(v.note)))
-	}
+	v.writeNoteProperty(&builder, emitEmpty, noteChunk)
 
-	if v.birthday != nil {
-		v.writeBirthdayProperty(&builder)
+	v.writeBirthdayProperty(&builder)
+	v.writeAnniversaryProperty(&builder)
+
+	if v.uid != "" {
+		builder.WriteString(foldLine(fmt.Sprintf("UID:%s", escapeValue(v.uid))) + "\n")
 	}
 
-	if v.anniversary != nil {
-		v.writeAnniversaryProperty(&builder)
+	v.writeMemberProperties(&builder)
+
+	// Apple Contacts displays the organization name instead of the person
+	if v.showCompany {
+		builder.WriteString("X-ABShowAs:COMPANY\n")
 	}
 
 	// Add custom properties
@@ -164,11 +220,64 @@ func (v *VCard) SaveToFile(filename string) error {
 
 // Validate checks if the vCard has required fields and valid data
 func (v *VCard) Validate() error {
-	// Check if name is provided (required field)
-	if v.name.First == "" && v.name.Last == "" {
+	// An unknown version would be written as an invalid VERSION line
+	if !v.version.Supported() {
+		return fmt.Errorf("%w: %q", ErrUnsupportedVersion, v.version)
+	}
+
+	// Check if name is provided (required field). Organization, group and
+	// location cards are identified by other properties instead of a personal
+	// name, and vCard 4.0 only mandates a formatted name, which may come from
+	// SetFormattedName or the fallback.
+	switch {
+	case v.kind == KindOrg:
+		if v.organization.Name == "" && v.formattedName() == "" {
+			return fmt.Errorf("organization vcard must have an organization name or formatted name")
+		}
+	case v.kind == KindGroup:
+		if v.formattedName() == "" {
+			return fmt.Errorf("group vcard must have a formatted name")
+		}
+	case v.kind == KindLocation:
+		if v.formattedName() == "" {
+			return fmt.Errorf("location vcard must have a formatted name")
+		}
+		if len(v.addresses) == 0 && v.geo == nil {
+			return fmt.Errorf("location vcard must have an address or geographic position")
+		}
+	case v.version == Version40:
+		if v.formattedName() == "" {
+			return fmt.Errorf("vcard 4.0 must have a formatted name (FN)")
+		}
+	case v.name.First == "" && v.name.Last == "":
 		return fmt.Errorf("vcard must have at least first name or last name")
 	}
 
+	// MEMBER is only defined for group cards (RFC 6350 section 6.6.5)
+	if len(v.members) > 0 && v.kind != KindGroup {
+		return fmt.Errorf("members require a group vcard")
+	}
+
+	// Reject spoofing characters when configured
+	if err := v.validateText(); err != nil {
+		return err
+	}
+
+	// Alternate languages are written as parameters
+	if err := v.validateAlternates(); err != nil {
+		return err
+	}
+
+	// Reject URLs that would run script when rendered as links
+	if err := v.validateURLs(); err != nil {
+		return err
+	}
+
+	// Validate geographic position
+	if v.geo != nil && !v.geo.valid() {
+		return fmt.Errorf("geographic position is out of range")
+	}
+
 	// Validate emails
 	for _, email := range v.emails {
 		if email.Address == "" {
@@ -194,21 +303,34 @@ func (v *VCard) IsValid() bool {
 // Reset clears all vCard data, allowing reuse of the instance
 func (v *VCard) Reset() *VCard {
 	v.version = Version30
+	v.kind = ""
+	v.showCompany = false
 	v.name = Name{}
+	v.fn = ""
+	v.fnFallback = false
 	v.emails = v.emails[:0]
 	v.phones = v.phones[:0]
 	v.addresses = v.addresses[:0]
 	v.organization = Organization{}
 	v.urls = v.urls[:0]
+	v.geo = nil
 	v.photo = ""
+	v.photoCrop = nil
+	v.logo = ""
 	v.note = ""
 	v.birthday = nil
 	v.anniversary = nil
+	v.bdayText = ""
+	v.annivText = ""
+	v.uid = ""
+	v.members = nil
+	v.alternates = nil
 
 	// Clear custom properties map
 	for k := range v.customProps {
 		delete(v.customProps, k)
 	}
+	v.meta = nil
 
 	return v
 }
@@ -217,23 +339,51 @@ func (v *VCard) Reset() *VCard {
 func (v *VCard) Clone() *VCard {
 	clone := &VCard{
 		version:      v.version,
+		kind:         v.kind,
+		showCompany:  v.showCompany,
 		name:         v.name,
+		fn:           v.fn,
+		fnFallback:   v.fnFallback,
 		emails:       make([]Email, len(v.emails)),
 		phones:       make([]Phone, len(v.phones)),
 		addresses:    make([]Address, len(v.addresses)),
 		organization: v.organization,
 		urls:         make([]URL, len(v.urls)),
 		photo:        v.photo,
+		logo:         v.logo,
 		note:         v.note,
+		bdayText:     v.bdayText,
+		annivText:    v.annivText,
+		uid:          v.uid,
 		customProps:  make(map[string]string),
 	}
+	clone.SetOptions(v.options)
 
 	// Copy slices
+	clone.organization.Units = append([]string(nil), v.organization.Units...)
+	clone.members = append([]string(nil), v.members...)
+	if v.alternates != nil {
+		clone.alternates = make(map[string][]Alternate, len(v.alternates))
+		for property, alternates := range v.alternates {
+			clone.alternates[property] = append([]Alternate(nil), alternates...)
+		}
+	}
 	copy(clone.emails, v.emails)
 	copy(clone.phones, v.phones)
 	copy(clone.addresses, v.addresses)
 	copy(clone.urls, v.urls)
 
+	// Copy geographic position
+	if v.geo != nil {
+		geo := *v.geo
+		clone.geo = &geo
+	}
+
+	if v.photoCrop != nil {
+		rect := *v.photoCrop
+		clone.photoCrop = &rect
+	}
+
 	// Copy time pointers
 	if v.birthday != nil {
 		birthday := *v.birthday
@@ -250,12 +400,49 @@ func (v *VCard) Clone() *VCard {
 		clone.customProps[k] = v
 	}
 
+	if v.meta != nil {
+		clone.meta = v.GetMetadata()
+	}
+
 	return clone
 }
 
-// GetFormattedName returns the formatted full name
+// GetKind returns the kind of object the card describes (empty means individual)
+func (v *VCard) GetKind() Kind {
+	return v.kind
+}
+
+// IsShownAsCompany reports whether the card is flagged to display as a company
+func (v *VCard) IsShownAsCompany() bool {
+	return v.showCompany
+}
+
+// GetFormattedName returns the formatted full name (FN property)
 func (v *VCard) GetFormattedName() string {
-	return v.name.FormattedName()
+	return v.formattedName()
+}
+
+// formattedName derives the FN value: the explicit formatted name, the
+// structured name, the organization for organization cards, and with the
+// fallback enabled the organization or email
+func (v *VCard) formattedName() string {
+	if v.fn != "" {
+		return v.fn
+	}
+
+	if name := v.name.FormattedName(); name != "" {
+		return name
+	}
+
+	if (v.fnFallback || v.kind == KindOrg) && v.organization.Name != "" {
+		return v.organization.Name
+	}
+
+	if v.fnFallback {
+		return v.GetEmail()
+	}
+
+	return ""
 }
 
 // GetName returns the name structure
@@ -263,29 +450,45 @@ func (v *VCard) GetName() Name {
 	return v.name
 }
 
-// GetEmails returns all email addresses
+// GetEmails returns a copy of all email addresses
 func (v *VCard) GetEmails() []Email {
-	return v.emails
+	return cloneSlice(v.emails)
 }
 
-// GetPhones returns all phone numbers
+// GetPhones returns a copy of all phone numbers
 func (v *VCard) GetPhones() []Phone {
-	return v.phones
+	return cloneSlice(v.phones)
 }
 
-// GetAddresses returns all addresses
+// GetAddresses returns a copy of all addresses
 func (v *VCard) GetAddresses() []Address {
-	return v.addresses
+	return cloneSlice(v.addresses)
 }
 
-// GetOrganization returns the organization information
+// GetOrganization returns a copy of the organization information
 func (v *VCard) GetOrganization() Organization {
-	return v.organization
+	organization := v.organization
+	organization.Units = cloneSlice(v.organization.Units)
+	return organization
+}
+
+// GetOrgUnits returns the organizational units (department first)
+func (v *VCard) GetOrgUnits() []string {
+	return v.organization.OrgUnits()
 }
 
-// GetURLs returns all URLs
+// GetURLs returns a copy of all URLs
 func (v *VCard) GetURLs() []URL {
-	return v.urls
+	return cloneSlice(v.urls)
+}
+
+// GetGeo returns a copy of the geographic position if set
+func (v *VCard) GetGeo() *Geo {
+	if v.geo == nil {
+		return nil
+	}
+	geo := *v.geo
+	return &geo
 }
 
 // GetPhoto returns the photo data/URL
@@ -293,19 +496,78 @@ func (v *VCard) GetPhoto() string {
 	return v.photo
 }
 
+// GetLogo returns the organization logo data/URL
+func (v *VCard) GetLogo() string {
+	return v.logo
+}
+
 // GetNote returns the note text
 func (v *VCard) GetNote() string {
 	return v.note
 }
 
-// GetBirthday returns the birthday if set
+// GetBirthday returns a copy of the birthday if set
 func (v *VCard) GetBirthday() *time.Time {
-	return v.birthday
+	return copyTime(v.birthday)
 }
 
-// GetAnniversary returns the anniversary if set
+// GetAnniversary returns a copy of the anniversary if set
 func (v *VCard) GetAnniversary() *time.Time {
-	return v.anniversary
+	return copyTime(v.anniversary)
+}
+
+// cloneSlice returns a copy of s that keeps nil and empty slices apart, so
+// that JSON output is unchanged
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// copyTime returns a copy of t, or nil when t is nil
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// GetBirthdayText returns the free-text birthday if set
+func (v *VCard) GetBirthdayText() string {
+	return v.bdayText
+}
+
+// GetAnniversaryText returns the free-text anniversary if set
+func (v *VCard) GetAnniversaryText() string {
+	return v.annivText
+}
+
+// GetValueType returns the VALUE type written for the property (PHOTO, LOGO,
+// BDAY or ANNIVERSARY), or an empty string when the property is not set
+func (v *VCard) GetValueType(property string) ValueType {
+	switch strings.ToUpper(property) {
+	case "PHOTO":
+		return v.photoValueType()
+	case "LOGO":
+		return v.mediaValueType(v.logo)
+	case "BDAY":
+		return v.dateValueType(v.birthday, v.bdayText)
+	case "ANNIVERSARY":
+		return v.dateValueType(v.anniversary, v.annivText)
+	}
+	return ""
+}
+
+// GetUID returns the unique identifier if set
+func (v *VCard) GetUID() string {
+	return v.uid
+}
+
+// GetMembers returns the URIs of the group members
+func (v *VCard) GetMembers() []string {
+	return append([]string(nil), v.members...)
 }
 
 // GetCustomProperties returns all custom properties
@@ -1,6 +1,7 @@
 package vcard
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -26,6 +27,59 @@ func TestNewWithVersion(t *testing.T) {
 	}
 }
 
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Version
+		wantErr  bool
+	}{
+		{"3.0", Version30, false},
+		{"4.0", Version40, false},
+		{" 4.0 ", Version40, false},
+		{"2.1", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		version, err := ParseVersion(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if version != tt.expected {
+			t.Errorf("ParseVersion(%q) = %s, want %s", tt.input, version, tt.expected)
+		}
+		if tt.wantErr && !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("ParseVersion(%q) error = %v, want ErrUnsupportedVersion", tt.input, err)
+		}
+	}
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	card := New().AddName("Jane", "Doe").SetVersion("5.0")
+	if _, err := card.String(); !errors.Is(err, ErrUnsupportedVersion) || !strings.Contains(err.Error(), `"5.0"`) {
+		t.Errorf("Expected ErrUnsupportedVersion, got %v", err)
+	}
+	if card.GetVersion() != "5.0" {
+		t.Errorf("Expected the version to be kept, got %s", card.GetVersion())
+	}
+
+	if NewWithVersion("2.1").AddName("Jane", "Doe").IsValid() {
+		t.Error("Expected vCard 2.1 to be invalid")
+	}
+	if !card.SetVersion(Version40).IsValid() {
+		t.Error("Expected the card to be valid after setting a supported version")
+	}
+
+	for _, version := range SupportedVersions() {
+		if !version.Supported() {
+			t.Errorf("Expected %s to be supported", version)
+		}
+	}
+	if Version("").Supported() || Version("4.0 ").Supported() {
+		t.Error("Expected only exact versions to be supported")
+	}
+}
+
 func TestBasicVCard(t *testing.T) {
 	card := New()
 	card.AddName("John", "Doe")
@@ -221,6 +275,26 @@ func TestCustomProperties(t *testing.T) {
 	}
 }
 
+func TestCustomPropertiesOrder(t *testing.T) {
+	card := New().AddName("John", "Doe")
+	for _, name := range []string{"X-ZULU", "X-ALPHA", "X-MIKE", "X-BRAVO", "X-YANKEE"} {
+		card.AddCustomProperty(name, "value")
+	}
+
+	first, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(first, "X-ALPHA:value\nX-BRAVO:value\nX-MIKE:value\nX-YANKEE:value\nX-ZULU:value\n") {
+		t.Errorf("Expected custom properties ordered by name, got:\n%s", first)
+	}
+	for range 20 {
+		if content, _ := card.String(); content != first {
+			t.Fatal("Expected the same output on every encoding")
+		}
+	}
+}
+
 func TestBirthday(t *testing.T) {
 	card := New()
 	card.AddName("John", "Doe")
@@ -254,7 +328,7 @@ func TestVersion40Features(t *testing.T) {
 		t.Error("vCard 4.0 version not found")
 	}
 
-	if !strings.Contains(content, "ANNIVERSARY:2020-06-01") {
+	if !strings.Contains(content, "ANNIVERSARY:20200601") {
 		t.Error("Anniversary not found (vCard 4.0 feature)")
 	}
 }
@@ -495,3 +569,362 @@ func TestAdditionalCoverage(t *testing.T) {
 	// Test photo from file (with error)
 	_ = card.AddPhotoFromFile("non-existent.jpg")
 }
+
+func TestUID(t *testing.T) {
+	card := New()
+	card.AddName("John", "Doe").SetUID("urn:uuid:1234")
+
+	if card.GetUID() != "urn:uuid:1234" {
+		t.Errorf("Expected UID urn:uuid:1234, got %s", card.GetUID())
+	}
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	if !strings.Contains(content, "UID:urn:uuid:1234") {
+		t.Error("vCard should contain UID property")
+	}
+
+	if card.Clone().GetUID() != card.GetUID() {
+		t.Error("Clone() should copy UID")
+	}
+
+	if card.Reset().GetUID() != "" {
+		t.Error("Reset() should clear UID")
+	}
+}
+
+func TestBirthdayWithTime(t *testing.T) {
+	zone := time.FixedZone("CEST", 2*60*60)
+	birthday := time.Date(1990, 5, 15, 10, 30, 0, 0, zone)
+
+	card := New()
+	card.AddName("John", "Doe")
+	card.AddBirthday(birthday)
+
+	content, _ := card.String()
+	if !strings.Contains(content, "BDAY;VALUE=date-time:1990-05-15T10:30:00+02:00") {
+		t.Errorf("Expected extended date-time for 3.0, got %s", content)
+	}
+
+	card.SetVersion(Version40)
+	content, _ = card.String()
+	if !strings.Contains(content, "BDAY:19900515T103000+0200") {
+		t.Errorf("Expected basic date-time for 4.0, got %s", content)
+	}
+
+	card.AddBirthday(time.Date(1990, 5, 15, 10, 30, 0, 0, time.UTC))
+	content, _ = card.String()
+	if !strings.Contains(content, "BDAY:19900515T103000Z") {
+		t.Errorf("Expected UTC designator, got %s", content)
+	}
+}
+
+func TestBirthdayFromDateTimeString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1990-05-15T10:30:00+02:00", "BDAY;VALUE=date-time:1990-05-15T10:30:00+02:00"},
+		{"19900515T103000Z", "BDAY;VALUE=date-time:1990-05-15T10:30:00Z"},
+		{"19900515", "BDAY:1990-05-15"},
+	}
+
+	for _, tt := range tests {
+		card := New()
+		card.AddName("John", "Doe")
+		if err := card.AddBirthdayFromString(tt.input); err != nil {
+			t.Fatalf("AddBirthdayFromString(%q) returned error: %v", tt.input, err)
+		}
+
+		content, _ := card.String()
+		if !strings.Contains(content, tt.expected) {
+			t.Errorf("AddBirthdayFromString(%q): expected %s in %s", tt.input, tt.expected, content)
+		}
+	}
+}
+
+func TestFloatingBirthday(t *testing.T) {
+	tests := []struct {
+		input    string
+		version  Version
+		expected string
+	}{
+		{"19900515T103000", Version40, "BDAY:19900515T103000\n"},
+		{"1990-05-15T10:30:00", Version30, "BDAY;VALUE=date-time:1990-05-15T10:30:00\n"},
+		{"19900515T000000", Version40, "BDAY:19900515T000000\n"},
+	}
+
+	for _, tt := range tests {
+		card := NewWithVersion(tt.version).AddName("John", "Doe")
+		if err := card.AddBirthdayFromString(tt.input); err != nil {
+			t.Fatalf("AddBirthdayFromString(%q) returned error: %v", tt.input, err)
+		}
+		if !IsFloating(*card.GetBirthday()) {
+			t.Errorf("%q: expected a floating time, got %v", tt.input, card.GetBirthday())
+		}
+
+		content, _ := card.String()
+		if !strings.Contains(content, tt.expected) {
+			t.Errorf("%q: expected %q in %s", tt.input, tt.expected, content)
+		}
+
+		parsed, err := Parse(content)
+		if err != nil || !IsFloating(*parsed.GetBirthday()) || parsed.GetBirthday().Hour() != card.GetBirthday().Hour() {
+			t.Errorf("%q: expected the floating time to survive a round trip, got %v (%v)", tt.input, parsed.GetBirthday(), err)
+		}
+	}
+
+	card := New().AddName("John", "Doe")
+	if err := card.AddBirthdayFromString("19900515T103000Z"); err != nil || IsFloating(*card.GetBirthday()) {
+		t.Errorf("Expected a UTC time not to be floating, got %v (%v)", card.GetBirthday(), err)
+	}
+}
+
+func TestVersion40DateFormats(t *testing.T) {
+	// Examples from RFC 6350 section 6.2.5 and 6.2.6
+	tests := []struct {
+		input    string
+		version  Version
+		expected string
+	}{
+		{"1996-04-15", Version40, "BDAY:19960415"},
+		{"--04-15", Version40, "BDAY:--0415"},
+		{"--0415", Version40, "BDAY:--0415"},
+		{"19531015T231000Z", Version40, "BDAY:19531015T231000Z"},
+		{"1996-04-15", Version30, "BDAY:1996-04-15"},
+		{"--0415", Version30, "BDAY:--04-15"},
+	}
+
+	for _, tt := range tests {
+		card := NewWithVersion(tt.version)
+		card.AddName("John", "Doe")
+		if err := card.AddBirthdayFromString(tt.input); err != nil {
+			t.Fatalf("AddBirthdayFromString(%q) returned error: %v", tt.input, err)
+		}
+
+		content, _ := card.String()
+		if !strings.Contains(content, tt.expected+"\n") {
+			t.Errorf("%s %q: expected %s in %s", tt.version, tt.input, tt.expected, content)
+		}
+	}
+}
+
+func TestVersion40FormattedNameRequired(t *testing.T) {
+	card := NewWithVersion(Version40)
+	card.AddOrganization("Acme Corp")
+	card.AddEmail("info@acme.com")
+
+	if err := card.Validate(); err == nil {
+		t.Error("4.0 card without derivable FN should be invalid")
+	}
+
+	// Fallback synthesizes FN from the organization
+	card.SetFormattedNameFallback(true)
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Expected fallback FN to validate: %v", err)
+	}
+	if !strings.Contains(content, "FN:Acme Corp\n") {
+		t.Errorf("Expected FN from organization, got %s", content)
+	}
+
+	// Email is used when there is no organization
+	card.SetOrganization(Organization{})
+	if card.GetFormattedName() != "info@acme.com" {
+		t.Errorf("Expected FN from email, got %s", card.GetFormattedName())
+	}
+
+	// Explicit FN takes precedence
+	card.SetFormattedName("Acme Help Desk")
+	if card.GetFormattedName() != "Acme Help Desk" {
+		t.Errorf("Expected explicit FN, got %s", card.GetFormattedName())
+	}
+
+	// Fallback does not relax 3.0 personal name requirement
+	card.SetVersion(Version30)
+	if err := card.Validate(); err == nil {
+		t.Error("3.0 card without first or last name should be invalid")
+	}
+}
+
+func TestOrganizationCard(t *testing.T) {
+	for _, version := range []Version{Version30, Version40} {
+		card := NewWithVersion(version)
+		card.SetKind(KindOrg)
+		card.AddOrganization("Acme Corp")
+		card.AddEmail("support@acme.com")
+
+		content, err := card.String()
+		if err != nil {
+			t.Fatalf("%s: organization card should be valid: %v", version, err)
+		}
+
+		if !strings.Contains(content, "FN:Acme Corp\n") {
+			t.Errorf("%s: expected FN from organization, got %s", version, content)
+		}
+
+		hasKind := strings.Contains(content, "KIND:org\n")
+		if hasKind != (version == Version40) {
+			t.Errorf("%s: KIND written = %v", version, hasKind)
+		}
+	}
+
+	card := New().SetKind(KindOrg)
+	if err := card.Validate(); err == nil {
+		t.Error("Organization card without ORG or FN should be invalid")
+	}
+
+	card.SetFormattedName("Help Desk")
+	if err := card.Validate(); err != nil {
+		t.Errorf("Organization card with FN should be valid: %v", err)
+	}
+
+	if card.Clone().GetKind() != KindOrg {
+		t.Error("Clone() should copy kind")
+	}
+	if card.Reset().GetKind() != "" {
+		t.Error("Reset() should clear kind")
+	}
+}
+
+func TestLocationCard(t *testing.T) {
+	card := NewWithVersion(Version40)
+	card.SetKind(KindLocation)
+	card.SetFormattedName("Meeting Room 4B")
+
+	if err := card.Validate(); err == nil {
+		t.Error("Location card without address or geo should be invalid")
+	}
+
+	card.SetGeo(37.386013, -122.082932)
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Location card should be valid: %v", err)
+	}
+
+	for _, expected := range []string{"KIND:location\n", "FN:Meeting Room 4B\n", "GEO:geo:37.386013,-122.082932\n"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in %s", expected, content)
+		}
+	}
+
+	card.SetVersion(Version30)
+	content, _ = card.String()
+	if !strings.Contains(content, "GEO:37.386013;-122.082932\n") {
+		t.Errorf("Expected 3.0 GEO format, got %s", content)
+	}
+
+	// Address alone is sufficient
+	room := New().SetKind(KindLocation).SetFormattedName("HQ")
+	room.AddAddress("1 Main St", "Anytown", "CA", "12345", "USA")
+	if err := room.Validate(); err != nil {
+		t.Errorf("Location card with address should be valid: %v", err)
+	}
+
+	if err := New().SetKind(KindLocation).SetGeo(1, 1).Validate(); err == nil {
+		t.Error("Location card without FN should be invalid")
+	}
+
+	if err := room.SetGeo(91, 0).Validate(); err == nil {
+		t.Error("Out of range latitude should be invalid")
+	}
+
+	if room.Clone().GetGeo() == room.GetGeo() {
+		t.Error("Clone() should deep copy geo")
+	}
+}
+
+func TestShowAsCompany(t *testing.T) {
+	card := New().SetKind(KindOrg).SetShowAsCompany(true)
+	card.AddOrganization("Acme Corp")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("Failed to generate vCard: %v", err)
+	}
+	if !strings.Contains(content, "X-ABShowAs:COMPANY\n") {
+		t.Errorf("Expected X-ABShowAs:COMPANY, got %s", content)
+	}
+
+	if !card.Clone().IsShownAsCompany() {
+		t.Error("Clone() should copy show-as-company flag")
+	}
+
+	card.SetShowAsCompany(false)
+	content, _ = card.String()
+	if strings.Contains(content, "X-ABShowAs") {
+		t.Error("X-ABShowAs should not be written when disabled")
+	}
+}
+
+func TestValueTypes(t *testing.T) {
+	card := NewWithVersion(Version40).AddName("John", "Doe")
+	card.SetBirthdayText("circa 1800")
+	card.SetAnniversaryText("spring; 1990")
+	card.AddPhoto("https://example.com/photo.jpg")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"BDAY;VALUE=text:circa 1800\n",
+		"ANNIVERSARY;VALUE=text:spring\\; 1990\n",
+		"PHOTO;VALUE=uri:https://example.com/photo.jpg\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected %q in %s", expected, content)
+		}
+	}
+
+	tests := []struct {
+		property string
+		expected ValueType
+	}{
+		{"BDAY", ValueText},
+		{"anniversary", ValueText},
+		{"PHOTO", ValueURI},
+		{"NOTE", ""},
+	}
+	for _, tt := range tests {
+		if got := card.GetValueType(tt.property); got != tt.expected {
+			t.Errorf("GetValueType(%s) = %q, want %q", tt.property, got, tt.expected)
+		}
+	}
+
+	// A date replaces the free text
+	card.AddBirthday(time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC))
+	if card.GetBirthdayText() != "" || card.GetValueType("BDAY") != ValueDateAndOrTime {
+		t.Errorf("Expected birthday date to replace text, got %q", card.GetBirthdayText())
+	}
+
+	card.SetVersion(Version30)
+	if got := card.GetValueType("BDAY"); got != ValueDate {
+		t.Errorf("Expected date value for 3.0, got %q", got)
+	}
+	card.AddBirthday(time.Date(1990, 5, 15, 10, 30, 0, 0, time.UTC))
+	if got := card.GetValueType("BDAY"); got != ValueDateTime {
+		t.Errorf("Expected date-time value for 3.0, got %q", got)
+	}
+}
+
+func TestTextDatesSkippedInVersion30(t *testing.T) {
+	card := New().AddName("John", "Doe").SetBirthdayText("unknown")
+
+	content, err := card.String()
+	if err != nil {
+		t.Fatalf("String() returned error: %v", err)
+	}
+	if strings.Contains(content, "BDAY") {
+		t.Errorf("Expected no BDAY in 3.0 output, got %s", content)
+	}
+
+	warnings := card.Lint()
+	if len(warnings) != 1 || warnings[0].Property != "BDAY" {
+		t.Errorf("Expected a BDAY lint warning, got %v", warnings)
+	}
+}
@@ -0,0 +1,89 @@
+// Package vcardotel traces vCard parse, generate and bulk operations with
+// OpenTelemetry. New adapts a TracerProvider to vcard.Tracer, which is
+// attached to a context or given to the adapters:
+//
+//	tracer := vcardotel.New(provider)
+//
+//	ctx = vcard.ContextWithTracer(ctx, tracer)
+//	content, err := book.StringContext(ctx)
+//
+//	r.Post("/export", chi.Bulk(chi.Options{Tracer: tracer}))
+//
+// Spans are named vcard.parse, vcard.generate and vcard.bulk and carry the
+// card count (vcard.cards) and size in bytes (vcard.bytes).
+//
+// The package is a separate module so the core library does not depend on
+// the OpenTelemetry API.
+package vcardotel
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.rumenx.com/vcard"
+)
+
+// ScopeName is the instrumentation scope of the spans
+const ScopeName = "go.rumenx.com/vcard"
+
+// Tracer traces vCard operations with an OpenTelemetry tracer
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ vcard.Tracer = (*Tracer)(nil)
+
+// New returns a tracer using the provider, or the global provider when nil
+func New(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(ScopeName)}
+}
+
+// Start implements vcard.Tracer
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, vcard.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan is a vcard.Span over an OpenTelemetry span
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttributes(attrs ...slog.Attr) {
+	kv := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kv = append(kv, keyValue(attr))
+	}
+	s.span.SetAttributes(kv...)
+}
+
+func (s *otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// keyValue converts a slog attribute to an OpenTelemetry attribute
+func keyValue(attr slog.Attr) attribute.KeyValue {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindInt64:
+		return attribute.Int64(attr.Key, value.Int64())
+	case slog.KindUint64:
+		return attribute.Int64(attr.Key, int64(value.Uint64()))
+	case slog.KindFloat64:
+		return attribute.Float64(attr.Key, value.Float64())
+	case slog.KindBool:
+		return attribute.Bool(attr.Key, value.Bool())
+	}
+	return attribute.String(attr.Key, value.String())
+}
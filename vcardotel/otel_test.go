@@ -0,0 +1,67 @@
+package vcardotel
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.rumenx.com/vcard"
+)
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "export")
+	ctx = vcard.ContextWithTracer(ctx, New(provider))
+
+	book := vcard.NewAddressBook(vcard.New().AddName("Jane", "Doe"), vcard.New().AddName("John", "Doe"))
+	content, err := book.StringContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vcard.ParseContext(ctx, "not a vcard"); err == nil {
+		t.Fatal("Expected a parse error")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+
+	bulk := spans[0]
+	if bulk.Name() != vcard.SpanBulk {
+		t.Errorf("Expected %s, got %s", vcard.SpanBulk, bulk.Name())
+	}
+	if bulk.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Expected the bulk span to be a child of the request span")
+	}
+	attrs := attribute.NewSet(bulk.Attributes()...)
+	if v, _ := attrs.Value(vcard.AttrCards); v.AsInt64() != 2 {
+		t.Errorf("Expected 2 cards, got %v", v.Emit())
+	}
+	if v, _ := attrs.Value(vcard.AttrBytes); v.AsInt64() != int64(len(content)) {
+		t.Errorf("Expected %d bytes, got %v", len(content), v.Emit())
+	}
+
+	parse := spans[1]
+	if parse.Name() != vcard.SpanParse || parse.Status().Code != codes.Error || len(parse.Events()) == 0 {
+		t.Errorf("Expected a failed parse span with the error recorded, got %s %v", parse.Name(), parse.Status())
+	}
+	if parse.SpanKind() != trace.SpanKindInternal {
+		t.Errorf("Expected an internal span, got %v", parse.SpanKind())
+	}
+}
+
+func TestGlobalProvider(t *testing.T) {
+	// The global provider is a no-op until one is installed
+	ctx := vcard.ContextWithTracer(context.Background(), New(nil))
+	if _, err := vcard.New().AddName("Jane", "Doe").StringContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
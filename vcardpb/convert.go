@@ -0,0 +1,179 @@
+// Package vcardpb holds the Protocol Buffers schema of vCards (vcard.proto)
+// with its generated Go bindings, and converts between the Contact message
+// and vcard.VCard, so gRPC services and Kafka topics share one wire schema:
+//
+//	data, err := proto.Marshal(vcardpb.ToProto(card))
+//
+//	var message vcardpb.Contact
+//	if err := proto.Unmarshal(data, &message); err != nil {
+//		return err
+//	}
+//	card, err := vcardpb.FromProto(&message)
+//
+// EventToProto and EventFromProto do the same for the contact change
+// envelopes of go.rumenx.com/vcard/events.
+//
+// The package is a separate module so the core library does not depend on
+// the protobuf runtime.
+package vcardpb
+
+import (
+	"fmt"
+	"time"
+
+	"go.rumenx.com/vcard"
+)
+
+// ToProto converts the card to a Contact message
+func ToProto(card *vcard.VCard) *Contact {
+	name := card.GetName()
+	message := &Contact{
+		Version:          card.GetVersion().String(),
+		Kind:             string(card.GetKind()),
+		Uid:              card.GetUID(),
+		Name:             &Name{First: name.First, Last: name.Last, Middle: name.Middle, Prefix: name.Prefix, Suffix: name.Suffix},
+		Photo:            card.GetPhoto(),
+		Logo:             card.GetLogo(),
+		Note:             card.GetNote(),
+		Birthday:         formatDate(card.GetBirthday(), card.GetBirthdayText()),
+		Anniversary:      formatDate(card.GetAnniversary(), card.GetAnniversaryText()),
+		Members:          card.GetMembers(),
+		CustomProperties: card.GetCustomProperties(),
+	}
+	if fn := card.GetFormattedName(); fn != name.FormattedName() {
+		message.FormattedName = fn
+	}
+
+	for _, email := range card.GetEmails() {
+		message.Emails = append(message.Emails, &Email{Address: email.Address, Type: string(email.Type), Preferred: email.Preferred})
+	}
+	for _, phone := range card.GetPhones() {
+		message.Phones = append(message.Phones, &Phone{Number: phone.Number, Type: string(phone.Type), Preferred: phone.Preferred})
+	}
+	for _, address := range card.GetAddresses() {
+		message.Addresses = append(message.Addresses, &Address{
+			Street:     address.Street,
+			Extended:   address.Extended,
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+			Type:       string(address.Type),
+			Preferred:  address.Preferred,
+		})
+	}
+	if org := card.GetOrganization(); org.Name != "" || org.Department != "" || len(org.Units) > 0 || org.Title != "" || org.Role != "" {
+		message.Organization = &Organization{Name: org.Name, Department: org.Department, Units: org.Units, Title: org.Title, Role: org.Role}
+	}
+	for _, url := range card.GetURLs() {
+		message.Urls = append(message.Urls, &URL{Address: url.Address, Type: string(url.Type), Preferred: url.Preferred})
+	}
+	if geo := card.GetGeo(); geo != nil {
+		message.Geo = &Geo{Latitude: geo.Latitude, Longitude: geo.Longitude}
+	}
+
+	return message
+}
+
+// FromProto converts a Contact message to a card. Messages without a
+// version give vCard 3.0 cards, like vcard.New.
+func FromProto(message *Contact) (*vcard.VCard, error) {
+	card := vcard.New()
+	if message.GetVersion() != "" {
+		version, err := vcard.ParseVersion(message.GetVersion())
+		if err != nil {
+			return nil, fmt.Errorf("vcardpb: %w", err)
+		}
+		card.SetVersion(version)
+	}
+
+	name := message.GetName()
+	card.SetName(vcard.Name{
+		First:  name.GetFirst(),
+		Last:   name.GetLast(),
+		Middle: name.GetMiddle(),
+		Prefix: name.GetPrefix(),
+		Suffix: name.GetSuffix(),
+	}).SetKind(vcard.Kind(message.GetKind()))
+	if message.GetFormattedName() != "" {
+		card.SetFormattedName(message.GetFormattedName())
+	}
+
+	for _, email := range message.GetEmails() {
+		card.AddEmailWithPreference(email.GetAddress(), vcard.EmailType(email.GetType()), email.GetPreferred())
+	}
+	for _, phone := range message.GetPhones() {
+		card.AddPhoneWithPreference(phone.GetNumber(), vcard.PhoneType(phone.GetType()), phone.GetPreferred())
+	}
+	for _, address := range message.GetAddresses() {
+		card.AddAddresses([]vcard.Address{{
+			Street:     address.GetStreet(),
+			Extended:   address.GetExtended(),
+			City:       address.GetCity(),
+			State:      address.GetState(),
+			PostalCode: address.GetPostalCode(),
+			Country:    address.GetCountry(),
+			Type:       vcard.AddressType(address.GetType()),
+			Preferred:  address.GetPreferred(),
+		}})
+	}
+	if org := message.GetOrganization(); org != nil {
+		card.SetOrganization(vcard.Organization{
+			Name:       org.GetName(),
+			Department: org.GetDepartment(),
+			Units:      org.GetUnits(),
+			Title:      org.GetTitle(),
+			Role:       org.GetRole(),
+		})
+	}
+	for _, url := range message.GetUrls() {
+		card.AddURLWithPreference(url.GetAddress(), vcard.URLType(url.GetType()), url.GetPreferred())
+	}
+	if geo := message.GetGeo(); geo != nil {
+		card.SetGeo(geo.GetLatitude(), geo.GetLongitude())
+	}
+	if message.GetPhoto() != "" {
+		card.AddPhoto(message.GetPhoto())
+	}
+	if message.GetLogo() != "" {
+		card.AddLogo(message.GetLogo())
+	}
+	if message.GetNote() != "" {
+		card.AddNote(message.GetNote())
+	}
+	if message.GetBirthday() != "" {
+		card.SetBirthdayValue(message.GetBirthday())
+	}
+	if message.GetAnniversary() != "" {
+		card.SetAnniversaryValue(message.GetAnniversary())
+	}
+	if message.GetUid() != "" {
+		card.SetUID(message.GetUid())
+	}
+	for _, member := range message.GetMembers() {
+		card.AddMember(member)
+	}
+	if len(message.GetCustomProperties()) > 0 {
+		card.AddCustomProperties(message.GetCustomProperties())
+	}
+
+	return card, nil
+}
+
+// formatDate formats a date as YYYY-MM-DD (--MM-DD without a year, RFC 3339
+// with a time of day and without the offset for floating times), or returns
+// the free text
+func formatDate(date *time.Time, text string) string {
+	switch {
+	case date == nil:
+		return text
+	case date.Year() == 0:
+		return date.Format("--01-02")
+	case vcard.IsFloating(*date):
+		return date.Format("2006-01-02T15:04:05")
+	case date.Hour() != 0 || date.Minute() != 0 || date.Second() != 0:
+		return date.Format(time.RFC3339)
+	default:
+		return date.Format(time.DateOnly)
+	}
+}
@@ -0,0 +1,90 @@
+package vcardpb
+
+import (
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRoundTrip(t *testing.T) {
+	original := vcard.NewWithVersion(vcard.Version40).
+		AddName("Jane", "Doe").
+		AddEmailWithPreference("jane@example.com", vcard.EmailWork, true).
+		AddPhone("+15551234567", vcard.PhoneType("WORK,VOICE")).
+		AddAddress("1 Main St", "Springfield", "IL", "62701", "USA", vcard.AddressHome).
+		SetOrganization(vcard.Organization{Name: "Acme", Department: "R&D", Title: "Engineer"}).
+		AddURL("https://example.com", vcard.URLWork).
+		SetGeo(42.69, 23.32).
+		AddNote("Met at GopherCon").
+		SetBirthdayValue("1990-05-15").
+		SetUID("urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1").
+		AddCustomProperty("X-EMPLOYEE-ID", "1042")
+
+	data, err := proto.Marshal(ToProto(original))
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+	var message Contact
+	if err := proto.Unmarshal(data, &message); err != nil {
+		t.Fatalf("Failed to unmarshal message: %v", err)
+	}
+	card, err := FromProto(&message)
+	if err != nil {
+		t.Fatalf("FromProto() returned error: %v", err)
+	}
+
+	if !proto.Equal(ToProto(card), ToProto(original)) {
+		t.Errorf("Round trip changed the message:\n%v\n%v", ToProto(card), ToProto(original))
+	}
+	if message.GetFormattedName() != "" {
+		t.Errorf("Expected a derived formatted name to be omitted, got %q", message.GetFormattedName())
+	}
+	if message.GetBirthday() != "1990-05-15" || message.GetPhones()[0].GetType() != "WORK,VOICE" {
+		t.Errorf("Unexpected message: %v", &message)
+	}
+
+	expected, _ := original.String()
+	got, _ := card.String()
+	if len(got) != len(expected) {
+		t.Errorf("Round trip changed the card:\n%s\n%s", got, expected)
+	}
+}
+
+func TestFloatingDate(t *testing.T) {
+	original := vcard.NewWithVersion(vcard.Version40).AddName("Jane", "Doe").SetBirthdayValue("19900515T103000")
+
+	message := ToProto(original)
+	if message.GetBirthday() != "1990-05-15T10:30:00" {
+		t.Errorf("Expected a floating date-time, got %q", message.GetBirthday())
+	}
+	card, err := FromProto(message)
+	if err != nil || !vcard.IsFloating(*card.GetBirthday()) {
+		t.Errorf("Expected the birthday to stay floating, got %v (%v)", card.GetBirthday(), err)
+	}
+}
+
+func TestFromProto(t *testing.T) {
+	card, err := FromProto(&Contact{
+		Kind:          "group",
+		FormattedName: "Team",
+		Members:       []string{"urn:uuid:a", "urn:uuid:b"},
+		Birthday:      "circa 1800",
+	})
+	if err != nil {
+		t.Fatalf("FromProto() returned error: %v", err)
+	}
+	if card.GetVersion() != vcard.Version30 || card.GetKind() != vcard.KindGroup || card.GetFormattedName() != "Team" {
+		t.Errorf("Unexpected card: %v, %v, %q", card.GetVersion(), card.GetKind(), card.GetFormattedName())
+	}
+	if len(card.GetMembers()) != 2 || card.GetBirthdayText() != "circa 1800" {
+		t.Errorf("Unexpected members or birthday: %v, %q", card.GetMembers(), card.GetBirthdayText())
+	}
+
+	if _, err := FromProto(&Contact{Version: "2.1"}); err == nil {
+		t.Error("Expected error for an unsupported version")
+	}
+	if card, err := FromProto(nil); err != nil || card == nil {
+		t.Errorf("Expected a nil message to give an empty card, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package vcardpb
+
+import (
+	"fmt"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/events"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EventToProto converts a contact change envelope to a ContactEvent message
+func EventToProto(event events.Envelope) *ContactEvent {
+	message := &ContactEvent{
+		SchemaVersion: int32(event.SchemaVersion),
+		Id:            event.ID,
+		Type:          event.Type,
+		Source:        event.Source,
+		Time:          timestamppb.New(event.Time),
+		Uid:           event.UID,
+		Version:       event.Version,
+		Card:          event.Card,
+	}
+	for _, change := range event.Changes {
+		message.Changes = append(message.Changes, &Change{Property: change.Property, Removed: change.Removed, Added: change.Added})
+	}
+	return message
+}
+
+// EventFromProto converts a ContactEvent message to an envelope, rejecting
+// newer schema versions like events.Unmarshal
+func EventFromProto(message *ContactEvent) (events.Envelope, error) {
+	if int(message.GetSchemaVersion()) > events.SchemaVersion {
+		return events.Envelope{}, fmt.Errorf("%w: %d", events.ErrSchemaVersion, message.GetSchemaVersion())
+	}
+
+	event := events.Envelope{
+		SchemaVersion: int(message.GetSchemaVersion()),
+		ID:            message.GetId(),
+		Type:          message.GetType(),
+		Source:        message.GetSource(),
+		UID:           message.GetUid(),
+		Version:       message.GetVersion(),
+		Card:          message.GetCard(),
+	}
+	if message.GetTime() != nil {
+		event.Time = message.GetTime().AsTime()
+	}
+	for _, change := range message.GetChanges() {
+		event.Changes = append(event.Changes, vcard.Change{Property: change.GetProperty(), Removed: change.GetRemoved(), Added: change.GetAdded()})
+	}
+	return event, nil
+}
@@ -0,0 +1,45 @@
+package vcardpb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEventRoundTrip(t *testing.T) {
+	old := vcard.New().AddName("Jane", "Doe").SetUID("jane")
+	event, err := events.Updated(old, vcard.New().AddName("Janet", "Doe").SetUID("jane"))
+	if err != nil {
+		t.Fatalf("Updated() returned error: %v", err)
+	}
+	event.Source = "crm"
+
+	data, err := proto.Marshal(EventToProto(event))
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+	var message ContactEvent
+	if err := proto.Unmarshal(data, &message); err != nil {
+		t.Fatalf("Failed to unmarshal message: %v", err)
+	}
+
+	decoded, err := EventFromProto(&message)
+	if err != nil {
+		t.Fatalf("EventFromProto() returned error: %v", err)
+	}
+	if !decoded.Time.Equal(event.Time) {
+		t.Errorf("Expected time %v, got %v", event.Time, decoded.Time)
+	}
+	decoded.Time = event.Time
+	if !reflect.DeepEqual(decoded, event) {
+		t.Errorf("Round trip changed the envelope:\n%+v\n%+v", decoded, event)
+	}
+
+	if _, err := EventFromProto(&ContactEvent{SchemaVersion: 2}); !errors.Is(err, events.ErrSchemaVersion) {
+		t.Errorf("Expected ErrSchemaVersion, got %v", err)
+	}
+}
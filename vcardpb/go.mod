@@ -0,0 +1,12 @@
+module go.rumenx.com/vcard/vcardpb
+
+go 1.23.6
+
+require (
+	go.rumenx.com/vcard v0.0.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require golang.org/x/text v0.28.0 // indirect
+
+replace go.rumenx.com/vcard => ../
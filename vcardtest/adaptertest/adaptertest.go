@@ -0,0 +1,226 @@
+// Package adaptertest is a conformance suite for framework adapters. It
+// checks that an adapter serves cards the way the bundled chi, echo, fiber
+// and gin adapters do: status codes, content types, filenames, extra
+// headers, version negotiation and the bulk error format.
+//
+// An adapter for another framework maps the suite's Options onto its own
+// options and exposes its handlers as an http.Handler:
+//
+//	func TestConformance(t *testing.T) {
+//		adaptertest.Run(t, adaptertest.Adapter{
+//			VCard: func(build adaptertest.CardFunc, o adaptertest.Options) http.Handler {
+//				return mux.VCard(func(c mux.Context) *vcard.VCard {
+//					return build(c.Request())
+//				}, mux.Options{FilenameTemplate: o.FilenameTemplate})
+//			},
+//		})
+//	}
+package adaptertest
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+// DefaultFilename is the filename served when no filename is configured
+const DefaultFilename = "contact.vcf"
+
+// CardFunc builds the card served for a request; it may return nil
+type CardFunc func(r *http.Request) *vcard.VCard
+
+// Options are the adapter options exercised by the suite. Zero values select
+// the adapter's defaults.
+type Options struct {
+	// FilenameTemplate builds the filename from card fields
+	FilenameTemplate string
+
+	// ContentDisposition sets how the file should be handled
+	ContentDisposition string
+
+	// ExtraHeaders are added to every successful vCard response
+	ExtraHeaders map[string]string
+
+	// StatusOnInvalid is the status returned for cards failing validation
+	StatusOnInvalid int
+}
+
+// Adapter builds the handlers under test
+type Adapter struct {
+	// VCard returns the adapter's VCard handler serving the card built for
+	// each GET request, with the version taken from the "version" query
+	// parameter as the adapters do by default
+	VCard func(build CardFunc, options Options) http.Handler
+
+	// Bulk returns the adapter's Bulk handler for POST requests; nil skips
+	// the bulk tests
+	Bulk func(options Options) http.Handler
+}
+
+// Card returns the valid card served by the suite
+func Card() *vcard.VCard {
+	return vcard.New().
+		AddName("Jane", "Doe").
+		AddEmail("jane@example.com", vcard.EmailWork).
+		AddPhone("+1 555 123 4567", vcard.PhoneWork).
+		AddOrganization("Acme")
+}
+
+// Run runs the conformance suite against the adapter
+func Run(t *testing.T, adapter Adapter) {
+	t.Helper()
+	if adapter.VCard == nil {
+		t.Fatal("adaptertest: Adapter.VCard is required")
+	}
+
+	serve := func(card *vcard.VCard) CardFunc {
+		return func(r *http.Request) *vcard.VCard { return card }
+	}
+
+	t.Run("ContentType", func(t *testing.T) {
+		resp := do(adapter.VCard(serve(Card()), Options{}), http.MethodGet, "/", "")
+		expectStatus(t, resp, http.StatusOK)
+		expectMediaType(t, resp, "text/vcard")
+		body := resp.Body.String()
+		if !strings.HasPrefix(body, "BEGIN:VCARD") || !strings.HasSuffix(strings.TrimSpace(body), "END:VCARD") {
+			t.Errorf("Expected a single vCard, got %q", body)
+		}
+		if !strings.Contains(body, "FN:Jane Doe") {
+			t.Errorf("Expected the handler's card, got %q", body)
+		}
+	})
+
+	t.Run("DefaultFilename", func(t *testing.T) {
+		resp := do(adapter.VCard(serve(Card()), Options{}), http.MethodGet, "/", "")
+		expectDisposition(t, resp, "attachment", DefaultFilename)
+	})
+
+	t.Run("FilenameTemplate", func(t *testing.T) {
+		card := Card()
+		options := Options{FilenameTemplate: "{last}-{first}", ContentDisposition: "inline"}
+		resp := do(adapter.VCard(serve(card), options), http.MethodGet, "/", "")
+		expectDisposition(t, resp, "inline", card.Filename(options.FilenameTemplate))
+	})
+
+	t.Run("ExtraHeaders", func(t *testing.T) {
+		options := Options{ExtraHeaders: map[string]string{"Cache-Control": "no-store", "X-Contact-Source": "adaptertest"}}
+		resp := do(adapter.VCard(serve(Card()), options), http.MethodGet, "/", "")
+		for name, value := range options.ExtraHeaders {
+			if got := resp.Header().Get(name); got != value {
+				t.Errorf("Expected %s: %q, got %q", name, value, got)
+			}
+		}
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		card := Card()
+		resp := do(adapter.VCard(serve(card), Options{}), http.MethodGet, "/?version=4.0", "")
+		expectStatus(t, resp, http.StatusOK)
+		if !strings.Contains(resp.Body.String(), "VERSION:4.0") {
+			t.Errorf("Expected a vCard 4.0 response, got %q", resp.Body.String())
+		}
+		if card.GetVersion() != vcard.Version30 {
+			t.Error("Expected the handler's card to keep its version")
+		}
+
+		resp = do(adapter.VCard(serve(Card()), Options{}), http.MethodGet, "/?version=9.9", "")
+		expectStatus(t, resp, http.StatusBadRequest)
+	})
+
+	t.Run("NilCard", func(t *testing.T) {
+		resp := do(adapter.VCard(serve(nil), Options{}), http.MethodGet, "/", "")
+		expectStatus(t, resp, http.StatusInternalServerError)
+	})
+
+	t.Run("InvalidCard", func(t *testing.T) {
+		invalid := vcard.New().AddEmail("jane@example.com")
+		resp := do(adapter.VCard(serve(invalid), Options{}), http.MethodGet, "/", "")
+		expectStatus(t, resp, http.StatusBadRequest)
+		if !strings.Contains(resp.Body.String(), "Invalid vCard") {
+			t.Errorf("Expected the validation error in the body, got %q", resp.Body.String())
+		}
+
+		resp = do(adapter.VCard(serve(invalid), Options{StatusOnInvalid: http.StatusUnprocessableEntity}), http.MethodGet, "/", "")
+		expectStatus(t, resp, http.StatusUnprocessableEntity)
+	})
+
+	if adapter.Bulk == nil {
+		return
+	}
+
+	t.Run("Bulk", func(t *testing.T) {
+		resp := do(adapter.Bulk(Options{}), http.MethodPost, "/", `[{"Name": {"First": "John", "Last": "Doe"}}, {"Name": {"First": "Jane"}}]`)
+		expectStatus(t, resp, http.StatusOK)
+		expectMediaType(t, resp, "text/vcard")
+		expectDisposition(t, resp, "attachment", vcard.DefaultBulkFilename)
+		if count := strings.Count(resp.Body.String(), "BEGIN:VCARD"); count != 2 {
+			t.Errorf("Expected 2 cards, got %d", count)
+		}
+	})
+
+	t.Run("BulkInvalidItems", func(t *testing.T) {
+		for _, status := range []int{0, http.StatusUnprocessableEntity} {
+			resp := do(adapter.Bulk(Options{StatusOnInvalid: status}), http.MethodPost, "/", `[{"Name": {"First": "John"}}, {"Note": "no name"}]`)
+			if status == 0 {
+				status = http.StatusBadRequest
+			}
+			expectStatus(t, resp, status)
+			expectMediaType(t, resp, "application/json")
+
+			var body struct {
+				Errors []vcard.ItemError `json:"errors"`
+			}
+			if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected a JSON error list, got %q: %v", resp.Body.String(), err)
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Index != 1 || body.Errors[0].Message == "" {
+				t.Errorf("Expected one error for index 1, got %+v", body.Errors)
+			}
+		}
+	})
+
+	t.Run("BulkMalformed", func(t *testing.T) {
+		resp := do(adapter.Bulk(Options{}), http.MethodPost, "/", `{"not": "an array"}`)
+		expectStatus(t, resp, http.StatusBadRequest)
+	})
+}
+
+// do serves a request with the handler
+func do(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func expectStatus(t *testing.T, resp *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if resp.Code != status {
+		t.Fatalf("Expected status %d, got %d: %s", status, resp.Code, resp.Body.String())
+	}
+}
+
+func expectMediaType(t *testing.T, resp *httptest.ResponseRecorder, expected string) {
+	t.Helper()
+	mediaType, _, err := mime.ParseMediaType(resp.Header().Get("Content-Type"))
+	if err != nil || mediaType != expected {
+		t.Errorf("Expected Content-Type %s, got %q", expected, resp.Header().Get("Content-Type"))
+	}
+}
+
+func expectDisposition(t *testing.T, resp *httptest.ResponseRecorder, disposition, filename string) {
+	t.Helper()
+	header := resp.Header().Get("Content-Disposition")
+	got, params, err := mime.ParseMediaType(header)
+	if err != nil || got != disposition || params["filename"] != filename {
+		t.Errorf("Expected Content-Disposition %s with filename %q, got %q", disposition, filename, header)
+	}
+}
@@ -0,0 +1,103 @@
+package adaptertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+// reference is a minimal net/http adapter following the bundled adapters
+type reference struct {
+	options Options
+}
+
+func (a reference) resolve(options Options) reference {
+	if options.ContentDisposition == "" {
+		options.ContentDisposition = "attachment"
+	}
+	if options.StatusOnInvalid == 0 {
+		options.StatusOnInvalid = http.StatusBadRequest
+	}
+	return reference{options: options}
+}
+
+func (a reference) write(w http.ResponseWriter, filename, content string) {
+	w.Header().Set("Content-Type", "text/vcard")
+	w.Header().Set("Content-Disposition", a.options.ContentDisposition+"; filename="+filename)
+	for name, value := range a.options.ExtraHeaders {
+		w.Header().Set(name, value)
+	}
+	w.Write([]byte(content))
+}
+
+func (a reference) vcard(build CardFunc, options Options) http.Handler {
+	a = a.resolve(options)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		card := build(r)
+		if card == nil {
+			http.Error(w, "Failed to generate vCard", http.StatusInternalServerError)
+			return
+		}
+		if requested := r.URL.Query().Get("version"); requested != "" {
+			version, err := vcard.ParseVersion(requested)
+			if err != nil {
+				http.Error(w, "Unsupported vCard version", http.StatusBadRequest)
+				return
+			}
+			card = card.Clone().SetVersion(version)
+		}
+		if err := card.Validate(); err != nil {
+			http.Error(w, "Invalid vCard: "+err.Error(), a.options.StatusOnInvalid)
+			return
+		}
+
+		content, err := card.String()
+		if err != nil {
+			http.Error(w, "Failed to generate vCard content", http.StatusInternalServerError)
+			return
+		}
+		filename := DefaultFilename
+		if a.options.FilenameTemplate != "" {
+			filename = card.Filename(a.options.FilenameTemplate)
+		}
+		a.write(w, filename, content)
+	})
+}
+
+func (a reference) bulk(options Options) http.Handler {
+	a = a.resolve(options)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var contacts []vcard.Contact
+		if err := json.NewDecoder(r.Body).Decode(&contacts); err != nil {
+			http.Error(w, "Invalid JSON: expected an array of contacts", http.StatusBadRequest)
+			return
+		}
+
+		book, errs := vcard.NewAddressBookFromContacts(contacts, "")
+		if len(errs) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(a.options.StatusOnInvalid)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": errs})
+			return
+		}
+
+		content, err := book.String()
+		if err != nil {
+			http.Error(w, "Failed to generate vCard content", http.StatusInternalServerError)
+			return
+		}
+		a.write(w, vcard.DefaultBulkFilename, content)
+	})
+}
+
+func TestRunReference(t *testing.T) {
+	var adapter reference
+	Run(t, Adapter{VCard: adapter.vcard, Bulk: adapter.bulk})
+}
+
+func TestRunWithoutBulk(t *testing.T) {
+	var adapter reference
+	Run(t, Adapter{VCard: adapter.vcard})
+}
@@ -0,0 +1,113 @@
+// Package vcardtest provides test doubles for code exchanging vCards with
+// other systems.
+//
+// CardDAVServer is an in-memory CardDAV server (RFC 6352) for integration
+// tests of CardDAV clients, so they don't need a Radicale or Nextcloud
+// instance:
+//
+//	server := vcardtest.NewCardDAVServer()
+//	defer server.Close()
+//
+//	server.Put(vcard.New().AddName("Jane", "Doe").SetUID("jane"))
+//	client := myapp.NewCardDAVClient(server.URL, "user", "secret")
+//	...
+//	if _, ok := server.Card("jane.vcf"); !ok {
+//		t.Error("expected the card to stay on the server")
+//	}
+package vcardtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/server"
+	"go.rumenx.com/vcard/store"
+)
+
+// Paths served by CardDAVServer. Requests for /.well-known/carddav are
+// redirected to the principal with 308, keeping the method.
+const (
+	PrincipalPath   = server.PrincipalPath
+	HomeSetPath     = server.HomeSetPath
+	AddressBookPath = server.AddressBookPath
+)
+
+// CardDAVServer is an in-memory CardDAV server with a single address book at
+// AddressBookPath, served by server.CardDAV over a store.MemoryStore. Cards
+// are stored at their path-escaped UID plus ".vcf". It is safe for
+// concurrent use.
+type CardDAVServer struct {
+	*httptest.Server
+
+	// Username and Password, when Username is set, are required through
+	// basic authentication
+	Username string
+	Password string
+
+	store   *store.MemoryStore
+	handler http.Handler
+}
+
+// NewCardDAVServer starts a server with an empty address book. Close it when
+// done.
+func NewCardDAVServer() *CardDAVServer {
+	s := &CardDAVServer{store: store.NewMemoryStore()}
+	s.handler = server.CardDAV(s.store)
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// AddressBookURL returns the absolute URL of the address book
+func (s *CardDAVServer) AddressBookURL() string {
+	return s.URL + AddressBookPath
+}
+
+// Put stores the card, as a client upload would, and returns the resource
+// name. Cards without a UID are not stored.
+func (s *CardDAVServer) Put(card *vcard.VCard) string {
+	if err := s.store.Put(card); err != nil {
+		return ""
+	}
+	return server.ResourceName(card.GetUID())
+}
+
+// Card returns the content of the named resource, e.g. "jane.vcf"
+func (s *CardDAVServer) Card(name string) (string, bool) {
+	uid, err := url.PathUnescape(strings.TrimSuffix(name, vcard.FileExtension))
+	if err != nil {
+		return "", false
+	}
+	content, err := s.store.Get(uid)
+	return content, err == nil
+}
+
+// Names returns the resource names in the address book in UID order
+func (s *CardDAVServer) Names() []string {
+	uids, _ := s.store.UIDs(context.Background())
+	names := make([]string, len(uids))
+	for i, uid := range uids {
+		names[i] = server.ResourceName(uid)
+	}
+	return names
+}
+
+// Reset removes all cards
+func (s *CardDAVServer) Reset() {
+	uids, _ := s.store.UIDs(context.Background())
+	for _, uid := range uids {
+		s.store.Delete(uid)
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (s *CardDAVServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Username != "" {
+		server.BasicAuth(s.Username, s.Password)(s.handler).ServeHTTP(w, r)
+		return
+	}
+	s.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,118 @@
+package vcardtest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.rumenx.com/vcard"
+)
+
+// do sends a request to the server and returns the status, ETag and body
+func do(t *testing.T, s *CardDAVServer, method, path, body string, headers map[string]string) (int, string, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get("ETag"), string(data)
+}
+
+func TestCardDAVServerCards(t *testing.T) {
+	s := NewCardDAVServer()
+	defer s.Close()
+
+	content, _ := vcard.New().AddName("Jane", "Doe").SetUID("jane").String()
+	path := AddressBookPath + "jane.vcf"
+
+	status, etag, _ := do(t, s, http.MethodPut, path, content, map[string]string{"If-None-Match": "*"})
+	if status != http.StatusCreated || etag == "" {
+		t.Fatalf("Expected 201 with an ETag, got %d %q", status, etag)
+	}
+	if status, _, _ := do(t, s, http.MethodPut, path, content, map[string]string{"If-None-Match": "*"}); status != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for an existing card, got %d", status)
+	}
+	if status, _, _ := do(t, s, http.MethodPut, path, content, map[string]string{"If-Match": `"stale"`}); status != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale ETag, got %d", status)
+	}
+	if status, _, body := do(t, s, http.MethodPut, AddressBookPath+"bad.vcf", "not a card", nil); status != http.StatusForbidden || !strings.Contains(body, "valid-address-data") {
+		t.Errorf("Expected 403 valid-address-data, got %d %s", status, body)
+	}
+
+	status, got, body := do(t, s, http.MethodGet, path, "", nil)
+	if status != http.StatusOK || got != etag || body != content {
+		t.Errorf("Unexpected GET: %d %q\n%s", status, got, body)
+	}
+
+	if status, _, _ := do(t, s, http.MethodDelete, path, "", map[string]string{"If-Match": etag}); status != http.StatusNoContent {
+		t.Errorf("Expected 204 for DELETE, got %d", status)
+	}
+	if _, ok := s.Card("jane.vcf"); ok || len(s.Names()) != 0 {
+		t.Errorf("Expected the card to be deleted, have %v", s.Names())
+	}
+}
+
+func TestCardDAVServerDiscovery(t *testing.T) {
+	s := NewCardDAVServer()
+	defer s.Close()
+	s.Put(vcard.New().AddName("Jane", "Doe").SetUID("jane"))
+	s.Put(vcard.New().AddName("John", "Doe").SetUID("john"))
+
+	status, _, body := do(t, s, "PROPFIND", "/.well-known/carddav", "", map[string]string{"Depth": "0"})
+	if status != http.StatusMultiStatus || !strings.Contains(body, "<card:addressbook-home-set><d:href>"+HomeSetPath) {
+		t.Errorf("Expected the principal after the well-known redirect, got %d\n%s", status, body)
+	}
+
+	status, _, body = do(t, s, "PROPFIND", AddressBookPath, "", map[string]string{"Depth": "1"})
+	if status != http.StatusMultiStatus || strings.Count(body, "<d:response>") != 3 || !strings.Contains(body, "<card:addressbook/>") {
+		t.Errorf("Expected the address book and two cards, got %d\n%s", status, body)
+	}
+
+	multiget := `<?xml version="1.0"?>
+<card:addressbook-multiget xmlns:d="DAV:" xmlns:card="urn:ietf:params:xml:ns:carddav">
+  <d:prop><d:getetag/><card:address-data/></d:prop>
+  <d:href>` + AddressBookPath + `jane.vcf</d:href>
+  <d:href>` + AddressBookPath + `gone.vcf</d:href>
+</card:addressbook-multiget>`
+	status, _, body = do(t, s, "REPORT", AddressBookPath, multiget, map[string]string{"Depth": "1"})
+	if status != http.StatusMultiStatus || !strings.Contains(body, "FN:Jane Doe") || strings.Contains(body, "John") || !strings.Contains(body, "404 Not Found") {
+		t.Errorf("Unexpected multiget response %d\n%s", status, body)
+	}
+
+	query := `<card:addressbook-query xmlns:d="DAV:" xmlns:card="urn:ietf:params:xml:ns:carddav"><d:prop><card:address-data/></d:prop></card:addressbook-query>`
+	status, _, body = do(t, s, "REPORT", AddressBookPath, query, nil)
+	if status != http.StatusMultiStatus || !strings.Contains(body, "FN:Jane Doe") || !strings.Contains(body, "FN:John Doe") {
+		t.Errorf("Unexpected query response %d\n%s", status, body)
+	}
+}
+
+func TestCardDAVServerAuth(t *testing.T) {
+	s := NewCardDAVServer()
+	defer s.Close()
+	s.Username, s.Password = "user", "secret"
+
+	if status, _, _ := do(t, s, "PROPFIND", AddressBookPath, "", nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", status)
+	}
+
+	req, _ := http.NewRequest("PROPFIND", s.AddressBookURL(), nil)
+	req.SetBasicAuth("user", "secret")
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		t.Errorf("Expected 207 with credentials, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,300 @@
+package vcardtest
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"go.rumenx.com/vcard"
+)
+
+// Edge cases generated by Vectors, in the order they rotate through
+const (
+	// CaseFolding has long values whose multibyte characters and escape
+	// sequences fall on every column around the 75-octet fold
+	CaseFolding = "folding"
+
+	// CaseEscapes puts backslashes, commas, semicolons, colons, quotes and
+	// line breaks in every text property and structured field
+	CaseEscapes = "escapes"
+
+	// CaseProperties sets every property the library writes
+	CaseProperties = "properties"
+
+	// CasePhoto embeds a photo and logo of 256 KiB to 1 MiB as data URIs
+	CasePhoto = "photo"
+
+	// CaseUnicode uses combining marks, right-to-left scripts with bidi
+	// controls, emoji ZWJ sequences, flags and characters outside the BMP.
+	// The bidi controls are written as is (see VectorOptions).
+	CaseUnicode = "unicode"
+)
+
+// VectorCases lists the edge cases in the order Vectors generates them
+var VectorCases = []string{CaseFolding, CaseEscapes, CaseProperties, CasePhoto, CaseUnicode}
+
+// VectorOptions returns the options of the generated cards. Sanitization is
+// off, so bidi controls such as U+202E RIGHT-TO-LEFT OVERRIDE, which the
+// default SanitizeStrip removes, reach the content for parsers to be tested
+// against. Read the content back with these options to get the same
+// encoding.
+func VectorOptions() vcard.CardOptions {
+	return vcard.CardOptions{Sanitize: vcard.SanitizeOff}
+}
+
+// Vector is a generated edge-case card and its encoding by this library
+type Vector struct {
+	// Name is the file name of the vector, e.g. "003-properties.vcf"
+	Name string
+
+	// Case is the edge case the card exercises, one of VectorCases
+	Case string
+
+	// Card is the generated card
+	Card *vcard.VCard
+
+	// Content is the card as written by Card.String
+	Content string
+}
+
+// Vectors returns n edge-case cards generated from the seed, rotating
+// through VectorCases. The same seed always produces the same cards and
+// content, and each vector depends only on the seed and its position, so
+// Vectors(seed, 10) starts with Vectors(seed, 5). Downstream parsers can be
+// regression-tested against the Content of each vector:
+//
+//	for _, vector := range vcardtest.Vectors(42, 50) {
+//		if _, err := myparser.Parse(vector.Content); err != nil {
+//			t.Errorf("%s: %v", vector.Name, err)
+//		}
+//	}
+//
+// Vectors panics when a generated card cannot be encoded, which is a bug in
+// the library.
+func Vectors(seed uint64, n int) []Vector {
+	vectors := make([]Vector, n)
+	for i := range vectors {
+		kind := VectorCases[i%len(VectorCases)]
+		g := &generator{rand: rand.New(rand.NewPCG(seed, uint64(i)))}
+		card := g.card(kind)
+		content, err := card.String()
+		if err != nil {
+			panic(fmt.Sprintf("vcardtest: encoding %s vector %d: %v", kind, i, err))
+		}
+		vectors[i] = Vector{
+			Name:    fmt.Sprintf("%03d-%s%s", i, kind, vcard.FileExtension),
+			Case:    kind,
+			Card:    card,
+			Content: content,
+		}
+	}
+	return vectors
+}
+
+// VectorCorpus returns the vectors of Vectors(seed, n) as a file system of
+// .vcf files named after the vectors, to be walked like vcard.Corpus or
+// copied to disk for parsers in other languages
+func VectorCorpus(seed uint64, n int) fs.FS {
+	files := make(fstest.MapFS, n)
+	for _, vector := range Vectors(seed, n) {
+		files[vector.Name] = &fstest.MapFile{Data: []byte(vector.Content), Mode: 0o444}
+	}
+	return files
+}
+
+// Character pools the generator draws from
+var (
+	// escapes are the characters with a meaning in property values
+	escapes = []string{`\`, ",", ";", ":", `"`, "\n", "\r\n", `\n`, `\,`, `\\`}
+
+	// wide are multibyte characters of two to four octets, so folds land
+	// inside every kind of UTF-8 sequence
+	wide = []string{"é", "ß", "Ж", "ש", "€", "中", "ア", "😀", "𝄞", "𠮷"}
+
+	// exotic are grapheme clusters that trip naive Unicode handling
+	exotic = []string{
+		"e\u0301",                   // e and a combining acute, composed by NFC
+		"Z\u0351\u0355\u035a\u0361", // stacked combining marks
+		"\u202eRTL\u202c",           // right-to-left override
+		"\u200fעברית",               // Hebrew after a right-to-left mark
+		"العربية",                   // Arabic
+		"👩\u200d👩\u200d👧\u200d👦",    // family ZWJ sequence
+		"👍🏽",                        // skin tone modifier
+		"🇧🇬",                        // regional indicator flag
+		"𠮷野家",                       // CJK outside the BMP
+		"\ufdfa",                    // single code point that renders wide
+		"a\u200bb",                  // zero-width space
+		"\u01c5",                    // titlecase digraph
+		"İstanbul",                  // dotted capital I
+		"\u00a0nbsp\u00a0",          // no-break spaces
+	}
+)
+
+// generator builds the cards of one vector
+type generator struct {
+	rand *rand.Rand
+}
+
+// card returns a card exercising the edge case
+func (g *generator) card(kind string) *vcard.VCard {
+	version := vcard.Version30
+	if g.rand.IntN(2) == 1 {
+		version = vcard.Version40
+	}
+	card := vcard.NewWithVersion(version).SetOptions(VectorOptions()).SetUID(g.uid())
+
+	switch kind {
+	case CaseFolding:
+		card.AddName(g.folded(80), g.folded(200)).
+			SetFormattedName(g.folded(300)).
+			AddNote(g.folded(2000)).
+			AddOrganization(g.folded(120)).
+			AddAddress(g.folded(150), "Sofia", "", "1000", "Bulgaria", vcard.AddressHome).
+			AddCustomProperty("X-FOLDING", g.folded(500))
+	case CaseEscapes:
+		card.SetName(vcard.Name{
+			First:  g.escaped(12),
+			Last:   g.escaped(12),
+			Middle: g.escaped(6),
+			Prefix: g.escaped(4),
+			Suffix: g.escaped(4),
+		}).
+			SetFormattedName(g.escaped(20)).
+			AddOrganization(g.escaped(16)).
+			AddDepartment(g.escaped(10)).
+			AddTitle(g.escaped(10)).
+			AddRole(g.escaped(10)).
+			AddNote(g.escaped(60)).
+			AddAddressExtended(g.escaped(12), g.escaped(6), g.escaped(8), g.escaped(6), g.escaped(5), g.escaped(8), vcard.AddressWork).
+			AddCustomProperty("X-ESCAPES", g.escaped(30))
+	case CaseProperties:
+		g.properties(card)
+	case CasePhoto:
+		card.AddName("Photo", fmt.Sprintf("Vector %d", g.rand.IntN(1000))).
+			AddPhoto(vcard.EncodeDataURI("image/jpeg", g.image(256<<10+g.rand.IntN(768<<10)))).
+			AddLogo(vcard.EncodeDataURI("image/png", g.image(256<<10)))
+	case CaseUnicode:
+		card.SetName(vcard.Name{First: g.unicode(3), Last: g.unicode(3), Middle: g.unicode(1)}).
+			SetFormattedName(g.unicode(6)).
+			AddEmail(g.pick(exotic)+"@example.com", vcard.EmailHome).
+			AddOrganization(g.unicode(4)).
+			AddTitle(g.unicode(2)).
+			AddNote(g.unicode(40)).
+			AddAddress(g.unicode(3), g.unicode(1), g.unicode(1), "", g.unicode(1), vcard.AddressHome).
+			AddCustomProperty("X-UNICODE", g.unicode(10))
+		if version == vcard.Version40 {
+			card.AddAlternate("FN", "ar", "العربية").AddAlternate("FN", "ja", "山田太郎")
+		}
+	}
+	return card
+}
+
+// properties sets every property the library writes for the card's version
+func (g *generator) properties(card *vcard.VCard) {
+	date := time.Date(1900+g.rand.IntN(200), time.Month(1+g.rand.IntN(12)), 1+g.rand.IntN(28), 0, 0, 0, 0, time.UTC)
+
+	card.SetName(vcard.Name{First: "Jane", Last: "Doe", Middle: "Q", Prefix: "Dr.", Suffix: "PhD"}).
+		SetFormattedName("Dr. Jane Q. Doe, PhD").
+		AddEmailWithPreference("jane@example.com", vcard.EmailWork, true).
+		AddEmail("jane@example.org", vcard.EmailHome).
+		AddPhoneWithPreference("+359 2 123 4567", vcard.PhoneWork, true).
+		AddPhone("+1-555-0100", vcard.PhoneMobile).
+		AddPhone("+1-555-0199", vcard.PhoneFax).
+		AddAddressWithPreference("1 Main St", "Springfield", "IL", "62701", "USA", vcard.AddressWork, true).
+		AddAddressExtended("2 Side St", "Apt 3", "Shelbyville", "IL", "62565", "USA", vcard.AddressHome).
+		AddOrganization("Example Corp").
+		AddDepartment("Research").
+		AddOrgUnit("Parsers").
+		AddTitle("Engineer").
+		AddRole("Reviewer").
+		AddURLWithPreference("https://example.com/jane", vcard.URLWork, true).
+		AddURL("https://social.example/@jane", vcard.URLSocial).
+		SetGeo(g.rand.Float64()*180-90, g.rand.Float64()*360-180).
+		AddPhoto("https://example.com/jane.jpg").
+		AddLogo("https://example.com/logo.png").
+		AddNote("Generated for interop testing").
+		AddBirthday(date).
+		AddAnniversary(date.AddDate(25, 0, 0)).
+		AddCustomProperty("X-SOURCE", "vcardtest")
+
+	switch g.rand.IntN(3) {
+	case 1:
+		card.SetKind(vcard.KindOrg).SetShowAsCompany(true)
+	case 2:
+		card.SetKind(vcard.KindGroup).
+			AddMember("urn:uuid:" + g.uid()).
+			AddMember("mailto:team@example.com")
+	}
+	if card.GetVersion() == vcard.Version40 {
+		card.AddAlternate("FN", "bg", "Д-р Джейн Доу")
+	}
+}
+
+// folded returns a value of about size octets that starts with an ASCII run
+// of random length, so the wide characters and escapes after it shift across
+// fold positions
+func (g *generator) folded(size int) string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("x", g.rand.IntN(75)))
+	for b.Len() < size {
+		switch g.rand.IntN(4) {
+		case 0:
+			b.WriteString(g.pick(escapes[:5]))
+		case 1:
+			b.WriteByte(byte('a' + g.rand.IntN(26)))
+		default:
+			b.WriteString(g.pick(wide))
+		}
+	}
+	return b.String()
+}
+
+// escaped returns n tokens mixing letters with characters to escape
+func (g *generator) escaped(n int) string {
+	var b strings.Builder
+	for range n {
+		if g.rand.IntN(2) == 0 {
+			b.WriteString(g.pick(escapes))
+		} else {
+			b.WriteByte(byte('a' + g.rand.IntN(26)))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// unicode returns n exotic clusters separated by spaces
+func (g *generator) unicode(n int) string {
+	clusters := make([]string, n)
+	for i := range clusters {
+		clusters[i] = g.pick(exotic)
+	}
+	return strings.Join(clusters, " ")
+}
+
+// image returns size pseudo-random octets
+func (g *generator) image(size int) []byte {
+	data := make([]byte, size)
+	for i := 0; i < size; i += 8 {
+		word := g.rand.Uint64()
+		for j := 0; j < 8 && i+j < size; j++ {
+			data[i+j] = byte(word >> (8 * j))
+		}
+	}
+	return data
+}
+
+// uid returns a random UUID
+func (g *generator) uid() string {
+	hi, lo := g.rand.Uint64(), g.rand.Uint64()
+	hi = hi&^0xf000 | 0x4000
+	lo = lo&^(0xc<<60) | 0x8<<60
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", hi>>32, hi>>16&0xffff, hi&0xffff, lo>>48, lo&0xffffffffffff)
+}
+
+// pick returns a random element of the pool
+func (g *generator) pick(pool []string) string {
+	return pool[g.rand.IntN(len(pool))]
+}
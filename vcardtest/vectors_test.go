@@ -0,0 +1,137 @@
+package vcardtest
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"go.rumenx.com/vcard"
+)
+
+func TestVectorsDeterministic(t *testing.T) {
+	first, second := Vectors(7, 10), Vectors(7, 10)
+	prefix := Vectors(7, 5)
+	for i, vector := range first {
+		if vector.Content != second[i].Content {
+			t.Errorf("%s: expected the same content for the same seed", vector.Name)
+		}
+		if i < len(prefix) && prefix[i].Content != vector.Content {
+			t.Errorf("%s: expected the vector not to depend on n", vector.Name)
+		}
+		if vector.Case != VectorCases[i%len(VectorCases)] {
+			t.Errorf("%s: expected case %s, got %s", vector.Name, VectorCases[i%len(VectorCases)], vector.Case)
+		}
+	}
+
+	if Vectors(8, 1)[0].Content == first[0].Content {
+		t.Error("Expected different seeds to give different vectors")
+	}
+}
+
+func TestVectorsRoundTrip(t *testing.T) {
+	vectors := Vectors(42, 2*len(VectorCases))
+	for _, vector := range vectors {
+		card, err := vcard.NewDecoder(strings.NewReader(vector.Content)).CardOptions(VectorOptions()).Decode()
+		if err != nil {
+			t.Errorf("%s: %v", vector.Name, err)
+			continue
+		}
+		content, err := card.String()
+		if err != nil {
+			t.Errorf("%s: %v", vector.Name, err)
+			continue
+		}
+		if content != vector.Content {
+			t.Errorf("%s: expected re-encoding to give the same content", vector.Name)
+		}
+		for _, line := range strings.Split(vector.Content, "\n") {
+			if line = strings.TrimSuffix(line, "\r"); len(line) > 75 {
+				t.Errorf("%s: line of %d octets: %q", vector.Name, len(line), line)
+				break
+			}
+		}
+		if card.GetUID() != vector.Card.GetUID() {
+			t.Errorf("%s: expected UID %q, got %q", vector.Name, vector.Card.GetUID(), card.GetUID())
+		}
+	}
+
+	var all strings.Builder
+	for _, vector := range vectors {
+		all.WriteString(vector.Content)
+	}
+	decoder := vcard.NewDecoder(strings.NewReader(all.String())).CardOptions(VectorOptions())
+	count := 0
+	for {
+		_, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != len(vectors) {
+		t.Errorf("Expected the decoder to read %d vectors, got %d", len(vectors), count)
+	}
+}
+
+func TestVectorsEdgeCases(t *testing.T) {
+	for _, vector := range Vectors(1, len(VectorCases)) {
+		switch vector.Case {
+		case CaseFolding:
+			if strings.Count(vector.Content, "\r\n ") < 20 {
+				t.Errorf("%s: expected long folded values", vector.Name)
+			}
+		case CaseEscapes:
+			for _, escape := range []string{`\\`, `\,`, `\;`, `\n`} {
+				if !strings.Contains(vector.Content, escape) {
+					t.Errorf("%s: expected the escape %s", vector.Name, escape)
+				}
+			}
+		case CasePhoto:
+			if len(vector.Content) < 512<<10 {
+				t.Errorf("%s: expected a large photo, got %d octets", vector.Name, len(vector.Content))
+			}
+		case CaseUnicode:
+			if !strings.ContainsFunc(vector.Content, func(r rune) bool { return r > 0xffff }) {
+				t.Errorf("%s: expected characters outside the BMP", vector.Name)
+			}
+		}
+	}
+}
+
+func TestVectorsKeepBidiControls(t *testing.T) {
+	found := false
+	for _, vector := range Vectors(1, 20*len(VectorCases)) {
+		if vector.Case != CaseUnicode {
+			continue
+		}
+		if strings.Contains(vector.Content, "RTL") {
+			found = true
+			if !strings.Contains(vector.Content, "\u202eRTL\u202c") {
+				t.Errorf("%s: expected the bidi controls to be kept", vector.Name)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a vector with a right-to-left override")
+	}
+}
+
+func TestVectorCorpus(t *testing.T) {
+	corpus := VectorCorpus(3, 5)
+	if err := fstest.TestFS(corpus, "000-folding.vcf", "004-unicode.vcf"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fs.ReadFile(corpus, "002-properties.vcf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != Vectors(3, 3)[2].Content {
+		t.Error("Expected the corpus file to hold the vector content")
+	}
+}
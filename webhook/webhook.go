@@ -0,0 +1,226 @@
+// Package webhook notifies downstream systems when vCards are generated or
+// imported.
+//
+// A Notifier POSTs a JSON event to a configured URL from a background worker,
+// so request handlers never wait on the receiving service:
+//
+//	notifier := webhook.New("https://crm.example.com/hooks/vcard")
+//	defer notifier.Close()
+//
+//	if event, err := webhook.NewEvent(webhook.EventGenerated, card); err == nil {
+//		notifier.Notify(event)
+//	}
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/retry"
+)
+
+// Event types
+const (
+	EventGenerated = "vcard.generated"
+	EventImported  = "vcard.imported"
+)
+
+const (
+	// DefaultQueueSize is the number of events buffered before Notify drops
+	// them
+	DefaultQueueSize = 100
+
+	// DefaultTimeout bounds each delivery attempt when Notifier.Timeout is
+	// zero
+	DefaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrQueueFull is reported to the error handler when an event is dropped
+	ErrQueueFull = errors.New("webhook: queue full")
+
+	// ErrClosed is reported to the error handler for events notified after
+	// Close
+	ErrClosed = errors.New("webhook: notifier closed")
+)
+
+// Event is the JSON payload delivered to the webhook URL
+type Event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	UID     string    `json:"uid,omitempty"`
+	Name    string    `json:"name,omitempty"`
+	Version string    `json:"version"`
+	Card    string    `json:"card"`
+}
+
+// NewEvent creates an event of the given type describing the card. It fails
+// when the card can't be generated.
+func NewEvent(eventType string, card *vcard.VCard) (Event, error) {
+	content, err := card.String()
+	if err != nil {
+		return Event{}, fmt.Errorf("webhook: %w", err)
+	}
+	return Event{
+		Type:    eventType,
+		Time:    time.Now().UTC(),
+		UID:     card.GetUID(),
+		Name:    card.GetFormattedName(),
+		Version: string(card.GetVersion()),
+		Card:    content,
+	}, nil
+}
+
+// Notifier delivers events to a webhook URL asynchronously
+type Notifier struct {
+	// Client sends the requests; http.DefaultClient is used when nil
+	Client *http.Client
+
+	// Timeout bounds each delivery attempt, so a receiver that hangs can't
+	// stall the worker; DefaultTimeout is used when zero
+	Timeout time.Duration
+
+	// Headers are added to every request (e.g. an authorization token)
+	Headers map[string]string
+
+	// Retry resends events after network errors and retryable statuses (see
+	// retry.Status); nil makes a single attempt
+	Retry *retry.Policy
+
+	// OnError receives events that could not be delivered
+	OnError func(event Event, err error)
+
+	url   string
+	queue chan Event
+	start sync.Once
+	done  chan struct{}
+
+	// mu guards closed, so Notify never sends on the closed queue
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New creates a Notifier posting events to the given URL
+func New(url string) *Notifier {
+	return &Notifier{
+		url:   url,
+		queue: make(chan Event, DefaultQueueSize),
+		done:  make(chan struct{}),
+	}
+}
+
+// Notify queues the event for delivery without blocking. Events are dropped
+// with ErrQueueFull when the queue is full and with ErrClosed after Close.
+func (n *Notifier) Notify(event Event) {
+	if err := n.enqueue(event); err != nil {
+		n.fail(event, err)
+	}
+}
+
+// enqueue adds the event to the queue unless it is full or closed
+func (n *Notifier) enqueue(event Event) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return ErrClosed
+	}
+	n.start.Do(func() { go n.run() })
+
+	select {
+	case n.queue <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close delivers the queued events and stops the background worker. Events
+// notified afterwards are dropped with ErrClosed.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		n.start.Do(func() { go n.run() })
+		close(n.queue)
+	}
+	n.mu.Unlock()
+	<-n.done
+}
+
+// Send delivers the event synchronously, retrying under the Retry policy
+func (n *Notifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("webhook: failed to encode event: %w", err)
+	}
+
+	return n.Retry.Do(ctx, func(ctx context.Context) error {
+		return n.post(ctx, body)
+	})
+}
+
+// post makes a single delivery attempt within the Timeout. Errors a retry
+// can't fix are marked permanent.
+func (n *Notifier) post(ctx context.Context, body []byte) error {
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("webhook: failed to create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+		if !retry.Status(resp.StatusCode) {
+			return retry.Permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// run delivers queued events until the queue is closed
+func (n *Notifier) run() {
+	defer close(n.done)
+
+	for event := range n.queue {
+		if err := n.Send(context.Background(), event); err != nil {
+			n.fail(event, err)
+		}
+	}
+}
+
+// fail reports an undelivered event to the error handler
+func (n *Notifier) fail(event Event, err error) {
+	if n.OnError != nil {
+		n.OnError(event, err)
+	}
+}
@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.rumenx.com/vcard"
+	"go.rumenx.com/vcard/retry"
+)
+
+func TestNewEvent(t *testing.T) {
+	card := vcard.New().AddName("John", "Doe").SetUID("urn:uuid:1234")
+
+	event, err := NewEvent(EventGenerated, card)
+	if err != nil {
+		t.Fatalf("NewEvent() returned error: %v", err)
+	}
+	if event.Type != EventGenerated {
+		t.Errorf("Expected type %s, got %s", EventGenerated, event.Type)
+	}
+	if event.UID != "urn:uuid:1234" {
+		t.Errorf("Expected UID urn:uuid:1234, got %s", event.UID)
+	}
+	if event.Name != "John Doe" {
+		t.Errorf("Expected name John Doe, got %s", event.Name)
+	}
+	if !strings.Contains(event.Card, "FN:John Doe") {
+		t.Errorf("Expected card content, got %s", event.Card)
+	}
+}
+
+// newEvent creates an event, failing the test on error
+func newEvent(t *testing.T, eventType string, card *vcard.VCard) Event {
+	t.Helper()
+	event, err := NewEvent(eventType, card)
+	if err != nil {
+		t.Fatalf("NewEvent() returned error: %v", err)
+	}
+	return event
+}
+
+func TestNewEventInvalidCard(t *testing.T) {
+	card := vcard.NewWithVersion(vcard.Version40).SetFormattedName("Jane").AddAlternate("FN", "en:x", "Jane")
+	if _, err := NewEvent(EventGenerated, card); err == nil {
+		t.Error("Expected error for a card that can't be generated")
+	}
+}
+
+func TestNotifyDelivers(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	notifier := New(server.URL)
+	notifier.Headers = map[string]string{"Authorization": "Bearer secret"}
+
+	card := vcard.New().AddName("John", "Doe")
+	notifier.Notify(newEvent(t, EventGenerated, card))
+	notifier.Notify(newEvent(t, EventImported, card))
+	notifier.Close()
+
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(received))
+	}
+	if received[1].Type != EventImported {
+		t.Errorf("Expected second event %s, got %s", EventImported, received[1].Type)
+	}
+}
+
+func TestNotifyReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var failures []error
+	notifier := New(server.URL)
+	notifier.OnError = func(event Event, err error) {
+		failures = append(failures, err)
+	}
+
+	notifier.Notify(newEvent(t, EventGenerated, vcard.New().AddName("John", "Doe")))
+	notifier.Close()
+
+	if len(failures) != 1 {
+		t.Fatalf("Expected 1 failure, got %d", len(failures))
+	}
+	if !strings.Contains(failures[0].Error(), "unexpected status 500") {
+		t.Errorf("Unexpected error: %v", failures[0])
+	}
+}
+
+func TestNotifyQueueFull(t *testing.T) {
+	notifier := &Notifier{
+		url:   "http://127.0.0.1:0",
+		queue: make(chan Event),
+		done:  make(chan struct{}),
+	}
+	// Mark the worker as started so nothing drains the unbuffered queue
+	notifier.start.Do(func() {})
+
+	var dropped error
+	notifier.OnError = func(event Event, err error) {
+		dropped = err
+	}
+
+	notifier.Notify(Event{Type: EventGenerated})
+	if !errors.Is(dropped, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", dropped)
+	}
+}
+
+func TestNotifyTimeout(t *testing.T) {
+	var delivered atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		json.NewDecoder(r.Body).Decode(&event)
+		if event.UID == "hang" {
+			// Hang until the notifier gives up
+			<-r.Context().Done()
+			return
+		}
+		delivered.Add(1)
+	}))
+	defer server.Close()
+
+	var failures []error
+	notifier := New(server.URL)
+	notifier.Timeout = 50 * time.Millisecond
+	notifier.OnError = func(event Event, err error) {
+		failures = append(failures, err)
+	}
+
+	notifier.Notify(Event{Type: EventGenerated, UID: "hang"})
+	notifier.Notify(Event{Type: EventGenerated, UID: "next"})
+	notifier.Close()
+
+	if len(failures) != 1 || !errors.Is(failures[0], context.DeadlineExceeded) {
+		t.Errorf("Expected the hanging delivery to time out, got %v", failures)
+	}
+	if delivered.Load() != 1 {
+		t.Errorf("Expected the next event to be delivered, got %d", delivered.Load())
+	}
+}
+
+func TestNotifyAfterClose(t *testing.T) {
+	var dropped []error
+	notifier := New("http://127.0.0.1:0")
+	notifier.OnError = func(event Event, err error) {
+		dropped = append(dropped, err)
+	}
+
+	notifier.Close()
+	notifier.Close()
+	notifier.Notify(Event{Type: EventGenerated})
+
+	if len(dropped) != 1 || !errors.Is(dropped[0], ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", dropped)
+	}
+}
+
+func TestSendInvalidURL(t *testing.T) {
+	notifier := New("://invalid")
+	if err := notifier.Send(context.Background(), Event{}); err == nil {
+		t.Error("Expected error for invalid URL")
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch {
+		case r.URL.Path == "/gone":
+			w.WriteHeader(http.StatusGone)
+		case n < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	notifier := New(server.URL)
+	notifier.Retry = &retry.Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+	event := newEvent(t, EventImported, vcard.New().AddName("Jane", "Doe"))
+
+	if err := notifier.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests.Load())
+	}
+
+	requests.Store(0)
+	gone := New(server.URL + "/gone")
+	gone.Retry = notifier.Retry
+	if err := gone.Send(context.Background(), event); err == nil {
+		t.Error("Expected error for 410")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no retry of a 410, got %d requests", requests.Load())
+	}
+}